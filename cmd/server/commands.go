@@ -46,6 +46,57 @@ func generateKeys(_ context.Context, _ *cli.Command) error {
 	return nil
 }
 
+// generateNextSecret prints the key_secrets entry rotating the secret of an API key, the current secrets
+// stay valid for the overlap after the new secret becomes active
+func generateNextSecret(_ context.Context, c *cli.Command) error {
+	apiKey := c.String("api-key")
+
+	conf, err := getConfig(c)
+	if err != nil {
+		return err
+	}
+	if err = conf.ValidateKeys(); err != nil {
+		return err
+	}
+
+	current := conf.KeySecrets[apiKey]
+	if secret, ok := conf.Keys[apiKey]; ok {
+		current = []config.KeySecret{{Secret: secret}}
+	}
+	if len(current) == 0 {
+		return fmt.Errorf("api key %s is not configured", apiKey)
+	}
+
+	activeAt := time.Now().Add(c.Duration("activate-after")).UTC().Truncate(time.Second)
+	expireAt := activeAt.Add(c.Duration("overlap"))
+
+	secrets := make([]config.KeySecret, 0, len(current)+1)
+	for _, s := range current {
+		if !s.NotAfter.IsZero() && !s.NotAfter.After(activeAt) {
+			// expires before the new secret is active, no longer needed
+			continue
+		}
+		if s.NotAfter.IsZero() || s.NotAfter.After(expireAt) {
+			s.NotAfter = expireAt
+		}
+		secrets = append(secrets, s)
+	}
+	secrets = append(secrets, config.KeySecret{
+		Secret:    utils.RandomSecret(),
+		NotBefore: activeAt,
+	})
+
+	out, err := yaml.Marshal(map[string]map[string][]config.KeySecret{
+		"key_secrets": {apiKey: secrets},
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("# replace the entry of %s in keys or key_secrets with\n", apiKey)
+	fmt.Print(string(out))
+	return nil
+}
+
 func printPorts(_ context.Context, c *cli.Command) error {
 	conf, err := getConfig(c)
 	if err != nil {
@@ -172,6 +223,10 @@ func listNodes(_ context.Context, c *cli.Command) error {
 	if err != nil {
 		return err
 	}
+	if conf.Cluster.Enabled {
+		// joining the cluster would make this command a member of it
+		return fmt.Errorf("list-nodes requires redis, nodes of an embedded cluster are logged as they join")
+	}
 
 	currentNode, err := routing.NewLocalNode(conf)
 	if err != nil {
@@ -144,6 +144,27 @@ func main() {
 				Usage:  "generates an API key and secret pair",
 				Action: generateKeys,
 			},
+			{
+				Name:   "generate-next-secret",
+				Usage:  "generates the next secret of an API key, printing the key_secrets config to rotate it",
+				Action: generateNextSecret,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "api-key",
+						Usage:    "API key to rotate the secret of",
+						Required: true,
+					},
+					&cli.DurationFlag{
+						Name:  "activate-after",
+						Usage: "delay before the new secret is used for signing",
+					},
+					&cli.DurationFlag{
+						Name:  "overlap",
+						Usage: "how long current secrets stay valid once the new secret is active",
+						Value: 24 * time.Hour,
+					},
+				},
+			},
 			{
 				Name:   "ports",
 				Usage:  "print ports that server is configured to use",
@@ -193,27 +214,42 @@ func main() {
 }
 
 func getConfig(c *cli.Command) (*config.Config, error) {
-	confString, err := getConfigString(c.String("config"), c.String("config-body"))
+	conf, placeholderKeys, err := readConfig(c)
 	if err != nil {
 		return nil, err
 	}
+	config.InitLoggerFromConfig(&conf.Logging)
+
+	if conf.Development {
+		logger.Infow("starting in development mode")
+	}
+	if placeholderKeys {
+		logger.Infow("no keys provided, using placeholder keys",
+			"API Key", "devkey",
+			"API Secret", "secret",
+		)
+	}
+	return conf, nil
+}
+
+// readConfig reads the config without initializing the logger from it,
+// placeholderKeys is true when development keys were filled in
+func readConfig(c *cli.Command) (conf *config.Config, placeholderKeys bool, err error) {
+	confString, err := getConfigString(c.String("config"), c.String("config-body"))
+	if err != nil {
+		return nil, false, err
+	}
 
 	strictMode := !c.Bool("disable-strict-config")
 
-	conf, err := config.NewConfig(confString, strictMode, c, baseFlags)
+	conf, err = config.NewConfig(confString, strictMode, c, baseFlags)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	config.InitLoggerFromConfig(&conf.Logging)
 
 	if conf.Development {
-		logger.Infow("starting in development mode")
-
-		if len(conf.Keys) == 0 {
-			logger.Infow("no keys provided, using placeholder keys",
-				"API Key", "devkey",
-				"API Secret", "secret",
-			)
+		if len(conf.Keys) == 0 && len(conf.KeySecrets) == 0 {
+			placeholderKeys = true
 			conf.Keys = map[string]string{
 				"devkey": "secret",
 			}
@@ -241,6 +277,22 @@ func getConfig(c *cli.Command) (*config.Config, error) {
 			}
 		}
 	}
+	return conf, placeholderKeys, nil
+}
+
+// loadConfig reads the config again for a reload, it is validated the same way as on startup.
+// The logger is initialized from it only once the reload is applied.
+func loadConfig(c *cli.Command) (*config.Config, error) {
+	conf, _, err := readConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if err = conf.ValidateKeys(); err != nil {
+		return nil, err
+	}
+	if err = conf.LoadTURNSecrets(); err != nil {
+		return nil, err
+	}
 	return conf, nil
 }
 
@@ -305,6 +357,23 @@ func startServer(ctx context.Context, c *cli.Command) error {
 		return err
 	}
 
+	// config given inline cannot change, reloading only makes sense when it is read from a file
+	if configFile := c.String("config"); configFile != "" && c.String("config-body") == "" {
+		server.ConfigReloader().SetLoader(func() (*config.Config, error) {
+			return loadConfig(c)
+		}, configFile)
+	}
+
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+
+	go func() {
+		for range reloadChan {
+			logger.Infow("config reload requested")
+			_, _ = server.ConfigReloader().Reload()
+		}
+	}()
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
@@ -0,0 +1,367 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certmanager
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/acme"
+)
+
+func TestManager_ACME(t *testing.T) {
+	ca := newACMEStandIn(t)
+
+	m, err := New(Params{
+		ACME: &ACMEParams{
+			Domains:      []string{"livekit.test"},
+			DirectoryURL: ca.server.URL + "/directory",
+			CAFile:       ca.writeServerCA(t),
+			CacheDir:     t.TempDir(),
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, m.Enabled())
+	require.Contains(t, m.TLSConfig("http/1.1").NextProtos, acme.ALPNProto)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", m.TLSConfig("http/1.1"))
+	require.NoError(t, err)
+	defer ln.Close()
+	go acceptHandshakes(ln)
+	ca.setValidationAddr(ln.Addr().String())
+
+	// the certificate is obtained during the first handshake
+	conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{
+		ServerName: "livekit.test",
+		RootCAs:    ca.rootPool(),
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Equal(t, []string{"livekit.test"}, conn.ConnectionState().PeerCertificates[0].DNSNames)
+	require.Equal(t, 1, ca.validations())
+
+	t.Run("domains outside of the policy are refused", func(t *testing.T) {
+		_, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{
+			ServerName: "other.test",
+			RootCAs:    ca.rootPool(),
+		})
+		require.Error(t, err)
+	})
+}
+
+// acmeStandIn implements the subset of RFC 8555 used to obtain a certificate with a TLS-ALPN-01 challenge
+type acmeStandIn struct {
+	server *httptest.Server
+
+	caKey  *ecdsa.PrivateKey
+	caCert *x509.Certificate
+
+	lock           sync.Mutex
+	nonce          int
+	validationAddr string
+	numValidations int
+	orders         map[string]*standInOrder
+}
+
+type standInOrder struct {
+	domain string
+	status string
+	der    []byte
+}
+
+func newACMEStandIn(t *testing.T) *acmeStandIn {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ACME stand-in CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	s := &acmeStandIn{
+		caKey:  caKey,
+		caCert: caCert,
+		orders: make(map[string]*standInOrder),
+	}
+	s.server = httptest.NewTLSServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *acmeStandIn) writeServerCA(t *testing.T) string {
+	file := filepath.Join(t.TempDir(), "acme-ca.pem")
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.server.Certificate().Raw}), 0600))
+	return file
+}
+
+func (s *acmeStandIn) rootPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.caCert)
+	return pool
+}
+
+func (s *acmeStandIn) setValidationAddr(addr string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.validationAddr = addr
+}
+
+func (s *acmeStandIn) validations() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.numValidations
+}
+
+func (s *acmeStandIn) handle(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	s.nonce++
+	w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", s.nonce))
+	s.lock.Unlock()
+
+	url := s.server.URL
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch path[0] {
+	case "directory":
+		writeJSON(w, http.StatusOK, map[string]string{
+			"newNonce":   url + "/nonce",
+			"newAccount": url + "/account",
+			"newOrder":   url + "/order",
+			"revokeCert": url + "/revoke",
+			"keyChange":  url + "/key-change",
+		})
+
+	case "nonce":
+		w.WriteHeader(http.StatusOK)
+
+	case "account":
+		w.Header().Set("Location", url+"/account/1")
+		writeJSON(w, http.StatusCreated, map[string]any{"status": acme.StatusValid})
+
+	case "order":
+		if len(path) == 1 {
+			var req struct {
+				Identifiers []struct{ Value string }
+			}
+			if !readJWSPayload(w, r, &req) {
+				return
+			}
+			s.lock.Lock()
+			id := fmt.Sprint(len(s.orders) + 1)
+			order := &standInOrder{domain: req.Identifiers[0].Value, status: acme.StatusPending}
+			s.orders[id] = order
+			s.lock.Unlock()
+			w.Header().Set("Location", url+"/order/"+id)
+			writeJSON(w, http.StatusCreated, s.orderJSON(id, order))
+			return
+		}
+		s.withOrder(w, path[1], func(order *standInOrder) {
+			w.Header().Set("Location", url+"/order/"+path[1])
+			writeJSON(w, http.StatusOK, s.orderJSON(path[1], order))
+		})
+
+	case "authz":
+		s.withOrder(w, path[1], func(order *standInOrder) {
+			writeJSON(w, http.StatusOK, s.authzJSON(path[1], order))
+		})
+
+	case "challenge":
+		var order *standInOrder
+		s.withOrder(w, path[1], func(o *standInOrder) { order = o })
+		if order == nil {
+			return
+		}
+		status := acme.StatusValid
+		if err := s.validate(order.domain); err != nil {
+			status = acme.StatusInvalid
+		}
+		s.lock.Lock()
+		order.status = map[string]string{acme.StatusValid: acme.StatusReady, acme.StatusInvalid: acme.StatusInvalid}[status]
+		s.lock.Unlock()
+		writeJSON(w, http.StatusOK, s.challengeJSON(path[1], status))
+
+	case "finalize":
+		var req struct{ CSR string }
+		if !readJWSPayload(w, r, &req) {
+			return
+		}
+		der, err := s.sign(req.CSR)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.withOrder(w, path[1], func(order *standInOrder) {
+			order.status = acme.StatusValid
+			order.der = der
+			w.Header().Set("Location", url+"/order/"+path[1])
+			writeJSON(w, http.StatusOK, s.orderJSON(path[1], order))
+		})
+
+	case "cert":
+		s.withOrder(w, path[1], func(order *standInOrder) {
+			w.Header().Set("Content-Type", "application/pem-certificate-chain")
+			_ = pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: order.der})
+			_ = pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: s.caCert.Raw})
+		})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *acmeStandIn) withOrder(w http.ResponseWriter, id string, f func(order *standInOrder)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	order, ok := s.orders[id]
+	if !ok {
+		http.NotFound(w, nil)
+		return
+	}
+	f(order)
+}
+
+func (s *acmeStandIn) orderJSON(id string, order *standInOrder) map[string]any {
+	v := map[string]any{
+		"status":         order.status,
+		"identifiers":    []map[string]string{{"type": "dns", "value": order.domain}},
+		"authorizations": []string{s.server.URL + "/authz/" + id},
+		"finalize":       s.server.URL + "/finalize/" + id,
+	}
+	if order.der != nil {
+		v["certificate"] = s.server.URL + "/cert/" + id
+	}
+	return v
+}
+
+func (s *acmeStandIn) authzJSON(id string, order *standInOrder) map[string]any {
+	status := acme.StatusPending
+	challengeStatus := acme.StatusPending
+	switch order.status {
+	case acme.StatusReady, acme.StatusValid:
+		status, challengeStatus = acme.StatusValid, acme.StatusValid
+	case acme.StatusInvalid:
+		status, challengeStatus = acme.StatusInvalid, acme.StatusInvalid
+	}
+	return map[string]any{
+		"status":     status,
+		"identifier": map[string]string{"type": "dns", "value": order.domain},
+		"challenges": []map[string]string{s.challengeJSON(id, challengeStatus)},
+	}
+}
+
+func (s *acmeStandIn) challengeJSON(id string, status string) map[string]string {
+	return map[string]string{
+		"type":   "tls-alpn-01",
+		"url":    s.server.URL + "/challenge/" + id,
+		"token":  "token-" + id,
+		"status": status,
+	}
+}
+
+// validate connects to the server negotiating the ACME ALPN protocol, as a CA validating a TLS-ALPN-01 challenge
+func (s *acmeStandIn) validate(domain string) error {
+	s.lock.Lock()
+	addr := s.validationAddr
+	s.numValidations++
+	s.lock.Unlock()
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{
+		ServerName:         domain,
+		NextProtos:         []string{acme.ALPNProto},
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if state.NegotiatedProtocol != acme.ALPNProto || !slices.Contains(state.PeerCertificates[0].DNSNames, domain) {
+		return fmt.Errorf("invalid challenge response for %s", domain)
+	}
+	return nil
+}
+
+func (s *acmeStandIn) sign(encodedCSR string) ([]byte, error) {
+	der, err := base64.RawURLEncoding.DecodeString(encodedCSR)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: csr.DNSNames[0]},
+		DNSNames:     csr.DNSNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return x509.CreateCertificate(rand.Reader, template, s.caCert, csr.PublicKey, s.caKey)
+}
+
+// readJWSPayload decodes the payload of a JWS request, signatures are not verified
+func readJWSPayload(w http.ResponseWriter, r *http.Request, v any) bool {
+	var jws struct{ Payload string }
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err == nil {
+		err = json.Unmarshal(payload, v)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
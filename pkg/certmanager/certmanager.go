@@ -0,0 +1,342 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package certmanager keeps the TLS certificates served by the node up to date. Certificates are loaded
+// from files, reloaded when the files change, or obtained and renewed from an ACME server. A reloaded
+// certificate is used by new handshakes, established connections are not affected.
+package certmanager
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/livekit/protocol/logger"
+)
+
+const DefaultWatchInterval = 10 * time.Second
+
+var (
+	ErrNoCertificate      = errors.New("no certificate configured")
+	ErrCertificateMissing = errors.New("both certificate and key files are required")
+)
+
+// Certificate provides the current certificate to TLS handshakes
+type Certificate interface {
+	GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error)
+}
+
+type ACMEParams struct {
+	Domains []string
+	Email   string
+	// directory of the ACME server, Let's Encrypt when empty
+	DirectoryURL string
+	// CA certificates trusted for the ACME server, system roots when empty
+	CAFile string
+	// directory storing the account key and certificates, kept in memory when empty
+	CacheDir string
+}
+
+type Params struct {
+	// certificate of the node, loaded from files
+	CertFile string
+	KeyFile  string
+	// certificate of the node obtained from an ACME server, exclusive with the certificate files
+	ACME *ACMEParams
+	// client certificates signed by these CAs are verified when presented
+	ClientCAFile string
+	// how often certificate files are checked for changes
+	WatchInterval time.Duration
+	Logger        logger.Logger
+}
+
+// Manager provides the certificate of the node, and any additional certificate loaded from files
+type Manager struct {
+	params Params
+
+	server    Certificate
+	acme      *autocert.Manager
+	clientCAs *x509.CertPool
+
+	lock  sync.Mutex
+	files map[filePair]*fileCertificate
+
+	closed chan struct{}
+	once   sync.Once
+}
+
+func New(params Params) (*Manager, error) {
+	if params.WatchInterval == 0 {
+		params.WatchInterval = DefaultWatchInterval
+	}
+	if params.Logger == nil {
+		params.Logger = logger.GetLogger()
+	}
+
+	m := &Manager{
+		params: params,
+		files:  make(map[filePair]*fileCertificate),
+		closed: make(chan struct{}),
+	}
+
+	switch {
+	case params.ACME != nil:
+		if params.CertFile != "" {
+			return nil, errors.New("certificate files and ACME are exclusive")
+		}
+		acmeManager, err := newACMEManager(params.ACME)
+		if err != nil {
+			return nil, err
+		}
+		m.acme = acmeManager
+		m.server = acmeManager
+
+	case params.CertFile != "" || params.KeyFile != "":
+		cert, err := m.LoadFiles(params.CertFile, params.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		m.server = cert
+	}
+
+	if params.ClientCAFile != "" {
+		pool, err := loadCertPool(params.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		m.clientCAs = pool
+	}
+	return m, nil
+}
+
+// Enabled returns true when the node has a certificate
+func (m *Manager) Enabled() bool {
+	return m.server != nil
+}
+
+// VerifiesClientCertificates returns true when presented client certificates are verified
+func (m *Manager) VerifiesClientCertificates() bool {
+	return m.clientCAs != nil
+}
+
+// GetCertificate returns the certificate of the node
+func (m *Manager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if m.server == nil {
+		return nil, ErrNoCertificate
+	}
+	return m.server.GetCertificate(hello)
+}
+
+// TLSConfig returns the server configuration serving the certificate of the node. Client certificates are
+// requested but not required, they are checked per request by the services requiring them.
+func (m *Manager) TLSConfig(nextProtos ...string) *tls.Config {
+	return m.TLSConfigWithCertificate(m, nextProtos...)
+}
+
+// TLSConfigWithCertificate returns the server configuration of TLSConfig, serving another certificate
+func (m *Manager) TLSConfigWithCertificate(cert Certificate, nextProtos ...string) *tls.Config {
+	conf := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: cert.GetCertificate,
+		NextProtos:     nextProtos,
+	}
+	if m.acme != nil && cert == Certificate(m) {
+		// answers TLS-ALPN-01 challenges
+		conf.NextProtos = append(slices.Clone(nextProtos), acme.ALPNProto)
+	}
+	if m.clientCAs != nil {
+		conf.ClientCAs = m.clientCAs
+		conf.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return conf
+}
+
+// HTTPHandler answers HTTP-01 challenges when ACME is used, other requests are handed to fallback
+func (m *Manager) HTTPHandler(fallback http.Handler) http.Handler {
+	if m.acme == nil {
+		return fallback
+	}
+	return m.acme.HTTPHandler(fallback)
+}
+
+// LoadFiles returns the certificate of the files, reloaded when they change.
+// Certificates are shared by all users of the same files.
+func (m *Manager) LoadFiles(certFile, keyFile string) (Certificate, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, ErrCertificateMissing
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	pair := filePair{certFile: certFile, keyFile: keyFile}
+	if cert, ok := m.files[pair]; ok {
+		return cert, nil
+	}
+
+	cert := &fileCertificate{filePair: pair}
+	if _, err := cert.reload(); err != nil {
+		return nil, err
+	}
+	m.files[pair] = cert
+	return cert, nil
+}
+
+// Start watches certificate files for changes
+func (m *Manager) Start() {
+	go m.watchWorker()
+}
+
+func (m *Manager) Stop() {
+	m.once.Do(func() {
+		close(m.closed)
+	})
+}
+
+// Reload reloads the certificates of files changed since they were loaded
+func (m *Manager) Reload() {
+	m.lock.Lock()
+	certs := make([]*fileCertificate, 0, len(m.files))
+	for _, cert := range m.files {
+		certs = append(certs, cert)
+	}
+	m.lock.Unlock()
+
+	for _, cert := range certs {
+		reloaded, err := cert.reload()
+		if err != nil {
+			m.params.Logger.Warnw("could not reload certificate, keeping the previous one", err, "certFile", cert.certFile)
+		} else if reloaded {
+			m.params.Logger.Infow("certificate reloaded", "certFile", cert.certFile)
+		}
+	}
+}
+
+func (m *Manager) watchWorker() {
+	ticker := time.NewTicker(m.params.WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.closed:
+			return
+		case <-ticker.C:
+			m.Reload()
+		}
+	}
+}
+
+// ------------------------------------------
+
+type filePair struct {
+	certFile string
+	keyFile  string
+}
+
+type fileCertificate struct {
+	filePair
+
+	lock        sync.Mutex
+	certModTime time.Time
+	keyModTime  time.Time
+
+	cert atomic.Pointer[tls.Certificate]
+}
+
+func (c *fileCertificate) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.cert.Load(), nil
+}
+
+// reload loads the files when either changed since the last load
+func (c *fileCertificate) reload() (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	certInfo, err := os.Stat(c.certFile)
+	if err != nil {
+		return false, err
+	}
+	keyInfo, err := os.Stat(c.keyFile)
+	if err != nil {
+		return false, err
+	}
+	if c.cert.Load() != nil && certInfo.ModTime().Equal(c.certModTime) && keyInfo.ModTime().Equal(c.keyModTime) {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("could not load certificate %s: %w", c.certFile, err)
+	}
+	c.cert.Store(&cert)
+	c.certModTime = certInfo.ModTime()
+	c.keyModTime = keyInfo.ModTime()
+	return true, nil
+}
+
+// ------------------------------------------
+
+func newACMEManager(params *ACMEParams) (*autocert.Manager, error) {
+	if len(params.Domains) == 0 {
+		return nil, errors.New("ACME requires at least one domain")
+	}
+
+	client := &acme.Client{
+		DirectoryURL: params.DirectoryURL,
+	}
+	if params.CAFile != "" {
+		pool, err := loadCertPool(params.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		client.HTTPClient = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: pool},
+			},
+		}
+	}
+
+	acmeManager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(params.Domains...),
+		Email:      params.Email,
+		Client:     client,
+	}
+	if params.CacheDir != "" {
+		acmeManager.Cache = autocert.DirCache(params.CacheDir)
+	}
+	return acmeManager, nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in %s", file)
+	}
+	return pool, nil
+}
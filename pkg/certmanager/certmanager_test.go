@@ -0,0 +1,194 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certmanager
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_FileReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeCertificate(t, certFile, keyFile, "first.livekit.test")
+
+	m, err := New(Params{CertFile: certFile, KeyFile: keyFile})
+	require.NoError(t, err)
+	require.True(t, m.Enabled())
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", m.TLSConfig())
+	require.NoError(t, err)
+	defer ln.Close()
+	go acceptHandshakes(ln)
+
+	// established connections are kept across reloads
+	established := dialCommonName(t, ln.Addr().String())
+	require.Equal(t, "first.livekit.test", established.ConnectionState().PeerCertificates[0].Subject.CommonName)
+	defer established.Close()
+
+	writeCertificate(t, certFile, keyFile, "second.livekit.test")
+	// modification times may not change within the resolution of the file system
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	m.Reload()
+
+	conn := dialCommonName(t, ln.Addr().String())
+	defer conn.Close()
+	require.Equal(t, "second.livekit.test", conn.ConnectionState().PeerCertificates[0].Subject.CommonName)
+
+	_, err = established.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	t.Run("invalid files keep the previous certificate", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0600))
+		future = future.Add(time.Minute)
+		require.NoError(t, os.Chtimes(keyFile, future, future))
+		m.Reload()
+
+		conn := dialCommonName(t, ln.Addr().String())
+		defer conn.Close()
+		require.Equal(t, "second.livekit.test", conn.ConnectionState().PeerCertificates[0].Subject.CommonName)
+	})
+
+	t.Run("files are shared", func(t *testing.T) {
+		cert, err := m.LoadFiles(certFile, keyFile)
+		require.NoError(t, err)
+		require.Same(t, m.server, cert)
+	})
+}
+
+func TestManager_ClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeCertificate(t, certFile, keyFile, "livekit.test")
+
+	clientCert := newCertificate(t, "client")
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientCert.Certificate[0]}), 0600))
+
+	m, err := New(Params{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile})
+	require.NoError(t, err)
+	require.True(t, m.VerifiesClientCertificates())
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", m.TLSConfig())
+	require.NoError(t, err)
+	defer ln.Close()
+
+	verified := make(chan int, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := conn.(*tls.Conn)
+			if tlsConn.Handshake() == nil {
+				verified <- len(tlsConn.ConnectionState().VerifiedChains)
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	// client certificates are optional at the TLS level
+	conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, err)
+	_ = conn.Close()
+	require.Equal(t, 0, <-verified)
+
+	conn, err = tls.Dial("tcp", ln.Addr().String(), &tls.Config{InsecureSkipVerify: true, Certificates: []tls.Certificate{clientCert}})
+	require.NoError(t, err)
+	_ = conn.Close()
+	require.Equal(t, 1, <-verified)
+}
+
+func TestManager_Errors(t *testing.T) {
+	_, err := New(Params{CertFile: "cert.pem"})
+	require.ErrorIs(t, err, ErrCertificateMissing)
+
+	_, err = New(Params{CertFile: "cert.pem", KeyFile: "key.pem", ACME: &ACMEParams{Domains: []string{"livekit.test"}}})
+	require.Error(t, err)
+
+	_, err = New(Params{ACME: &ACMEParams{}})
+	require.Error(t, err)
+
+	m, err := New(Params{})
+	require.NoError(t, err)
+	require.False(t, m.Enabled())
+	_, err = m.GetCertificate(&tls.ClientHelloInfo{})
+	require.ErrorIs(t, err, ErrNoCertificate)
+}
+
+func acceptHandshakes(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			_ = conn.(*tls.Conn).Handshake()
+			_, _ = conn.Read(make([]byte, 4))
+		}()
+	}
+}
+
+func dialCommonName(t *testing.T, addr string) *tls.Conn {
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, err)
+	return conn
+}
+
+func newCertificate(t *testing.T, commonName string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              []string{commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func writeCertificate(t *testing.T, certFile, keyFile, commonName string) {
+	cert := newCertificate(t, commonName)
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
@@ -0,0 +1,466 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"maps"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/psrpc"
+)
+
+const (
+	busPeerQueueSize = 1024
+	// full queue subscription updates are resent to every peer on this interval
+	queueSyncInterval = 10 * time.Second
+)
+
+// messageBus delivers publications to the subscribers of every alive node. Subscriptions are served by a
+// local bus on each node, publications are delivered locally and streamed to the other nodes which publish
+// them on their local bus. Queue subscriptions are served by a separate local bus and every node streams
+// changes of its queue channels to the others, the publishing node picks a single node among those with
+// subscribers so that a queue message is handled once across the cluster.
+type messageBus struct {
+	psrpc.MessageBus
+	subs   psrpc.MessageBus
+	queues psrpc.MessageBus
+
+	nodeID    string
+	transport *transport
+	logger    logger.Logger
+
+	lock       sync.RWMutex
+	peers      map[string]*busPeer
+	members    []Member
+	nodeQueues map[string]map[string]struct{}
+
+	queueLock sync.Mutex
+	queueSubs map[string]int
+}
+
+func newMessageBus(nodeID string, t *transport, logger logger.Logger) *messageBus {
+	b := &messageBus{
+		subs:       psrpc.NewLocalMessageBus(),
+		queues:     psrpc.NewLocalMessageBus(),
+		nodeID:     nodeID,
+		transport:  t,
+		logger:     logger,
+		peers:      make(map[string]*busPeer),
+		nodeQueues: make(map[string]map[string]struct{}),
+		queueSubs:  make(map[string]int),
+	}
+	b.MessageBus = newSplitBus(b.subs, b.subs.Subscribe, trackSubscribe(b.queues.SubscribeQueue, b.trackQueue))
+	return b
+}
+
+func (b *messageBus) Publish(ctx context.Context, channel psrpc.Channel, msg proto.Message) error {
+	if err := b.subs.Publish(ctx, channel, msg); err != nil {
+		return err
+	}
+
+	queueNode := b.queueNode(channel.Legacy)
+	if queueNode == "" {
+		// no node is known to have queue subscribers yet, keep the message on this node
+		queueNode = b.nodeID
+	}
+	if queueNode == b.nodeID {
+		if err := b.queues.Publish(ctx, channel, msg); err != nil {
+			return err
+		}
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	if len(b.peers) == 0 {
+		return nil
+	}
+
+	a, err := anypb.New(msg)
+	if err != nil {
+		return err
+	}
+	data, err := proto.Marshal(a)
+	if err != nil {
+		return err
+	}
+	m := &busMessage{Channel: channel.Legacy, Message: data, QueueNode: queueNode}
+	for _, p := range b.peers {
+		p.send(m)
+	}
+	return nil
+}
+
+// queueNode picks the node delivering a publication to queue subscribers, by rendezvous hashing the channel
+// over the nodes with subscribers. It returns an empty string when no node is known to have any.
+func (b *messageBus) queueNode(channel string) string {
+	var (
+		node string
+		best uint64
+	)
+	consider := func(id string) {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(channel))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(id))
+		if score := h.Sum64(); node == "" || score > best {
+			node, best = id, score
+		}
+	}
+
+	b.queueLock.Lock()
+	if b.queueSubs[channel] > 0 {
+		consider(b.nodeID)
+	}
+	b.queueLock.Unlock()
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	for _, m := range b.members {
+		if _, ok := b.nodeQueues[m.ID][channel]; ok {
+			consider(m.ID)
+		}
+	}
+	return node
+}
+
+// trackQueue counts a local queue subscription of a channel, until the returned release is called
+func (b *messageBus) trackQueue(channel string) func() {
+	b.updateQueue(channel, 1)
+	return func() {
+		b.updateQueue(channel, -1)
+	}
+}
+
+func (b *messageBus) updateQueue(channel string, delta int) {
+	b.queueLock.Lock()
+	defer b.queueLock.Unlock()
+
+	count := b.queueSubs[channel] + delta
+	if count > 0 {
+		b.queueSubs[channel] = count
+	} else {
+		delete(b.queueSubs, channel)
+	}
+
+	var update *queueUpdate
+	switch {
+	case count == 1 && delta > 0:
+		update = &queueUpdate{NodeID: b.nodeID, Added: []string{channel}}
+	case count == 0:
+		update = &queueUpdate{NodeID: b.nodeID, Removed: []string{channel}}
+	default:
+		return
+	}
+
+	// sent while holding the queue lock, so that peers get updates in the order of the changes
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	for _, p := range b.peers {
+		p.send(&busMessage{Queues: update})
+	}
+}
+
+// localQueues returns every channel with local queue subscribers
+func (b *messageBus) localQueues() *queueUpdate {
+	b.queueLock.Lock()
+	defer b.queueLock.Unlock()
+
+	return &queueUpdate{
+		NodeID: b.nodeID,
+		Full:   true,
+		Added:  slices.Sorted(maps.Keys(b.queueSubs)),
+	}
+}
+
+// handleQueueUpdate applies the queue subscription changes of another node
+func (b *messageBus) handleQueueUpdate(u *queueUpdate) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	queues := b.nodeQueues[u.NodeID]
+	if queues == nil || u.Full {
+		queues = make(map[string]struct{}, len(u.Added))
+		b.nodeQueues[u.NodeID] = queues
+	}
+	for _, c := range u.Added {
+		queues[c] = struct{}{}
+	}
+	for _, c := range u.Removed {
+		delete(queues, c)
+	}
+}
+
+// handleMessage publishes a message received from another node to the local subscribers
+func (b *messageBus) handleMessage(m *busMessage) {
+	if m.Queues != nil {
+		b.handleQueueUpdate(m.Queues)
+		return
+	}
+
+	a := &anypb.Any{}
+	if err := proto.Unmarshal(m.Message, a); err != nil {
+		b.logger.Warnw("could not decode cluster bus message", err, "channel", m.Channel)
+		return
+	}
+	msg, err := a.UnmarshalNew()
+	if err != nil {
+		b.logger.Warnw("could not decode cluster bus message", err, "channel", m.Channel, "type", a.TypeUrl)
+		return
+	}
+	// the local bus routes by the legacy channel name only
+	channel := psrpc.Channel{Legacy: m.Channel, Server: m.Channel, Local: m.Channel}
+	if err := b.subs.Publish(context.Background(), channel, msg); err != nil {
+		b.logger.Warnw("could not publish cluster bus message", err, "channel", m.Channel)
+	}
+	if m.QueueNode == b.nodeID {
+		if err := b.queues.Publish(context.Background(), channel, msg); err != nil {
+			b.logger.Warnw("could not publish cluster bus message", err, "channel", m.Channel)
+		}
+	}
+}
+
+// setPeers updates the nodes publications are streamed to
+func (b *messageBus) setPeers(members []Member) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	current := make(map[string]bool, len(members))
+	ids := make(map[string]bool, len(members))
+	for _, m := range members {
+		current[m.Addr] = true
+		ids[m.ID] = true
+		if b.peers[m.Addr] == nil {
+			p := &busPeer{
+				address:   m.Addr,
+				transport: b.transport,
+				logger:    b.logger,
+				queues:    b.localQueues,
+				queue:     make(chan *busMessage, busPeerQueueSize),
+				wake:      make(chan struct{}, 1),
+				done:      make(chan struct{}),
+			}
+			b.peers[m.Addr] = p
+			go p.worker()
+		}
+	}
+	for addr, p := range b.peers {
+		if !current[addr] {
+			p.close()
+			delete(b.peers, addr)
+		}
+	}
+	// nodes which are not members yet may already have sent their queues
+	for _, m := range b.members {
+		if !ids[m.ID] {
+			delete(b.nodeQueues, m.ID)
+		}
+	}
+	b.members = members
+}
+
+func (b *messageBus) close() {
+	b.setPeers(nil)
+}
+
+type subscribeFunc[R any] func(ctx context.Context, channel psrpc.Channel, channelSize int) (R, error)
+
+// splitBus serves subscriptions and queue subscriptions from different buses. psrpc does not export its
+// reader type, it is inferred from the subscribe functions.
+type splitBus[R any] struct {
+	psrpc.MessageBus
+	subscribe      subscribeFunc[R]
+	subscribeQueue subscribeFunc[R]
+}
+
+func newSplitBus[R any](publisher psrpc.MessageBus, subscribe, subscribeQueue subscribeFunc[R]) *splitBus[R] {
+	return &splitBus[R]{
+		MessageBus:     publisher,
+		subscribe:      subscribe,
+		subscribeQueue: subscribeQueue,
+	}
+}
+
+func (s *splitBus[R]) Subscribe(ctx context.Context, channel psrpc.Channel, channelSize int) (R, error) {
+	return s.subscribe(ctx, channel, channelSize)
+}
+
+func (s *splitBus[R]) SubscribeQueue(ctx context.Context, channel psrpc.Channel, channelSize int) (R, error) {
+	return s.subscribeQueue(ctx, channel, channelSize)
+}
+
+// trackSubscribe calls track for every subscription and the release it returns once the reader is closed
+func trackSubscribe[R any](subscribe subscribeFunc[R], track func(channel string) func()) subscribeFunc[R] {
+	return func(ctx context.Context, channel psrpc.Channel, channelSize int) (R, error) {
+		release := sync.OnceFunc(track(channel.Legacy))
+		r, err := subscribe(newReleaseContext(ctx, release), channel, channelSize)
+		if err != nil {
+			release()
+		}
+		return r, err
+	}
+}
+
+// releaseContext calls release when a context derived from it with context.WithCancel is canceled. The
+// local bus derives such a context for every subscription and cancels it when the reader is closed, the
+// context package then stops the callback registered through AfterFunc.
+type releaseContext struct {
+	context.Context
+	done    chan struct{}
+	release func()
+}
+
+func newReleaseContext(parent context.Context, release func()) *releaseContext {
+	return &releaseContext{
+		Context: parent,
+		done:    make(chan struct{}),
+		release: release,
+	}
+}
+
+// Done is closed by the callback registered through AfterFunc. Returning a channel of its own keeps the
+// context package from attaching derived contexts to a cancelable parent directly.
+func (c *releaseContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *releaseContext) AfterFunc(f func()) func() bool {
+	var closeOnce sync.Once
+	stop := context.AfterFunc(c.Context, func() {
+		closeOnce.Do(func() { close(c.done) })
+		f()
+	})
+	return func() bool {
+		c.release()
+		return stop()
+	}
+}
+
+// busPeer streams publications to one node in order, dropping them while the node cannot be reached. The
+// queue channels of this node are sent in full on every new connection, after an update was dropped and
+// periodically, and as changes in between.
+type busPeer struct {
+	address   string
+	transport *transport
+	logger    logger.Logger
+	queues    func() *queueUpdate
+	queue     chan *busMessage
+	wake      chan struct{}
+	resync    atomic.Bool
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (p *busPeer) send(m *busMessage) {
+	select {
+	case p.queue <- m:
+	case <-p.done:
+	default:
+		if m.Queues != nil {
+			p.requestResync()
+			return
+		}
+		p.logger.Warnw("cluster bus queue full, dropping message", nil, "peer", p.address, "channel", m.Channel)
+	}
+}
+
+func (p *busPeer) requestResync() {
+	p.resync.Store(true)
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *busPeer) close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+}
+
+func (p *busPeer) worker() {
+	var (
+		conn net.Conn
+		w    *bufio.Writer
+		enc  *json.Encoder
+	)
+	defer func() {
+		if conn != nil {
+			_ = conn.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(queueSyncInterval)
+	defer ticker.Stop()
+
+	// connect right away to send the queue channels of this node
+	p.wake <- struct{}{}
+	for {
+		var m *busMessage
+		select {
+		case <-p.done:
+			return
+		case m = <-p.queue:
+		case <-p.wake:
+		case <-ticker.C:
+			p.resync.Store(true)
+		}
+
+		if conn == nil {
+			c, err := p.transport.dial(p.address, connTypeBus, dialTimeout)
+			if err != nil {
+				p.logger.Debugw("could not connect to cluster peer", "peer", p.address, "error", err)
+				continue
+			}
+			conn = c
+			w = bufio.NewWriter(conn)
+			enc = json.NewEncoder(w)
+			p.resync.Store(true)
+		}
+
+		var err error
+		_ = conn.SetWriteDeadline(time.Now().Add(dialTimeout))
+		if m != nil {
+			err = enc.Encode(m)
+		}
+		// batch whatever is queued before flushing
+		for err == nil && len(p.queue) > 0 {
+			err = enc.Encode(<-p.queue)
+		}
+		// the full update goes after the queued messages, which may be older than it
+		if err == nil && p.resync.Swap(false) {
+			err = enc.Encode(&busMessage{Queues: p.queues()})
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			p.logger.Debugw("could not send to cluster peer", "peer", p.address, "error", err)
+			_ = conn.Close()
+			conn = nil
+		}
+	}
+}
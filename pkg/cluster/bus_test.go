@@ -0,0 +1,124 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/psrpc"
+)
+
+func newTestBus(t *testing.T, nodeID string) *messageBus {
+	var b *messageBus
+	tr, err := newTransport(transportParams{
+		BindAddress: "127.0.0.1:0",
+		Secret:      "secret",
+		OnBusMessage: func(m *busMessage) {
+			b.handleMessage(m)
+		},
+		Logger: logger.GetLogger(),
+	})
+	require.NoError(t, err)
+	t.Cleanup(tr.Close)
+
+	b = newMessageBus(nodeID, tr, logger.GetLogger())
+	t.Cleanup(b.close)
+	return b
+}
+
+func TestMessageBusQueueSubscriptions(t *testing.T) {
+	channel := psrpc.Channel{Legacy: "work", Server: "work", Local: "work"}
+
+	t.Run("counts subscriptions until their reader is closed", func(t *testing.T) {
+		b := newMessageBus("a", nil, logger.GetLogger())
+
+		first, err := b.SubscribeQueue(context.Background(), channel, 1)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		second, err := b.SubscribeQueue(ctx, channel, 1)
+		require.NoError(t, err)
+		require.Equal(t, "a", b.queueNode(channel.Legacy))
+
+		// canceling the context does not close the reader
+		cancel()
+		require.Equal(t, 2, b.queueSubs[channel.Legacy])
+
+		require.NoError(t, second.Close())
+		require.Equal(t, 1, b.queueSubs[channel.Legacy])
+		require.NoError(t, first.Close())
+		require.Empty(t, b.queueSubs)
+		require.Empty(t, b.queueNode(channel.Legacy))
+	})
+
+	t.Run("streams queue channels to other nodes", func(t *testing.T) {
+		a := newTestBus(t, "a")
+		b := newTestBus(t, "b")
+		a.setPeers([]Member{{ID: "b", Addr: b.transport.Addr().String()}})
+		b.setPeers([]Member{{ID: "a", Addr: a.transport.Addr().String()}})
+
+		r, err := a.SubscribeQueue(context.Background(), channel, 1)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return b.queueNode(channel.Legacy) == "a"
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, r.Close())
+		require.Eventually(t, func() bool {
+			return b.queueNode(channel.Legacy) == ""
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// a node joining later gets the channels in full
+		r, err = a.SubscribeQueue(context.Background(), channel, 1)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close() })
+		c := newTestBus(t, "c")
+		c.setPeers([]Member{{ID: "a", Addr: a.transport.Addr().String()}})
+		a.setPeers([]Member{
+			{ID: "b", Addr: b.transport.Addr().String()},
+			{ID: "c", Addr: c.transport.Addr().String()},
+		})
+		require.Eventually(t, func() bool {
+			return c.queueNode(channel.Legacy) == "a"
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// queues of departed nodes are forgotten
+		b.setPeers(nil)
+		require.Empty(t, b.nodeQueues)
+	})
+
+	t.Run("keeps messages on the publishing node while no node has subscribers", func(t *testing.T) {
+		b := newMessageBus("a", nil, logger.GetLogger())
+		peer := &busPeer{queue: make(chan *busMessage, 1), done: make(chan struct{})}
+		b.peers["b"] = peer
+		b.members = []Member{{ID: "b"}}
+
+		b.handleQueueUpdate(&queueUpdate{NodeID: "b", Added: []string{"other"}})
+		require.NoError(t, b.Publish(context.Background(), channel, wrapperspb.String("ping")))
+		require.Equal(t, "a", (<-peer.queue).QueueNode)
+
+		b.handleQueueUpdate(&queueUpdate{NodeID: "b", Full: true, Added: []string{channel.Legacy}})
+		require.NoError(t, b.Publish(context.Background(), channel, wrapperspb.String("ping")))
+		require.Equal(t, "b", (<-peer.queue).QueueNode)
+
+		b.handleQueueUpdate(&queueUpdate{NodeID: "b", Removed: []string{channel.Legacy}})
+		require.Empty(t, b.queueNode(channel.Legacy))
+	})
+}
@@ -0,0 +1,385 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cluster lets nodes form a cluster without external services. Nodes find each other through
+// gossip, replicate a small key value store with raft, and stream message bus publications to each other.
+// It is meant for small deployments of a few nodes on a private network.
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/psrpc"
+)
+
+const (
+	DefaultReapTimeout = 30 * time.Second
+
+	reconcileInterval = time.Second
+	raftMaxPool       = 3
+	raftTimeout       = 10 * time.Second
+)
+
+var ErrAdvertiseAddressRequired = errors.New("an advertise address is required when binding to all interfaces")
+
+type Params struct {
+	NodeID string
+	// host:port used for TCP and UDP
+	BindAddress string
+	// host:port other nodes reach this node on, defaults to the bind address
+	AdvertiseAddress string
+	// addresses of nodes contacted to join the cluster
+	Seeds []string
+	// number of nodes expected before the cluster is formed, nodes joining later are added to it
+	BootstrapExpect int
+	// shared by every node, required to join
+	Secret          string
+	GossipInterval  time.Duration
+	DeadNodeTimeout time.Duration
+	// how long a node stays dead before it is removed from the raft voters
+	ReapTimeout  time.Duration
+	ApplyTimeout time.Duration
+	Logger       logger.Logger
+}
+
+// Cluster is the membership of this node in the cluster. A node forms the cluster once BootstrapExpect
+// nodes see each other, the member with the lowest ID bootstraps raft and, as the leader, adds the others as
+// voters. Replicas are kept in memory, a restarted node joins as a new member. Losing the majority of voters
+// at once stops writes until the remaining nodes are restarted.
+type Cluster struct {
+	params Params
+
+	transport     *transport
+	membership    *membership
+	raft          *raft.Raft
+	raftTransport *raft.NetworkTransport
+	store         *Store
+	bus           *messageBus
+
+	lock  sync.Mutex
+	voter bool
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+}
+
+func New(params Params) (*Cluster, error) {
+	if params.NodeID == "" {
+		return nil, errors.New("node ID is required")
+	}
+	if params.Logger == nil {
+		params.Logger = logger.GetLogger()
+	}
+	if params.ReapTimeout <= 0 {
+		params.ReapTimeout = DefaultReapTimeout
+	}
+	if params.ApplyTimeout <= 0 {
+		params.ApplyTimeout = DefaultApplyTimeout
+	}
+	if params.AdvertiseAddress == "" {
+		host, _, err := net.SplitHostPort(params.BindAddress)
+		if err != nil {
+			return nil, err
+		}
+		if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+			return nil, ErrAdvertiseAddressRequired
+		}
+		params.AdvertiseAddress = params.BindAddress
+	}
+
+	c := &Cluster{
+		params: params,
+		closed: make(chan struct{}),
+	}
+
+	var err error
+	c.transport, err = newTransport(transportParams{
+		BindAddress:      params.BindAddress,
+		AdvertiseAddress: params.AdvertiseAddress,
+		Secret:           params.Secret,
+		Logger:           params.Logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.bus = newMessageBus(params.NodeID, c.transport, params.Logger)
+
+	hcLogger := hclog.New(&hclog.LoggerOptions{
+		Name:   "raft",
+		Level:  hclog.Warn,
+		Output: &logWriter{logger: params.Logger},
+	})
+	c.raftTransport = raft.NewNetworkTransportWithConfig(&raft.NetworkTransportConfig{
+		Stream:  &raftStreamLayer{t: c.transport},
+		MaxPool: raftMaxPool,
+		Timeout: raftTimeout,
+		Logger:  hcLogger,
+	})
+
+	conf := raft.DefaultConfig()
+	conf.LocalID = raft.ServerID(params.NodeID)
+	conf.Logger = hcLogger
+	logs := raft.NewInmemStore()
+	f := newFSM()
+	c.raft, err = raft.NewRaft(conf, f, logs, logs, raft.NewInmemSnapshotStore(), c.raftTransport)
+	if err != nil {
+		_ = c.raftTransport.Close()
+		c.transport.Close()
+		return nil, err
+	}
+	c.store = &Store{
+		raft:         c.raft,
+		fsm:          f,
+		transport:    c.transport,
+		applyTimeout: params.ApplyTimeout,
+		closed:       c.closed,
+	}
+	c.transport.params.OnApply = c.store.handleApply
+	c.transport.params.OnBusMessage = c.bus.handleMessage
+
+	c.membership, err = newMembership(membershipParams{
+		Local: Member{
+			ID:   params.NodeID,
+			Addr: params.AdvertiseAddress,
+		},
+		BindAddress:     params.BindAddress,
+		Seeds:           params.Seeds,
+		Secret:          params.Secret,
+		GossipInterval:  params.GossipInterval,
+		DeadNodeTimeout: params.DeadNodeTimeout,
+		Logger:          params.Logger,
+	})
+	if err != nil {
+		_ = c.raft.Shutdown().Error()
+		_ = c.raftTransport.Close()
+		c.transport.Close()
+		return nil, err
+	}
+
+	c.wg.Add(1)
+	go c.reconcileWorker()
+
+	params.Logger.Infow("cluster started", "nodeID", params.NodeID, "address", params.AdvertiseAddress, "seeds", params.Seeds)
+	return c, nil
+}
+
+func (c *Cluster) LocalID() string {
+	return c.params.NodeID
+}
+
+// Members returns the alive members of the cluster, this node included
+func (c *Cluster) Members() []Member {
+	return c.membership.Members()
+}
+
+// MemberState returns the state of a member known to this node
+func (c *Cluster) MemberState(id string) (MemberState, bool) {
+	state, _, ok := c.membership.State(id)
+	return state, ok
+}
+
+// SetLocalMeta publishes data about this node to the other members
+func (c *Cluster) SetLocalMeta(meta []byte) {
+	c.membership.UpdateLocal(func(m *Member) {
+		m.Meta = meta
+	})
+}
+
+// Leader returns the ID of the raft leader, empty when there is none
+func (c *Cluster) Leader() string {
+	_, id := c.raft.LeaderWithID()
+	return string(id)
+}
+
+func (c *Cluster) Store() *Store {
+	return c.store
+}
+
+func (c *Cluster) MessageBus() psrpc.MessageBus {
+	return c.bus
+}
+
+// Leave removes this node from the cluster, it should be called before Stop on graceful shutdown
+func (c *Cluster) Leave() {
+	if c.raft.State() == raft.Leader {
+		if err := c.raft.RemoveServer(raft.ServerID(c.params.NodeID), 0, 0).Error(); err != nil {
+			c.params.Logger.Warnw("could not leave raft cluster", err)
+		}
+	}
+	c.membership.Leave()
+}
+
+func (c *Cluster) Stop() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.wg.Wait()
+
+		c.bus.close()
+		if err := c.raft.Shutdown().Error(); err != nil {
+			c.params.Logger.Warnw("raft shutdown failed", err)
+		}
+		_ = c.raftTransport.Close()
+		c.membership.Close()
+		c.transport.Close()
+		c.params.Logger.Infow("cluster stopped", "nodeID", c.params.NodeID)
+	})
+}
+
+func (c *Cluster) reconcileWorker() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			c.reconcile()
+		}
+	}
+}
+
+func (c *Cluster) reconcile() {
+	members := c.membership.Members()
+
+	peers := make([]Member, 0, len(members))
+	for _, m := range members {
+		if m.ID != c.params.NodeID {
+			peers = append(peers, m)
+		}
+	}
+	c.bus.setPeers(peers)
+
+	if !c.isVoter() {
+		c.maybeBootstrap(members)
+		return
+	}
+	if c.raft.State() == raft.Leader {
+		c.reconcileVoters(members)
+	}
+}
+
+func (c *Cluster) isVoter() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.voter {
+		// contacted by a leader after being added
+		if leader, _ := c.raft.LeaderWithID(); leader != "" {
+			c.setVoterLocked()
+		}
+	}
+	return c.voter
+}
+
+func (c *Cluster) setVoterLocked() {
+	c.voter = true
+	c.membership.UpdateLocal(func(m *Member) {
+		m.Voter = true
+	})
+}
+
+func (c *Cluster) maybeBootstrap(members []Member) {
+	if c.params.BootstrapExpect <= 0 || len(members) < c.params.BootstrapExpect {
+		return
+	}
+	for _, m := range members {
+		// an existing cluster adds this node, the member with the lowest ID forms a new one
+		if m.Voter || m.ID < c.params.NodeID {
+			return
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err := c.raft.BootstrapCluster(raft.Configuration{
+		Servers: []raft.Server{{
+			Suffrage: raft.Voter,
+			ID:       raft.ServerID(c.params.NodeID),
+			Address:  raft.ServerAddress(c.params.AdvertiseAddress),
+		}},
+	}).Error()
+	if err != nil && !errors.Is(err, raft.ErrCantBootstrap) {
+		c.params.Logger.Warnw("could not bootstrap cluster", err)
+		return
+	}
+	c.params.Logger.Infow("bootstrapped cluster", "members", len(members))
+	c.setVoterLocked()
+}
+
+func (c *Cluster) reconcileVoters(members []Member) {
+	future := c.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return
+	}
+
+	servers := make(map[raft.ServerID]bool)
+	for _, s := range future.Configuration().Servers {
+		servers[s.ID] = true
+	}
+
+	for _, m := range members {
+		id := raft.ServerID(m.ID)
+		if servers[id] {
+			continue
+		}
+		if m.Voter {
+			c.params.Logger.Warnw("member belongs to another cluster", nil, "member", m.ID)
+			continue
+		}
+		if err := c.raft.AddVoter(id, raft.ServerAddress(m.Addr), 0, 0).Error(); err != nil {
+			c.params.Logger.Warnw("could not add cluster voter", err, "member", m.ID)
+			continue
+		}
+		c.params.Logger.Infow("added cluster voter", "member", m.ID, "address", m.Addr)
+	}
+
+	for id := range servers {
+		if string(id) == c.params.NodeID {
+			continue
+		}
+		state, since, ok := c.membership.State(string(id))
+		if ok && (state == MemberAlive || (state == MemberDead && since < c.params.ReapTimeout)) {
+			continue
+		}
+		if err := c.raft.RemoveServer(id, 0, 0).Error(); err != nil {
+			c.params.Logger.Warnw("could not remove cluster voter", err, "member", id)
+			continue
+		}
+		c.params.Logger.Infow("removed cluster voter", "member", id, "state", state)
+	}
+}
+
+// logWriter forwards raft logs
+type logWriter struct {
+	logger logger.Logger
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.logger.Infow(fmt.Sprintf("raft: %s", strings.TrimSpace(string(p))))
+	return len(p), nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/psrpc"
+	"github.com/livekit/psrpc/pkg/client"
+	"github.com/livekit/psrpc/pkg/info"
+	"github.com/livekit/psrpc/pkg/server"
+)
+
+func newTestCluster(t *testing.T, n int) []*Cluster {
+	addrs := make([]string, n)
+	for i := range addrs {
+		addrs[i] = freeAddress(t)
+	}
+
+	nodes := make([]*Cluster, n)
+	for i := range nodes {
+		c, err := New(Params{
+			NodeID:          fmt.Sprintf("node_%d", i),
+			BindAddress:     addrs[i],
+			Seeds:           addrs,
+			BootstrapExpect: n,
+			Secret:          "secret",
+			GossipInterval:  50 * time.Millisecond,
+			DeadNodeTimeout: 500 * time.Millisecond,
+			ReapTimeout:     time.Second,
+			Logger:          logger.GetLogger(),
+		})
+		require.NoError(t, err)
+		t.Cleanup(c.Stop)
+		nodes[i] = c
+	}
+
+	for _, c := range nodes {
+		require.Eventually(t, func() bool {
+			return c.Leader() != "" && len(voters(t, c)) == n
+		}, 20*time.Second, 50*time.Millisecond)
+	}
+	return nodes
+}
+
+func voters(t *testing.T, c *Cluster) []string {
+	future := c.raft.GetConfiguration()
+	require.NoError(t, future.Error())
+	var ids []string
+	for _, s := range future.Configuration().Servers {
+		ids = append(ids, string(s.ID))
+	}
+	return ids
+}
+
+func leaderAndFollowers(nodes []*Cluster) (*Cluster, []*Cluster) {
+	var leader *Cluster
+	var followers []*Cluster
+	for _, c := range nodes {
+		if c.raft.State() == raft.Leader {
+			leader = c
+		} else {
+			followers = append(followers, c)
+		}
+	}
+	return leader, followers
+}
+
+func TestCluster(t *testing.T) {
+	nodes := newTestCluster(t, 3)
+	ctx := context.Background()
+
+	leader, followers := leaderAndFollowers(nodes)
+	require.NotNil(t, leader)
+	require.Len(t, followers, 2)
+
+	t.Run("writes are forwarded to the leader", func(t *testing.T) {
+		s := followers[0].Store()
+		results, err := s.Apply(ctx, HSet("rooms", "room", []byte("node_1")))
+		require.NoError(t, err)
+		require.Equal(t, []bool{true}, results)
+
+		// visible on the writing node once applied
+		v, ok := s.HGet("rooms", "room")
+		require.True(t, ok)
+		require.Equal(t, []byte("node_1"), v)
+
+		for _, c := range nodes {
+			require.Eventually(t, func() bool {
+				_, ok := c.Store().HGet("rooms", "room")
+				return ok
+			}, 5*time.Second, 10*time.Millisecond)
+		}
+	})
+
+	t.Run("locks", func(t *testing.T) {
+		results, err := followers[0].Store().Apply(ctx, SetNX("lock", []byte("a"), time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, []bool{true}, results)
+
+		results, err = followers[1].Store().Apply(ctx, SetNX("lock", []byte("b"), time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, []bool{false}, results)
+
+		results, err = leader.Store().Apply(ctx, DelIfEqual("lock", []byte("a")))
+		require.NoError(t, err)
+		require.Equal(t, []bool{true}, results)
+	})
+
+	t.Run("message bus", func(t *testing.T) {
+		sd := &info.ServiceDefinition{Name: "Test", ID: "test"}
+		sd.RegisterMethod("Echo", false, false, false, false)
+
+		target := followers[1]
+		srv := server.NewRPCServer(sd, target.MessageBus())
+		t.Cleanup(func() { srv.Close(true) })
+		require.NoError(t, server.RegisterHandler(srv, "Echo", []string{target.LocalID()},
+			func(_ context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+				return wrapperspb.String(req.Value + " from " + target.LocalID()), nil
+			}, nil))
+
+		cli, err := client.NewRPCClient(sd, followers[0].MessageBus())
+		require.NoError(t, err)
+		t.Cleanup(cli.Close)
+
+		res, err := client.RequestSingle[*wrapperspb.StringValue](ctx, cli, "Echo", []string{target.LocalID()}, wrapperspb.String("hello"))
+		require.NoError(t, err)
+		require.Equal(t, "hello from "+target.LocalID(), res.Value)
+	})
+}
+
+func TestClusterQueueSubscriptions(t *testing.T) {
+	nodes := newTestCluster(t, 3)
+	ctx := context.Background()
+
+	sd := &info.ServiceDefinition{Name: "Test", ID: "test"}
+	sd.RegisterMethod("Work", false, false, false, true)
+
+	var handled atomic.Int32
+	for _, c := range nodes[1:] {
+		srv := server.NewRPCServer(sd, c.MessageBus())
+		t.Cleanup(func() { srv.Close(true) })
+		require.NoError(t, server.RegisterHandler(srv, "Work", nil,
+			func(_ context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+				handled.Inc()
+				return req, nil
+			}, nil))
+	}
+
+	cli, err := client.NewRPCClient(sd, nodes[0].MessageBus())
+	require.NoError(t, err)
+	t.Cleanup(cli.Close)
+
+	// queue subscriptions of the other nodes are streamed over the message bus
+	require.Eventually(t, func() bool {
+		_, err := client.RequestSingle[*wrapperspb.StringValue](ctx, cli, "Work", nil, wrapperspb.String("ping"), psrpc.WithRequestTimeout(200*time.Millisecond))
+		return err == nil
+	}, 10*time.Second, 50*time.Millisecond)
+	// let late deliveries of the probing requests land
+	time.Sleep(200 * time.Millisecond)
+	handled.Store(0)
+
+	const requests = 10
+	for i := range requests {
+		res, err := client.RequestSingle[*wrapperspb.StringValue](ctx, cli, "Work", nil, wrapperspb.String(fmt.Sprint(i)))
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprint(i), res.Value)
+	}
+	time.Sleep(200 * time.Millisecond)
+	require.EqualValues(t, requests, handled.Load())
+}
+
+func TestClusterMembershipChanges(t *testing.T) {
+	nodes := newTestCluster(t, 3)
+	ctx := context.Background()
+
+	leader, followers := leaderAndFollowers(nodes)
+	require.NotNil(t, leader)
+
+	// a departing node is removed right away
+	followers[0].Leave()
+	followers[0].Stop()
+	require.Eventually(t, func() bool {
+		return len(voters(t, leader)) == 2
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// the leader hands over when it leaves
+	leader.Leave()
+	leader.Stop()
+	remaining := followers[1]
+	require.Eventually(t, func() bool {
+		return remaining.raft.State() == raft.Leader
+	}, 10*time.Second, 50*time.Millisecond)
+	require.Equal(t, []string{remaining.LocalID()}, voters(t, remaining))
+
+	results, err := remaining.Store().Apply(ctx, HSet("rooms", "room", []byte("node")))
+	require.NoError(t, err)
+	require.Equal(t, []bool{true}, results)
+}
+
+func TestClusterReapsDeadNodes(t *testing.T) {
+	nodes := newTestCluster(t, 3)
+
+	leader, followers := leaderAndFollowers(nodes)
+	require.NotNil(t, leader)
+
+	followers[0].Stop()
+	require.Eventually(t, func() bool {
+		return len(voters(t, leader)) == 2
+	}, 10*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,413 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/logger"
+)
+
+const (
+	DefaultGossipInterval  = 500 * time.Millisecond
+	DefaultDeadNodeTimeout = 5 * time.Second
+
+	gossipFanout    = 3
+	maxGossipPacket = 65000
+)
+
+// Member is a node of the cluster as seen by gossip
+type Member struct {
+	ID string `json:"id"`
+	// cluster address, used by raft, forwarded applies and the message bus
+	Addr string `json:"addr"`
+	// opaque data published by the node
+	Meta []byte `json:"meta,omitempty"`
+	// the node takes part in a raft cluster
+	Voter bool `json:"voter,omitempty"`
+}
+
+type MemberState int
+
+const (
+	MemberAlive MemberState = iota
+	// heartbeat has not increased within the dead node timeout
+	MemberDead
+	// the node announced it is leaving
+	MemberLeft
+)
+
+func (s MemberState) String() string {
+	switch s {
+	case MemberAlive:
+		return "alive"
+	case MemberDead:
+		return "dead"
+	case MemberLeft:
+		return "left"
+	default:
+		return "unknown"
+	}
+}
+
+type gossipMember struct {
+	Member
+	// set by the member itself, increasing over restarts
+	Heartbeat int64 `json:"heartbeat"`
+	Left      bool  `json:"left,omitempty"`
+}
+
+type memberRecord struct {
+	gossipMember
+	// local time the heartbeat last increased
+	updatedAt    time.Time
+	reportedDead bool
+}
+
+func (r *memberRecord) state(now time.Time, deadTimeout time.Duration) MemberState {
+	switch {
+	case r.Left:
+		return MemberLeft
+	case now.Sub(r.updatedAt) > deadTimeout:
+		return MemberDead
+	default:
+		return MemberAlive
+	}
+}
+
+type gossipMessage struct {
+	From    string         `json:"from"`
+	Members []gossipMember `json:"members"`
+}
+
+type membershipParams struct {
+	Local           Member
+	BindAddress     string
+	Seeds           []string
+	Secret          string
+	GossipInterval  time.Duration
+	DeadNodeTimeout time.Duration
+	Logger          logger.Logger
+}
+
+// membership tracks the nodes of the cluster by periodically exchanging heartbeats over UDP with a few
+// random peers and the seeds. A node is dead when its heartbeat stops increasing, it is forgotten some time
+// later. Records of dead and departed nodes are kept meanwhile so late gossip cannot bring them back.
+type membership struct {
+	params membershipParams
+	conn   *net.UDPConn
+	key    []byte
+
+	lock    sync.RWMutex
+	local   *memberRecord
+	members map[string]*memberRecord
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newMembership(params membershipParams) (*membership, error) {
+	if params.GossipInterval <= 0 {
+		params.GossipInterval = DefaultGossipInterval
+	}
+	if params.DeadNodeTimeout <= 0 {
+		params.DeadNodeTimeout = DefaultDeadNodeTimeout
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", params.BindAddress)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	local := &memberRecord{
+		gossipMember: gossipMember{
+			Member:    params.Local,
+			Heartbeat: now.UnixNano(),
+		},
+		updatedAt: now,
+	}
+	m := &membership{
+		params:  params,
+		conn:    conn,
+		key:     clusterKey(params.Secret, "gossip"),
+		local:   local,
+		members: map[string]*memberRecord{params.Local.ID: local},
+		closed:  make(chan struct{}),
+	}
+
+	m.wg.Add(2)
+	go m.receiveWorker()
+	go m.gossipWorker()
+	return m, nil
+}
+
+func (m *membership) Close() {
+	m.closeOnce.Do(func() {
+		close(m.closed)
+		_ = m.conn.Close()
+	})
+	m.wg.Wait()
+}
+
+// Leave announces the departure of this node to every known member
+func (m *membership) Leave() {
+	m.lock.Lock()
+	m.local.Left = true
+	m.local.Heartbeat = max(m.local.Heartbeat+1, time.Now().UnixNano())
+	targets := make([]string, 0, len(m.members))
+	for id, r := range m.members {
+		if id != m.local.ID && r.state(time.Now(), m.params.DeadNodeTimeout) == MemberAlive {
+			targets = append(targets, r.Addr)
+		}
+	}
+	m.lock.Unlock()
+
+	for _, addr := range targets {
+		m.sendTo(addr)
+	}
+}
+
+// UpdateLocal changes the data published by this node, it spreads with the next gossip round
+func (m *membership) UpdateLocal(fn func(member *Member)) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	fn(&m.local.Member)
+	m.local.Heartbeat = max(m.local.Heartbeat+1, time.Now().UnixNano())
+}
+
+func (m *membership) Local() Member {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.local.Member
+}
+
+// Members returns the alive members, this node included
+func (m *membership) Members() []Member {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	now := time.Now()
+	members := make([]Member, 0, len(m.members))
+	for _, r := range m.members {
+		if r.state(now, m.params.DeadNodeTimeout) == MemberAlive {
+			members = append(members, r.Member)
+		}
+	}
+	slices.SortFunc(members, func(a, b Member) int {
+		switch {
+		case a.ID < b.ID:
+			return -1
+		case a.ID > b.ID:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return members
+}
+
+// State returns the state of a member and how long it has been in it, ok is false for unknown members
+func (m *membership) State(id string) (state MemberState, since time.Duration, ok bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	r := m.members[id]
+	if r == nil {
+		return MemberDead, 0, false
+	}
+	now := time.Now()
+	state = r.state(now, m.params.DeadNodeTimeout)
+	since = now.Sub(r.updatedAt)
+	if state == MemberDead {
+		since -= m.params.DeadNodeTimeout
+	}
+	return state, since, true
+}
+
+func (m *membership) gossipWorker() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.params.GossipInterval)
+	defer ticker.Stop()
+
+	m.gossip()
+	for {
+		select {
+		case <-m.closed:
+			return
+		case <-ticker.C:
+			m.gossip()
+		}
+	}
+}
+
+func (m *membership) gossip() {
+	now := time.Now()
+	forgetAfter := 10 * m.params.DeadNodeTimeout
+
+	m.lock.Lock()
+	if !m.local.Left {
+		m.local.Heartbeat = max(m.local.Heartbeat+1, now.UnixNano())
+		m.local.updatedAt = now
+	}
+
+	var peers []string
+	for id, r := range m.members {
+		if id == m.local.ID {
+			continue
+		}
+		if now.Sub(r.updatedAt) > forgetAfter {
+			delete(m.members, id)
+			continue
+		}
+		switch r.state(now, m.params.DeadNodeTimeout) {
+		case MemberAlive:
+			peers = append(peers, r.Addr)
+		case MemberDead:
+			if !r.reportedDead {
+				m.params.Logger.Infow("cluster member failed", "member", id, "address", r.Addr)
+				r.reportedDead = true
+			}
+		}
+	}
+	m.lock.Unlock()
+
+	rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+	targets := peers[:min(len(peers), gossipFanout)]
+	// seeds not yet seen alive are contacted every round, so nodes find each other regardless of start order
+	for _, seed := range m.params.Seeds {
+		if seed != m.local.Addr && !slices.Contains(peers, seed) && !slices.Contains(targets, seed) {
+			targets = append(targets, seed)
+		}
+	}
+
+	for _, addr := range targets {
+		m.sendTo(addr)
+	}
+}
+
+func (m *membership) sendTo(address string) {
+	m.lock.RLock()
+	now := time.Now()
+	msg := gossipMessage{From: m.local.ID}
+	for _, r := range m.members {
+		if r.state(now, m.params.DeadNodeTimeout) != MemberDead {
+			msg.Members = append(msg.Members, r.gossipMember)
+		}
+	}
+	m.lock.RUnlock()
+
+	packet, err := json.Marshal(&msg)
+	if err != nil {
+		return
+	}
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write(packet)
+	packet = mac.Sum(packet)
+	if len(packet) > maxGossipPacket {
+		m.params.Logger.Warnw("gossip message too large", nil, "size", len(packet))
+		return
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		m.params.Logger.Debugw("could not resolve cluster member", "address", address, "error", err)
+		return
+	}
+	if _, err := m.conn.WriteToUDP(packet, addr); err != nil {
+		m.params.Logger.Debugw("could not send gossip", "address", address, "error", err)
+	}
+}
+
+func (m *membership) receiveWorker() {
+	defer m.wg.Done()
+
+	buf := make([]byte, maxGossipPacket+1)
+	for {
+		n, from, err := m.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		if n <= sha256.Size {
+			continue
+		}
+		packet, sum := buf[:n-sha256.Size], buf[n-sha256.Size:n]
+		mac := hmac.New(sha256.New, m.key)
+		mac.Write(packet)
+		if !hmac.Equal(mac.Sum(nil), sum) {
+			m.params.Logger.Debugw("dropping gossip with invalid signature", "from", from)
+			continue
+		}
+
+		var msg gossipMessage
+		if err := json.Unmarshal(packet, &msg); err != nil {
+			continue
+		}
+		m.merge(msg.Members)
+	}
+}
+
+func (m *membership) merge(members []gossipMember) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := time.Now()
+	for _, gm := range members {
+		if gm.ID == m.local.ID || gm.ID == "" {
+			continue
+		}
+		r := m.members[gm.ID]
+		if r == nil {
+			if gm.Left {
+				continue
+			}
+			m.params.Logger.Infow("cluster member joined", "member", gm.ID, "address", gm.Addr)
+			m.members[gm.ID] = &memberRecord{gossipMember: gm, updatedAt: now}
+			continue
+		}
+		if gm.Heartbeat <= r.Heartbeat {
+			continue
+		}
+		if gm.Left && !r.Left {
+			m.params.Logger.Infow("cluster member left", "member", gm.ID)
+		} else if r.state(now, m.params.DeadNodeTimeout) == MemberDead {
+			m.params.Logger.Infow("cluster member recovered", "member", gm.ID)
+		}
+		r.gossipMember = gm
+		r.updatedAt = now
+		r.reportedDead = false
+	}
+}
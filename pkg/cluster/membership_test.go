@@ -0,0 +1,152 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/logger"
+)
+
+// freeAddress returns a localhost address with a port free for both TCP and UDP
+func freeAddress(t *testing.T) string {
+	t.Helper()
+
+	for range 10 {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := l.Addr().String()
+		_ = l.Close()
+
+		udpAddr, err := net.ResolveUDPAddr("udp", addr)
+		require.NoError(t, err)
+		conn, err := net.ListenUDP("udp", udpAddr)
+		if err != nil {
+			continue
+		}
+		_ = conn.Close()
+		return addr
+	}
+	t.Fatal("no free port")
+	return ""
+}
+
+func newTestMemberships(t *testing.T, n int, secret func(i int) string) []*membership {
+	addrs := make([]string, n)
+	for i := range addrs {
+		addrs[i] = freeAddress(t)
+	}
+
+	members := make([]*membership, n)
+	for i := range members {
+		m, err := newMembership(membershipParams{
+			Local: Member{
+				ID:   fmt.Sprintf("node_%d", i),
+				Addr: addrs[i],
+			},
+			BindAddress:     addrs[i],
+			Seeds:           addrs[:1],
+			Secret:          secret(i),
+			GossipInterval:  20 * time.Millisecond,
+			DeadNodeTimeout: 300 * time.Millisecond,
+			Logger:          logger.GetLogger(),
+		})
+		require.NoError(t, err)
+		t.Cleanup(m.Close)
+		members[i] = m
+	}
+	return members
+}
+
+func memberIDs(members []Member) []string {
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestMembership(t *testing.T) {
+	members := newTestMemberships(t, 3, func(int) string { return "secret" })
+	all := []string{"node_0", "node_1", "node_2"}
+
+	t.Run("converges", func(t *testing.T) {
+		for _, m := range members {
+			require.Eventually(t, func() bool {
+				return len(m.Members()) == 3
+			}, 5*time.Second, 10*time.Millisecond)
+			require.Equal(t, all, memberIDs(m.Members()))
+		}
+	})
+
+	t.Run("meta spreads", func(t *testing.T) {
+		members[2].UpdateLocal(func(m *Member) {
+			m.Meta = []byte("meta")
+		})
+		for _, m := range members {
+			require.Eventually(t, func() bool {
+				for _, member := range m.Members() {
+					if member.ID == "node_2" {
+						return string(member.Meta) == "meta"
+					}
+				}
+				return false
+			}, 5*time.Second, 10*time.Millisecond)
+		}
+	})
+
+	t.Run("leave", func(t *testing.T) {
+		members[2].Leave()
+		members[2].Close()
+		for _, m := range members[:2] {
+			require.Eventually(t, func() bool {
+				state, _, ok := m.State("node_2")
+				return ok && state == MemberLeft
+			}, 5*time.Second, 10*time.Millisecond)
+			require.Equal(t, all[:2], memberIDs(m.Members()))
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		members[1].Close()
+		require.Eventually(t, func() bool {
+			state, _, ok := members[0].State("node_1")
+			return ok && state == MemberDead
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, all[:1], memberIDs(members[0].Members()))
+	})
+}
+
+func TestMembershipSecret(t *testing.T) {
+	members := newTestMemberships(t, 3, func(i int) string {
+		if i == 2 {
+			return "other"
+		}
+		return "secret"
+	})
+
+	require.Eventually(t, func() bool {
+		return len(members[0].Members()) == 2 && len(members[1].Members()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	time.Sleep(200 * time.Millisecond)
+	require.Equal(t, []string{"node_0", "node_1"}, memberIDs(members[0].Members()))
+	require.Equal(t, []string{"node_2"}, memberIDs(members[2].Members()))
+}
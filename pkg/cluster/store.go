@@ -0,0 +1,430 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+const (
+	DefaultApplyTimeout = 5 * time.Second
+
+	retryInterval = 50 * time.Millisecond
+)
+
+var (
+	ErrNoLeader      = errors.New("no cluster leader")
+	ErrStoreClosed   = errors.New("cluster store closed")
+	errNotLeader     = errors.New("not the cluster leader")
+	errInvalidResult = errors.New("invalid apply result")
+)
+
+const (
+	opHSet       = "hset"
+	opHDel       = "hdel"
+	opDel        = "del"
+	opSetNX      = "setnx"
+	opDelIfEqual = "delifeq"
+)
+
+// Command is a write to the store, commands passed to a single Apply are applied atomically
+type Command struct {
+	Op    string        `json:"op"`
+	Key   string        `json:"key"`
+	Field string        `json:"field,omitempty"`
+	Value []byte        `json:"value,omitempty"`
+	TTL   time.Duration `json:"ttl,omitempty"`
+}
+
+// HSet sets a field of a hash
+func HSet(key, field string, value []byte) Command {
+	return Command{Op: opHSet, Key: key, Field: field, Value: value}
+}
+
+// HDel removes a field of a hash, its result is false when the field did not exist
+func HDel(key, field string) Command {
+	return Command{Op: opHDel, Key: key, Field: field}
+}
+
+// Del removes a hash or a value
+func Del(key string) Command {
+	return Command{Op: opDel, Key: key}
+}
+
+// SetNX sets a value expiring after ttl unless it is already set, its result is false when it was set
+func SetNX(key string, value []byte, ttl time.Duration) Command {
+	return Command{Op: opSetNX, Key: key, Value: value, TTL: ttl}
+}
+
+// DelIfEqual removes a value if it is equal to value, its result is false when it was not removed
+func DelIfEqual(key string, value []byte) Command {
+	return Command{Op: opDelIfEqual, Key: key, Value: value}
+}
+
+type commandBatch struct {
+	// time of the proposing node, expirations are evaluated against it so every replica agrees
+	Now      int64     `json:"now"`
+	Commands []Command `json:"commands"`
+}
+
+type valueEntry struct {
+	Value   []byte `json:"value"`
+	Expires int64  `json:"expires,omitempty"`
+}
+
+type fsmState struct {
+	Hashes map[string]map[string][]byte `json:"hashes"`
+	Values map[string]*valueEntry       `json:"values"`
+}
+
+type fsmSnapshotData struct {
+	// index of the last log applied to the state
+	Index uint64   `json:"index"`
+	State fsmState `json:"state"`
+}
+
+// fsm is the replicated state: hashes of byte values, and single values with an expiration
+type fsm struct {
+	lock         sync.RWMutex
+	state        fsmState
+	appliedIndex uint64
+	applied      *sync.Cond
+}
+
+func newFSM() *fsm {
+	f := &fsm{
+		state: fsmState{
+			Hashes: make(map[string]map[string][]byte),
+			Values: make(map[string]*valueEntry),
+		},
+	}
+	f.applied = sync.NewCond(f.lock.RLocker())
+	return f
+}
+
+func (f *fsm) Apply(log *raft.Log) interface{} {
+	var batch commandBatch
+	if err := json.Unmarshal(log.Data, &batch); err != nil {
+		return err
+	}
+
+	f.lock.Lock()
+	defer func() {
+		f.appliedIndex = log.Index
+		f.lock.Unlock()
+		f.applied.Broadcast()
+	}()
+
+	results := make([]bool, len(batch.Commands))
+	for i, cmd := range batch.Commands {
+		results[i] = f.applyCommand(batch.Now, cmd)
+	}
+	return results
+}
+
+func (f *fsm) applyCommand(now int64, cmd Command) bool {
+	switch cmd.Op {
+	case opHSet:
+		h := f.state.Hashes[cmd.Key]
+		if h == nil {
+			h = make(map[string][]byte)
+			f.state.Hashes[cmd.Key] = h
+		}
+		h[cmd.Field] = cmd.Value
+		return true
+
+	case opHDel:
+		h := f.state.Hashes[cmd.Key]
+		if _, ok := h[cmd.Field]; !ok {
+			return false
+		}
+		delete(h, cmd.Field)
+		if len(h) == 0 {
+			delete(f.state.Hashes, cmd.Key)
+		}
+		return true
+
+	case opDel:
+		_, isHash := f.state.Hashes[cmd.Key]
+		_, isValue := f.state.Values[cmd.Key]
+		delete(f.state.Hashes, cmd.Key)
+		delete(f.state.Values, cmd.Key)
+		return isHash || isValue
+
+	case opSetNX:
+		if v := f.state.Values[cmd.Key]; v != nil && !v.expired(now) {
+			return false
+		}
+		v := &valueEntry{Value: cmd.Value}
+		if cmd.TTL > 0 {
+			v.Expires = now + int64(cmd.TTL)
+		}
+		f.state.Values[cmd.Key] = v
+		return true
+
+	case opDelIfEqual:
+		v := f.state.Values[cmd.Key]
+		if v == nil || v.expired(now) || string(v.Value) != string(cmd.Value) {
+			return false
+		}
+		delete(f.state.Values, cmd.Key)
+		return true
+
+	default:
+		return false
+	}
+}
+
+func (v *valueEntry) expired(now int64) bool {
+	return v.Expires != 0 && now >= v.Expires
+}
+
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	// the state is encoded right away, raft persists the snapshot concurrently with further applies
+	data, err := json.Marshal(&fsmSnapshotData{
+		Index: f.appliedIndex,
+		State: f.state,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fsmSnapshot(data), nil
+}
+
+func (f *fsm) Restore(snapshot io.ReadCloser) error {
+	defer snapshot.Close()
+
+	var data fsmSnapshotData
+	if err := json.NewDecoder(snapshot).Decode(&data); err != nil {
+		return err
+	}
+	state := data.State
+	if state.Hashes == nil {
+		state.Hashes = make(map[string]map[string][]byte)
+	}
+	if state.Values == nil {
+		state.Values = make(map[string]*valueEntry)
+	}
+
+	f.lock.Lock()
+	f.state = state
+	f.appliedIndex = data.Index
+	f.lock.Unlock()
+	f.applied.Broadcast()
+	return nil
+}
+
+// waitApplied blocks until the log at index has been applied locally
+func (f *fsm) waitApplied(ctx context.Context, index uint64) error {
+	stop := context.AfterFunc(ctx, func() {
+		f.lock.RLock()
+		f.applied.Broadcast()
+		f.lock.RUnlock()
+	})
+	defer stop()
+
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	for f.appliedIndex < index {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		f.applied.Wait()
+	}
+	return nil
+}
+
+type fsmSnapshot []byte
+
+func (s fsmSnapshot) Persist(sink raft.SnapshotSink) error {
+	if _, err := sink.Write(s); err != nil {
+		_ = sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+func (s fsmSnapshot) Release() {}
+
+// Store is a key value store replicated with raft. Reads are served from the local replica, writes are
+// applied by the leader, forwarded to it when needed, and visible locally once Apply returns.
+type Store struct {
+	raft         *raft.Raft
+	fsm          *fsm
+	transport    *transport
+	applyTimeout time.Duration
+	closed       chan struct{}
+}
+
+// Apply applies the commands atomically, returning the result of each command
+func (s *Store) Apply(ctx context.Context, cmds ...Command) ([]bool, error) {
+	data, err := json.Marshal(&commandBatch{
+		Now:      time.Now().UnixNano(),
+		Commands: cmds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.applyTimeout)
+	defer cancel()
+
+	for {
+		results, retry, err := s.tryApply(ctx, data)
+		if !retry {
+			return results, err
+		}
+
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				err = ErrNoLeader
+			}
+			return nil, err
+		case <-s.closed:
+			return nil, ErrStoreClosed
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+func (s *Store) tryApply(ctx context.Context, data []byte) (results []bool, retry bool, err error) {
+	if s.raft.State() == raft.Leader {
+		index, results, err := s.applyLocal(ctx, data)
+		if errors.Is(err, errNotLeader) {
+			return nil, true, err
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return results, false, s.fsm.waitApplied(ctx, index)
+	}
+
+	leader, _ := s.raft.LeaderWithID()
+	if leader == "" {
+		return nil, true, nil
+	}
+	timeout := s.applyTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	res, err := s.transport.Apply(string(leader), &applyRequest{Command: data}, timeout)
+	if err != nil {
+		// the leader may have changed or be unreachable
+		return nil, true, err
+	}
+	if res.Error != "" {
+		if res.Error == errNotLeader.Error() {
+			return nil, true, errNotLeader
+		}
+		return nil, false, errors.New(res.Error)
+	}
+	// make the write visible to reads on this node before returning
+	return res.Result, false, s.fsm.waitApplied(ctx, res.Index)
+}
+
+func (s *Store) applyLocal(ctx context.Context, data []byte) (uint64, []bool, error) {
+	timeout := s.applyTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	f := s.raft.Apply(data, timeout)
+	if err := f.Error(); err != nil {
+		if errors.Is(err, raft.ErrNotLeader) || errors.Is(err, raft.ErrLeadershipLost) || errors.Is(err, raft.ErrLeadershipTransferInProgress) {
+			return 0, nil, errNotLeader
+		}
+		return 0, nil, err
+	}
+	switch res := f.Response().(type) {
+	case []bool:
+		return f.Index(), res, nil
+	case error:
+		return 0, nil, res
+	default:
+		return 0, nil, errInvalidResult
+	}
+}
+
+// handleApply applies a command forwarded by another node
+func (s *Store) handleApply(req *applyRequest) *applyResponse {
+	if s.raft.State() != raft.Leader {
+		return &applyResponse{Error: errNotLeader.Error()}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.applyTimeout)
+	defer cancel()
+
+	index, results, err := s.applyLocal(ctx, req.Command)
+	if err != nil {
+		return &applyResponse{Error: err.Error()}
+	}
+	return &applyResponse{Index: index, Result: results}
+}
+
+// HGet returns a field of a hash. Returned values must not be modified.
+func (s *Store) HGet(key, field string) ([]byte, bool) {
+	s.fsm.lock.RLock()
+	defer s.fsm.lock.RUnlock()
+
+	v, ok := s.fsm.state.Hashes[key][field]
+	return v, ok
+}
+
+// HMGet returns fields of a hash, nil for missing fields
+func (s *Store) HMGet(key string, fields ...string) [][]byte {
+	s.fsm.lock.RLock()
+	defer s.fsm.lock.RUnlock()
+
+	h := s.fsm.state.Hashes[key]
+	values := make([][]byte, len(fields))
+	for i, field := range fields {
+		values[i] = h[field]
+	}
+	return values
+}
+
+// HGetAll returns every field of a hash
+func (s *Store) HGetAll(key string) map[string][]byte {
+	s.fsm.lock.RLock()
+	defer s.fsm.lock.RUnlock()
+
+	h := s.fsm.state.Hashes[key]
+	values := make(map[string][]byte, len(h))
+	for field, v := range h {
+		values[field] = v
+	}
+	return values
+}
+
+// Get returns a value unless it has expired
+func (s *Store) Get(key string) ([]byte, bool) {
+	s.fsm.lock.RLock()
+	defer s.fsm.lock.RUnlock()
+
+	v := s.fsm.state.Values[key]
+	if v == nil || v.expired(time.Now().UnixNano()) {
+		return nil, false
+	}
+	return v.Value, true
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+)
+
+func applyBatch(t *testing.T, f *fsm, index uint64, now time.Time, cmds ...Command) []bool {
+	data, err := json.Marshal(&commandBatch{Now: now.UnixNano(), Commands: cmds})
+	require.NoError(t, err)
+	res := f.Apply(&raft.Log{Index: index, Type: raft.LogCommand, Data: data})
+	results, ok := res.([]bool)
+	require.True(t, ok, res)
+	return results
+}
+
+func TestFSM(t *testing.T) {
+	now := time.Now()
+
+	t.Run("hashes", func(t *testing.T) {
+		f := newFSM()
+		s := &Store{fsm: f}
+
+		require.Equal(t, []bool{true, true, true}, applyBatch(t, f, 1, now,
+			HSet("rooms", "a", []byte("1")),
+			HSet("rooms", "b", []byte("2")),
+			HSet("nodes", "a", []byte("node")),
+		))
+		v, ok := s.HGet("rooms", "a")
+		require.True(t, ok)
+		require.Equal(t, []byte("1"), v)
+		require.Equal(t, [][]byte{[]byte("2"), nil}, s.HMGet("rooms", "b", "c"))
+		require.Len(t, s.HGetAll("rooms"), 2)
+
+		require.Equal(t, []bool{true, false, true}, applyBatch(t, f, 2, now,
+			HDel("rooms", "a"),
+			HDel("rooms", "a"),
+			Del("nodes"),
+		))
+		_, ok = s.HGet("rooms", "a")
+		require.False(t, ok)
+		require.Empty(t, s.HGetAll("nodes"))
+	})
+
+	t.Run("values expire", func(t *testing.T) {
+		f := newFSM()
+		s := &Store{fsm: f}
+
+		require.Equal(t, []bool{true, false}, applyBatch(t, f, 1, now,
+			SetNX("lock", []byte("a"), time.Second),
+			SetNX("lock", []byte("b"), time.Second),
+		))
+		require.Equal(t, []bool{false}, applyBatch(t, f, 2, now, DelIfEqual("lock", []byte("b"))))
+		v, ok := s.Get("lock")
+		require.True(t, ok)
+		require.Equal(t, []byte("a"), v)
+
+		// expiration is evaluated at the time of the proposing node
+		require.Equal(t, []bool{true}, applyBatch(t, f, 3, now.Add(time.Second), SetNX("lock", []byte("b"), 0)))
+		require.Equal(t, []bool{false}, applyBatch(t, f, 4, now, DelIfEqual("lock", []byte("a"))))
+		require.Equal(t, []bool{true}, applyBatch(t, f, 5, now, DelIfEqual("lock", []byte("b"))))
+		_, ok = s.Get("lock")
+		require.False(t, ok)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		f := newFSM()
+		applyBatch(t, f, 7, now,
+			HSet("rooms", "a", []byte("1")),
+			SetNX("lock", []byte("a"), time.Hour),
+		)
+
+		snapshot, err := f.Snapshot()
+		require.NoError(t, err)
+		sink := &testSnapshotSink{}
+		require.NoError(t, snapshot.Persist(sink))
+
+		restored := newFSM()
+		require.NoError(t, restored.Restore(io.NopCloser(&sink.Buffer)))
+		require.Equal(t, uint64(7), restored.appliedIndex)
+		s := &Store{fsm: restored}
+		v, ok := s.HGet("rooms", "a")
+		require.True(t, ok)
+		require.Equal(t, []byte("1"), v)
+		_, ok = s.Get("lock")
+		require.True(t, ok)
+	})
+}
+
+type testSnapshotSink struct {
+	bytes.Buffer
+}
+
+func (s *testSnapshotSink) ID() string    { return "test" }
+func (s *testSnapshotSink) Cancel() error { return nil }
+func (s *testSnapshotSink) Close() error  { return nil }
@@ -0,0 +1,401 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/livekit/protocol/logger"
+)
+
+// first byte of every cluster TCP connection, followed by the handshake
+const (
+	connTypeRaft byte = iota + 1
+	// requests forwarded to the raft leader, answered on the same connection
+	connTypeApply
+	// one way stream of message bus publications and queue subscription updates
+	connTypeBus
+)
+
+const (
+	handshakeTimeout = 5 * time.Second
+	dialTimeout      = 5 * time.Second
+	nonceLength      = 32
+	proofLength      = sha256.Size
+)
+
+var ErrTransportClosed = errors.New("cluster transport closed")
+
+// clusterKey derives a key from the cluster secret, each use of the secret gets its own key
+func clusterKey(secret string, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("livekit-cluster " + purpose))
+	return mac.Sum(nil)
+}
+
+// handshakeProof proves knowledge of the cluster secret for one connection. The accepting node sends a
+// random nonce, the dialing node answers with a MAC over it and the connection type, so a captured
+// handshake cannot be replayed. It keeps misconfigured nodes out of the cluster, it does not encrypt the
+// traffic, which should stay on a private network.
+func handshakeProof(key []byte, nonce []byte, connType byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(nonce)
+	mac.Write([]byte{connType})
+	return mac.Sum(nil)
+}
+
+type applyRequest struct {
+	Command []byte `json:"command"`
+}
+
+type applyResponse struct {
+	Index  uint64 `json:"index,omitempty"`
+	Result []bool `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type busMessage struct {
+	Channel string `json:"channel"`
+	Message []byte `json:"message"`
+	// node delivering the message to queue subscribers
+	QueueNode string `json:"queueNode,omitempty"`
+	// set instead of the message when the sending node updates its queue subscriptions
+	Queues *queueUpdate `json:"queues,omitempty"`
+}
+
+// queueUpdate lists the message bus channels a node added or removed queue subscribers for
+type queueUpdate struct {
+	NodeID string `json:"nodeId"`
+	// Added holds every channel of the node and replaces the known ones
+	Full    bool     `json:"full,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+type transportParams struct {
+	// address the listener binds to
+	BindAddress string
+	// address other nodes dial, the raft address of this node
+	AdvertiseAddress string
+	Secret           string
+	OnApply          func(req *applyRequest) *applyResponse
+	OnBusMessage     func(msg *busMessage)
+	Logger           logger.Logger
+}
+
+// transport multiplexes raft, forwarded applies and message bus traffic on the cluster TCP port
+type transport struct {
+	params   transportParams
+	key      []byte
+	listener net.Listener
+
+	raftConns chan net.Conn
+
+	lock    sync.Mutex
+	clients map[string]*applyClient
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newTransport(params transportParams) (*transport, error) {
+	listener, err := net.Listen("tcp", params.BindAddress)
+	if err != nil {
+		return nil, err
+	}
+	if params.AdvertiseAddress == "" {
+		params.AdvertiseAddress = listener.Addr().String()
+	}
+
+	t := &transport{
+		params:    params,
+		key:       clusterKey(params.Secret, "transport"),
+		listener:  listener,
+		raftConns: make(chan net.Conn),
+		clients:   make(map[string]*applyClient),
+		closed:    make(chan struct{}),
+	}
+	t.wg.Add(1)
+	go t.acceptWorker()
+	return t, nil
+}
+
+func (t *transport) Addr() net.Addr {
+	return t.listener.Addr()
+}
+
+func (t *transport) Close() {
+	t.closeOnce.Do(func() {
+		close(t.closed)
+		_ = t.listener.Close()
+
+		t.lock.Lock()
+		clients := t.clients
+		t.clients = make(map[string]*applyClient)
+		t.lock.Unlock()
+		for _, c := range clients {
+			c.close()
+		}
+	})
+	t.wg.Wait()
+}
+
+func (t *transport) acceptWorker() {
+	defer t.wg.Done()
+
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			select {
+			case <-t.closed:
+				return
+			default:
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			t.params.Logger.Warnw("cluster accept failed", err)
+			return
+		}
+		go t.handleConn(conn)
+	}
+}
+
+func (t *transport) handleConn(conn net.Conn) {
+	connType, ok := t.acceptHandshake(conn)
+	if !ok {
+		_ = conn.Close()
+		return
+	}
+
+	switch connType {
+	case connTypeRaft:
+		select {
+		case t.raftConns <- conn:
+		case <-t.closed:
+			_ = conn.Close()
+		}
+	case connTypeApply:
+		t.serveApply(conn)
+	case connTypeBus:
+		t.serveBus(conn)
+	default:
+		_ = conn.Close()
+	}
+}
+
+func (t *transport) acceptHandshake(conn net.Conn) (byte, bool) {
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	connType := make([]byte, 1)
+	if _, err := io.ReadFull(conn, connType); err != nil {
+		return 0, false
+	}
+	nonce := make([]byte, nonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		t.params.Logger.Warnw("could not generate cluster handshake nonce", err)
+		return 0, false
+	}
+	if _, err := conn.Write(nonce); err != nil {
+		return 0, false
+	}
+	proof := make([]byte, proofLength)
+	if _, err := io.ReadFull(conn, proof); err != nil {
+		return 0, false
+	}
+	if !hmac.Equal(proof, handshakeProof(t.key, nonce, connType[0])) {
+		t.params.Logger.Warnw("rejecting cluster connection", errors.New("invalid cluster handshake"), "remote", conn.RemoteAddr())
+		return 0, false
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return connType[0], true
+}
+
+func (t *transport) serveApply(conn net.Conn) {
+	defer conn.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	enc := json.NewEncoder(conn)
+	for {
+		var req applyRequest
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		var res *applyResponse
+		if t.params.OnApply != nil {
+			res = t.params.OnApply(&req)
+		} else {
+			res = &applyResponse{Error: "not accepting requests"}
+		}
+		if err := enc.Encode(res); err != nil {
+			return
+		}
+	}
+}
+
+func (t *transport) serveBus(conn net.Conn) {
+	defer conn.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	for {
+		var msg busMessage
+		if err := dec.Decode(&msg); err != nil {
+			return
+		}
+		if t.params.OnBusMessage != nil {
+			t.params.OnBusMessage(&msg)
+		}
+	}
+}
+
+func (t *transport) dial(address string, connType byte, timeout time.Duration) (net.Conn, error) {
+	select {
+	case <-t.closed:
+		return nil, ErrTransportClosed
+	default:
+	}
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	nonce := make([]byte, nonceLength)
+	_, err = conn.Write([]byte{connType})
+	if err == nil {
+		_, err = io.ReadFull(conn, nonce)
+	}
+	if err == nil {
+		_, err = conn.Write(handshakeProof(t.key, nonce, connType))
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// Apply forwards a command to the node at address, expected to be the raft leader
+func (t *transport) Apply(address string, req *applyRequest, timeout time.Duration) (*applyResponse, error) {
+	t.lock.Lock()
+	c := t.clients[address]
+	if c == nil {
+		c = &applyClient{t: t, address: address}
+		t.clients[address] = c
+	}
+	t.lock.Unlock()
+
+	return c.apply(req, timeout)
+}
+
+// applyClient keeps one connection to a node for forwarded applies, requests are serialized
+type applyClient struct {
+	t       *transport
+	address string
+
+	lock sync.Mutex
+	conn net.Conn
+	dec  *json.Decoder
+	enc  *json.Encoder
+}
+
+func (c *applyClient) apply(req *applyRequest, timeout time.Duration) (*applyResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.conn == nil {
+		conn, err := c.t.dial(c.address, connTypeApply, dialTimeout)
+		if err != nil {
+			return nil, err
+		}
+		c.conn = conn
+		c.dec = json.NewDecoder(bufio.NewReader(conn))
+		c.enc = json.NewEncoder(conn)
+	}
+
+	_ = c.conn.SetDeadline(time.Now().Add(timeout))
+	var res applyResponse
+	err := c.enc.Encode(req)
+	if err == nil {
+		err = c.dec.Decode(&res)
+	}
+	if err != nil {
+		// the stream is out of sync after a failure
+		_ = c.conn.Close()
+		c.conn = nil
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *applyClient) close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// raftStreamLayer hands raft its connections from the shared listener
+type raftStreamLayer struct {
+	t *transport
+}
+
+func (s *raftStreamLayer) Accept() (net.Conn, error) {
+	select {
+	case conn := <-s.t.raftConns:
+		return conn, nil
+	case <-s.t.closed:
+		return nil, ErrTransportClosed
+	}
+}
+
+func (s *raftStreamLayer) Close() error {
+	// the listener is owned by the transport
+	return nil
+}
+
+func (s *raftStreamLayer) Addr() net.Addr {
+	return advertiseAddr(s.t.params.AdvertiseAddress)
+}
+
+func (s *raftStreamLayer) Dial(address raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	return s.t.dial(string(address), connTypeRaft, timeout)
+}
+
+type advertiseAddr string
+
+func (a advertiseAddr) Network() string {
+	return "tcp"
+}
+
+func (a advertiseAddr) String() string {
+	return string(a)
+}
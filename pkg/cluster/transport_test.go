@@ -0,0 +1,90 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/logger"
+)
+
+func newTestTransport(t *testing.T, secret string) *transport {
+	tr, err := newTransport(transportParams{
+		BindAddress: "127.0.0.1:0",
+		Secret:      secret,
+		OnApply: func(_ *applyRequest) *applyResponse {
+			return &applyResponse{Index: 1}
+		},
+		Logger: logger.GetLogger(),
+	})
+	require.NoError(t, err)
+	t.Cleanup(tr.Close)
+	return tr
+}
+
+func TestTransportHandshake(t *testing.T) {
+	server := newTestTransport(t, "secret")
+	address := server.Addr().String()
+
+	t.Run("accepts nodes with the cluster secret", func(t *testing.T) {
+		client := newTestTransport(t, "secret")
+		res, err := client.Apply(address, &applyRequest{}, time.Second)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, res.Index)
+	})
+
+	t.Run("rejects nodes with another secret", func(t *testing.T) {
+		client := newTestTransport(t, "other")
+		_, err := client.Apply(address, &applyRequest{}, time.Second)
+		require.Error(t, err)
+	})
+
+	t.Run("rejects replayed handshakes", func(t *testing.T) {
+		key := clusterKey("secret", "transport")
+
+		conn, err := net.Dial("tcp", address)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Write([]byte{connTypeApply})
+		require.NoError(t, err)
+		nonce := make([]byte, nonceLength)
+		_, err = io.ReadFull(conn, nonce)
+		require.NoError(t, err)
+		proof := handshakeProof(key, nonce, connTypeApply)
+
+		replay, err := net.Dial("tcp", address)
+		require.NoError(t, err)
+		defer replay.Close()
+		_, err = replay.Write([]byte{connTypeApply})
+		require.NoError(t, err)
+		_, err = io.ReadFull(replay, make([]byte, nonceLength))
+		require.NoError(t, err)
+		_, err = replay.Write(proof)
+		require.NoError(t, err)
+
+		// the connection is closed without serving the request
+		_, err = replay.Write([]byte("{}\n"))
+		if err == nil {
+			_ = replay.SetReadDeadline(time.Now().Add(time.Second))
+			_, err = replay.Read(make([]byte, 1))
+		}
+		require.Error(t, err)
+	})
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/bwe/sendsidebwe"
 	"github.com/livekit/livekit-server/pkg/sfu/pacer"
 	"github.com/livekit/livekit-server/pkg/sfu/streamallocator"
+	"github.com/livekit/livekit-server/pkg/sfu/uplinkcongestion"
 )
 
 const (
@@ -51,7 +52,7 @@ const (
 var (
 	ErrKeyFileIncorrectPermission        = errors.New("key file others permissions must be set to 0")
 	ErrTURNSecretFileIncorrectPermission = errors.New("turn secret file others permissions must be set to 0")
-	ErrKeysNotSet                        = errors.New("one of key-file or keys must be provided")
+	ErrKeysNotSet                        = errors.New("one of key-file, keys or key_secrets must be provided")
 )
 
 type Config struct {
@@ -70,11 +71,15 @@ type Config struct {
 	Ingress        IngressConfig            `yaml:"ingress,omitempty"`
 	SIP            SIPConfig                `yaml:"sip,omitempty"`
 	WebHook        webhook.WebHookConfig    `yaml:"webhook,omitempty"`
+	ParticipantRPC ParticipantRPCConfig     `yaml:"participant_rpc,omitempty"`
 	NodeSelector   NodeSelectorConfig       `yaml:"node_selector,omitempty"`
+	Drain          DrainConfig              `yaml:"drain,omitempty"`
 	KeyFile        string                   `yaml:"key_file,omitempty"`
 	Keys           map[string]string        `yaml:"keys,omitempty"`
+	KeySecrets     map[string][]KeySecret   `yaml:"key_secrets,omitempty"`
 	Region         string                   `yaml:"region,omitempty"`
 	SignalRelay    SignalRelayConfig        `yaml:"signal_relay,omitempty"`
+	HTTPSignal     HTTPSignalConfig         `yaml:"http_signal,omitempty"`
 	PSRPC          rpc.PSRPCConfig          `yaml:"psrpc,omitempty"`
 	// Deprecated: LogLevel is deprecated
 	LogLevel string        `yaml:"log_level,omitempty"`
@@ -89,9 +94,19 @@ type Config struct {
 
 	NodeStats NodeStatsConfig `yaml:"node_stats,omitempty"`
 
-	EnableDataTracks bool `yaml:"enable_data_tracks,omitempty"`
+	EnableDataTracks    bool                      `yaml:"enable_data_tracks,omitempty"`
+	DataTrackSubscriber DataTrackSubscriberConfig `yaml:"data_track_subscriber,omitempty"`
+	DataTrackReliable   DataTrackReliableConfig   `yaml:"data_track_reliable,omitempty"`
 
 	API APIConfig `yaml:"api,omitempty"`
+
+	Cascade CascadeConfig `yaml:"cascade,omitempty"`
+
+	PortMux PortMuxConfig `yaml:"port_mux,omitempty"`
+
+	TLS TLSConfig `yaml:"tls,omitempty"`
+
+	Cluster ClusterConfig `yaml:"cluster,omitempty"`
 }
 
 type RTCConfig struct {
@@ -196,6 +211,8 @@ type VideoConfig struct {
 	StreamTrackerManager sfu.StreamTrackerManagerConfig `yaml:"stream_tracker_manager,omitempty"`
 
 	CodecRegressionThreshold int `yaml:"codec_regression_threshold,omitempty"`
+
+	UplinkCongestion uplinkcongestion.UplinkCongestionConfig `yaml:"uplink_congestion,omitempty"`
 }
 
 type RoomConfig struct {
@@ -220,6 +237,165 @@ type RoomConfig struct {
 	// deprecated, moved to limits
 	MaxParticipantIdentityLength int                                   `yaml:"max_participant_identity_length,omitempty"`
 	RoomConfigurations           map[string]*livekit.RoomConfiguration `yaml:"room_configurations,omitempty"`
+	Lobby                        LobbyConfig                           `yaml:"lobby,omitempty"`
+	// topic level permissions for data messages, the first matching rule applies
+	DataTopicACLs []DataTopicACL    `yaml:"data_topic_acls,omitempty"`
+	DataHistory   DataHistoryConfig `yaml:"data_history,omitempty"`
+	// restricts tracks participants with auto subscribe enabled are subscribed to
+	AutoSubscribe AutoSubscribePolicy `yaml:"auto_subscribe,omitempty"`
+	// auto subscribe policies of rooms created with a named room configuration, keyed by room_configurations name
+	AutoSubscribePolicies map[string]AutoSubscribePolicy `yaml:"auto_subscribe_policies,omitempty"`
+	Spatial               SpatialConfig                  `yaml:"spatial,omitempty"`
+	Timeline              TimelineConfig                 `yaml:"timeline,omitempty"`
+	RelayOnly             RelayOnlyConfig                `yaml:"relay_only,omitempty"`
+}
+
+// RelayOnlyConfig hides participant IP addresses from the SFU's peers by connecting participants only through
+// the embedded TURN server. Participants can also opt in with the lk.relay_only attribute in their token
+type RelayOnlyConfig struct {
+	// all participants connect through the embedded TURN server
+	Enabled bool `yaml:"enabled,omitempty"`
+	// rooms created with one of these named room configurations are relay only
+	RoomPresets []string `yaml:"room_presets,omitempty"`
+}
+
+// TimelineConfig keeps recent events of each room, such as joins, ICE state changes, layer switches
+// and congestion, exposed at /debug/rooms/{room}/timeline of the debug handler
+type TimelineConfig struct {
+	// number of events kept per room, a negative value disables the timeline
+	MaxEvents int `yaml:"max_events,omitempty"`
+	// when set, the timeline of a room is written to this directory when the room closes
+	// after a participant had poor or lost connection quality
+	DumpDir string `yaml:"dump_dir,omitempty"`
+}
+
+// SpatialConfig subscribes participants only to participants near them, based on positions reported
+// in the lk.position attribute as "x,y". It cannot be combined with auto subscribe policies.
+type SpatialConfig struct {
+	// participants within these distances subscribe to audio/video tracks of each other,
+	// tracks of a kind are not subscribed to when its radius is 0. Spatial mode is disabled when both are 0
+	AudioRadius float64 `yaml:"audio_radius,omitempty"`
+	VideoRadius float64 `yaml:"video_radius,omitempty"`
+	// subscriptions are kept until participants move further apart than radius + hysteresis
+	Hysteresis float64 `yaml:"hysteresis,omitempty"`
+}
+
+func (s SpatialConfig) IsEnabled() bool {
+	return s.AudioRadius > 0 || s.VideoRadius > 0
+}
+
+const (
+	AutoSubscribeVideoSelectionJoinOrder     = "join_order"
+	AutoSubscribeVideoSelectionActiveSpeaker = "active_speaker"
+)
+
+// AutoSubscribePolicy selects the tracks participants are automatically subscribed to,
+// all tracks are subscribed to when empty
+type AutoSubscribePolicy struct {
+	// only subscribe to tracks of participants having all of these attributes, e.g. role: stage
+	PublisherAttributes map[string]string `yaml:"publisher_attributes,omitempty"`
+	// only subscribe to tracks of these sources: camera, microphone, screen_share, screen_share_audio
+	Sources []string `yaml:"sources,omitempty"`
+	// maximum number of video tracks a participant is subscribed to, 0 for no limit
+	MaxVideoTracks int `yaml:"max_video_tracks,omitempty"`
+	// how video tracks are picked when limited, join_order (default) prefers publishers that joined first,
+	// active_speaker prefers publishers that most recently started speaking
+	VideoSelection string `yaml:"video_selection,omitempty"`
+}
+
+func (a AutoSubscribePolicy) IsEnabled() bool {
+	return len(a.PublisherAttributes) != 0 || len(a.Sources) != 0 || a.MaxVideoTracks > 0
+}
+
+// TrackSources returns the track sources of Sources, it fails on unknown sources
+func (a AutoSubscribePolicy) TrackSources() ([]livekit.TrackSource, error) {
+	sources := make([]livekit.TrackSource, 0, len(a.Sources))
+	for _, source := range a.Sources {
+		s, ok := livekit.TrackSource_value[strings.ToUpper(source)]
+		if !ok || livekit.TrackSource(s) == livekit.TrackSource_UNKNOWN {
+			return nil, fmt.Errorf("unknown track source %q", source)
+		}
+		sources = append(sources, livekit.TrackSource(s))
+	}
+	return sources, nil
+}
+
+func (a AutoSubscribePolicy) Validate() error {
+	if _, err := a.TrackSources(); err != nil {
+		return err
+	}
+	switch a.VideoSelection {
+	case "", AutoSubscribeVideoSelectionJoinOrder, AutoSubscribeVideoSelectionActiveSpeaker:
+	default:
+		return fmt.Errorf("unknown video selection %q", a.VideoSelection)
+	}
+	return nil
+}
+
+// validateAutoSubscribe checks the auto subscribe policies. Participants of spatial rooms are subscribed
+// to each other by position, which cannot be combined with a policy.
+func (r RoomConfig) validateAutoSubscribe() error {
+	if err := r.AutoSubscribe.Validate(); err != nil {
+		return fmt.Errorf("room.auto_subscribe: %w", err)
+	}
+	for preset, policy := range r.AutoSubscribePolicies {
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("room.auto_subscribe_policies.%s: %w", preset, err)
+		}
+	}
+	if r.Spatial.IsEnabled() && (r.AutoSubscribe.IsEnabled() || len(r.AutoSubscribePolicies) != 0) {
+		return errors.New("room.spatial cannot be combined with auto subscribe policies")
+	}
+	return nil
+}
+
+// LobbyConfig holds participants in a pending state until a host admits them into the room
+type LobbyConfig struct {
+	// participants with this attribute in their token grants set to "true" wait in the lobby,
+	// lobby is disabled when empty
+	Attribute string `yaml:"attribute,omitempty"`
+	// participants with this attribute in their token grants set to "true" can admit/reject,
+	// participants with RoomAdmin grant are always considered hosts
+	HostAttribute string `yaml:"host_attribute,omitempty"`
+	// how long a participant can wait in the lobby before being rejected
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+func (l LobbyConfig) IsEnabled() bool {
+	return l.Attribute != ""
+}
+
+// DataHistoryConfig keeps recent reliable data messages on selected topics so that they can be
+// replayed to participants joining later
+type DataHistoryConfig struct {
+	// topics to keep history for, a trailing "*" matches any suffix. History is disabled when empty
+	Topics []string `yaml:"topics,omitempty"`
+	// bounds of the history of a room, oldest messages are evicted first
+	MaxMessages int           `yaml:"max_messages,omitempty"`
+	MaxBytes    int           `yaml:"max_bytes,omitempty"`
+	MaxAge      time.Duration `yaml:"max_age,omitempty"`
+}
+
+func (h DataHistoryConfig) IsEnabled() bool {
+	return len(h.Topics) != 0
+}
+
+// DataTopicACL restricts who can send and receive data messages on matching topics.
+// Topic matches user packet and data stream topics, RPCMethod matches RPC request methods,
+// a trailing "*" matches any suffix. Encrypted matches end-to-end encrypted packets, whose topic
+// cannot be inspected, they are rejected when ACLs are configured without an encrypted rule.
+type DataTopicACL struct {
+	Topic     string              `yaml:"topic,omitempty"`
+	RPCMethod string              `yaml:"rpc_method,omitempty"`
+	Encrypted bool                `yaml:"encrypted,omitempty"`
+	Publish   DataTopicPermission `yaml:"publish,omitempty"`
+	Subscribe DataTopicPermission `yaml:"subscribe,omitempty"`
+}
+
+// DataTopicPermission grants access to participants whose token attributes contain all of Attributes,
+// an empty permission grants access to everyone. Participants with RoomAdmin grant are always allowed.
+type DataTopicPermission struct {
+	Attributes map[string]string `yaml:"attributes,omitempty"`
 }
 
 type CodecSpec struct {
@@ -254,6 +430,36 @@ type TURNConfig struct {
 	// This applies to all peer CIDRs, including restricted ones.
 	// Deny list takes precedence over allow list.
 	DenyPeerCIDRs []string `yaml:"deny_peer_cidrs,omitempty"`
+	// maximum number of concurrent allocations per participant, 0 for no limit
+	MaxAllocationsPerUser int `yaml:"max_allocations_per_user,omitempty"`
+	// maximum number of concurrent allocations across all participants of an API key, 0 for no limit
+	MaxAllocationsPerAPIKey int `yaml:"max_allocations_per_api_key,omitempty"`
+	// maximum relayed bytes per second per participant, shared by all of its allocations.
+	// Packets exceeding the cap are dropped, 0 for no limit
+	RelayBytesPerSecPerUser uint64 `yaml:"relay_bytes_per_sec_per_user,omitempty"`
+	// allocations are closed once they have been open for this long, regardless of refreshes, 0 for no limit
+	MaxAllocationLifetime time.Duration `yaml:"max_allocation_lifetime,omitempty"`
+	// allocations are closed when no data has been relayed for this long, 0 to disable
+	AllocationIdleTimeout time.Duration `yaml:"allocation_idle_timeout,omitempty"`
+}
+
+// KeySecret is one of the secrets of an API key. All secrets inside their validity window are accepted
+// when verifying, the one with the latest not_before is used for signing.
+type KeySecret struct {
+	Secret string `yaml:"secret"`
+	// zero values leave the window open on that side
+	NotBefore time.Time `yaml:"not_before,omitempty"`
+	NotAfter  time.Time `yaml:"not_after,omitempty"`
+}
+
+func (s KeySecret) IsValidAt(t time.Time) bool {
+	if !s.NotBefore.IsZero() && t.Before(s.NotBefore) {
+		return false
+	}
+	if !s.NotAfter.IsZero() && !t.Before(s.NotAfter) {
+		return false
+	}
+	return true
 }
 
 type NodeSelectorConfig struct {
@@ -265,6 +471,18 @@ type NodeSelectorConfig struct {
 	Regions      []RegionConfig `yaml:"regions,omitempty"`
 }
 
+// DrainConfig controls how a node shutting down is drained. By default the node waits for participants
+// to leave, with migrate enabled rooms are moved to other nodes and their participants reconnect there
+type DrainConfig struct {
+	Migrate bool `yaml:"migrate,omitempty"`
+	// number of participants asked to migrate at a time, rooms are always migrated as a whole
+	BatchSize int `yaml:"batch_size,omitempty"`
+	// pause between batches, to let target nodes absorb reconnecting participants
+	BatchInterval time.Duration `yaml:"batch_interval,omitempty"`
+	// how long the preStop handler waits for the drain to complete before returning
+	PreStopTimeout time.Duration `yaml:"prestop_timeout,omitempty"`
+}
+
 type SignalRelayConfig struct {
 	RetryTimeout     time.Duration `yaml:"retry_timeout,omitempty"`
 	MinRetryInterval time.Duration `yaml:"min_retry_interval,omitempty"`
@@ -273,6 +491,131 @@ type SignalRelayConfig struct {
 	ConnectAttempts  int           `yaml:"connect_attempts,omitempty"`
 }
 
+// DataTrackSubscriberConfig limits what is forwarded to each subscriber of a data track
+type DataTrackSubscriberConfig struct {
+	// maximum bitrate forwarded to a subscriber, frames exceeding it are dropped. 0 for no limit
+	MaxBitrate uint64 `yaml:"max_bitrate,omitempty"`
+	// maximum bytes queued for a slow subscriber, queued frames are dropped to catch up with the latest frame when exceeded
+	MaxQueuedBytes int `yaml:"max_queued_bytes,omitempty"`
+}
+
+// DataTrackReliableConfig sizes the retransmission buffer kept for each reliable data track.
+// Publishers opt data tracks into reliable mode with the lk.reliable_data_tracks attribute
+type DataTrackReliableConfig struct {
+	// number of packets kept for retransmission
+	BufferSize int `yaml:"buffer_size,omitempty"`
+	// packets older than this are not retransmitted
+	BufferMaxAge time.Duration `yaml:"buffer_max_age,omitempty"`
+}
+
+// CascadeConfig relays published tracks between nodes hosting participants of the same room,
+// one copy of a track per node
+type CascadeConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// UDP port media is relayed between nodes on, it needs to be reachable from the other nodes of the cluster
+	Port uint32 `yaml:"port,omitempty"`
+	// shared by all nodes of the cluster to authenticate relayed media and subscriptions, required when enabled
+	Secret string `yaml:"secret,omitempty"`
+	// relays are stopped when the subscribing node does not refresh its subscription within this duration
+	SubscriptionTimeout time.Duration `yaml:"subscription_timeout,omitempty"`
+}
+
+// PortMuxConfig serves signalling, ICE/TCP and TURN on a single TCP port, typically 443,
+// for clients behind firewalls only allowing HTTPS
+type PortMuxConfig struct {
+	// port listened on all interfaces, disabled when 0
+	Port uint32 `yaml:"port,omitempty"`
+	// certificate terminating HTTPS signalling on the port, only plain HTTP is served when not set
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+	// TLS connections with these server names are handed to TURN/TLS, defaults to turn.domain
+	TURNServerNames []string `yaml:"turn_server_names,omitempty"`
+	// connections not identified within the timeout are closed
+	SniffTimeout time.Duration `yaml:"sniff_timeout,omitempty"`
+}
+
+// TLSConfig terminates TLS on the HTTP/WebSocket port, with a certificate loaded from files or obtained with ACME.
+// Certificate files are reloaded when changed, without affecting established connections.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+	// client certificates signed by these CAs are required for the server APIs
+	ClientCAFile string     `yaml:"client_ca_file,omitempty"`
+	ACME         ACMEConfig `yaml:"acme,omitempty"`
+	// how often certificate files are checked for changes
+	WatchInterval time.Duration `yaml:"watch_interval,omitempty"`
+}
+
+func (c TLSConfig) IsEnabled() bool {
+	return c.CertFile != "" || c.ACME.Enabled
+}
+
+type ACMEConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// domains certificates are requested for, turn.domain is added when TURN/TLS uses the certificate
+	Domains []string `yaml:"domains,omitempty"`
+	Email   string   `yaml:"email,omitempty"`
+	// directory of the ACME server, defaults to Let's Encrypt
+	DirectoryURL string `yaml:"directory_url,omitempty"`
+	// CA certificates trusted for the ACME server, for private servers
+	CAFile string `yaml:"ca_file,omitempty"`
+	// directory storing the account key and certificates, certificates are requested again on restart when empty
+	CacheDir string `yaml:"cache_dir,omitempty"`
+	// port serving HTTP-01 challenges, only TLS-ALPN-01 challenges are answered, on the TLS port, when 0
+	HTTPPort uint32 `yaml:"http_port,omitempty"`
+}
+
+// ClusterConfig runs several nodes without Redis. Nodes find each other through gossip and replicate
+// room assignments and room state with raft, messages are exchanged directly between nodes.
+// Meant for a few nodes on a private network, exclusive with redis.
+type ClusterConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// address the cluster port is bound to, all interfaces when empty
+	BindAddress string `yaml:"bind_address,omitempty"`
+	// address other nodes reach this node on, defaults to the node IP
+	AdvertiseAddress string `yaml:"advertise_address,omitempty"`
+	// TCP and UDP port used between nodes
+	Port uint32 `yaml:"port,omitempty"`
+	// host:port of nodes contacted to join the cluster, usually every node
+	Seeds []string `yaml:"seeds,omitempty"`
+	// number of nodes expected before forming the cluster, nodes started later join it
+	BootstrapExpect int `yaml:"bootstrap_expect,omitempty"`
+	// shared by all nodes, connections and gossip without it are rejected
+	Secret string `yaml:"secret,omitempty"`
+	// how often membership is exchanged with other nodes
+	GossipInterval time.Duration `yaml:"gossip_interval,omitempty"`
+	// a node not heard from within the timeout is considered dead
+	DeadNodeTimeout time.Duration `yaml:"dead_node_timeout,omitempty"`
+}
+
+// ParticipantRPCConfig forwards RPC requests sent by participants to the server as signed HTTP POST requests
+type ParticipantRPCConfig struct {
+	// backend URL receiving the requests, disabled when empty
+	URL string `yaml:"url,omitempty"`
+	// the API key used to sign requests, defaults to the webhook API key
+	APIKey string `yaml:"api_key,omitempty"`
+	// methods forwarded to the backend, a trailing "*" matches any suffix. All methods when empty
+	Methods []string `yaml:"methods,omitempty"`
+	// maximum duration of a backend request, bounded by the response timeout of the RPC request
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// requests beyond these numbers of pending requests are rejected, unlimited when 0
+	MaxPendingPerParticipant int `yaml:"max_pending_per_participant,omitempty"`
+	MaxPendingPerRoom        int `yaml:"max_pending_per_room,omitempty"`
+}
+
+// HTTPSignalConfig controls the signal transport used by clients which cannot establish a WebSocket,
+// streaming responses with Server-Sent Events or long polling and sending requests with HTTP POST
+type HTTPSignalConfig struct {
+	// disabled by default
+	Enabled bool `yaml:"enabled,omitempty"`
+	// sessions without an active stream or poll are closed after this duration
+	SessionTimeout time.Duration `yaml:"session_timeout,omitempty"`
+	// maximum duration of a single long poll request
+	PollTimeout time.Duration `yaml:"poll_timeout,omitempty"`
+	// number of unacknowledged responses retained to resume a session, older responses are dropped
+	MaxBufferedMessages int `yaml:"max_buffered_messages,omitempty"`
+}
+
 // RegionConfig lists available regions and their latitude/longitude, so the selector would prefer
 // regions that are closer
 type RegionConfig struct {
@@ -284,6 +627,8 @@ type RegionConfig struct {
 type LimitConfig struct {
 	NumTracks              int32   `yaml:"num_tracks,omitempty"`
 	BytesPerSec            float32 `yaml:"bytes_per_sec,omitempty"`
+	NumRooms               int32   `yaml:"num_rooms,omitempty"`
+	NumParticipants        int32   `yaml:"num_participants,omitempty"`
 	SubscriptionLimitVideo int32   `yaml:"subscription_limit_video,omitempty"`
 	SubscriptionLimitAudio int32   `yaml:"subscription_limit_audio,omitempty"`
 	MaxMetadataSize        uint32  `yaml:"max_metadata_size,omitempty"`
@@ -292,6 +637,43 @@ type LimitConfig struct {
 	MaxRoomNameLength            int    `yaml:"max_room_name_length,omitempty"`
 	MaxParticipantIdentityLength int    `yaml:"max_participant_identity_length,omitempty"`
 	MaxParticipantNameLength     int    `yaml:"max_participant_name_length,omitempty"`
+	// quotas applied to each participant within a room
+	ParticipantQuota ParticipantQuotaConfig `yaml:"participant_quota,omitempty"`
+	// quota overrides keyed by room preset, selected by the room_preset grant in the participant's token
+	PresetQuotas map[string]ParticipantQuotaConfig `yaml:"preset_quotas,omitempty"`
+}
+
+// ParticipantQuotaConfig limits the resources a single participant can use, 0 means no limit
+type ParticipantQuotaConfig struct {
+	MaxPublishedTracks int32 `yaml:"max_published_tracks,omitempty"`
+	// total bitrate of published tracks, in bits per second. Declared layer bitrates are checked on publish,
+	// the bitrate received is measured and tracks are unpublished when it stays above the quota
+	MaxPublishBitrate  uint64 `yaml:"max_publish_bitrate,omitempty"`
+	MaxSubscriptions   int32  `yaml:"max_subscriptions,omitempty"`
+	MaxDataBytesPerSec uint64 `yaml:"max_data_bytes_per_sec,omitempty"`
+}
+
+// GetParticipantQuota returns the participant quota, with any limits set for the room preset taking precedence
+func (l LimitConfig) GetParticipantQuota(roomPreset string) ParticipantQuotaConfig {
+	quota := l.ParticipantQuota
+	override, ok := l.PresetQuotas[roomPreset]
+	if roomPreset == "" || !ok {
+		return quota
+	}
+
+	if override.MaxPublishedTracks != 0 {
+		quota.MaxPublishedTracks = override.MaxPublishedTracks
+	}
+	if override.MaxPublishBitrate != 0 {
+		quota.MaxPublishBitrate = override.MaxPublishBitrate
+	}
+	if override.MaxSubscriptions != 0 {
+		quota.MaxSubscriptions = override.MaxSubscriptions
+	}
+	if override.MaxDataBytesPerSec != 0 {
+		quota.MaxDataBytesPerSec = override.MaxDataBytesPerSec
+	}
+	return quota
 }
 
 func (l LimitConfig) CheckRoomNameLength(name string) bool {
@@ -416,6 +798,7 @@ var DefaultConfig = Config{
 		DynacastPauseDelay:       5 * time.Second,
 		StreamTrackerManager:     sfu.DefaultStreamTrackerManagerConfig,
 		CodecRegressionThreshold: 5,
+		UplinkCongestion:         uplinkcongestion.DefaultUplinkCongestionConfig,
 	},
 	Redis: redisLiveKit.RedisConfig{},
 	Room: RoomConfig{
@@ -438,6 +821,17 @@ var DefaultConfig = Config{
 		CreateRoomTimeout:     10 * time.Second,
 		CreateRoomAttempts:    3,
 		UpdateBatchTargetSize: 128 * 1024,
+		Lobby: LobbyConfig{
+			Timeout: 5 * time.Minute,
+		},
+		DataHistory: DataHistoryConfig{
+			MaxMessages: 100,
+			MaxBytes:    256 * 1024,
+			MaxAge:      time.Hour,
+		},
+		Timeline: TimelineConfig{
+			MaxEvents: 1000,
+		},
 	},
 	Limit: LimitConfig{
 		MaxMetadataSize:              64000,
@@ -468,6 +862,16 @@ var DefaultConfig = Config{
 		StreamBufferSize: 1000,
 		ConnectAttempts:  3,
 	},
+	ParticipantRPC: ParticipantRPCConfig{
+		Timeout:                  10 * time.Second,
+		MaxPendingPerParticipant: 8,
+		MaxPendingPerRoom:        128,
+	},
+	HTTPSignal: HTTPSignalConfig{
+		SessionTimeout:      30 * time.Second,
+		PollTimeout:         25 * time.Second,
+		MaxBufferedMessages: 512,
+	},
 	Agents: agent.Config{
 		TargetLoad: agent.DefaultTargetLoad,
 	},
@@ -478,6 +882,34 @@ var DefaultConfig = Config{
 	NodeStats:        DefaultNodeStatsConfig,
 	API:              DefaultAPIConfig(),
 	EnableDataTracks: true,
+	DataTrackSubscriber: DataTrackSubscriberConfig{
+		MaxQueuedBytes: 256 * 1024,
+	},
+	DataTrackReliable: DataTrackReliableConfig{
+		BufferSize:   1024,
+		BufferMaxAge: 5 * time.Second,
+	},
+	Drain: DrainConfig{
+		BatchSize:      50,
+		BatchInterval:  5 * time.Second,
+		PreStopTimeout: 5 * time.Minute,
+	},
+	Cascade: CascadeConfig{
+		Port:                7890,
+		SubscriptionTimeout: 10 * time.Second,
+	},
+	PortMux: PortMuxConfig{
+		SniffTimeout: 5 * time.Second,
+	},
+	TLS: TLSConfig{
+		WatchInterval: 10 * time.Second,
+	},
+	Cluster: ClusterConfig{
+		Port:            7900,
+		BootstrapExpect: 3,
+		GossipInterval:  500 * time.Millisecond,
+		DeadNodeTimeout: 5 * time.Second,
+	},
 }
 
 func NewConfig(confString string, strictMode bool, c *cli.Command, baseFlags []cli.Flag) (*Config, error) {
@@ -511,6 +943,43 @@ func NewConfig(confString string, strictMode bool, c *cli.Command, baseFlags []c
 		return nil, fmt.Errorf("could not validate RTC config: %v", err)
 	}
 
+	if err := conf.Room.validateAutoSubscribe(); err != nil {
+		return nil, err
+	}
+
+	if conf.Cascade.Enabled && conf.Cascade.Secret == "" {
+		return nil, errors.New("cascade.secret is required when cascading is enabled")
+	}
+
+	if (conf.PortMux.CertFile == "") != (conf.PortMux.KeyFile == "") {
+		return nil, errors.New("port_mux.cert_file and port_mux.key_file must be set together")
+	}
+	if (conf.TLS.CertFile == "") != (conf.TLS.KeyFile == "") {
+		return nil, errors.New("tls.cert_file and tls.key_file must be set together")
+	}
+	if conf.TLS.CertFile != "" && conf.TLS.ACME.Enabled {
+		return nil, errors.New("tls.cert_file and tls.acme are exclusive")
+	}
+	if conf.TLS.ACME.Enabled && len(conf.TLS.ACME.Domains) == 0 {
+		return nil, errors.New("tls.acme.domains is required when ACME is enabled")
+	}
+	if conf.TLS.ClientCAFile != "" && !conf.TLS.IsEnabled() {
+		return nil, errors.New("tls.client_ca_file requires a TLS certificate")
+	}
+
+	if conf.Cluster.Enabled {
+		if conf.Redis.IsConfigured() {
+			return nil, errors.New("cluster and redis are exclusive")
+		}
+		if conf.Cluster.Secret == "" {
+			return nil, errors.New("cluster.secret is required when clustering is enabled")
+		}
+	}
+
+	if conf.PortMux.Port != 0 && len(conf.PortMux.TURNServerNames) == 0 && conf.TURN.Domain != "" {
+		conf.PortMux.TURNServerNames = []string{conf.TURN.Domain}
+	}
+
 	// expand env vars in filenames
 	file, err := homedir.Expand(os.ExpandEnv(conf.KeyFile))
 	if err != nil {
@@ -648,20 +1117,46 @@ func (conf *Config) ValidateKeys() error {
 		}
 	}
 
-	if len(conf.Keys) == 0 {
+	if len(conf.Keys) == 0 && len(conf.KeySecrets) == 0 {
 		return ErrKeysNotSet
 	}
 
+	for key, secrets := range conf.KeySecrets {
+		if _, ok := conf.Keys[key]; ok {
+			return fmt.Errorf("api key %s is set in both keys and key_secrets", key)
+		}
+		if len(secrets) == 0 {
+			return fmt.Errorf("api key %s has no secrets", key)
+		}
+		for _, s := range secrets {
+			if s.Secret == "" {
+				return fmt.Errorf("api key %s has an empty secret", key)
+			}
+			if !s.NotBefore.IsZero() && !s.NotAfter.IsZero() && !s.NotAfter.After(s.NotBefore) {
+				return fmt.Errorf("api key %s has a secret with not_after before not_before", key)
+			}
+		}
+	}
+
 	if !conf.Development {
 		for key, secret := range conf.Keys {
-			if len(secret) < 32 {
-				logger.Errorw("secret is too short, should be at least 32 characters for security", nil, "apiKey", key)
+			checkSecretLength(key, secret)
+		}
+		for key, secrets := range conf.KeySecrets {
+			for _, s := range secrets {
+				checkSecretLength(key, s.Secret)
 			}
 		}
 	}
 	return nil
 }
 
+func checkSecretLength(key string, secret string) {
+	if len(secret) < 32 {
+		logger.Errorw("secret is too short, should be at least 32 characters for security", nil, "apiKey", key)
+	}
+}
+
 func (conf *Config) LoadTURNSecrets() error {
 	var otherFilter os.FileMode = 0o007
 	for i, s := range conf.RTC.TURNServers {
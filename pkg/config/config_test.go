@@ -48,6 +48,26 @@ room:
 	require.Error(t, err)
 }
 
+func TestConfig_AutoSubscribePolicies(t *testing.T) {
+	_, err := NewConfig(`room:
+  auto_subscribe:
+    sources: [camera, microphone]`, true, nil, nil)
+	require.NoError(t, err)
+
+	_, err = NewConfig(`room:
+  auto_subscribe_policies:
+    stage:
+      sources: [camra]`, true, nil, nil)
+	require.Error(t, err)
+
+	_, err = NewConfig(`room:
+  spatial:
+    audio_radius: 10
+  auto_subscribe:
+    max_video_tracks: 4`, true, nil, nil)
+	require.Error(t, err)
+}
+
 func TestGeneratedFlags(t *testing.T) {
 	generatedFlags, err := GenerateCLIFlags(nil, false)
 	require.NoError(t, err)
@@ -83,3 +103,44 @@ func TestGeneratedFlags(t *testing.T) {
 func TestYAMLTag(t *testing.T) {
 	require.NoError(t, configtest.CheckYAMLTags(Config{}))
 }
+
+func TestConfig_Diff(t *testing.T) {
+	const content = `port: 7880
+limit:
+  num_tracks: 10
+turn:
+  enabled: true
+  deny_peer_cidrs:
+    - 10.0.0.0/8
+`
+	conf, err := NewConfig(content, true, nil, nil)
+	require.NoError(t, err)
+
+	t.Run("no changes", func(t *testing.T) {
+		next, err := NewConfig(content, true, nil, nil)
+		require.NoError(t, err)
+		require.True(t, conf.Diff(next).IsEmpty())
+	})
+
+	t.Run("reloadable and restart sections", func(t *testing.T) {
+		next, err := NewConfig(`port: 7881
+limit:
+  num_tracks: 20
+turn:
+  enabled: true
+  deny_peer_cidrs:
+    - 192.168.0.0/16
+`, true, nil, nil)
+		require.NoError(t, err)
+
+		diff := conf.Diff(next)
+		require.ElementsMatch(t, []string{"limit", "turn.deny_peer_cidrs"}, diff.Reloadable)
+		require.ElementsMatch(t, []string{"port"}, diff.RequiresRestart)
+
+		merged := conf.WithReloadable(next)
+		require.Equal(t, int32(20), merged.Limit.NumTracks)
+		require.Equal(t, []string{"192.168.0.0/16"}, merged.TURN.DenyPeerCIDRs)
+		require.Equal(t, uint32(7880), merged.Port)
+		require.Equal(t, int32(10), conf.Limit.NumTracks)
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// config sections that can be applied to a running server, keyed by yaml path
+var reloadableSections = map[string]bool{
+	"limit":                    true,
+	"room.room_configurations": true,
+	"node_selector":            true,
+	"turn.deny_peer_cidrs":     true,
+	"webhook":                  true,
+	"key_file":                 true,
+	"keys":                     true,
+	"key_secrets":              true,
+	"node_stats":               true,
+	// the logger is initialized again once the reload is applied
+	"logging": true,
+}
+
+// sections that are diffed field by field as only some of their fields are reloadable
+var partiallyReloadableSections = map[string]bool{
+	"room": true,
+	"turn": true,
+}
+
+type ConfigDiff struct {
+	// changed sections which are applied without a restart
+	Reloadable []string
+	// changed sections which take effect only after a restart
+	RequiresRestart []string
+}
+
+func (d ConfigDiff) IsEmpty() bool {
+	return len(d.Reloadable) == 0 && len(d.RequiresRestart) == 0
+}
+
+// Diff compares two configs section by section
+func (conf *Config) Diff(next *Config) ConfigDiff {
+	var diff ConfigDiff
+	diffFields(reflect.ValueOf(conf).Elem(), reflect.ValueOf(next).Elem(), "", &diff)
+	return diff
+}
+
+// WithReloadable returns a copy of conf with the reloadable sections taken from next,
+// all other sections keep their current values
+func (conf *Config) WithReloadable(next *Config) *Config {
+	merged := *conf
+	merged.Limit = next.Limit
+	merged.Room.RoomConfigurations = next.Room.RoomConfigurations
+	merged.NodeSelector = next.NodeSelector
+	merged.TURN.DenyPeerCIDRs = next.TURN.DenyPeerCIDRs
+	merged.WebHook = next.WebHook
+	merged.KeyFile = next.KeyFile
+	merged.Keys = next.Keys
+	merged.KeySecrets = next.KeySecrets
+	merged.NodeStats = next.NodeStats
+	merged.Logging = next.Logging
+	return &merged
+}
+
+func diffFields(cur, next reflect.Value, prefix string, diff *ConfigDiff) {
+	t := cur.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		if partiallyReloadableSections[path] {
+			diffFields(cur.Field(i), next.Field(i), path, diff)
+			continue
+		}
+
+		if reflect.DeepEqual(cur.Field(i).Interface(), next.Field(i).Interface()) {
+			continue
+		}
+		if reloadableSections[path] {
+			diff.Reloadable = append(diff.Reloadable, path)
+		} else {
+			diff.RequiresRestart = append(diff.RequiresRestart, path)
+		}
+	}
+}
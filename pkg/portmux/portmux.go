@@ -0,0 +1,356 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package portmux serves several protocols on a single TCP port. The first bytes of each connection are
+// inspected to tell HTTP, TLS, STUN framed for ICE-TCP and plain TURN apart, TLS connections are further
+// split into HTTPS and TURN/TLS by the ALPN protocols and server name of the ClientHello.
+package portmux
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/logger"
+)
+
+type Protocol int
+
+const (
+	ProtocolHTTP Protocol = iota
+	// TLS connections not meant for TURN
+	ProtocolHTTPS
+	// STUN messages framed with a 2 byte length as defined by RFC 4571
+	ProtocolICETCP
+	// unframed STUN messages, TURN over TCP
+	ProtocolTURN
+	// TLS connections negotiating a TURN ALPN protocol, or using one of the TURN server names
+	ProtocolTURNTLS
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolHTTP:
+		return "http"
+	case ProtocolHTTPS:
+		return "https"
+	case ProtocolICETCP:
+		return "ice-tcp"
+	case ProtocolTURN:
+		return "turn"
+	case ProtocolTURNTLS:
+		return "turn-tls"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	DefaultSniffTimeout = 5 * time.Second
+
+	stunMagicCookie = 0x2112A442
+
+	// the longest prefix needed to identify a protocol, 2 bytes of RFC 4571 framing followed by
+	// the STUN message type, length and magic cookie
+	sniffLength = 10
+)
+
+// ALPN protocol IDs for TURN and STUN over TLS, RFC 7443
+var turnALPNProtocols = []string{"stun.turn", "stun.nat-discovery"}
+
+var (
+	ErrProtocolNotServed = errors.New("protocol not served")
+
+	errClientHelloRead = errors.New("client hello read")
+)
+
+type Params struct {
+	Listener net.Listener
+	// TLS connections with these server names are dispatched to TURN
+	TURNServerNames []string
+	// connections not identified within the timeout are closed
+	SniffTimeout time.Duration
+	Logger       logger.Logger
+}
+
+// Mux accepts connections from the listener and hands each one to the listener of its protocol
+type Mux struct {
+	params Params
+
+	lock      sync.Mutex
+	listeners map[Protocol]*muxListener
+	isStarted bool
+	closed    chan struct{}
+}
+
+func New(params Params) *Mux {
+	if params.SniffTimeout == 0 {
+		params.SniffTimeout = DefaultSniffTimeout
+	}
+	if params.Logger == nil {
+		params.Logger = logger.GetLogger()
+	}
+	return &Mux{
+		params:    params,
+		listeners: make(map[Protocol]*muxListener),
+		closed:    make(chan struct{}),
+	}
+}
+
+// Listener returns the listener connections of the protocol are accepted from.
+// Connections of protocols without a listener are closed.
+func (m *Mux) Listener(protocol Protocol) net.Listener {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if l, ok := m.listeners[protocol]; ok {
+		return l
+	}
+	l := &muxListener{
+		mux:      m,
+		protocol: protocol,
+		conns:    make(chan net.Conn),
+		closed:   make(chan struct{}),
+	}
+	m.listeners[protocol] = l
+	return l
+}
+
+func (m *Mux) Addr() net.Addr {
+	return m.params.Listener.Addr()
+}
+
+// Start accepts connections till the mux is closed
+func (m *Mux) Start() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.isStarted {
+		return
+	}
+	m.isStarted = true
+	go m.acceptWorker()
+}
+
+func (m *Mux) Close() error {
+	m.lock.Lock()
+	select {
+	case <-m.closed:
+		m.lock.Unlock()
+		return nil
+	default:
+	}
+	close(m.closed)
+	m.lock.Unlock()
+
+	return m.params.Listener.Close()
+}
+
+func (m *Mux) acceptWorker() {
+	for {
+		conn, err := m.params.Listener.Accept()
+		if err != nil {
+			select {
+			case <-m.closed:
+				return
+			default:
+			}
+
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			m.params.Logger.Warnw("port mux accept failed", err)
+			_ = m.Close()
+			return
+		}
+
+		go m.dispatch(conn)
+	}
+}
+
+func (m *Mux) dispatch(conn net.Conn) {
+	protocol, sniffed, err := m.sniff(conn)
+	if err != nil {
+		m.params.Logger.Debugw("could not identify protocol", err, "remote", conn.RemoteAddr())
+		_ = conn.Close()
+		return
+	}
+
+	m.lock.Lock()
+	l := m.listeners[protocol]
+	m.lock.Unlock()
+	if l == nil {
+		m.params.Logger.Debugw("protocol not served", nil, "protocol", protocol, "remote", conn.RemoteAddr())
+		_ = conn.Close()
+		return
+	}
+
+	if err := l.deliver(sniffed); err != nil {
+		_ = conn.Close()
+	}
+}
+
+// sniff identifies the protocol, the returned connection replays the bytes read while doing so
+func (m *Mux) sniff(conn net.Conn) (Protocol, net.Conn, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(m.params.SniffTimeout)); err != nil {
+		return 0, nil, err
+	}
+
+	var buf bytes.Buffer
+	r := io.TeeReader(conn, &buf)
+	head := make([]byte, sniffLength)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return 0, nil, err
+	}
+
+	protocol := identify(head)
+	if protocol == ProtocolHTTPS {
+		hello, err := readClientHello(io.MultiReader(bytes.NewReader(head), r))
+		if err != nil {
+			return 0, nil, err
+		}
+		if m.isTURNClientHello(hello) {
+			protocol = ProtocolTURNTLS
+		}
+	}
+
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		return 0, nil, err
+	}
+	return protocol, &sniffedConn{Conn: conn, buffered: buf.Bytes()}, nil
+}
+
+func (m *Mux) isTURNClientHello(hello *tls.ClientHelloInfo) bool {
+	for _, proto := range hello.SupportedProtos {
+		if slices.Contains(turnALPNProtocols, proto) {
+			return true
+		}
+	}
+	return hello.ServerName != "" && slices.Contains(m.params.TURNServerNames, hello.ServerName)
+}
+
+func identify(head []byte) Protocol {
+	// TLS handshake record
+	if head[0] == 0x16 && head[1] == 0x03 {
+		return ProtocolHTTPS
+	}
+	// STUN message types have the two most significant bits unset and carry the magic cookie
+	if head[0]&0xc0 == 0 && binary.BigEndian.Uint32(head[4:8]) == stunMagicCookie {
+		return ProtocolTURN
+	}
+	if head[2]&0xc0 == 0 && binary.BigEndian.Uint32(head[6:10]) == stunMagicCookie {
+		return ProtocolICETCP
+	}
+	return ProtocolHTTP
+}
+
+// readClientHello parses the ClientHello using the handshake of crypto/tls which is stopped once it has been read
+func readClientHello(r io.Reader) (*tls.ClientHelloInfo, error) {
+	var hello *tls.ClientHelloInfo
+	err := tls.Server(&readOnlyConn{reader: r}, &tls.Config{
+		GetConfigForClient: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			hello = &tls.ClientHelloInfo{
+				ServerName:      info.ServerName,
+				SupportedProtos: slices.Clone(info.SupportedProtos),
+			}
+			return nil, errClientHelloRead
+		},
+	}).Handshake()
+	if hello == nil {
+		return nil, err
+	}
+	return hello, nil
+}
+
+// ------------------------------------------------
+
+type muxListener struct {
+	mux      *Mux
+	protocol Protocol
+	conns    chan net.Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (l *muxListener) deliver(conn net.Conn) error {
+	select {
+	case l.conns <- conn:
+		return nil
+	case <-l.closed:
+		return ErrProtocolNotServed
+	case <-l.mux.closed:
+		return net.ErrClosed
+	}
+}
+
+func (l *muxListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	case <-l.mux.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *muxListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+	})
+	return nil
+}
+
+func (l *muxListener) Addr() net.Addr {
+	return l.mux.Addr()
+}
+
+// ------------------------------------------------
+
+type sniffedConn struct {
+	net.Conn
+	buffered []byte
+}
+
+func (c *sniffedConn) Read(b []byte) (int, error) {
+	if len(c.buffered) != 0 {
+		n := copy(b, c.buffered)
+		c.buffered = c.buffered[n:]
+		return n, nil
+	}
+	return c.Conn.Read(b)
+}
+
+// ------------------------------------------------
+
+type readOnlyConn struct {
+	reader io.Reader
+}
+
+func (c *readOnlyConn) Read(b []byte) (int, error)         { return c.reader.Read(b) }
+func (c *readOnlyConn) Write(b []byte) (int, error)        { return 0, io.ErrClosedPipe }
+func (c *readOnlyConn) Close() error                       { return nil }
+func (c *readOnlyConn) LocalAddr() net.Addr                { return nil }
+func (c *readOnlyConn) RemoteAddr() net.Addr               { return nil }
+func (c *readOnlyConn) SetDeadline(_ time.Time) error      { return nil }
+func (c *readOnlyConn) SetReadDeadline(_ time.Time) error  { return nil }
+func (c *readOnlyConn) SetWriteDeadline(_ time.Time) error { return nil }
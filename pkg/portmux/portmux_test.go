@@ -0,0 +1,222 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portmux
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMux(t *testing.T) *Mux {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	m := New(Params{
+		Listener:        ln,
+		TURNServerNames: []string{"turn.example.com"},
+		SniffTimeout:    time.Second,
+	})
+	t.Cleanup(func() {
+		_ = m.Close()
+	})
+	return m
+}
+
+func stunBindingRequest() []byte {
+	msg := make([]byte, 20)
+	binary.BigEndian.PutUint16(msg[0:2], 0x0001)
+	binary.BigEndian.PutUint32(msg[4:8], stunMagicCookie)
+	copy(msg[8:], "transaction1")
+	return msg
+}
+
+func acceptOne(t *testing.T, l net.Listener) <-chan net.Conn {
+	ch := make(chan net.Conn, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			ch <- conn
+		}
+	}()
+	return ch
+}
+
+func requireConn(t *testing.T, ch <-chan net.Conn) net.Conn {
+	select {
+	case conn := <-ch:
+		return conn
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "connection not dispatched")
+		return nil
+	}
+}
+
+func TestMux_HTTP(t *testing.T) {
+	m := newTestMux(t)
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}),
+	}
+	go server.Serve(m.Listener(ProtocolHTTP))
+	defer server.Close()
+	m.Start()
+
+	res, err := http.Get("http://" + m.Addr().String() + "/")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "ok", string(body))
+}
+
+func TestMux_STUN(t *testing.T) {
+	m := newTestMux(t)
+	iceTCP := acceptOne(t, m.Listener(ProtocolICETCP))
+	turn := acceptOne(t, m.Listener(ProtocolTURN))
+	m.Start()
+
+	t.Run("framed", func(t *testing.T) {
+		msg := stunBindingRequest()
+		framed := binary.BigEndian.AppendUint16(nil, uint16(len(msg)))
+		framed = append(framed, msg...)
+
+		client, err := net.Dial("tcp", m.Addr().String())
+		require.NoError(t, err)
+		defer client.Close()
+		_, err = client.Write(framed)
+		require.NoError(t, err)
+
+		conn := requireConn(t, iceTCP)
+		defer conn.Close()
+		read := make([]byte, len(framed))
+		_, err = io.ReadFull(conn, read)
+		require.NoError(t, err)
+		require.Equal(t, framed, read)
+	})
+
+	t.Run("unframed", func(t *testing.T) {
+		msg := stunBindingRequest()
+
+		client, err := net.Dial("tcp", m.Addr().String())
+		require.NoError(t, err)
+		defer client.Close()
+		_, err = client.Write(msg)
+		require.NoError(t, err)
+
+		conn := requireConn(t, turn)
+		defer conn.Close()
+		read := make([]byte, len(msg))
+		_, err = io.ReadFull(conn, read)
+		require.NoError(t, err)
+		require.Equal(t, msg, read)
+	})
+}
+
+func TestMux_TLS(t *testing.T) {
+	cert := newTestCertificate(t)
+	serverConf := &tls.Config{Certificates: []tls.Certificate{cert}, NextProtos: []string{"h2", "stun.turn"}}
+
+	m := newTestMux(t)
+	https := m.Listener(ProtocolHTTPS)
+	turnTLS := m.Listener(ProtocolTURNTLS)
+	m.Start()
+
+	dial := func(serverName string, nextProtos []string) {
+		go func() {
+			conn, err := tls.Dial("tcp", m.Addr().String(), &tls.Config{
+				ServerName:         serverName,
+				NextProtos:         nextProtos,
+				InsecureSkipVerify: true,
+			})
+			if err == nil {
+				_, _ = conn.Write([]byte("ping"))
+				_ = conn.Close()
+			}
+		}()
+	}
+	expect := func(l net.Listener) {
+		conn := requireConn(t, acceptOne(t, l))
+		defer conn.Close()
+
+		// handshake completes on the dispatched connection
+		tlsConn := tls.Server(conn, serverConf)
+		require.NoError(t, tlsConn.Handshake())
+		read := make([]byte, 4)
+		_, err := io.ReadFull(tlsConn, read)
+		require.NoError(t, err)
+		require.Equal(t, "ping", string(read))
+	}
+
+	dial("livekit.example.com", []string{"h2", "http/1.1"})
+	expect(https)
+
+	dial("livekit.example.com", []string{"stun.turn"})
+	expect(turnTLS)
+
+	dial("turn.example.com", nil)
+	expect(turnTLS)
+}
+
+func TestMux_ProtocolNotServed(t *testing.T) {
+	m := newTestMux(t)
+	m.Start()
+
+	client, err := net.Dial("tcp", m.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	_, err = client.Write(stunBindingRequest())
+	require.NoError(t, err)
+
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(2*time.Second)))
+	// closed by the mux, either cleanly or with a reset since the sniffed bytes were not consumed
+	_, err = client.Read(make([]byte, 1))
+	require.Error(t, err)
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		require.False(t, netErr.Timeout())
+	}
+}
+
+func newTestCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "livekit.example.com"},
+		DNSNames:     []string{"livekit.example.com", "turn.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
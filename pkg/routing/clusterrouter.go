@@ -0,0 +1,218 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routing
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/atomic"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/cluster"
+)
+
+var (
+	_ Router        = (*ClusterRouter)(nil)
+	_ HealthChecker = (*ClusterRouter)(nil)
+)
+
+// ClusterRouter routes across the nodes of an embedded cluster. Nodes are registered through the
+// cluster membership and room assignments are kept in its replicated store.
+type ClusterRouter struct {
+	*LocalRouter
+
+	cluster   *cluster.Cluster
+	ctx       context.Context
+	isStarted atomic.Bool
+
+	cancel func()
+}
+
+func NewClusterRouter(lr *LocalRouter, c *cluster.Cluster) *ClusterRouter {
+	cr := &ClusterRouter{
+		LocalRouter: lr,
+		cluster:     c,
+	}
+	cr.ctx, cr.cancel = context.WithCancel(context.Background())
+	return cr
+}
+
+func (r *ClusterRouter) RegisterNode() error {
+	data, err := proto.Marshal(r.currentNode.Clone())
+	if err != nil {
+		return err
+	}
+	r.cluster.SetLocalMeta(data)
+	return nil
+}
+
+func (r *ClusterRouter) UnregisterNode() error {
+	r.cluster.SetLocalMeta(nil)
+	return nil
+}
+
+// RemoveDeadNodes is a no-op, members that stop gossiping are dropped by the cluster
+func (r *ClusterRouter) RemoveDeadNodes() error {
+	return nil
+}
+
+// GetNodeForRoom finds the node where the room is hosted at
+func (r *ClusterRouter) GetNodeForRoom(_ context.Context, roomName livekit.RoomName) (*livekit.Node, error) {
+	nodeID, ok := r.cluster.Store().HGet(NodeRoomKey, string(roomName))
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return r.GetNode(livekit.NodeID(nodeID))
+}
+
+func (r *ClusterRouter) SetNodeForRoom(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error {
+	_, err := r.cluster.Store().Apply(ctx, cluster.HSet(NodeRoomKey, string(roomName), []byte(nodeID)))
+	return err
+}
+
+func (r *ClusterRouter) ClearRoomState(_ context.Context, roomName livekit.RoomName) error {
+	if _, err := r.cluster.Store().Apply(
+		context.Background(),
+		cluster.HDel(NodeRoomKey, string(roomName)),
+		cluster.Del(RoomCascadeNodesKeyPrefix+string(roomName)),
+	); err != nil {
+		return errors.Wrap(err, "could not clear room state")
+	}
+	return nil
+}
+
+func (r *ClusterRouter) GetCascadeNodesForRoom(_ context.Context, roomName livekit.RoomName) ([]livekit.NodeID, error) {
+	fields := r.cluster.Store().HGetAll(RoomCascadeNodesKeyPrefix + string(roomName))
+	nodeIDs := make([]livekit.NodeID, 0, len(fields))
+	for id := range fields {
+		nodeIDs = append(nodeIDs, livekit.NodeID(id))
+	}
+	return nodeIDs, nil
+}
+
+func (r *ClusterRouter) AddCascadeNodeForRoom(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error {
+	_, err := r.cluster.Store().Apply(ctx, cluster.HSet(RoomCascadeNodesKeyPrefix+string(roomName), string(nodeID), nil))
+	return err
+}
+
+func (r *ClusterRouter) GetNode(nodeID livekit.NodeID) (*livekit.Node, error) {
+	for _, m := range r.cluster.Members() {
+		if m.ID == string(nodeID) && len(m.Meta) != 0 {
+			n := livekit.Node{}
+			if err := proto.Unmarshal(m.Meta, &n); err != nil {
+				return nil, err
+			}
+			return &n, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// ListNodes returns the registered nodes among the alive members
+func (r *ClusterRouter) ListNodes() ([]*livekit.Node, error) {
+	members := r.cluster.Members()
+	nodes := make([]*livekit.Node, 0, len(members))
+	for _, m := range members {
+		if len(m.Meta) == 0 {
+			continue
+		}
+		n := livekit.Node{}
+		if err := proto.Unmarshal(m.Meta, &n); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, &n)
+	}
+	return nodes, nil
+}
+
+func (r *ClusterRouter) CreateRoom(ctx context.Context, req *livekit.CreateRoomRequest) (res *livekit.Room, err error) {
+	rtcNode, err := r.GetNodeForRoom(ctx, livekit.RoomName(req.Name))
+	if err != nil {
+		return
+	}
+
+	return r.CreateRoomWithNodeID(ctx, req, livekit.NodeID(rtcNode.Id))
+}
+
+// StartParticipantSignal signal connection sets up paths to the RTC node, and starts to route messages to that message queue
+func (r *ClusterRouter) StartParticipantSignal(ctx context.Context, roomName livekit.RoomName, pi ParticipantInit) (res StartParticipantSignalResults, err error) {
+	if pi.NodeID != "" {
+		return r.StartParticipantSignalWithNodeID(ctx, roomName, pi, pi.NodeID)
+	}
+
+	rtcNode, err := r.GetNodeForRoom(ctx, roomName)
+	if err != nil {
+		return
+	}
+
+	return r.StartParticipantSignalWithNodeID(ctx, roomName, pi, livekit.NodeID(rtcNode.Id))
+}
+
+func (r *ClusterRouter) Start() error {
+	if r.isStarted.Swap(true) {
+		return nil
+	}
+
+	go r.statsWorker()
+	return nil
+}
+
+func (r *ClusterRouter) Drain() {
+	r.currentNode.SetState(livekit.NodeState_SHUTTING_DOWN)
+	if err := r.RegisterNode(); err != nil {
+		logger.Errorw("failed to mark as draining", err, "nodeID", r.currentNode.NodeID())
+	}
+}
+
+// CheckHealth reports whether the cluster has a leader, writes to the store cannot be applied otherwise
+func (r *ClusterRouter) CheckHealth(_ context.Context) error {
+	if r.cluster.Leader() == "" {
+		return cluster.ErrNoLeader
+	}
+	return nil
+}
+
+// Stop unregisters the node, the cluster is left once the server has closed its rooms
+func (r *ClusterRouter) Stop() {
+	if !r.isStarted.Swap(false) {
+		return
+	}
+	logger.Debugw("stopping ClusterRouter")
+	_ = r.UnregisterNode()
+	r.cancel()
+}
+
+// update node stats, membership gossip spreads them to the other nodes
+func (r *ClusterRouter) statsWorker() {
+	for r.ctx.Err() == nil {
+		select {
+		case <-time.After(r.nodeStatsConfig.StatsUpdateInterval):
+			if !r.currentNode.UpdateNodeStats() {
+				continue
+			}
+			if err := r.RegisterNode(); err != nil {
+				logger.Errorw("could not update node", err)
+			}
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/livekit/livekit-server/pkg/cluster"
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/protocol/auth"
@@ -136,6 +137,10 @@ type Router interface {
 	SetNodeForRoom(ctx context.Context, roomName livekit.RoomName, nodeId livekit.NodeID) error
 	ClearRoomState(ctx context.Context, roomName livekit.RoomName) error
 
+	// nodes hosting participants of a room besides the node of the room, when the room is cascaded
+	GetCascadeNodesForRoom(ctx context.Context, roomName livekit.RoomName) ([]livekit.NodeID, error)
+	AddCascadeNodeForRoom(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error
+
 	GetRegion() string
 
 	Start() error
@@ -143,6 +148,11 @@ type Router interface {
 	Stop()
 }
 
+// HealthChecker is implemented by routers relying on an external bus, to report whether it can be reached
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
 type StartParticipantSignalResults struct {
 	ConnectionID        livekit.ConnectionID
 	RequestSink         MessageSink
@@ -165,6 +175,7 @@ type MessageRouter interface {
 
 func CreateRouter(
 	rc redis.UniversalClient,
+	c *cluster.Cluster,
 	node LocalNode,
 	signalClient SignalClient,
 	roomManagerClient RoomManagerClient,
@@ -177,6 +188,11 @@ func CreateRouter(
 		return NewRedisRouter(lr, rc, kps)
 	}
 
+	if c != nil {
+		logger.Infow("using cluster routing")
+		return NewClusterRouter(lr, c)
+	}
+
 	// local routing and store
 	logger.Infow("using single-node routing")
 	return lr
@@ -204,6 +220,8 @@ type ParticipantInit struct {
 	PublisherOffer          *livekit.SessionDescription
 	SyncState               *livekit.SyncState
 	UseSinglePeerConnection bool
+	// node to start the session on instead of the node of the room, set when the room is cascaded to it
+	NodeID livekit.NodeID
 }
 
 func (pi *ParticipantInit) MarshalLogObject(e zapcore.ObjectEncoder) error {
@@ -68,6 +68,15 @@ func (r *LocalRouter) ClearRoomState(_ context.Context, _ livekit.RoomName) erro
 	return nil
 }
 
+// GetCascadeNodesForRoom returns no nodes, rooms are not cascaded without other nodes
+func (r *LocalRouter) GetCascadeNodesForRoom(_ context.Context, _ livekit.RoomName) ([]livekit.NodeID, error) {
+	return nil, nil
+}
+
+func (r *LocalRouter) AddCascadeNodeForRoom(_ context.Context, _ livekit.RoomName, _ livekit.NodeID) error {
+	return nil
+}
+
 func (r *LocalRouter) RegisterNode() error {
 	return nil
 }
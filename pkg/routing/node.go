@@ -36,6 +36,7 @@ type LocalNode interface {
 	SetState(state livekit.NodeState)
 	SetStats(stats *livekit.NodeStats)
 	UpdateNodeStats() bool
+	UpdateNodeStatsConfig(conf *config.NodeStatsConfig)
 	SecondsSinceNodeStatsUpdate() float64
 }
 
@@ -150,6 +151,16 @@ func (l *LocalNodeImpl) UpdateNodeStats() bool {
 	return true
 }
 
+func (l *LocalNodeImpl) UpdateNodeStatsConfig(conf *config.NodeStatsConfig) {
+	l.lock.RLock()
+	nodeStats := l.nodeStats
+	l.lock.RUnlock()
+
+	if nodeStats != nil {
+		nodeStats.UpdateConfig(conf)
+	}
+}
+
 func (l *LocalNodeImpl) SecondsSinceNodeStatsUpdate() float64 {
 	l.lock.RLock()
 	defer l.lock.RUnlock()
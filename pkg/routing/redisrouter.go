@@ -38,9 +38,15 @@ const (
 
 	// hash of room_name => node_id
 	NodeRoomKey = "room_node_map"
+
+	// set of node_id the room is cascaded to, suffixed by room_name
+	RoomCascadeNodesKeyPrefix = "room_cascade_nodes:"
 )
 
-var _ Router = (*RedisRouter)(nil)
+var (
+	_ Router        = (*RedisRouter)(nil)
+	_ HealthChecker = (*RedisRouter)(nil)
+)
 
 // RedisRouter uses Redis pub/sub to route signaling messages across different nodes
 // It relies on the RTC node to be the primary driver of the participant connection.
@@ -117,9 +123,29 @@ func (r *RedisRouter) ClearRoomState(_ context.Context, roomName livekit.RoomNam
 	if err := r.rc.HDel(context.Background(), NodeRoomKey, string(roomName)).Err(); err != nil {
 		return errors.Wrap(err, "could not clear room state")
 	}
+	if err := r.rc.Del(context.Background(), RoomCascadeNodesKeyPrefix+string(roomName)).Err(); err != nil {
+		return errors.Wrap(err, "could not clear room state")
+	}
 	return nil
 }
 
+func (r *RedisRouter) GetCascadeNodesForRoom(_ context.Context, roomName livekit.RoomName) ([]livekit.NodeID, error) {
+	ids, err := r.rc.SMembers(r.ctx, RoomCascadeNodesKeyPrefix+string(roomName)).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get cascade nodes for room")
+	}
+
+	nodeIDs := make([]livekit.NodeID, 0, len(ids))
+	for _, id := range ids {
+		nodeIDs = append(nodeIDs, livekit.NodeID(id))
+	}
+	return nodeIDs, nil
+}
+
+func (r *RedisRouter) AddCascadeNodeForRoom(_ context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error {
+	return r.rc.SAdd(r.ctx, RoomCascadeNodesKeyPrefix+string(roomName), string(nodeID)).Err()
+}
+
 func (r *RedisRouter) GetNode(nodeID livekit.NodeID) (*livekit.Node, error) {
 	data, err := r.rc.HGet(r.ctx, NodesKey, string(nodeID)).Result()
 	if err == redis.Nil {
@@ -161,6 +187,10 @@ func (r *RedisRouter) CreateRoom(ctx context.Context, req *livekit.CreateRoomReq
 
 // StartParticipantSignal signal connection sets up paths to the RTC node, and starts to route messages to that message queue
 func (r *RedisRouter) StartParticipantSignal(ctx context.Context, roomName livekit.RoomName, pi ParticipantInit) (res StartParticipantSignalResults, err error) {
+	if pi.NodeID != "" {
+		return r.StartParticipantSignalWithNodeID(ctx, roomName, pi, pi.NodeID)
+	}
+
 	rtcNode, err := r.GetNodeForRoom(ctx, roomName)
 	if err != nil {
 		return
@@ -189,6 +219,14 @@ func (r *RedisRouter) Drain() {
 	}
 }
 
+// CheckHealth pings Redis, which carries both the node registry and the psrpc bus
+func (r *RedisRouter) CheckHealth(ctx context.Context) error {
+	if err := r.rc.Ping(ctx).Err(); err != nil {
+		return errors.Wrap(err, "could not reach redis")
+	}
+	return nil
+}
+
 func (r *RedisRouter) Stop() {
 	if !r.isStarted.Swap(false) {
 		return
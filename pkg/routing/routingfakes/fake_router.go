@@ -10,6 +10,19 @@ import (
 )
 
 type FakeRouter struct {
+	AddCascadeNodeForRoomStub        func(context.Context, livekit.RoomName, livekit.NodeID) error
+	addCascadeNodeForRoomMutex       sync.RWMutex
+	addCascadeNodeForRoomArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.NodeID
+	}
+	addCascadeNodeForRoomReturns struct {
+		result1 error
+	}
+	addCascadeNodeForRoomReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ClearRoomStateStub        func(context.Context, livekit.RoomName) error
 	clearRoomStateMutex       sync.RWMutex
 	clearRoomStateArgsForCall []struct {
@@ -40,6 +53,20 @@ type FakeRouter struct {
 	drainMutex       sync.RWMutex
 	drainArgsForCall []struct {
 	}
+	GetCascadeNodesForRoomStub        func(context.Context, livekit.RoomName) ([]livekit.NodeID, error)
+	getCascadeNodesForRoomMutex       sync.RWMutex
+	getCascadeNodesForRoomArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	getCascadeNodesForRoomReturns struct {
+		result1 []livekit.NodeID
+		result2 error
+	}
+	getCascadeNodesForRoomReturnsOnCall map[int]struct {
+		result1 []livekit.NodeID
+		result2 error
+	}
 	GetNodeForRoomStub        func(context.Context, livekit.RoomName) (*livekit.Node, error)
 	getNodeForRoomMutex       sync.RWMutex
 	getNodeForRoomArgsForCall []struct {
@@ -152,6 +179,69 @@ type FakeRouter struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeRouter) AddCascadeNodeForRoom(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.NodeID) error {
+	fake.addCascadeNodeForRoomMutex.Lock()
+	ret, specificReturn := fake.addCascadeNodeForRoomReturnsOnCall[len(fake.addCascadeNodeForRoomArgsForCall)]
+	fake.addCascadeNodeForRoomArgsForCall = append(fake.addCascadeNodeForRoomArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.NodeID
+	}{arg1, arg2, arg3})
+	stub := fake.AddCascadeNodeForRoomStub
+	fakeReturns := fake.addCascadeNodeForRoomReturns
+	fake.recordInvocation("AddCascadeNodeForRoom", []interface{}{arg1, arg2, arg3})
+	fake.addCascadeNodeForRoomMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) AddCascadeNodeForRoomCallCount() int {
+	fake.addCascadeNodeForRoomMutex.RLock()
+	defer fake.addCascadeNodeForRoomMutex.RUnlock()
+	return len(fake.addCascadeNodeForRoomArgsForCall)
+}
+
+func (fake *FakeRouter) AddCascadeNodeForRoomCalls(stub func(context.Context, livekit.RoomName, livekit.NodeID) error) {
+	fake.addCascadeNodeForRoomMutex.Lock()
+	defer fake.addCascadeNodeForRoomMutex.Unlock()
+	fake.AddCascadeNodeForRoomStub = stub
+}
+
+func (fake *FakeRouter) AddCascadeNodeForRoomArgsForCall(i int) (context.Context, livekit.RoomName, livekit.NodeID) {
+	fake.addCascadeNodeForRoomMutex.RLock()
+	defer fake.addCascadeNodeForRoomMutex.RUnlock()
+	argsForCall := fake.addCascadeNodeForRoomArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRouter) AddCascadeNodeForRoomReturns(result1 error) {
+	fake.addCascadeNodeForRoomMutex.Lock()
+	defer fake.addCascadeNodeForRoomMutex.Unlock()
+	fake.AddCascadeNodeForRoomStub = nil
+	fake.addCascadeNodeForRoomReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) AddCascadeNodeForRoomReturnsOnCall(i int, result1 error) {
+	fake.addCascadeNodeForRoomMutex.Lock()
+	defer fake.addCascadeNodeForRoomMutex.Unlock()
+	fake.AddCascadeNodeForRoomStub = nil
+	if fake.addCascadeNodeForRoomReturnsOnCall == nil {
+		fake.addCascadeNodeForRoomReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addCascadeNodeForRoomReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRouter) ClearRoomState(arg1 context.Context, arg2 livekit.RoomName) error {
 	fake.clearRoomStateMutex.Lock()
 	ret, specificReturn := fake.clearRoomStateReturnsOnCall[len(fake.clearRoomStateArgsForCall)]
@@ -303,6 +393,71 @@ func (fake *FakeRouter) DrainCalls(stub func()) {
 	fake.DrainStub = stub
 }
 
+func (fake *FakeRouter) GetCascadeNodesForRoom(arg1 context.Context, arg2 livekit.RoomName) ([]livekit.NodeID, error) {
+	fake.getCascadeNodesForRoomMutex.Lock()
+	ret, specificReturn := fake.getCascadeNodesForRoomReturnsOnCall[len(fake.getCascadeNodesForRoomArgsForCall)]
+	fake.getCascadeNodesForRoomArgsForCall = append(fake.getCascadeNodesForRoomArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.GetCascadeNodesForRoomStub
+	fakeReturns := fake.getCascadeNodesForRoomReturns
+	fake.recordInvocation("GetCascadeNodesForRoom", []interface{}{arg1, arg2})
+	fake.getCascadeNodesForRoomMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRouter) GetCascadeNodesForRoomCallCount() int {
+	fake.getCascadeNodesForRoomMutex.RLock()
+	defer fake.getCascadeNodesForRoomMutex.RUnlock()
+	return len(fake.getCascadeNodesForRoomArgsForCall)
+}
+
+func (fake *FakeRouter) GetCascadeNodesForRoomCalls(stub func(context.Context, livekit.RoomName) ([]livekit.NodeID, error)) {
+	fake.getCascadeNodesForRoomMutex.Lock()
+	defer fake.getCascadeNodesForRoomMutex.Unlock()
+	fake.GetCascadeNodesForRoomStub = stub
+}
+
+func (fake *FakeRouter) GetCascadeNodesForRoomArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.getCascadeNodesForRoomMutex.RLock()
+	defer fake.getCascadeNodesForRoomMutex.RUnlock()
+	argsForCall := fake.getCascadeNodesForRoomArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRouter) GetCascadeNodesForRoomReturns(result1 []livekit.NodeID, result2 error) {
+	fake.getCascadeNodesForRoomMutex.Lock()
+	defer fake.getCascadeNodesForRoomMutex.Unlock()
+	fake.GetCascadeNodesForRoomStub = nil
+	fake.getCascadeNodesForRoomReturns = struct {
+		result1 []livekit.NodeID
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) GetCascadeNodesForRoomReturnsOnCall(i int, result1 []livekit.NodeID, result2 error) {
+	fake.getCascadeNodesForRoomMutex.Lock()
+	defer fake.getCascadeNodesForRoomMutex.Unlock()
+	fake.GetCascadeNodesForRoomStub = nil
+	if fake.getCascadeNodesForRoomReturnsOnCall == nil {
+		fake.getCascadeNodesForRoomReturnsOnCall = make(map[int]struct {
+			result1 []livekit.NodeID
+			result2 error
+		})
+	}
+	fake.getCascadeNodesForRoomReturnsOnCall[i] = struct {
+		result1 []livekit.NodeID
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRouter) GetNodeForRoom(arg1 context.Context, arg2 livekit.RoomName) (*livekit.Node, error) {
 	fake.getNodeForRoomMutex.Lock()
 	ret, specificReturn := fake.getNodeForRoomReturnsOnCall[len(fake.getNodeForRoomArgsForCall)]
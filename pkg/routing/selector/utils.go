@@ -76,6 +76,32 @@ func LimitsReached(limitConfig config.LimitConfig, nodeStats *livekit.NodeStats)
 	return false
 }
 
+// RoomLimitReached checks if the node hosts as many rooms as allowed, existing rooms still accept participants
+func RoomLimitReached(limitConfig config.LimitConfig, nodeStats *livekit.NodeStats) bool {
+	if nodeStats == nil {
+		return false
+	}
+
+	return limitConfig.NumRooms > 0 && limitConfig.NumRooms <= nodeStats.NumRooms
+}
+
+// LoadLimitReached checks if the node is above the load limit of the configured selector,
+// such nodes are only picked when every node is overloaded
+func LoadLimitReached(selectorConfig config.NodeSelectorConfig, node *livekit.Node) bool {
+	if node.Stats == nil {
+		return false
+	}
+
+	switch selectorConfig.Kind {
+	case "cpuload":
+		return node.Stats.CpuLoad >= selectorConfig.CPULoadLimit
+	case "sysload", "regionaware":
+		return GetNodeSysload(node) >= selectorConfig.SysloadLimit
+	default:
+		return false
+	}
+}
+
 func SelectSortedNode(nodes []*livekit.Node, sortBy string, algorithm string) (*livekit.Node, error) {
 	if sortBy == "" {
 		return nil, ErrSortByNotSet
@@ -22,6 +22,7 @@ import (
 
 	"github.com/livekit/protocol/livekit"
 
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing/selector"
 )
 
@@ -44,3 +45,38 @@ func TestIsAvailable(t *testing.T) {
 		require.False(t, selector.IsAvailable(n))
 	})
 }
+
+func TestLimitsReached(t *testing.T) {
+	stats := &livekit.NodeStats{
+		NumRooms:    3,
+		NumClients:  10,
+		NumTracksIn: 20,
+	}
+
+	require.False(t, selector.LimitsReached(config.LimitConfig{}, stats))
+	require.True(t, selector.LimitsReached(config.LimitConfig{NumTracks: 20}, stats))
+	// room limit only applies to new rooms, participant limit when joining
+	require.False(t, selector.LimitsReached(config.LimitConfig{NumRooms: 3}, stats))
+	require.False(t, selector.LimitsReached(config.LimitConfig{NumParticipants: 10}, stats))
+
+	require.False(t, selector.RoomLimitReached(config.LimitConfig{}, stats))
+	require.False(t, selector.RoomLimitReached(config.LimitConfig{NumRooms: 4}, stats))
+	require.True(t, selector.RoomLimitReached(config.LimitConfig{NumRooms: 3}, stats))
+	require.False(t, selector.RoomLimitReached(config.LimitConfig{NumRooms: 3}, nil))
+}
+
+func TestLoadLimitReached(t *testing.T) {
+	node := &livekit.Node{
+		Stats: &livekit.NodeStats{
+			NumCpus:         4,
+			CpuLoad:         0.5,
+			LoadAvgLast1Min: 3.8,
+		},
+	}
+
+	require.False(t, selector.LoadLimitReached(config.NodeSelectorConfig{Kind: "any", CPULoadLimit: 0.1, SysloadLimit: 0.1}, node))
+	require.False(t, selector.LoadLimitReached(config.NodeSelectorConfig{Kind: "cpuload", CPULoadLimit: 0.6}, node))
+	require.True(t, selector.LoadLimitReached(config.NodeSelectorConfig{Kind: "cpuload", CPULoadLimit: 0.5}, node))
+	require.False(t, selector.LoadLimitReached(config.NodeSelectorConfig{Kind: "sysload", SysloadLimit: 0.96}, node))
+	require.True(t, selector.LoadLimitReached(config.NodeSelectorConfig{Kind: "regionaware", SysloadLimit: 0.9}, node))
+}
@@ -0,0 +1,314 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// var to allow unit test override
+var autoSubscribeReconcileInterval = 100 * time.Millisecond // room changes within the interval are applied together
+
+type autoSubscribeCandidate struct {
+	trackID     livekit.TrackID
+	publisherID livekit.ParticipantID
+	isVideo     bool
+}
+
+// AutoSubscribePolicy selects the tracks participants with auto subscribe enabled are subscribed to.
+//
+// Tracks subscribed to by the policy are remembered per subscriber, so that they can be unsubscribed from
+// when they are not selected anymore, without affecting subscriptions made by the subscriber itself.
+type AutoSubscribePolicy struct {
+	conf    config.AutoSubscribePolicy
+	sources map[livekit.TrackSource]struct{}
+
+	lock              sync.Mutex
+	subscribed        map[livekit.ParticipantID]map[livekit.TrackID]struct{}
+	speakingStartedAt map[livekit.ParticipantID]int64
+}
+
+func NewAutoSubscribePolicy(conf config.AutoSubscribePolicy) (*AutoSubscribePolicy, error) {
+	sources, err := conf.TrackSources()
+	if err != nil {
+		return nil, err
+	}
+
+	a := &AutoSubscribePolicy{
+		conf:              conf,
+		subscribed:        make(map[livekit.ParticipantID]map[livekit.TrackID]struct{}),
+		speakingStartedAt: make(map[livekit.ParticipantID]int64),
+	}
+	if len(sources) != 0 {
+		a.sources = make(map[livekit.TrackSource]struct{}, len(sources))
+		for _, source := range sources {
+			a.sources[source] = struct{}{}
+		}
+	}
+	return a, nil
+}
+
+func (a *AutoSubscribePolicy) IsEnabled() bool {
+	return a.conf.IsEnabled()
+}
+
+func (a *AutoSubscribePolicy) isSelectingActiveSpeakers() bool {
+	return a.conf.MaxVideoTracks > 0 && a.conf.VideoSelection == config.AutoSubscribeVideoSelectionActiveSpeaker
+}
+
+func (a *AutoSubscribePolicy) isSelectingAttributes() bool {
+	return len(a.conf.PublisherAttributes) != 0
+}
+
+// OnSpeakingStarted records the participant as the latest speaker, returns true when selected video tracks may have changed
+func (a *AutoSubscribePolicy) OnSpeakingStarted(participantID livekit.ParticipantID) bool {
+	if !a.isSelectingActiveSpeakers() {
+		return false
+	}
+
+	a.lock.Lock()
+	a.speakingStartedAt[participantID] = time.Now().UnixNano()
+	a.lock.Unlock()
+	return true
+}
+
+func (a *AutoSubscribePolicy) RemoveParticipant(participantID livekit.ParticipantID) {
+	a.lock.Lock()
+	delete(a.subscribed, participantID)
+	delete(a.speakingStartedAt, participantID)
+	a.lock.Unlock()
+}
+
+func (a *AutoSubscribePolicy) isPublisherAllowed(pub types.Participant) bool {
+	if len(a.conf.PublisherAttributes) == 0 {
+		return true
+	}
+
+	attributes := publisherAttributes(pub)
+	for k, v := range a.conf.PublisherAttributes {
+		if attributes[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *AutoSubscribePolicy) isSourceAllowed(track types.MediaTrack) bool {
+	if a.sources == nil {
+		return true
+	}
+	_, ok := a.sources[track.Source()]
+	return ok
+}
+
+// candidates returns the tracks allowed by the policy, in order of preference
+func (a *AutoSubscribePolicy) candidates(participants []types.Participant) []autoSubscribeCandidate {
+	type publisher struct {
+		participant       types.Participant
+		info              *livekit.ParticipantInfo
+		speakingStartedAt int64
+	}
+
+	a.lock.Lock()
+	publishers := make([]publisher, 0, len(participants))
+	for _, p := range participants {
+		if !a.isPublisherAllowed(p) {
+			continue
+		}
+		publishers = append(publishers, publisher{
+			participant:       p,
+			info:              p.ToProto(),
+			speakingStartedAt: a.speakingStartedAt[p.ID()],
+		})
+	}
+	a.lock.Unlock()
+
+	slices.SortFunc(publishers, func(p1, p2 publisher) int {
+		if a.isSelectingActiveSpeakers() {
+			if c := cmp.Compare(p2.speakingStartedAt, p1.speakingStartedAt); c != 0 {
+				return c
+			}
+		}
+		return CompareParticipant(p1.info, p2.info)
+	})
+
+	var candidates []autoSubscribeCandidate
+	for _, pub := range publishers {
+		for _, track := range pub.participant.GetPublishedTracks() {
+			if !a.isSourceAllowed(track) {
+				continue
+			}
+			candidates = append(candidates, autoSubscribeCandidate{
+				trackID:     track.ID(),
+				publisherID: pub.participant.ID(),
+				isVideo:     track.Kind() == livekit.TrackType_VIDEO,
+			})
+		}
+	}
+	return candidates
+}
+
+// update selects tracks for the subscriber, returning tracks to subscribe to and to unsubscribe from
+func (a *AutoSubscribePolicy) update(
+	subscriberID livekit.ParticipantID,
+	candidates []autoSubscribeCandidate,
+	published map[livekit.TrackID]struct{},
+) (subscribe []livekit.TrackID, unsubscribe []livekit.TrackID) {
+	selected := make(map[livekit.TrackID]struct{}, len(candidates))
+	numVideoTracks := 0
+	for _, c := range candidates {
+		if c.publisherID == subscriberID {
+			continue
+		}
+		if c.isVideo && a.conf.MaxVideoTracks > 0 {
+			if numVideoTracks >= a.conf.MaxVideoTracks {
+				continue
+			}
+			numVideoTracks++
+		}
+		selected[c.trackID] = struct{}{}
+	}
+
+	a.lock.Lock()
+	prev := a.subscribed[subscriberID]
+	a.subscribed[subscriberID] = selected
+	a.lock.Unlock()
+
+	for trackID := range selected {
+		if _, ok := prev[trackID]; !ok {
+			subscribe = append(subscribe, trackID)
+		}
+	}
+	for trackID := range prev {
+		if _, ok := selected[trackID]; ok {
+			continue
+		}
+		if _, ok := published[trackID]; ok {
+			unsubscribe = append(unsubscribe, trackID)
+		}
+	}
+	return
+}
+
+func (r *Room) SetAutoSubscribePolicy(conf config.AutoSubscribePolicy) error {
+	policy, err := NewAutoSubscribePolicy(conf)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	r.autoSubscribePolicy = policy
+	r.lock.Unlock()
+
+	r.reconcileAutoSubscriptions(false)
+	return nil
+}
+
+func (r *Room) getAutoSubscribePolicy() *AutoSubscribePolicy {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.autoSubscribePolicy
+}
+
+// scheduleAutoSubscriptionsReconcile coalesces room changes affecting the auto subscribe policy,
+// applying them in a single pass over participants once the interval has elapsed
+func (r *Room) scheduleAutoSubscriptionsReconcile() {
+	if !r.getAutoSubscribePolicy().IsEnabled() || r.autoSubscribeReconcilePending.Swap(true) {
+		return
+	}
+
+	time.AfterFunc(autoSubscribeReconcileInterval, func() {
+		// changes made while reconciling schedule another pass
+		r.autoSubscribeReconcilePending.Store(false)
+		if r.IsClosed() {
+			return
+		}
+		r.reconcileAutoSubscriptions(false)
+	})
+}
+
+// reconcileAutoSubscriptions applies the auto subscribe policy to all active participants with auto subscribe enabled
+func (r *Room) reconcileAutoSubscriptions(isSync bool) {
+	policy := r.getAutoSubscribePolicy()
+	if !policy.IsEnabled() {
+		return
+	}
+
+	publishers := r.autoSubscribePublishers()
+	candidates := policy.candidates(publishers)
+	published := publishedTrackIDs(publishers)
+	for _, p := range r.GetParticipants() {
+		if p.State() != livekit.ParticipantInfo_ACTIVE {
+			// not fully joined, existing tracks are subscribed to when ready
+			continue
+		}
+		r.reconcileAutoSubscription(policy, p, candidates, published, isSync)
+	}
+}
+
+func (r *Room) reconcileAutoSubscription(
+	policy *AutoSubscribePolicy,
+	p types.LocalParticipant,
+	candidates []autoSubscribeCandidate,
+	published map[livekit.TrackID]struct{},
+	isSync bool,
+) {
+	r.lock.RLock()
+	autoSubscribe := r.autoSubscribe(p)
+	r.lock.RUnlock()
+	if !autoSubscribe {
+		return
+	}
+
+	subscribe, unsubscribe := policy.update(p.ID(), candidates, published)
+	for _, trackID := range subscribe {
+		p.SubscribeToTrack(trackID, isSync)
+	}
+	for _, trackID := range unsubscribe {
+		p.UnsubscribeFromTrack(trackID)
+	}
+	if len(subscribe) != 0 || len(unsubscribe) != 0 {
+		p.GetLogger().Debugw("applied auto subscribe policy", "subscribed", subscribe, "unsubscribed", unsubscribe)
+	}
+}
+
+// autoSubscribePublishers returns the participants whose tracks can be selected by the policy,
+// including participants of other nodes hosting the room
+func (r *Room) autoSubscribePublishers() []types.Participant {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return append(toParticipants(slices.Collect(maps.Values(r.participants))), r.remoteParticipantsLocked()...)
+}
+
+func publishedTrackIDs(participants []types.Participant) map[livekit.TrackID]struct{} {
+	published := make(map[livekit.TrackID]struct{})
+	for _, p := range participants {
+		for _, track := range p.GetPublishedTracks() {
+			published[track.ID()] = struct{}{}
+		}
+	}
+	return published
+}
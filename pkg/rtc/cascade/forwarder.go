@@ -0,0 +1,176 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"net"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+	"go.uber.org/atomic"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+// NodeSubscriberID is the subscriber ID of a node in the receivers of tracks relayed to it
+func NodeSubscriberID(nodeID livekit.NodeID) livekit.ParticipantID {
+	return livekit.ParticipantID("cascade:" + string(nodeID))
+}
+
+var _ sfu.TrackSender = (*TrackForwarder)(nil)
+
+// TrackForwarder relays a published track to an edge node, it is attached to the track's receiver
+// like the down track of a participant, so there is one copy of the track per node regardless of
+// the number of subscribers on the node. Spatial layers above the quality the edge node subscribes to
+// are not relayed.
+type TrackForwarder struct {
+	relay    *Relay
+	nodeID   livekit.NodeID
+	trackID  livekit.TrackID
+	receiver atomic.Value // sfu.TrackReceiver
+	logger   logger.Logger
+
+	addr      atomic.Value // net.Addr
+	maxLayer  atomic.Int32
+	refreshed atomic.Int64
+	closed    atomic.Bool
+
+	onClose func(f *TrackForwarder)
+}
+
+func newTrackForwarder(
+	relay *Relay,
+	nodeID livekit.NodeID,
+	addr net.Addr,
+	receiver sfu.TrackReceiver,
+	onClose func(f *TrackForwarder),
+) *TrackForwarder {
+	f := &TrackForwarder{
+		relay:   relay,
+		nodeID:  nodeID,
+		trackID: receiver.TrackID(),
+		logger:  relay.params.Logger.WithValues("edgeNodeID", nodeID, "trackID", receiver.TrackID()),
+		onClose: onClose,
+	}
+	f.receiver.Store(receiver)
+	f.addr.Store(addr)
+	f.maxLayer.Store(buffer.InvalidLayerSpatial)
+	f.refresh(addr)
+	return f
+}
+
+func (f *TrackForwarder) getReceiver() sfu.TrackReceiver {
+	return f.receiver.Load().(sfu.TrackReceiver)
+}
+
+func (f *TrackForwarder) getAddr() net.Addr {
+	return f.addr.Load().(net.Addr)
+}
+
+func (f *TrackForwarder) refresh(addr net.Addr) {
+	f.addr.Store(addr)
+	f.refreshed.Store(time.Now().UnixNano())
+}
+
+func (f *TrackForwarder) isExpired(now time.Time, timeout time.Duration) bool {
+	return now.Sub(time.Unix(0, f.refreshed.Load())) > timeout
+}
+
+// SetMaxQuality sets the highest quality any subscriber of the edge node subscribes to
+func (f *TrackForwarder) SetMaxQuality(quality livekit.VideoQuality) {
+	receiver := f.getReceiver()
+	if !mime.IsMimeTypeVideo(receiver.Mime()) {
+		if quality == livekit.VideoQuality_OFF {
+			f.maxLayer.Store(buffer.InvalidLayerSpatial)
+		} else {
+			f.maxLayer.Store(0)
+		}
+		return
+	}
+
+	f.maxLayer.Store(buffer.GetSpatialLayerForVideoQuality(receiver.Mime(), quality, receiver.TrackInfo()))
+}
+
+func (f *TrackForwarder) send(typ MessageType, layer int32, payload []byte) {
+	f.relay.send(f.getAddr(), &Message{
+		Type:    typ,
+		NodeID:  f.relay.params.NodeID,
+		TrackID: f.trackID,
+		Layer:   layer,
+		Payload: payload,
+	})
+}
+
+func (f *TrackForwarder) UpTrackLayersChange()                           {}
+func (f *TrackForwarder) UpTrackBitrateAvailabilityChange()              {}
+func (f *TrackForwarder) UpTrackMaxPublishedLayerChange(_ int32)         {}
+func (f *TrackForwarder) UpTrackMaxTemporalLayerSeenChange(_ int32)      {}
+func (f *TrackForwarder) UpTrackBitrateReport(_ []int32, _ sfu.Bitrates) {}
+func (f *TrackForwarder) Resync()                                        {}
+func (f *TrackForwarder) ReceiverRestart(receiver sfu.TrackReceiver)     { f.SetReceiver(receiver) }
+func (f *TrackForwarder) SetReceiver(receiver sfu.TrackReceiver)         { f.receiver.Store(receiver) }
+func (f *TrackForwarder) ID() string                                     { return string(f.trackID) + ":" + string(f.nodeID) }
+func (f *TrackForwarder) SubscriberID() livekit.ParticipantID            { return NodeSubscriberID(f.nodeID) }
+func (f *TrackForwarder) IsClosed() bool                                 { return f.closed.Load() }
+
+func (f *TrackForwarder) WriteRTP(p *buffer.ExtPacket, layer int32) int32 {
+	if f.closed.Load() || layer > f.maxLayer.Load() {
+		return 0
+	}
+
+	raw := p.RawPacket
+	if raw == nil {
+		var err error
+		if raw, err = p.Packet.Marshal(); err != nil {
+			return 0
+		}
+	}
+	f.send(MessageTypeRTP, layer, raw)
+	return 1
+}
+
+func (f *TrackForwarder) HandleRTCPSenderReportData(
+	_ webrtc.PayloadType,
+	layer int32,
+	publisherSRData *livekit.RTCPSenderReportState,
+) error {
+	if f.closed.Load() || layer > f.maxLayer.Load() || publisherSRData == nil {
+		return nil
+	}
+
+	payload, err := proto.Marshal(publisherSRData)
+	if err != nil {
+		return err
+	}
+	f.send(MessageTypeSenderReport, layer, payload)
+	return nil
+}
+
+func (f *TrackForwarder) Close() {
+	if f.closed.Swap(true) {
+		return
+	}
+
+	f.logger.Debugw("closing cascade track forwarder")
+	if f.onClose != nil {
+		f.onClose(f)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+// MessageType is the type of a message exchanged between nodes hosting a cascaded room.
+// The origin node hosts the publisher, edge nodes subscribe to its tracks on behalf of their participants.
+// The primary node is the node the room is assigned to, it relays participants between the nodes hosting the room.
+type MessageType uint8
+
+const (
+	// origin -> edge
+	MessageTypeRTP MessageType = iota + 1
+	MessageTypeSenderReport
+	// edge -> origin
+	MessageTypeSubscribe
+	MessageTypeUnsubscribe
+	MessageTypePLI
+	// origin -> edge
+	MessageTypeTrackCodec
+	// node -> primary
+	MessageTypeJoinRoom
+	MessageTypeLeaveRoom
+	// node -> primary -> nodes
+	MessageTypeParticipant
+)
+
+func (m MessageType) String() string {
+	switch m {
+	case MessageTypeRTP:
+		return "RTP"
+	case MessageTypeSenderReport:
+		return "SENDER_REPORT"
+	case MessageTypeSubscribe:
+		return "SUBSCRIBE"
+	case MessageTypeUnsubscribe:
+		return "UNSUBSCRIBE"
+	case MessageTypePLI:
+		return "PLI"
+	case MessageTypeTrackCodec:
+		return "TRACK_CODEC"
+	case MessageTypeJoinRoom:
+		return "JOIN_ROOM"
+	case MessageTypeLeaveRoom:
+		return "LEAVE_ROOM"
+	case MessageTypeParticipant:
+		return "PARTICIPANT"
+	default:
+		return fmt.Sprintf("%d", int(m))
+	}
+}
+
+const (
+	messageVersion = 4
+
+	// version, type, timestamp, nonce, node ID length, room name length, track ID length, layer
+	messageHeaderFixedSize = 25
+	messageDigestSize      = 16
+
+	maxIDLength = math.MaxUint16
+)
+
+var (
+	errMessageTooShort     = errors.New("cascade message too short")
+	errMessageVersion      = errors.New("unsupported cascade message version")
+	errMessageDigest       = errors.New("cascade message digest mismatch")
+	errMessageIDTooLong    = errors.New("cascade message ID too long")
+	errMessageTypeNotKnown = errors.New("unknown cascade message type")
+)
+
+// Message is a datagram exchanged between relays of different nodes,
+//
+//	| version | type | timestamp | nonce | node ID length | node ID | room name length | room name |
+//	| track ID length | track ID | layer | payload | digest |
+//
+// with IDs prefixed by their length on 2 bytes.
+// Payload is a RTP packet of the layer for RTP messages, a marshalled livekit.RTCPSenderReportState
+// for sender reports, the maximum video quality requested by the edge followed by the edge's address
+// for subscriptions, the codec of the track for track codecs, the node's address for joins,
+// a fragment of a participant update for participants and empty otherwise.
+//
+// The digest is a truncated HMAC-SHA256 of the message keyed by the cluster's cascade secret,
+// it prevents anyone who can reach the relay port from subscribing to tracks. It covers the whole message,
+// including the send time and a nonce unique to the sender, so that replayed messages can be detected.
+type Message struct {
+	Type      MessageType
+	Timestamp int64 // unix nanoseconds
+	Nonce     uint64
+	NodeID    livekit.NodeID
+	RoomName  livekit.RoomName
+	TrackID   livekit.TrackID
+	Layer     int32
+	Payload   []byte
+}
+
+func (m *Message) MarshalSize() int {
+	return messageHeaderFixedSize + len(m.NodeID) + len(m.RoomName) + len(m.TrackID) + len(m.Payload) + messageDigestSize
+}
+
+func (m *Message) Marshal(secret []byte) ([]byte, error) {
+	if len(m.NodeID) > maxIDLength || len(m.RoomName) > maxIDLength || len(m.TrackID) > maxIDLength {
+		return nil, errMessageIDTooLong
+	}
+
+	buf := make([]byte, 0, m.MarshalSize())
+	buf = append(buf, messageVersion, byte(m.Type))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(m.Timestamp))
+	buf = binary.BigEndian.AppendUint64(buf, m.Nonce)
+	buf = appendID(buf, string(m.NodeID))
+	buf = appendID(buf, string(m.RoomName))
+	buf = appendID(buf, string(m.TrackID))
+	buf = append(buf, byte(m.Layer))
+	buf = append(buf, m.Payload...)
+	return append(buf, digest(secret, buf)...), nil
+}
+
+// Unmarshal parses and authenticates a message, the payload references buf
+func (m *Message) Unmarshal(buf []byte, secret []byte) error {
+	if len(buf) < messageHeaderFixedSize+messageDigestSize {
+		return errMessageTooShort
+	}
+
+	body := buf[:len(buf)-messageDigestSize]
+	if !hmac.Equal(buf[len(body):], digest(secret, body)) {
+		return errMessageDigest
+	}
+	if body[0] != messageVersion {
+		return errMessageVersion
+	}
+
+	m.Type = MessageType(body[1])
+	if m.Type < MessageTypeRTP || m.Type > MessageTypeParticipant {
+		return errMessageTypeNotKnown
+	}
+
+	m.Timestamp = int64(binary.BigEndian.Uint64(body[2:10]))
+	m.Nonce = binary.BigEndian.Uint64(body[10:18])
+
+	offset := 18
+	nodeID, ok := readID(body, &offset)
+	if !ok {
+		return errMessageTooShort
+	}
+	roomName, ok := readID(body, &offset)
+	if !ok {
+		return errMessageTooShort
+	}
+	trackID, ok := readID(body, &offset)
+	if !ok || len(body) < offset+1 {
+		return errMessageTooShort
+	}
+	m.NodeID = livekit.NodeID(nodeID)
+	m.RoomName = livekit.RoomName(roomName)
+	m.TrackID = livekit.TrackID(trackID)
+
+	m.Layer = int32(body[offset])
+	offset++
+
+	m.Payload = body[offset:]
+	return nil
+}
+
+func appendID(buf []byte, id string) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(id)))
+	return append(buf, id...)
+}
+
+// readID reads an ID prefixed by its length at offset, advancing offset past it
+func readID(buf []byte, offset *int) (string, bool) {
+	if len(buf) < *offset+2 {
+		return "", false
+	}
+	length := int(binary.BigEndian.Uint16(buf[*offset:]))
+	*offset += 2
+	if len(buf) < *offset+length {
+		return "", false
+	}
+	id := string(buf[*offset : *offset+length])
+	*offset += length
+	return id, true
+}
+
+func digest(secret []byte, data []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(data)
+	return mac.Sum(nil)[:messageDigestSize]
+}
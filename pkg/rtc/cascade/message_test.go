@@ -0,0 +1,111 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+func TestMessage(t *testing.T) {
+	secret := []byte("secret")
+
+	t.Run("round trip", func(t *testing.T) {
+		m := &Message{
+			Type:      MessageTypeRTP,
+			Timestamp: 1_700_000_000_000_000_000,
+			Nonce:     42,
+			NodeID:    "ND_origin",
+			RoomName:  "room",
+			TrackID:   "TR_video",
+			Layer:     2,
+			Payload:   []byte{0x80, 0x60, 0x00, 0x01},
+		}
+		buf, err := m.Marshal(secret)
+		require.NoError(t, err)
+		require.Len(t, buf, m.MarshalSize())
+
+		var parsed Message
+		require.NoError(t, parsed.Unmarshal(buf, secret))
+		require.Equal(t, *m, parsed)
+	})
+
+	t.Run("empty payload", func(t *testing.T) {
+		m := &Message{
+			Type:    MessageTypeUnsubscribe,
+			NodeID:  "ND_edge",
+			TrackID: "TR_audio",
+		}
+		buf, err := m.Marshal(secret)
+		require.NoError(t, err)
+
+		var parsed Message
+		require.NoError(t, parsed.Unmarshal(buf, secret))
+		require.Equal(t, MessageTypeUnsubscribe, parsed.Type)
+		require.Equal(t, m.TrackID, parsed.TrackID)
+		require.Empty(t, parsed.Payload)
+	})
+
+	t.Run("long IDs", func(t *testing.T) {
+		m := &Message{
+			Type:     MessageTypeParticipant,
+			NodeID:   "ND_edge",
+			RoomName: livekit.RoomName(strings.Repeat("r", 1000)),
+		}
+		buf, err := m.Marshal(secret)
+		require.NoError(t, err)
+
+		var parsed Message
+		require.NoError(t, parsed.Unmarshal(buf, secret))
+		require.Equal(t, m.RoomName, parsed.RoomName)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		m := &Message{Type: MessageTypeSubscribe, NodeID: "ND_edge", TrackID: "TR_video"}
+		buf, err := m.Marshal(secret)
+		require.NoError(t, err)
+
+		var parsed Message
+		require.ErrorIs(t, parsed.Unmarshal(buf, []byte("other")), errMessageDigest)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		m := &Message{Type: MessageTypeSubscribe, NodeID: "ND_edge", TrackID: "TR_video", Payload: []byte{1}}
+		buf, err := m.Marshal(secret)
+		require.NoError(t, err)
+
+		buf[len(buf)-messageDigestSize-1] = 2
+		var parsed Message
+		require.ErrorIs(t, parsed.Unmarshal(buf, secret), errMessageDigest)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var parsed Message
+		require.ErrorIs(t, parsed.Unmarshal([]byte{1, 2, 3}, secret), errMessageTooShort)
+
+		_, err := (&Message{Type: MessageTypeRTP, TrackID: livekit.TrackID(strings.Repeat("a", maxIDLength+1))}).Marshal(secret)
+		require.ErrorIs(t, err, errMessageIDTooLong)
+
+		// track ID length pointing past the end of the message
+		body := append([]byte{messageVersion, byte(MessageTypePLI)}, make([]byte, 16)...)
+		body = append(body, 0, 0, 0, 0, 0, 10, 'T')
+		buf := append(body, digest(secret, body)...)
+		require.ErrorIs(t, parsed.Unmarshal(buf, secret), errMessageTooShort)
+	})
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"encoding/binary"
+	"slices"
+	"sync"
+
+	"github.com/pion/webrtc/v4"
+
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/utils/mono"
+
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
+)
+
+var _ sfu.TrackReceiver = (*Receiver)(nil)
+
+type ReceiverParams struct {
+	Subscription               *Subscription
+	TrackInfo                  *livekit.TrackInfo
+	StreamID                   string
+	Codec                      webrtc.RTPCodecParameters
+	HeaderExtensions           []webrtc.RTPHeaderExtensionParameter
+	MaxVideoPkts               int
+	MaxAudioPkts               int
+	StreamTrackerManagerConfig sfu.StreamTrackerManagerConfig
+	Logger                     logger.Logger
+}
+
+// Receiver is the receiver of a track relayed from its origin node, it feeds the packets of
+// the subscription into buffers like the receiver of a published track, so that participants of
+// this node subscribe to it through down tracks as to any other track.
+type Receiver struct {
+	*sfu.ReceiverBase
+
+	params ReceiverParams
+
+	lock    sync.Mutex
+	buffers [buffer.DefaultMaxLayerSpatial + 1]*buffer.BufferBase
+}
+
+func NewReceiver(params ReceiverParams) *Receiver {
+	r := &Receiver{
+		params: params,
+	}
+
+	kind := webrtc.RTPCodecTypeAudio
+	if mime.IsMimeTypeStringVideo(params.Codec.MimeType) {
+		kind = webrtc.RTPCodecTypeVideo
+	}
+	r.ReceiverBase = sfu.NewReceiverBase(
+		sfu.ReceiverBaseParams{
+			TrackID:                    params.Subscription.TrackID(),
+			StreamID:                   params.StreamID,
+			Kind:                       kind,
+			Codec:                      params.Codec,
+			HeaderExtensions:           params.HeaderExtensions,
+			Logger:                     params.Logger,
+			StreamTrackerManagerConfig: params.StreamTrackerManagerConfig,
+		},
+		params.TrackInfo,
+		sfu.ReceiverCodecStateNormal,
+	)
+
+	params.Subscription.OnPacket(r.handlePacket)
+	params.Subscription.OnSenderReport(r.handleSenderReport)
+	return r
+}
+
+func (r *Receiver) layerIndex(layer int32) int32 {
+	// for svc codecs, all spatial layers are in a single buffer
+	if r.VideoLayerMode() == livekit.VideoLayer_MULTIPLE_SPATIAL_LAYERS_PER_STREAM {
+		return 0
+	}
+	return layer
+}
+
+func (r *Receiver) getBuffer(layer int32) *buffer.BufferBase {
+	layer = r.layerIndex(layer)
+	if layer < 0 || int(layer) >= len(r.buffers) {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.buffers[layer]
+}
+
+func (r *Receiver) getOrCreateBuffer(layer int32, ssrc uint32) *buffer.BufferBase {
+	layer = r.layerIndex(layer)
+	if layer < 0 || int(layer) >= len(r.buffers) || r.IsClosed() {
+		return nil
+	}
+
+	r.lock.Lock()
+	if buff := r.buffers[layer]; buff != nil {
+		r.lock.Unlock()
+		return buff
+	}
+
+	buff := buffer.NewBufferBase(buffer.BufferBaseParams{
+		SSRC:             ssrc,
+		MaxVideoPkts:     r.params.MaxVideoPkts,
+		MaxAudioPkts:     r.params.MaxAudioPkts,
+		LoggerComponents: []string{sutils.ComponentSFU},
+		SendPLI: func() {
+			r.params.Subscription.SendPLI(layer)
+		},
+	})
+	err := buff.Bind(
+		webrtc.RTPParameters{
+			HeaderExtensions: r.params.HeaderExtensions,
+			Codecs:           []webrtc.RTPCodecParameters{r.params.Codec},
+		},
+		r.params.Codec.RTPCodecCapability,
+		0,
+	)
+	if err != nil {
+		r.lock.Unlock()
+		r.params.Logger.Warnw("could not bind relayed track buffer", err, "layer", layer)
+		return nil
+	}
+	r.buffers[layer] = buff
+	r.lock.Unlock()
+
+	r.ReceiverBase.AddBuffer(buff, layer)
+	r.ReceiverBase.StartBuffer(buff, layer)
+	return buff
+}
+
+func (r *Receiver) handlePacket(layer int32, pkt []byte) {
+	if len(pkt) < 12 {
+		return
+	}
+
+	buff := r.getOrCreateBuffer(layer, binary.BigEndian.Uint32(pkt[8:12]))
+	if buff == nil {
+		return
+	}
+	// the packet is only valid during the callback
+	if _, err := buff.HandleIncomingPacket(slices.Clone(pkt), nil, mono.UnixNano(), false, false, nil, 0); err != nil {
+		r.params.Logger.Debugw("could not handle relayed packet", "error", err, "layer", layer)
+	}
+}
+
+func (r *Receiver) handleSenderReport(layer int32, sr *livekit.RTCPSenderReportState) {
+	if buff := r.getBuffer(layer); buff != nil {
+		buff.SetSenderReportData(sr)
+	}
+}
+
+// SendPLI requests a key frame from the origin, before packets of the layer are received there is no buffer to throttle it
+func (r *Receiver) SendPLI(layer int32, force bool) {
+	if buff := r.getBuffer(layer); buff != nil {
+		buff.SendPLI(force)
+		return
+	}
+	r.params.Subscription.SendPLI(layer)
+}
+
+func (r *Receiver) Close(reason string) {
+	r.params.Subscription.Close()
+	r.ReceiverBase.Close(reason, true)
+}
@@ -0,0 +1,653 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/frostbyte73/core"
+	"github.com/pion/webrtc/v4"
+	"go.uber.org/atomic"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const (
+	// largest UDP payload, messages carry IDs of any length
+	maxMessageSize = 65507
+
+	defaultSubscriptionTimeout = 10 * time.Second
+
+	// messages sent longer ago are dropped, clocks of nodes are expected to be synchronized within this duration
+	maxMessageAge = 30 * time.Second
+)
+
+var ErrRelayClosed = errors.New("cascade relay closed")
+
+// TrackResolver returns a track published on this node
+type TrackResolver func(trackID livekit.TrackID) types.LocalMediaTrack
+
+// ParticipantResolver returns the participants of a room connected to this node
+type ParticipantResolver func(roomName livekit.RoomName) []*ParticipantUpdate
+
+type RelayParams struct {
+	NodeID livekit.NodeID
+	// shared by all nodes of the cluster, used to authenticate messages
+	Secret string
+	Conn   net.PacketConn
+	// address other nodes relay tracks to, defaults to the local address of Conn
+	AdvertisedAddr net.Addr
+	// subscriptions are dropped by the origin when not refreshed by the edge within this duration
+	SubscriptionTimeout time.Duration
+	TrackResolver       TrackResolver
+	ParticipantResolver ParticipantResolver
+	// called for changes of participants connected to other nodes hosting a room joined with JoinRoom,
+	// origin is the address of the node's relay, tracks of the participant are subscribed to from it
+	OnParticipantUpdate func(roomName livekit.RoomName, nodeID livekit.NodeID, origin net.Addr, update *ParticipantUpdate)
+	Logger              logger.Logger
+}
+
+type forwarderKey struct {
+	nodeID  livekit.NodeID
+	trackID livekit.TrackID
+}
+
+type subscriptionKey struct {
+	originNodeID livekit.NodeID
+	trackID      livekit.TrackID
+}
+
+type nonceKey struct {
+	nodeID livekit.NodeID
+	nonce  uint64
+}
+
+// Relay exchanges media of cascaded rooms with other nodes over a single UDP socket.
+//
+// As origin, it relays tracks published on this node to edge nodes subscribing to them,
+// one copy per edge node, and feeds the quality edge nodes subscribe to into dynacast.
+// As edge, it subscribes to tracks of other nodes on behalf of participants connected to this node.
+//
+// Participants of a room hosted by several nodes are exchanged through the room's primary node,
+// which forwards the participants of each node to the other nodes hosting the room.
+//
+// Messages are authenticated with the shared secret. Control messages sent by edges are only accepted once
+// and while recent, and tracks are relayed to the address carried in the authenticated SUBSCRIBE message
+// rather than to the source address of the packet, so replayed or spoofed packets cannot redirect a relay.
+type Relay struct {
+	params RelayParams
+	secret []byte
+	nonce  atomic.Uint64
+
+	lock          sync.RWMutex
+	forwarders    map[forwarderKey]*TrackForwarder
+	subscriptions map[subscriptionKey]*Subscription
+	rooms         map[livekit.RoomName]*cascadedRoom
+
+	nonceLock  sync.Mutex
+	seenNonces map[nonceKey]int64
+
+	fragmentLock sync.Mutex
+	fragments    map[fragmentKey]*fragmentedUpdate
+
+	closed core.Fuse
+}
+
+func NewRelay(params RelayParams) *Relay {
+	if params.SubscriptionTimeout == 0 {
+		params.SubscriptionTimeout = defaultSubscriptionTimeout
+	}
+	if params.Logger == nil {
+		params.Logger = logger.GetLogger()
+	}
+	if params.AdvertisedAddr == nil {
+		params.AdvertisedAddr = params.Conn.LocalAddr()
+	}
+	r := &Relay{
+		params:        params,
+		secret:        []byte(params.Secret),
+		forwarders:    make(map[forwarderKey]*TrackForwarder),
+		subscriptions: make(map[subscriptionKey]*Subscription),
+		rooms:         make(map[livekit.RoomName]*cascadedRoom),
+		seenNonces:    make(map[nonceKey]int64),
+		fragments:     make(map[fragmentKey]*fragmentedUpdate),
+	}
+	// nonces are unique across restarts of the node
+	r.nonce.Store(rand.Uint64())
+	return r
+}
+
+func (r *Relay) Start() {
+	go r.readWorker()
+	go r.refreshWorker()
+}
+
+func (r *Relay) Stop() {
+	if r.closed.IsBroken() {
+		return
+	}
+	r.closed.Break()
+	_ = r.params.Conn.Close()
+
+	r.lock.Lock()
+	forwarders := r.forwarders
+	r.forwarders = make(map[forwarderKey]*TrackForwarder)
+	subscriptions := r.subscriptions
+	r.subscriptions = make(map[subscriptionKey]*Subscription)
+	r.rooms = make(map[livekit.RoomName]*cascadedRoom)
+	r.lock.Unlock()
+
+	for _, f := range forwarders {
+		r.removeForwarder(f)
+	}
+	for _, s := range subscriptions {
+		s.closed.Store(true)
+	}
+}
+
+func (r *Relay) LocalAddr() net.Addr {
+	return r.params.Conn.LocalAddr()
+}
+
+// Subscribe requests a track published on the origin node, packets are delivered to the callbacks of the returned subscription.
+// The origin replies with the codec of the track even when the quality is OFF.
+func (r *Relay) Subscribe(
+	originNodeID livekit.NodeID,
+	origin net.Addr,
+	trackID livekit.TrackID,
+	quality livekit.VideoQuality,
+) (*Subscription, error) {
+	if r.closed.IsBroken() {
+		return nil, ErrRelayClosed
+	}
+
+	key := subscriptionKey{originNodeID: originNodeID, trackID: trackID}
+	r.lock.Lock()
+	s := r.subscriptions[key]
+	created := s == nil
+	if created {
+		s = newSubscription(r, originNodeID, origin, trackID)
+		r.subscriptions[key] = s
+	}
+	r.lock.Unlock()
+
+	if !s.setMaxQuality(quality) && created {
+		s.sendSubscribe()
+	}
+	return s, nil
+}
+
+func (r *Relay) removeSubscription(s *Subscription) {
+	r.lock.Lock()
+	key := subscriptionKey{originNodeID: s.originNodeID, trackID: s.trackID}
+	if r.subscriptions[key] == s {
+		delete(r.subscriptions, key)
+	}
+	r.lock.Unlock()
+}
+
+func (r *Relay) send(addr net.Addr, m *Message) {
+	if err := r.sendTo([]net.Addr{addr}, m); err != nil {
+		r.params.Logger.Warnw("could not marshal cascade message", err, "type", m.Type)
+	}
+}
+
+func (r *Relay) sendTo(addrs []net.Addr, m *Message) error {
+	if len(addrs) == 0 {
+		return nil
+	}
+	m.Timestamp = time.Now().UnixNano()
+	m.Nonce = r.nonce.Inc()
+	buf, err := m.Marshal(r.secret)
+	if err != nil {
+		return err
+	}
+	r.write(addrs, m.Type, buf)
+	return nil
+}
+
+func (r *Relay) write(addrs []net.Addr, typ MessageType, buf []byte) {
+	for _, addr := range addrs {
+		if _, err := r.params.Conn.WriteTo(buf, addr); err != nil && !r.closed.IsBroken() {
+			r.params.Logger.Debugw("could not send cascade message", "error", err, "type", typ, "addr", addr)
+		}
+	}
+}
+
+func (r *Relay) readWorker() {
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, addr, err := r.params.Conn.ReadFrom(buf)
+		if err != nil {
+			if !r.closed.IsBroken() {
+				r.params.Logger.Warnw("cascade relay read failed", err)
+			}
+			return
+		}
+
+		var m Message
+		if err := m.Unmarshal(buf[:n], r.secret); err != nil {
+			r.params.Logger.Debugw("dropping cascade message", "error", err, "addr", addr)
+			continue
+		}
+		if !r.isFresh(&m, time.Now()) {
+			r.params.Logger.Debugw("dropping replayed cascade message", "type", m.Type, "nodeID", m.NodeID, "addr", addr)
+			continue
+		}
+		r.handleMessage(addr, &m)
+	}
+}
+
+// isFresh returns false for messages sent too long ago, and for control messages which were already received.
+// Media is only checked for age, as repeated packets are dropped by the receiving buffers.
+func (r *Relay) isFresh(m *Message, now time.Time) bool {
+	age := now.Sub(time.Unix(0, m.Timestamp))
+	if age > maxMessageAge || age < -maxMessageAge {
+		return false
+	}
+	if m.Type == MessageTypeRTP || m.Type == MessageTypeSenderReport {
+		return true
+	}
+
+	key := nonceKey{nodeID: m.NodeID, nonce: m.Nonce}
+	r.nonceLock.Lock()
+	defer r.nonceLock.Unlock()
+	if _, ok := r.seenNonces[key]; ok {
+		return false
+	}
+	r.seenNonces[key] = m.Timestamp
+	return true
+}
+
+func (r *Relay) pruneNonces(now time.Time) {
+	r.nonceLock.Lock()
+	defer r.nonceLock.Unlock()
+
+	for key, sentAt := range r.seenNonces {
+		// a message sent before this cannot pass the age check anymore
+		if now.Sub(time.Unix(0, sentAt)) > 2*maxMessageAge {
+			delete(r.seenNonces, key)
+		}
+	}
+}
+
+func (r *Relay) handleMessage(addr net.Addr, m *Message) {
+	switch m.Type {
+	case MessageTypeRTP, MessageTypeSenderReport, MessageTypeTrackCodec:
+		r.lock.RLock()
+		s := r.subscriptions[subscriptionKey{originNodeID: m.NodeID, trackID: m.TrackID}]
+		r.lock.RUnlock()
+		if s != nil {
+			s.handleMessage(m)
+		}
+
+	case MessageTypeSubscribe:
+		// quality, followed by the address of the edge relay
+		if len(m.Payload) < 2 {
+			return
+		}
+		edgeAddr, err := net.ResolveUDPAddr("udp", string(m.Payload[1:]))
+		if err != nil {
+			r.params.Logger.Debugw("invalid cascade subscription address", "error", err, "edgeNodeID", m.NodeID, "addr", addr)
+			return
+		}
+		r.handleSubscribe(edgeAddr, m.NodeID, m.TrackID, livekit.VideoQuality(m.Payload[0]))
+
+	case MessageTypeUnsubscribe:
+		r.lock.Lock()
+		f := r.forwarders[forwarderKey{nodeID: m.NodeID, trackID: m.TrackID}]
+		delete(r.forwarders, forwarderKey{nodeID: m.NodeID, trackID: m.TrackID})
+		r.lock.Unlock()
+		if f != nil {
+			r.removeForwarder(f)
+		}
+
+	case MessageTypePLI:
+		r.lock.RLock()
+		f := r.forwarders[forwarderKey{nodeID: m.NodeID, trackID: m.TrackID}]
+		r.lock.RUnlock()
+		if f != nil {
+			f.getReceiver().SendPLI(m.Layer, false)
+		}
+
+	case MessageTypeJoinRoom:
+		edgeAddr, err := net.ResolveUDPAddr("udp", string(m.Payload))
+		if err != nil {
+			r.params.Logger.Debugw("invalid cascade room address", "error", err, "edgeNodeID", m.NodeID, "addr", addr)
+			return
+		}
+		r.handleJoinRoom(m.RoomName, m.NodeID, edgeAddr)
+
+	case MessageTypeLeaveRoom:
+		r.handleLeaveRoom(m.RoomName, m.NodeID)
+
+	case MessageTypeParticipant:
+		r.handleParticipant(m)
+	}
+}
+
+func (r *Relay) handleSubscribe(addr net.Addr, nodeID livekit.NodeID, trackID livekit.TrackID, quality livekit.VideoQuality) {
+	key := forwarderKey{nodeID: nodeID, trackID: trackID}
+	r.lock.RLock()
+	f := r.forwarders[key]
+	r.lock.RUnlock()
+
+	if f == nil {
+		track := r.params.TrackResolver(trackID)
+		if track == nil {
+			r.params.Logger.Debugw("cascade subscription to unknown track", "edgeNodeID", nodeID, "trackID", trackID)
+			return
+		}
+		receivers := track.Receivers()
+		if len(receivers) == 0 {
+			return
+		}
+
+		// only the primary codec is relayed
+		f = newTrackForwarder(r, nodeID, addr, receivers[0], func(f *TrackForwarder) {
+			r.lock.Lock()
+			if r.forwarders[key] == f {
+				delete(r.forwarders, key)
+			}
+			r.lock.Unlock()
+		})
+
+		r.lock.Lock()
+		if existing := r.forwarders[key]; existing != nil {
+			r.lock.Unlock()
+			f = existing
+		} else {
+			r.forwarders[key] = f
+			r.lock.Unlock()
+
+			if err := receivers[0].AddDownTrack(f); err != nil {
+				r.params.Logger.Warnw("could not relay track", err, "edgeNodeID", nodeID, "trackID", trackID)
+				f.Close()
+				return
+			}
+			f.logger.Infow("relaying track to edge node", "addr", addr)
+		}
+	}
+
+	f.refresh(addr)
+	f.SetMaxQuality(quality)
+	r.notifyNodeQuality(f, quality)
+	// sent with every subscription refresh, so that a lost reply does not stall the edge
+	r.sendTrackCodec(f)
+}
+
+// trackCodec is the payload of MessageTypeTrackCodec, edges need it to set up the receiver of a relayed track
+type trackCodec struct {
+	Codec            webrtc.RTPCodecParameters            `json:"codec"`
+	HeaderExtensions []webrtc.RTPHeaderExtensionParameter `json:"headerExtensions,omitempty"`
+}
+
+func (r *Relay) sendTrackCodec(f *TrackForwarder) {
+	receiver := f.getReceiver()
+	payload, err := json.Marshal(&trackCodec{
+		Codec:            receiver.Codec(),
+		HeaderExtensions: receiver.HeaderExtensions(),
+	})
+	if err != nil {
+		f.logger.Warnw("could not marshal relayed track codec", err)
+		return
+	}
+	r.send(f.getAddr(), &Message{
+		Type:    MessageTypeTrackCodec,
+		NodeID:  r.params.NodeID,
+		TrackID: f.trackID,
+		Payload: payload,
+	})
+}
+
+func (r *Relay) removeForwarder(f *TrackForwarder) {
+	f.getReceiver().DeleteDownTrack(f.SubscriberID())
+	f.Close()
+	r.notifyNodeQuality(f, livekit.VideoQuality_OFF)
+}
+
+// notifyNodeQuality lets dynacast of the origin track know the quality the edge node needs,
+// so that layers subscribed only by participants of edge nodes are not paused
+func (r *Relay) notifyNodeQuality(f *TrackForwarder, quality livekit.VideoQuality) {
+	track := r.params.TrackResolver(f.trackID)
+	if track == nil {
+		return
+	}
+
+	receiver := f.getReceiver()
+	if !mime.IsMimeTypeVideo(receiver.Mime()) {
+		track.NotifySubscriptionNode(f.nodeID, []*livekit.SubscribedAudioCodec{
+			{Codec: receiver.Mime().String(), Enabled: quality != livekit.VideoQuality_OFF},
+		})
+		return
+	}
+	track.NotifySubscriberNodeMaxQuality(f.nodeID, []types.SubscribedCodecQuality{
+		{CodecMime: receiver.Mime(), Quality: quality},
+	})
+}
+
+func (r *Relay) refreshWorker() {
+	ticker := time.NewTicker(r.params.SubscriptionTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.closed.Watch():
+			return
+
+		case now := <-ticker.C:
+			r.lock.Lock()
+			var expired []*TrackForwarder
+			for key, f := range r.forwarders {
+				if f.isExpired(now, r.params.SubscriptionTimeout) {
+					expired = append(expired, f)
+					delete(r.forwarders, key)
+				}
+			}
+			subscriptions := make([]*Subscription, 0, len(r.subscriptions))
+			for _, s := range r.subscriptions {
+				subscriptions = append(subscriptions, s)
+			}
+			r.lock.Unlock()
+
+			for _, f := range expired {
+				f.logger.Infow("cascade subscription expired")
+				r.removeForwarder(f)
+			}
+			r.pruneNonces(now)
+			r.pruneFragments(now)
+			for _, s := range subscriptions {
+				s.sendSubscribe()
+			}
+			r.refreshRooms(now)
+		}
+	}
+}
+
+// ------------------------------------------------
+
+// Subscription receives a track relayed by its origin node
+type Subscription struct {
+	relay        *Relay
+	originNodeID livekit.NodeID
+	origin       net.Addr
+	trackID      livekit.TrackID
+
+	lock           sync.RWMutex
+	quality        livekit.VideoQuality
+	onPacket       func(layer int32, pkt []byte)
+	onSenderReport func(layer int32, sr *livekit.RTCPSenderReportState)
+	onTrackCodec   func(codec webrtc.RTPCodecParameters, headerExtensions []webrtc.RTPHeaderExtensionParameter)
+	hasTrackCodec  bool
+
+	closed atomic.Bool
+}
+
+func newSubscription(relay *Relay, originNodeID livekit.NodeID, origin net.Addr, trackID livekit.TrackID) *Subscription {
+	return &Subscription{
+		relay:        relay,
+		originNodeID: originNodeID,
+		origin:       origin,
+		trackID:      trackID,
+		quality:      livekit.VideoQuality_OFF,
+	}
+}
+
+func (s *Subscription) TrackID() livekit.TrackID {
+	return s.trackID
+}
+
+// OnPacket sets the callback receiving RTP packets of the track, the packet is only valid during the callback
+func (s *Subscription) OnPacket(f func(layer int32, pkt []byte)) {
+	s.lock.Lock()
+	s.onPacket = f
+	s.lock.Unlock()
+}
+
+func (s *Subscription) OnSenderReport(f func(layer int32, sr *livekit.RTCPSenderReportState)) {
+	s.lock.Lock()
+	s.onSenderReport = f
+	s.lock.Unlock()
+}
+
+// OnTrackCodec sets the callback receiving the codec of the track, it is called once, before packets are delivered
+func (s *Subscription) OnTrackCodec(f func(codec webrtc.RTPCodecParameters, headerExtensions []webrtc.RTPHeaderExtensionParameter)) {
+	s.lock.Lock()
+	s.onTrackCodec = f
+	s.lock.Unlock()
+}
+
+// SetMaxQuality sets the highest quality subscribed to by participants of this node,
+// the origin does not relay higher spatial layers
+func (s *Subscription) SetMaxQuality(quality livekit.VideoQuality) {
+	s.setMaxQuality(quality)
+}
+
+func (s *Subscription) setMaxQuality(quality livekit.VideoQuality) bool {
+	s.lock.Lock()
+	changed := s.quality != quality
+	s.quality = quality
+	s.lock.Unlock()
+
+	if changed {
+		s.sendSubscribe()
+	}
+	return changed
+}
+
+func (s *Subscription) SendPLI(layer int32) {
+	if s.closed.Load() {
+		return
+	}
+	s.relay.send(s.origin, &Message{
+		Type:    MessageTypePLI,
+		NodeID:  s.relay.params.NodeID,
+		TrackID: s.trackID,
+		Layer:   layer,
+	})
+}
+
+func (s *Subscription) Close() {
+	if s.closed.Swap(true) {
+		return
+	}
+	s.relay.removeSubscription(s)
+	s.relay.send(s.origin, &Message{
+		Type:    MessageTypeUnsubscribe,
+		NodeID:  s.relay.params.NodeID,
+		TrackID: s.trackID,
+	})
+}
+
+func (s *Subscription) sendSubscribe() {
+	if s.closed.Load() {
+		return
+	}
+
+	s.lock.RLock()
+	quality := s.quality
+	s.lock.RUnlock()
+
+	s.relay.send(s.origin, &Message{
+		Type:    MessageTypeSubscribe,
+		NodeID:  s.relay.params.NodeID,
+		TrackID: s.trackID,
+		Payload: append([]byte{byte(quality)}, s.relay.params.AdvertisedAddr.String()...),
+	})
+}
+
+func (s *Subscription) handleMessage(m *Message) {
+	if m.Type == MessageTypeTrackCodec {
+		s.handleTrackCodec(m)
+		return
+	}
+
+	s.lock.RLock()
+	onPacket, onSenderReport, hasTrackCodec := s.onPacket, s.onSenderReport, s.hasTrackCodec
+	s.lock.RUnlock()
+	if !hasTrackCodec {
+		// cannot be decoded before the codec is known
+		return
+	}
+
+	switch m.Type {
+	case MessageTypeRTP:
+		if onPacket != nil {
+			onPacket(m.Layer, m.Payload)
+		}
+
+	case MessageTypeSenderReport:
+		if onSenderReport == nil {
+			return
+		}
+		sr := &livekit.RTCPSenderReportState{}
+		if err := proto.Unmarshal(m.Payload, sr); err != nil {
+			s.relay.params.Logger.Debugw("could not unmarshal relayed sender report", "error", err, "trackID", s.trackID)
+			return
+		}
+		onSenderReport(m.Layer, sr)
+	}
+}
+
+func (s *Subscription) handleTrackCodec(m *Message) {
+	s.lock.Lock()
+	if s.hasTrackCodec {
+		s.lock.Unlock()
+		return
+	}
+	var tc trackCodec
+	if err := json.Unmarshal(m.Payload, &tc); err != nil {
+		s.lock.Unlock()
+		s.relay.params.Logger.Debugw("could not unmarshal relayed track codec", "error", err, "trackID", s.trackID)
+		return
+	}
+	s.hasTrackCodec = true
+	onTrackCodec := s.onTrackCodec
+	s.lock.Unlock()
+
+	if onTrackCodec != nil {
+		onTrackCodec(tc.Codec, tc.HeaderExtensions)
+	}
+}
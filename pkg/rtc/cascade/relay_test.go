@@ -0,0 +1,241 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+type testReceiver struct {
+	sfu.TrackReceiver
+
+	lock       sync.Mutex
+	downTracks map[livekit.ParticipantID]sfu.TrackSender
+	plis       []int32
+}
+
+func (r *testReceiver) TrackID() livekit.TrackID { return "TR_video" }
+func (r *testReceiver) Mime() mime.MimeType      { return mime.MimeTypeVP8 }
+func (r *testReceiver) Codec() webrtc.RTPCodecParameters {
+	return webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: mime.MimeTypeVP8.String(), ClockRate: 90000},
+		PayloadType:        96,
+	}
+}
+func (r *testReceiver) HeaderExtensions() []webrtc.RTPHeaderExtensionParameter { return nil }
+func (r *testReceiver) TrackInfo() *livekit.TrackInfo {
+	return &livekit.TrackInfo{
+		Sid: "TR_video",
+		Layers: []*livekit.VideoLayer{
+			{Quality: livekit.VideoQuality_LOW, SpatialLayer: 0},
+			{Quality: livekit.VideoQuality_MEDIUM, SpatialLayer: 1},
+			{Quality: livekit.VideoQuality_HIGH, SpatialLayer: 2},
+		},
+	}
+}
+
+func (r *testReceiver) AddDownTrack(track sfu.TrackSender) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.downTracks[track.SubscriberID()] = track
+	return nil
+}
+
+func (r *testReceiver) DeleteDownTrack(subscriberID livekit.ParticipantID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.downTracks, subscriberID)
+}
+
+func (r *testReceiver) SendPLI(layer int32, _ bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.plis = append(r.plis, layer)
+}
+
+func (r *testReceiver) downTrack(subscriberID livekit.ParticipantID) sfu.TrackSender {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.downTracks[subscriberID]
+}
+
+func (r *testReceiver) numPLIs() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.plis)
+}
+
+func newTestRelay(t *testing.T, nodeID livekit.NodeID, resolver TrackResolver) *Relay {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	r := NewRelay(RelayParams{
+		NodeID:        nodeID,
+		Secret:        "secret",
+		Conn:          conn,
+		TrackResolver: resolver,
+	})
+	r.Start()
+	t.Cleanup(r.Stop)
+	return r
+}
+
+func TestRelay(t *testing.T) {
+	receiver := &testReceiver{downTracks: make(map[livekit.ParticipantID]sfu.TrackSender)}
+	track := &typesfakes.FakeLocalMediaTrack{}
+	track.ReceiversReturns([]sfu.TrackReceiver{receiver})
+
+	origin := newTestRelay(t, "ND_origin", func(trackID livekit.TrackID) types.LocalMediaTrack {
+		if trackID == "TR_video" {
+			return track
+		}
+		return nil
+	})
+	edge := newTestRelay(t, "ND_edge", func(_ livekit.TrackID) types.LocalMediaTrack { return nil })
+
+	sub, err := edge.Subscribe("ND_origin", origin.LocalAddr(), "TR_video", livekit.VideoQuality_MEDIUM)
+	require.NoError(t, err)
+
+	codecs := make(chan webrtc.RTPCodecParameters, 1)
+	sub.OnTrackCodec(func(codec webrtc.RTPCodecParameters, _ []webrtc.RTPHeaderExtensionParameter) {
+		codecs <- codec
+	})
+	packets := make(chan int32, 10)
+	sub.OnPacket(func(layer int32, pkt []byte) {
+		var p rtp.Packet
+		if p.Unmarshal(pkt) == nil {
+			packets <- layer
+		}
+	})
+
+	var forwarder sfu.TrackSender
+	require.Eventually(t, func() bool {
+		forwarder = receiver.downTrack(NodeSubscriberID("ND_edge"))
+		return forwarder != nil
+	}, time.Second, 10*time.Millisecond)
+
+	// edge node demand is reported to dynacast
+	require.Eventually(t, func() bool { return track.NotifySubscriberNodeMaxQualityCallCount() > 0 }, time.Second, 10*time.Millisecond)
+	nodeID, qualities := track.NotifySubscriberNodeMaxQualityArgsForCall(0)
+	require.Equal(t, livekit.NodeID("ND_edge"), nodeID)
+	require.Equal(t, livekit.VideoQuality_MEDIUM, qualities[0].Quality)
+
+	// the codec is sent before packets can be delivered
+	select {
+	case codec := <-codecs:
+		require.Equal(t, mime.MimeTypeVP8.String(), codec.MimeType)
+	case <-time.After(time.Second):
+		require.Fail(t, "track codec not received")
+	}
+
+	packet := func(sn uint16) *buffer.ExtPacket {
+		p := &rtp.Packet{Header: rtp.Header{Version: 2, SequenceNumber: sn, SSRC: 1234}, Payload: []byte{1, 2, 3}}
+		return &buffer.ExtPacket{Packet: p}
+	}
+
+	// layers above the subscribed quality are not relayed
+	require.Equal(t, int32(0), forwarder.WriteRTP(packet(1), 2))
+	require.Equal(t, int32(1), forwarder.WriteRTP(packet(2), 1))
+	select {
+	case layer := <-packets:
+		require.Equal(t, int32(1), layer)
+	case <-time.After(time.Second):
+		require.Fail(t, "relayed packet not received")
+	}
+
+	sub.SendPLI(1)
+	require.Eventually(t, func() bool { return receiver.numPLIs() == 1 }, time.Second, 10*time.Millisecond)
+
+	sub.Close()
+	require.Eventually(t, func() bool { return receiver.downTrack(NodeSubscriberID("ND_edge")) == nil }, time.Second, 10*time.Millisecond)
+	require.True(t, forwarder.IsClosed())
+}
+
+func TestRelayRejectsUnauthenticated(t *testing.T) {
+	receiver := &testReceiver{downTracks: make(map[livekit.ParticipantID]sfu.TrackSender)}
+	track := &typesfakes.FakeLocalMediaTrack{}
+	track.ReceiversReturns([]sfu.TrackReceiver{receiver})
+	origin := newTestRelay(t, "ND_origin", func(_ livekit.TrackID) types.LocalMediaTrack { return track })
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	buf, err := (&Message{Type: MessageTypeSubscribe, NodeID: "ND_other", TrackID: "TR_video", Payload: []byte{byte(livekit.VideoQuality_HIGH)}}).Marshal([]byte("wrong"))
+	require.NoError(t, err)
+	_, err = conn.WriteTo(buf, origin.LocalAddr())
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	require.Nil(t, receiver.downTrack(NodeSubscriberID("ND_other")))
+}
+
+func TestRelayRejectsReplayed(t *testing.T) {
+	receiver := &testReceiver{downTracks: make(map[livekit.ParticipantID]sfu.TrackSender)}
+	track := &typesfakes.FakeLocalMediaTrack{}
+	track.ReceiversReturns([]sfu.TrackReceiver{receiver})
+	origin := newTestRelay(t, "ND_origin", func(_ livekit.TrackID) types.LocalMediaTrack { return track })
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	send := func(m *Message) {
+		buf, err := m.Marshal([]byte("secret"))
+		require.NoError(t, err)
+		_, err = conn.WriteTo(buf, origin.LocalAddr())
+		require.NoError(t, err)
+	}
+	subscribe := &Message{
+		Type:      MessageTypeSubscribe,
+		Timestamp: time.Now().UnixNano(),
+		Nonce:     1,
+		NodeID:    "ND_other",
+		TrackID:   "TR_video",
+		Payload:   append([]byte{byte(livekit.VideoQuality_HIGH)}, conn.LocalAddr().String()...),
+	}
+	send(subscribe)
+	require.Eventually(t, func() bool { return receiver.downTrack(NodeSubscriberID("ND_other")) != nil }, time.Second, 10*time.Millisecond)
+
+	send(&Message{Type: MessageTypeUnsubscribe, Timestamp: time.Now().UnixNano(), Nonce: 2, NodeID: "ND_other", TrackID: "TR_video"})
+	require.Eventually(t, func() bool { return receiver.downTrack(NodeSubscriberID("ND_other")) == nil }, time.Second, 10*time.Millisecond)
+
+	// a captured subscription cannot be replayed
+	send(subscribe)
+
+	// nor sent again with a new nonce once it is stale
+	stale := *subscribe
+	stale.Nonce = 3
+	stale.Timestamp = time.Now().Add(-2 * maxMessageAge).UnixNano()
+	send(&stale)
+
+	time.Sleep(100 * time.Millisecond)
+	require.Nil(t, receiver.downTrack(NodeSubscriberID("ND_other")))
+}
@@ -0,0 +1,545 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+const (
+	// participant updates are split in fragments fitting a datagram within the usual MTU,
+	// the info of a participant can be larger than a datagram with its metadata and attributes
+	maxParticipantFragmentSize = 1100
+	maxParticipantFragments    = 512
+	// update ID, fragment index, fragment count
+	participantFragmentHeaderSize = 12
+	// fragments of an update are dropped when it is not complete within this duration
+	participantFragmentTimeout   = 5 * time.Second
+	maxPendingParticipantUpdates = 1024
+)
+
+var (
+	errInvalidParticipantUpdate  = errors.New("invalid cascade participant update")
+	errParticipantUpdateTooLarge = errors.New("cascade participant update too large")
+)
+
+// ParticipantUpdate is the state of a participant connected to one of the nodes hosting a room
+type ParticipantUpdate struct {
+	Info *livekit.ParticipantInfo
+	// subscription permission set by the participant, nil when all participants are allowed
+	Permission *livekit.SubscriptionPermission
+}
+
+// participantMessage is the payload of MessageTypeParticipant,
+//
+//	| node ID length | node ID | origin length | origin | info length (4 bytes) | info | permission |
+//
+// the node the participant is connected to is carried in the payload as updates are relayed by the primary node.
+// It is sent in fragments, each prefixed by
+//
+//	| update ID (8 bytes) | fragment index (2 bytes) | fragment count (2 bytes) |
+type participantMessage struct {
+	nodeID livekit.NodeID
+	origin string
+	update *ParticipantUpdate
+}
+
+func (p *participantMessage) marshal() ([]byte, error) {
+	if len(p.nodeID) > maxIDLength || len(p.origin) > maxIDLength {
+		return nil, errMessageIDTooLong
+	}
+	info, err := proto.Marshal(p.update.Info)
+	if err != nil {
+		return nil, err
+	}
+	var permission []byte
+	if p.update.Permission != nil {
+		if permission, err = proto.Marshal(p.update.Permission); err != nil {
+			return nil, err
+		}
+	}
+
+	buf := make([]byte, 0, 8+len(p.nodeID)+len(p.origin)+len(info)+len(permission))
+	buf = appendID(buf, string(p.nodeID))
+	buf = appendID(buf, p.origin)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(info)))
+	buf = append(buf, info...)
+	buf = append(buf, permission...)
+	return buf, nil
+}
+
+func (p *participantMessage) unmarshal(buf []byte) error {
+	offset := 0
+	nodeID, ok := readID(buf, &offset)
+	if !ok {
+		return errInvalidParticipantUpdate
+	}
+	origin, ok := readID(buf, &offset)
+	if !ok || len(buf) < offset+4 {
+		return errInvalidParticipantUpdate
+	}
+	infoLength := int(binary.BigEndian.Uint32(buf[offset:]))
+	offset += 4
+	if len(buf) < offset+infoLength {
+		return errInvalidParticipantUpdate
+	}
+
+	update := &ParticipantUpdate{Info: &livekit.ParticipantInfo{}}
+	if err := proto.Unmarshal(buf[offset:offset+infoLength], update.Info); err != nil {
+		return err
+	}
+	offset += infoLength
+	if offset < len(buf) {
+		update.Permission = &livekit.SubscriptionPermission{}
+		if err := proto.Unmarshal(buf[offset:], update.Permission); err != nil {
+			return err
+		}
+	}
+
+	p.nodeID = livekit.NodeID(nodeID)
+	p.origin = origin
+	p.update = update
+	return nil
+}
+
+type fragmentKey struct {
+	nodeID   livekit.NodeID
+	updateID uint64
+}
+
+type fragmentedUpdate struct {
+	fragments [][]byte
+	received  int
+	createdAt time.Time
+}
+
+// ------------------------------------------------
+
+type roomMember struct {
+	addr      net.Addr
+	refreshed time.Time
+}
+
+type remoteParticipant struct {
+	nodeID    livekit.NodeID
+	origin    net.Addr
+	update    *ParticipantUpdate
+	refreshed time.Time
+}
+
+// cascadedRoom is a room hosted by several nodes
+type cascadedRoom struct {
+	primaryNodeID livekit.NodeID
+	// nil when this node is the primary node of the room
+	primary net.Addr
+	// other nodes hosting the room, only tracked by the primary node
+	members      map[livekit.NodeID]*roomMember
+	participants map[livekit.ParticipantIdentity]*remoteParticipant
+}
+
+func (c *cascadedRoom) isPrimary() bool {
+	return c.primary == nil
+}
+
+func (c *cascadedRoom) memberAddrs(except livekit.NodeID) []net.Addr {
+	addrs := make([]net.Addr, 0, len(c.members))
+	for nodeID, m := range c.members {
+		if nodeID != except {
+			addrs = append(addrs, m.addr)
+		}
+	}
+	return addrs
+}
+
+// JoinRoom starts exchanging participants of the room with the other nodes hosting it.
+// primary is the address of the relay of the room's primary node, nil when this node is the primary node.
+func (r *Relay) JoinRoom(roomName livekit.RoomName, primaryNodeID livekit.NodeID, primary net.Addr) error {
+	if r.closed.IsBroken() {
+		return ErrRelayClosed
+	}
+	if len(roomName) > maxIDLength {
+		return errMessageIDTooLong
+	}
+
+	r.lock.Lock()
+	if r.rooms[roomName] != nil {
+		r.lock.Unlock()
+		return nil
+	}
+	r.rooms[roomName] = &cascadedRoom{
+		primaryNodeID: primaryNodeID,
+		primary:       primary,
+		members:       make(map[livekit.NodeID]*roomMember),
+		participants:  make(map[livekit.ParticipantIdentity]*remoteParticipant),
+	}
+	r.lock.Unlock()
+
+	if primary != nil {
+		r.params.Logger.Infow("joining cascaded room", "room", roomName, "primaryNodeID", primaryNodeID, "primary", primary)
+		r.sendJoinRoom(roomName, primary)
+	}
+	return nil
+}
+
+// LeaveRoom stops exchanging participants of the room
+func (r *Relay) LeaveRoom(roomName livekit.RoomName) {
+	r.lock.Lock()
+	room := r.rooms[roomName]
+	delete(r.rooms, roomName)
+	r.lock.Unlock()
+
+	if room != nil && !room.isPrimary() {
+		r.send(room.primary, &Message{
+			Type:     MessageTypeLeaveRoom,
+			NodeID:   r.params.NodeID,
+			RoomName: roomName,
+		})
+	}
+}
+
+// UpdateParticipant sends the state of a participant connected to this node to the other nodes hosting the room
+func (r *Relay) UpdateParticipant(roomName livekit.RoomName, update *ParticipantUpdate) error {
+	r.lock.RLock()
+	room := r.rooms[roomName]
+	var addrs []net.Addr
+	if room != nil {
+		if room.isPrimary() {
+			addrs = room.memberAddrs("")
+		} else {
+			addrs = []net.Addr{room.primary}
+		}
+	}
+	r.lock.RUnlock()
+
+	return r.sendParticipant(addrs, roomName, &participantMessage{
+		nodeID: r.params.NodeID,
+		origin: r.params.AdvertisedAddr.String(),
+		update: update,
+	})
+}
+
+func (r *Relay) sendJoinRoom(roomName livekit.RoomName, primary net.Addr) {
+	r.send(primary, &Message{
+		Type:     MessageTypeJoinRoom,
+		NodeID:   r.params.NodeID,
+		RoomName: roomName,
+		Payload:  []byte(r.params.AdvertisedAddr.String()),
+	})
+}
+
+func (r *Relay) sendParticipant(addrs []net.Addr, roomName livekit.RoomName, p *participantMessage) error {
+	if len(addrs) == 0 {
+		return nil
+	}
+	payload, err := p.marshal()
+	if err != nil {
+		return err
+	}
+	count := (len(payload) + maxParticipantFragmentSize - 1) / maxParticipantFragmentSize
+	if count > maxParticipantFragments {
+		return errParticipantUpdateTooLarge
+	}
+
+	updateID := r.nonce.Inc()
+	for index := range count {
+		chunk := payload[index*maxParticipantFragmentSize : min((index+1)*maxParticipantFragmentSize, len(payload))]
+		fragment := make([]byte, 0, participantFragmentHeaderSize+len(chunk))
+		fragment = binary.BigEndian.AppendUint64(fragment, updateID)
+		fragment = binary.BigEndian.AppendUint16(fragment, uint16(index))
+		fragment = binary.BigEndian.AppendUint16(fragment, uint16(count))
+		fragment = append(fragment, chunk...)
+		if err := r.sendTo(addrs, &Message{
+			Type:     MessageTypeParticipant,
+			NodeID:   r.params.NodeID,
+			RoomName: roomName,
+			Payload:  fragment,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forwardParticipant sends participant updates the relay sends by itself, when a node joins a room,
+// on refresh and when relaying updates through the primary node, errors are logged
+func (r *Relay) forwardParticipant(addrs []net.Addr, roomName livekit.RoomName, p *participantMessage) {
+	if err := r.sendParticipant(addrs, roomName, p); err != nil {
+		r.params.Logger.Warnw(
+			"could not send cascade participant update", err,
+			"room", roomName,
+			"participant", p.update.Info.Identity,
+		)
+	}
+}
+
+func (r *Relay) localParticipants(roomName livekit.RoomName) []*participantMessage {
+	if r.params.ParticipantResolver == nil {
+		return nil
+	}
+	updates := r.params.ParticipantResolver(roomName)
+	messages := make([]*participantMessage, 0, len(updates))
+	for _, update := range updates {
+		messages = append(messages, &participantMessage{
+			nodeID: r.params.NodeID,
+			origin: r.params.AdvertisedAddr.String(),
+			update: update,
+		})
+	}
+	return messages
+}
+
+func (r *Relay) handleJoinRoom(roomName livekit.RoomName, nodeID livekit.NodeID, addr net.Addr) {
+	if nodeID == r.params.NodeID {
+		return
+	}
+
+	r.lock.Lock()
+	room := r.rooms[roomName]
+	if room == nil || !room.isPrimary() {
+		r.lock.Unlock()
+		return
+	}
+	member := room.members[nodeID]
+	joined := member == nil
+	if joined {
+		member = &roomMember{}
+		room.members[nodeID] = member
+	}
+	member.addr = addr
+	member.refreshed = time.Now()
+
+	// participants of the other nodes, the joining node gets the participants of this node below
+	var others []*participantMessage
+	if joined {
+		for _, p := range room.participants {
+			if p.nodeID != nodeID {
+				others = append(others, &participantMessage{nodeID: p.nodeID, origin: p.origin.String(), update: p.update})
+			}
+		}
+	}
+	r.lock.Unlock()
+
+	if !joined {
+		return
+	}
+	r.params.Logger.Infow("node joined cascaded room", "room", roomName, "nodeID", nodeID, "addr", addr)
+	for _, p := range append(r.localParticipants(roomName), others...) {
+		r.forwardParticipant([]net.Addr{addr}, roomName, p)
+	}
+}
+
+func (r *Relay) handleLeaveRoom(roomName livekit.RoomName, nodeID livekit.NodeID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if room := r.rooms[roomName]; room != nil && room.isPrimary() {
+		delete(room.members, nodeID)
+	}
+}
+
+// reassembleParticipant collects the fragments of a participant update, it returns the update once complete
+func (r *Relay) reassembleParticipant(m *Message) ([]byte, bool) {
+	if len(m.Payload) < participantFragmentHeaderSize {
+		return nil, false
+	}
+	updateID := binary.BigEndian.Uint64(m.Payload)
+	index := int(binary.BigEndian.Uint16(m.Payload[8:]))
+	count := int(binary.BigEndian.Uint16(m.Payload[10:]))
+	data := m.Payload[participantFragmentHeaderSize:]
+	if count == 0 || count > maxParticipantFragments || index >= count {
+		return nil, false
+	}
+	if count == 1 {
+		return data, true
+	}
+
+	key := fragmentKey{nodeID: m.NodeID, updateID: updateID}
+	r.fragmentLock.Lock()
+	defer r.fragmentLock.Unlock()
+
+	u := r.fragments[key]
+	if u == nil {
+		if len(r.fragments) >= maxPendingParticipantUpdates {
+			return nil, false
+		}
+		u = &fragmentedUpdate{
+			fragments: make([][]byte, count),
+			createdAt: time.Now(),
+		}
+		r.fragments[key] = u
+	}
+	if len(u.fragments) != count {
+		delete(r.fragments, key)
+		return nil, false
+	}
+	if u.fragments[index] == nil {
+		// the payload references the read buffer
+		u.fragments[index] = slices.Clone(data)
+		u.received++
+	}
+	if u.received < count {
+		return nil, false
+	}
+	delete(r.fragments, key)
+	return bytes.Join(u.fragments, nil), true
+}
+
+func (r *Relay) pruneFragments(now time.Time) {
+	r.fragmentLock.Lock()
+	defer r.fragmentLock.Unlock()
+
+	for key, u := range r.fragments {
+		if now.Sub(u.createdAt) > participantFragmentTimeout {
+			delete(r.fragments, key)
+		}
+	}
+}
+
+func (r *Relay) handleParticipant(m *Message) {
+	payload, ok := r.reassembleParticipant(m)
+	if !ok {
+		return
+	}
+
+	var p participantMessage
+	if err := p.unmarshal(payload); err != nil {
+		r.params.Logger.Debugw("dropping cascade participant update", "error", err, "room", m.RoomName, "nodeID", m.NodeID)
+		return
+	}
+	if p.nodeID == r.params.NodeID {
+		return
+	}
+	origin, err := net.ResolveUDPAddr("udp", p.origin)
+	if err != nil {
+		r.params.Logger.Debugw("invalid cascade participant origin", "error", err, "room", m.RoomName, "nodeID", p.nodeID)
+		return
+	}
+
+	identity := livekit.ParticipantIdentity(p.update.Info.Identity)
+	now := time.Now()
+
+	r.lock.Lock()
+	room := r.rooms[m.RoomName]
+	if room == nil {
+		r.lock.Unlock()
+		return
+	}
+
+	var forwardTo []net.Addr
+	if room.isPrimary() {
+		// updates of a member double as its heartbeat
+		if member := room.members[p.nodeID]; member != nil {
+			member.addr = origin
+			member.refreshed = now
+		} else {
+			room.members[p.nodeID] = &roomMember{addr: origin, refreshed: now}
+		}
+		forwardTo = room.memberAddrs(p.nodeID)
+	}
+
+	existing := room.participants[identity]
+	if existing != nil && existing.update.Info.Sid == p.update.Info.Sid && existing.update.Info.Version > p.update.Info.Version {
+		// reordered while relayed
+		r.lock.Unlock()
+		return
+	}
+	if p.update.Info.State == livekit.ParticipantInfo_DISCONNECTED {
+		if existing != nil && existing.update.Info.Sid == p.update.Info.Sid {
+			delete(room.participants, identity)
+		}
+	} else {
+		room.participants[identity] = &remoteParticipant{
+			nodeID:    p.nodeID,
+			origin:    origin,
+			update:    p.update,
+			refreshed: now,
+		}
+	}
+	r.lock.Unlock()
+
+	r.forwardParticipant(forwardTo, m.RoomName, &p)
+	if onParticipantUpdate := r.params.OnParticipantUpdate; onParticipantUpdate != nil {
+		onParticipantUpdate(m.RoomName, p.nodeID, origin, p.update)
+	}
+}
+
+// refreshRooms sends the participants of this node to the other nodes hosting each room,
+// and drops members and participants of other nodes which stopped refreshing
+func (r *Relay) refreshRooms(now time.Time) {
+	type expiredParticipant struct {
+		roomName livekit.RoomName
+		p        *remoteParticipant
+	}
+	var expired []expiredParticipant
+	targets := make(map[livekit.RoomName][]net.Addr)
+
+	r.lock.Lock()
+	for roomName, room := range r.rooms {
+		for nodeID, member := range room.members {
+			if now.Sub(member.refreshed) > r.params.SubscriptionTimeout {
+				delete(room.members, nodeID)
+			}
+		}
+		for identity, p := range room.participants {
+			if now.Sub(p.refreshed) > r.params.SubscriptionTimeout {
+				delete(room.participants, identity)
+				expired = append(expired, expiredParticipant{roomName: roomName, p: p})
+			}
+		}
+
+		if room.isPrimary() {
+			targets[roomName] = room.memberAddrs("")
+		} else {
+			targets[roomName] = []net.Addr{room.primary}
+		}
+	}
+	r.lock.Unlock()
+
+	for _, e := range expired {
+		info := proto.Clone(e.p.update.Info).(*livekit.ParticipantInfo)
+		info.State = livekit.ParticipantInfo_DISCONNECTED
+		r.params.Logger.Infow(
+			"cascaded participant expired",
+			"room", e.roomName,
+			"nodeID", e.p.nodeID,
+			"participant", info.Identity,
+			"pID", info.Sid,
+		)
+		if onParticipantUpdate := r.params.OnParticipantUpdate; onParticipantUpdate != nil {
+			onParticipantUpdate(e.roomName, e.p.nodeID, e.p.origin, &ParticipantUpdate{Info: info, Permission: e.p.update.Permission})
+		}
+	}
+
+	for roomName, addrs := range targets {
+		r.lock.RLock()
+		room := r.rooms[roomName]
+		r.lock.RUnlock()
+		if room != nil && !room.isPrimary() {
+			r.sendJoinRoom(roomName, room.primary)
+		}
+		for _, p := range r.localParticipants(roomName) {
+			r.forwardParticipant(addrs, roomName, p)
+		}
+	}
+}
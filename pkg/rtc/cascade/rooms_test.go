@@ -0,0 +1,184 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cascade
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+func TestParticipantMessage(t *testing.T) {
+	p := &participantMessage{
+		nodeID: "ND_edge",
+		origin: "127.0.0.1:7890",
+		update: &ParticipantUpdate{
+			Info: &livekit.ParticipantInfo{Sid: "PA_a", Identity: "a", State: livekit.ParticipantInfo_ACTIVE},
+			Permission: &livekit.SubscriptionPermission{
+				TrackPermissions: []*livekit.TrackPermission{{ParticipantIdentity: "b", AllTracks: true}},
+			},
+		},
+	}
+	buf, err := p.marshal()
+	require.NoError(t, err)
+
+	var decoded participantMessage
+	require.NoError(t, decoded.unmarshal(buf))
+	require.Equal(t, p.nodeID, decoded.nodeID)
+	require.Equal(t, p.origin, decoded.origin)
+	require.True(t, proto.Equal(p.update.Info, decoded.update.Info))
+	require.True(t, proto.Equal(p.update.Permission, decoded.update.Permission))
+
+	// no permission
+	p.update.Permission = nil
+	buf, err = p.marshal()
+	require.NoError(t, err)
+	require.NoError(t, decoded.unmarshal(buf))
+	require.Nil(t, decoded.update.Permission)
+
+	require.ErrorIs(t, decoded.unmarshal(buf[:len(buf)-1]), errInvalidParticipantUpdate)
+}
+
+type testRoomNode struct {
+	relay *Relay
+
+	lock         sync.Mutex
+	local        []*ParticipantUpdate
+	participants map[livekit.ParticipantIdentity]livekit.NodeID
+	infos        map[livekit.ParticipantIdentity]*livekit.ParticipantInfo
+}
+
+func newTestRoomNode(t *testing.T, nodeID livekit.NodeID, identity livekit.ParticipantIdentity) *testRoomNode {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	n := &testRoomNode{
+		local: []*ParticipantUpdate{{
+			Info: &livekit.ParticipantInfo{Sid: "PA_" + string(identity), Identity: string(identity), State: livekit.ParticipantInfo_ACTIVE},
+		}},
+		participants: make(map[livekit.ParticipantIdentity]livekit.NodeID),
+		infos:        make(map[livekit.ParticipantIdentity]*livekit.ParticipantInfo),
+	}
+	n.relay = NewRelay(RelayParams{
+		NodeID: nodeID,
+		Secret: "secret",
+		Conn:   conn,
+		ParticipantResolver: func(_ livekit.RoomName) []*ParticipantUpdate {
+			n.lock.Lock()
+			defer n.lock.Unlock()
+			return n.local
+		},
+		OnParticipantUpdate: func(_ livekit.RoomName, nodeID livekit.NodeID, _ net.Addr, update *ParticipantUpdate) {
+			n.lock.Lock()
+			defer n.lock.Unlock()
+			identity := livekit.ParticipantIdentity(update.Info.Identity)
+			if update.Info.State == livekit.ParticipantInfo_DISCONNECTED {
+				delete(n.participants, identity)
+				delete(n.infos, identity)
+			} else {
+				n.participants[identity] = nodeID
+				n.infos[identity] = update.Info
+			}
+		},
+	})
+	n.relay.Start()
+	t.Cleanup(n.relay.Stop)
+	return n
+}
+
+func (n *testRoomNode) participantInfo(identity livekit.ParticipantIdentity) *livekit.ParticipantInfo {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.infos[identity]
+}
+
+func (n *testRoomNode) participantNode(identity livekit.ParticipantIdentity) livekit.NodeID {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.participants[identity]
+}
+
+func TestRelayRooms(t *testing.T) {
+	primary := newTestRoomNode(t, "ND_primary", "a")
+	edge1 := newTestRoomNode(t, "ND_edge1", "b")
+	edge2 := newTestRoomNode(t, "ND_edge2", "c")
+
+	require.NoError(t, primary.relay.JoinRoom("room", "ND_primary", nil))
+	require.NoError(t, edge1.relay.JoinRoom("room", "ND_primary", primary.relay.LocalAddr()))
+	require.NoError(t, edge2.relay.JoinRoom("room", "ND_primary", primary.relay.LocalAddr()))
+
+	// edges get the participants of the primary on join
+	require.Eventually(t, func() bool {
+		return edge1.participantNode("a") == "ND_primary" && edge2.participantNode("a") == "ND_primary"
+	}, time.Second, 10*time.Millisecond)
+
+	// participants of edges are relayed through the primary
+	require.NoError(t, edge1.relay.UpdateParticipant("room", edge1.local[0]))
+	require.NoError(t, edge2.relay.UpdateParticipant("room", edge2.local[0]))
+	require.Eventually(t, func() bool {
+		return primary.participantNode("b") == "ND_edge1" &&
+			primary.participantNode("c") == "ND_edge2" &&
+			edge2.participantNode("b") == "ND_edge1" &&
+			edge1.participantNode("c") == "ND_edge2"
+	}, time.Second, 10*time.Millisecond)
+
+	// nodes do not mirror their own participants
+	require.Empty(t, edge1.participantNode("b"))
+
+	edge1.lock.Lock()
+	left := proto.Clone(edge1.local[0].Info).(*livekit.ParticipantInfo)
+	left.State = livekit.ParticipantInfo_DISCONNECTED
+	edge1.local = nil
+	edge1.lock.Unlock()
+	require.NoError(t, edge1.relay.UpdateParticipant("room", &ParticipantUpdate{Info: left}))
+	require.Eventually(t, func() bool {
+		return primary.participantNode("b") == "" && edge2.participantNode("b") == ""
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRelayLargeParticipantUpdates(t *testing.T) {
+	primary := newTestRoomNode(t, "ND_primary", "a")
+	edge1 := newTestRoomNode(t, "ND_edge1", "b")
+	edge2 := newTestRoomNode(t, "ND_edge2", "c")
+
+	roomName := livekit.RoomName(strings.Repeat("r", 300))
+	require.NoError(t, primary.relay.JoinRoom(roomName, "ND_primary", nil))
+	require.NoError(t, edge1.relay.JoinRoom(roomName, "ND_primary", primary.relay.LocalAddr()))
+	require.NoError(t, edge2.relay.JoinRoom(roomName, "ND_primary", primary.relay.LocalAddr()))
+	require.Eventually(t, func() bool {
+		return edge1.participantNode("a") == "ND_primary" && edge2.participantNode("a") == "ND_primary"
+	}, time.Second, 10*time.Millisecond)
+
+	// larger than a datagram, relayed to the other edge through the primary
+	update := &ParticipantUpdate{Info: proto.Clone(edge1.local[0].Info).(*livekit.ParticipantInfo)}
+	update.Info.Metadata = strings.Repeat("m", 64*1024)
+	require.NoError(t, edge1.relay.UpdateParticipant(roomName, update))
+	require.Eventually(t, func() bool {
+		info := edge2.participantInfo("b")
+		return info != nil && info.Metadata == update.Info.Metadata
+	}, time.Second, 10*time.Millisecond)
+
+	update.Info.Metadata = strings.Repeat("m", maxParticipantFragments*maxParticipantFragmentSize)
+	require.ErrorIs(t, edge1.relay.UpdateParticipant(roomName, update), errParticipantUpdateTooLarge)
+
+	require.ErrorIs(t, edge1.relay.JoinRoom(livekit.RoomName(strings.Repeat("r", maxIDLength+1)), "ND_primary", nil), errMessageIDTooLong)
+}
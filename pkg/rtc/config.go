@@ -15,6 +15,9 @@
 package rtc
 
 import (
+	"net"
+
+	"github.com/pion/ice/v4"
 	"github.com/pion/sdp/v3"
 	"github.com/pion/webrtc/v4"
 
@@ -28,6 +31,9 @@ import (
 const (
 	frameMarkingURI        = "urn:ietf:params:rtp-hdrext:framemarking"
 	repairedRTPStreamIDURI = "urn:ietf:params:rtp-hdrext:sdes:repaired-rtp-stream-id"
+
+	iceTCPReadBufferSize  = 50
+	iceTCPWriteBufferSize = 4 * 1024 * 1024
 )
 
 type WebRTCConfig struct {
@@ -37,6 +43,8 @@ type WebRTCConfig struct {
 	Receiver      ReceiverConfig
 	Publisher     DirectionConfig
 	Subscriber    DirectionConfig
+	// enables simulating network impairment
+	Development bool
 }
 
 type ReceiverConfig struct {
@@ -59,14 +67,33 @@ type DirectionConfig struct {
 	RTCPFeedback       RTCPFeedbackConfig
 }
 
-func NewWebRTCConfig(conf *config.Config) (*WebRTCConfig, error) {
+// NewWebRTCConfig creates the WebRTC configuration, iceTCPListener is an optional listener
+// receiving ICE/TCP connections in addition to the ones accepted on rtc.tcp_port
+func NewWebRTCConfig(conf *config.Config, iceTCPListener net.Listener) (*WebRTCConfig, error) {
 	rtcConf := conf.RTC
 
-	webRTCConfig, err := rtcconfig.NewWebRTCConfig(&rtcConf.RTCConfig, conf.Development)
+	mediaConf := rtcConf.RTCConfig
+	if iceTCPListener != nil {
+		// TCP muxes are created here to serve both listeners
+		mediaConf.TCPPort = 0
+		if mediaConf.ForceTCP {
+			mediaConf.ForceTCP = false
+			mediaConf.UDPPort = rtcconfig.PortRange{}
+			mediaConf.ICEPortRangeStart, mediaConf.ICEPortRangeEnd = 0, 0
+		}
+	}
+
+	webRTCConfig, err := rtcconfig.NewWebRTCConfig(&mediaConf, conf.Development)
 	if err != nil {
 		return nil, err
 	}
 
+	if iceTCPListener != nil {
+		if err := setICETCPMux(webRTCConfig, &rtcConf.RTCConfig, iceTCPListener); err != nil {
+			return nil, err
+		}
+	}
+
 	// we don't want to use active TCP on a server, clients should be dialing
 	webRTCConfig.SettingEngine.DisableActiveTCP(true)
 
@@ -86,11 +113,44 @@ func NewWebRTCConfig(conf *config.Config) (*WebRTCConfig, error) {
 			PacketBufferSizeVideo: rtcConf.PacketBufferSizeVideo,
 			PacketBufferSizeAudio: rtcConf.PacketBufferSizeAudio,
 		},
-		Publisher:  getPublisherConfig(false),
-		Subscriber: getSubscriberConfig(rtcConf.CongestionControl.UseSendSideBWEInterceptor || rtcConf.CongestionControl.UseSendSideBWE),
+		Publisher:   getPublisherConfig(false),
+		Subscriber:  getSubscriberConfig(rtcConf.CongestionControl.UseSendSideBWEInterceptor || rtcConf.CongestionControl.UseSendSideBWE),
+		Development: conf.Development,
 	}, nil
 }
 
+func setICETCPMux(webRTCConfig *rtcconfig.WebRTCConfig, rtcConf *rtcconfig.RTCConfig, iceTCPListener net.Listener) error {
+	newTCPMux := func(l net.Listener) ice.TCPMux {
+		return ice.NewTCPMuxDefault(ice.TCPMuxParams{
+			Logger:          webRTCConfig.SettingEngine.LoggerFactory.NewLogger("tcp_mux"),
+			Listener:        l,
+			ReadBufferSize:  iceTCPReadBufferSize,
+			WriteBufferSize: iceTCPWriteBufferSize,
+		})
+	}
+
+	tcpMuxes := []ice.TCPMux{newTCPMux(iceTCPListener)}
+	if rtcConf.TCPPort != 0 {
+		tcpListener, err := net.ListenTCP("tcp", &net.TCPAddr{
+			Port: int(rtcConf.TCPPort),
+		})
+		if err != nil {
+			return err
+		}
+		webRTCConfig.TCPMuxListener = tcpListener
+		tcpMuxes = append(tcpMuxes, newTCPMux(tcpListener))
+	}
+	webRTCConfig.SettingEngine.SetICETCPMux(ice.NewMultiTCPMuxDefault(tcpMuxes...))
+
+	networkTypes := make([]webrtc.NetworkType, 0, 4)
+	if !rtcConf.ForceTCP {
+		networkTypes = append(networkTypes, webrtc.NetworkTypeUDP4, webrtc.NetworkTypeUDP6)
+	}
+	networkTypes = append(networkTypes, webrtc.NetworkTypeTCP4, webrtc.NetworkTypeTCP6)
+	webRTCConfig.SettingEngine.SetNetworkTypes(networkTypes)
+	return nil
+}
+
 func (c *WebRTCConfig) UpdatePublisherConfig(consolidated bool) {
 	c.Publisher = getPublisherConfig(consolidated)
 }
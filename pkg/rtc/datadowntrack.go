@@ -16,10 +16,15 @@ package rtc
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/frostbyte73/core"
+	"github.com/gammazero/deque"
+
 	"github.com/livekit/livekit-server/pkg/rtc/datatrack"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 )
@@ -34,32 +39,92 @@ type DataDownTrackParams struct {
 	Handle           uint16
 	Transport        types.DataTrackTransport
 	BytesTrackStats  *BytesTrackStats
+	// maximum bitrate forwarded to the subscriber, 0 for no limit
+	MaxBitrate uint64
+	// maximum bytes queued for the subscriber transport, 0 for no limit
+	MaxQueuedBytes int
+	// every packet is forwarded in reliable mode, packets missed by the subscriber are retransmitted on NACK
+	Reliable bool
+}
+
+type dataDownTrackPacket struct {
+	frameNumber uint16
+	isFinal     bool
+	buf         []byte
 }
 
+// DataDownTrack forwards the packets of a data track to a subscriber.
+//
+// Forwarding decisions are made per frame, a frame is either forwarded or dropped as a whole:
+//   - frames are decimated to honour the target frame rate requested by the subscriber
+//   - frames exceeding the max bitrate are dropped
+//   - packets are sent from a bounded queue, when the subscriber transport cannot keep up
+//     queued frames are dropped to catch up with the latest one
+//
+// Tracks in reliable mode are not decimated or rate limited, packets which do not fit in the queue
+// are dropped individually and recovered by the subscriber through NACKs.
 type DataDownTrack struct {
 	params    DataDownTrackParams
 	logger    logger.Logger
 	createdAt int64
+
+	bitrateLimiter *utils.ByteRateLimiter
+
+	lock             sync.Mutex
+	minFrameInterval int64
+	frameNumber      uint16
+	inFrame          bool
+	forwardingFrame  bool
+	lastFrameAt      int64
+	queue            deque.Deque[dataDownTrackPacket]
+	queuedBytes      int
+	sendingFrame     uint16
+	sendingPartial   bool
+	framesForwarded  uint32
+	framesDropped    uint32
+	packetsDropped   uint32
+	retransmitted    uint32
+
+	notify chan struct{}
+	closed core.Fuse
 }
 
 func NewDataDownTrack(params DataDownTrackParams) (*DataDownTrack, error) {
 	d := &DataDownTrack{
 		params:    params,
 		createdAt: time.Now().UnixNano(),
+		notify:    make(chan struct{}, 1),
 	}
 	d.logger = params.Logger.WithValues("name", d.Name(), "handle", d.Handle())
+	if params.MaxBitrate > 0 {
+		d.bitrateLimiter = utils.NewByteRateLimiter(params.MaxBitrate/8, 0)
+	}
 
 	if err := d.params.PublishDataTrack.AddDataDownTrack(d); err != nil {
 		d.logger.Warnw("could not add data down track", err)
 		return nil, err
 	}
 
+	go d.sendWorker()
+
 	d.logger.Infow("created data down track")
 	return d, nil
 }
 
 func (d *DataDownTrack) Close() {
-	d.logger.Infow("closing data down track")
+	d.lock.Lock()
+	framesForwarded, framesDropped := d.framesForwarded, d.framesDropped
+	packetsDropped, retransmitted := d.packetsDropped, d.retransmitted
+	d.lock.Unlock()
+	d.logger.Infow(
+		"closing data down track",
+		"framesForwarded", framesForwarded,
+		"framesDropped", framesDropped,
+		"packetsDropped", packetsDropped,
+		"retransmitted", retransmitted,
+	)
+
+	d.closed.Break()
 	if d.params.BytesTrackStats != nil {
 		d.params.BytesTrackStats.Stop()
 	}
@@ -87,23 +152,203 @@ func (d *DataDownTrack) SubscriberID() livekit.ParticipantID {
 	return livekit.ParticipantID(fmt.Sprintf("%s:%d", d.params.SubscriberID, d.createdAt))
 }
 
-func (d *DataDownTrack) WritePacket(data []byte, packet *datatrack.Packet, _arrivalTime int64) {
-	forwardedPacket := *packet
-	forwardedPacket.Handle = d.params.Handle
-	buf, err := forwardedPacket.Marshal()
+func (d *DataDownTrack) WritePacket(data []byte, packet *datatrack.Packet, arrivalTime int64) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.params.Reliable {
+		if packet.IsStartOfFrame {
+			d.framesForwarded++
+		}
+		d.enqueueReliableLocked(packet)
+		return
+	}
+
+	if !d.inFrame || packet.IsStartOfFrame || packet.FrameNumber != d.frameNumber {
+		d.startFrameLocked(packet, len(data), arrivalTime)
+	} else if d.forwardingFrame && d.bitrateLimiter != nil {
+		// the frame has been admitted, remaining packets are accounted for without being dropped
+		d.bitrateLimiter.Take(len(data))
+	}
+	if packet.IsFinalOfFrame {
+		d.inFrame = false
+	}
+	if !d.forwardingFrame {
+		return
+	}
+
+	buf, err := d.marshal(packet)
 	if err != nil {
 		d.logger.Warnw("could not marshal data track message", err)
 		return
 	}
-	if err := d.params.Transport.SendDataTrackMessage(buf); err != nil {
-		d.logger.Warnw("could not send data track message", err)
+
+	if d.params.MaxQueuedBytes > 0 && d.queuedBytes+len(buf) > d.params.MaxQueuedBytes {
+		// subscriber is not keeping up, drop queued frames to catch up with the latest one
+		d.dropQueuedFramesLocked(packet.IsStartOfFrame)
+		if !d.forwardingFrame {
+			return
+		}
+		if d.queuedBytes+len(buf) > d.params.MaxQueuedBytes {
+			d.forwardingFrame = false
+			d.framesForwarded--
+			d.framesDropped++
+			return
+		}
+	}
+
+	d.pushLocked(packet, buf)
+}
+
+// HandleNack queues retransmissions of packets the subscriber reported missing
+func (d *DataDownTrack) HandleNack(nack *datatrack.ExtensionNack) {
+	if !d.params.Reliable {
 		return
 	}
-	if d.params.BytesTrackStats != nil {
-		d.params.BytesTrackStats.AddBytes(uint64(len(buf)), true)
+
+	packets := d.params.PublishDataTrack.GetRetransmissionPackets(nack.SequenceNumbers())
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for _, packet := range packets {
+		if d.enqueueReliableLocked(packet) {
+			d.retransmitted++
+		}
+	}
+}
+
+func (d *DataDownTrack) enqueueReliableLocked(packet *datatrack.Packet) bool {
+	buf, err := d.marshal(packet)
+	if err != nil {
+		d.logger.Warnw("could not marshal data track message", err)
+		return false
+	}
+
+	if d.params.MaxQueuedBytes > 0 && d.queuedBytes+len(buf) > d.params.MaxQueuedBytes {
+		d.packetsDropped++
+		return false
+	}
+
+	d.pushLocked(packet, buf)
+	return true
+}
+
+func (d *DataDownTrack) marshal(packet *datatrack.Packet) ([]byte, error) {
+	forwardedPacket := *packet
+	forwardedPacket.Handle = d.params.Handle
+	return forwardedPacket.Marshal()
+}
+
+func (d *DataDownTrack) pushLocked(packet *datatrack.Packet, buf []byte) {
+	d.queue.PushBack(dataDownTrackPacket{
+		frameNumber: packet.FrameNumber,
+		isFinal:     packet.IsFinalOfFrame,
+		buf:         buf,
+	})
+	d.queuedBytes += len(buf)
+
+	select {
+	case d.notify <- struct{}{}:
+	default:
+	}
+}
+
+// decides whether the frame starting with this packet is forwarded,
+// frames with a missing start are dropped as they cannot be reassembled
+func (d *DataDownTrack) startFrameLocked(packet *datatrack.Packet, size int, arrivalTime int64) {
+	d.inFrame = true
+	d.frameNumber = packet.FrameNumber
+
+	switch {
+	case !packet.IsStartOfFrame:
+		d.forwardingFrame = false
+	case d.minFrameInterval > 0 && d.lastFrameAt != 0 && arrivalTime-d.lastFrameAt < d.minFrameInterval:
+		d.forwardingFrame = false
+	case d.bitrateLimiter != nil && !d.bitrateLimiter.Allow(size):
+		d.forwardingFrame = false
+	default:
+		d.forwardingFrame = true
+		d.lastFrameAt = arrivalTime
+	}
+
+	if d.forwardingFrame {
+		d.framesForwarded++
+	} else {
+		d.framesDropped++
+	}
+}
+
+// drops queued packets, keeping the frame partially sent to the transport.
+// The frame currently being queued is kept if it is just starting.
+func (d *DataDownTrack) dropQueuedFramesLocked(isStartOfFrame bool) {
+	keep := 0
+	if d.sendingPartial {
+		for keep < d.queue.Len() && d.queue.At(keep).frameNumber == d.sendingFrame {
+			keep++
+		}
+	}
+
+	dropped := map[uint16]struct{}{}
+	for d.queue.Len() > keep {
+		p := d.queue.PopBack()
+		d.queuedBytes -= len(p.buf)
+		dropped[p.frameNumber] = struct{}{}
+	}
+	if !isStartOfFrame {
+		// the current frame lost its queued packets, it is dropped
+		if _, ok := dropped[d.frameNumber]; ok {
+			delete(dropped, d.frameNumber)
+			d.forwardingFrame = false
+			d.framesForwarded--
+			d.framesDropped++
+		}
+	}
+	d.framesForwarded -= uint32(len(dropped))
+	d.framesDropped += uint32(len(dropped))
+}
+
+func (d *DataDownTrack) sendWorker() {
+	for {
+		select {
+		case <-d.closed.Watch():
+			return
+		case <-d.notify:
+		}
+
+		for !d.closed.IsBroken() {
+			d.lock.Lock()
+			if d.queue.Len() == 0 {
+				d.lock.Unlock()
+				break
+			}
+			p := d.queue.PopFront()
+			d.queuedBytes -= len(p.buf)
+			d.sendingFrame = p.frameNumber
+			d.sendingPartial = !p.isFinal
+			d.lock.Unlock()
+
+			if err := d.params.Transport.SendDataTrackMessage(p.buf); err != nil {
+				d.logger.Debugw("could not send data track message", "error", err)
+				continue
+			}
+			if d.params.BytesTrackStats != nil {
+				d.params.BytesTrackStats.AddBytes(uint64(len(p.buf)), true)
+			}
+		}
 	}
 }
 
 func (d *DataDownTrack) UpdateSubscriptionOptions(subscriptionOptions *livekit.DataTrackSubscriptionOptions) {
-	// DT-TODO
+	var minFrameInterval int64
+	if fps := subscriptionOptions.GetTargetFps(); fps > 0 {
+		// allow for jitter in frame arrival, so that decimating by an integer factor is stable
+		minFrameInterval = int64(time.Second) / int64(fps) * 9 / 10
+	}
+
+	d.lock.Lock()
+	d.minFrameInterval = minFrameInterval
+	d.lock.Unlock()
+
+	d.logger.Debugw("updated data track subscription options", "subscriptionOptions", logger.Proto(subscriptionOptions))
 }
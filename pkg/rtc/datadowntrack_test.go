@@ -0,0 +1,203 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/datatrack"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+)
+
+type testDataTrackTransport struct {
+	*typesfakes.FakeDataTrackTransport
+
+	lock    sync.Mutex
+	packets []*datatrack.Packet
+}
+
+func newTestDataTrackTransport(t *testing.T, block <-chan struct{}) *testDataTrackTransport {
+	transport := &testDataTrackTransport{FakeDataTrackTransport: &typesfakes.FakeDataTrackTransport{}}
+	transport.SendDataTrackMessageCalls(func(buf []byte) error {
+		var packet datatrack.Packet
+		require.NoError(t, packet.Unmarshal(buf))
+
+		transport.lock.Lock()
+		transport.packets = append(transport.packets, &packet)
+		transport.lock.Unlock()
+
+		if block != nil {
+			<-block
+		}
+		return nil
+	})
+	return transport
+}
+
+func (tt *testDataTrackTransport) sentFrames() map[uint16]int {
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+
+	frames := make(map[uint16]int)
+	for _, p := range tt.packets {
+		frames[p.FrameNumber]++
+	}
+	return frames
+}
+
+func newDataDownTrackForTest(t *testing.T, transport types.DataTrackTransport, maxBitrate uint64, maxQueuedBytes int) *DataDownTrack {
+	d, err := NewDataDownTrack(DataDownTrackParams{
+		Logger:           logger.GetLogger(),
+		SubscriberID:     "sub",
+		PublishDataTrack: &typesfakes.FakeDataTrack{},
+		Handle:           7,
+		Transport:        transport,
+		MaxBitrate:       maxBitrate,
+		MaxQueuedBytes:   maxQueuedBytes,
+	})
+	require.NoError(t, err)
+	t.Cleanup(d.Close)
+	return d
+}
+
+func generateDataPacketsForTest(t *testing.T, numFrames int, frameSize int) ([][]byte, []*datatrack.Packet) {
+	rawPackets := datatrack.GenerateRawDataPackets(1, 1, 1, numFrames, frameSize, 10*time.Millisecond)
+	packets := make([]*datatrack.Packet, 0, len(rawPackets))
+	for _, raw := range rawPackets {
+		var packet datatrack.Packet
+		require.NoError(t, packet.Unmarshal(raw))
+		packets = append(packets, &packet)
+	}
+	return rawPackets, packets
+}
+
+func TestDataDownTrack(t *testing.T) {
+	t.Run("decimates frames to the target frame rate", func(t *testing.T) {
+		transport := newTestDataTrackTransport(t, nil)
+		d := newDataDownTrackForTest(t, transport, 0, 0)
+		d.UpdateSubscriptionOptions(&livekit.DataTrackSubscriptionOptions{TargetFps: proto.Uint32(15)})
+
+		rawPackets, packets := generateDataPacketsForTest(t, 10, 100)
+		for i, packet := range packets {
+			// 30 fps
+			d.WritePacket(rawPackets[i], packet, int64(time.Second)+int64(i)*int64(time.Second)/30)
+		}
+
+		require.Eventually(t, func() bool {
+			return transport.SendDataTrackMessageCallCount() == 5
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("drops whole frames above the max bitrate", func(t *testing.T) {
+		transport := newTestDataTrackTransport(t, nil)
+		// 2 packets per frame, allowing a single frame through the burst
+		d := newDataDownTrackForTest(t, transport, 8*600, 0)
+
+		rawPackets, packets := generateDataPacketsForTest(t, 5, 512)
+		for i, packet := range packets {
+			d.WritePacket(rawPackets[i], packet, int64(time.Second))
+		}
+
+		require.Eventually(t, func() bool {
+			return transport.SendDataTrackMessageCallCount() == 2
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, map[uint16]int{1: 2}, transport.sentFrames())
+	})
+
+	t.Run("drops queued frames to catch up with the latest frame", func(t *testing.T) {
+		block := make(chan struct{})
+		transport := newTestDataTrackTransport(t, block)
+		rawPackets, packets := generateDataPacketsForTest(t, 10, 512)
+		d := newDataDownTrackForTest(t, transport, 0, 3*len(rawPackets[0])+len(rawPackets[0])/2)
+
+		// first packet is being sent, blocking the transport
+		d.WritePacket(rawPackets[0], packets[0], 0)
+		require.Eventually(t, func() bool {
+			return transport.SendDataTrackMessageCallCount() == 1
+		}, time.Second, 10*time.Millisecond)
+
+		for i := 1; i < len(packets); i++ {
+			d.WritePacket(rawPackets[i], packets[i], 0)
+		}
+		close(block)
+
+		// the frame in flight is completed, followed by the latest frame
+		require.Eventually(t, func() bool {
+			return transport.SendDataTrackMessageCallCount() == 4
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, map[uint16]int{1: 2, 10: 2}, transport.sentFrames())
+	})
+
+	t.Run("reliable mode forwards every packet and retransmits on nack", func(t *testing.T) {
+		dt := NewDataTrack(DataTrackParams{
+			Logger:         logger.GetLogger(),
+			ParticipantID:  func() livekit.ParticipantID { return "pub" },
+			Reliable:       true,
+			ReliableConfig: config.DataTrackReliableConfig{BufferSize: 64, BufferMaxAge: time.Minute},
+		}, &livekit.DataTrackInfo{PubHandle: 1, Sid: "DTR_test", Name: "control"})
+		t.Cleanup(dt.Close)
+
+		transport := newTestDataTrackTransport(t, nil)
+		d, err := NewDataDownTrack(DataDownTrackParams{
+			Logger:           logger.GetLogger(),
+			SubscriberID:     "sub",
+			PublishDataTrack: dt,
+			Handle:           7,
+			Transport:        transport,
+			Reliable:         dt.IsReliable(),
+		})
+		require.NoError(t, err)
+		t.Cleanup(d.Close)
+		d.UpdateSubscriptionOptions(&livekit.DataTrackSubscriptionOptions{TargetFps: proto.Uint32(15)})
+
+		rawPackets, packets := generateDataPacketsForTest(t, 10, 100)
+		for i, packet := range packets {
+			if i == 3 {
+				// lost upstream
+				continue
+			}
+			dt.HandlePacket(rawPackets[i], packet, int64(time.Second)+int64(i)*int64(time.Second)/30)
+		}
+
+		// not decimated
+		require.Eventually(t, func() bool {
+			return transport.SendDataTrackMessageCallCount() == 9
+		}, time.Second, 10*time.Millisecond)
+
+		// subscriber requests a packet it missed and one the SFU never received
+		subscriberNack, err := datatrack.NewExtensionNack([]uint16{packets[3].SequenceNumber, packets[5].SequenceNumber})
+		require.NoError(t, err)
+		d.HandleNack(subscriberNack)
+
+		require.Eventually(t, func() bool {
+			return transport.SendDataTrackMessageCallCount() == 10
+		}, time.Second, 10*time.Millisecond)
+		transport.lock.Lock()
+		retransmitted := transport.packets[9]
+		transport.lock.Unlock()
+		require.Equal(t, packets[5].SequenceNumber, retransmitted.SequenceNumber)
+		require.Equal(t, uint16(7), retransmitted.Handle)
+		require.Equal(t, packets[5].Payload, retransmitted.Payload)
+	})
+}
@@ -0,0 +1,335 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gammazero/deque"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/psrpc"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// DataHistoryTopic is the reserved data packet topic used to manage the data history of a room,
+// requests are accepted from RoomService.SendData only
+const DataHistoryTopic = "lk.data_history"
+
+const (
+	DataHistoryActionClear = "clear"
+)
+
+var (
+	ErrInvalidDataHistoryRequest = psrpc.NewErrorf(psrpc.InvalidArgument, "invalid data history request")
+	ErrDataHistoryNotFound       = psrpc.NewErrorf(psrpc.NotFound, "data history does not exist")
+)
+
+type DataHistoryRequest struct {
+	Action string `json:"action"`
+	// limits the request to a topic, all topics when empty
+	Topic string `json:"topic,omitempty"`
+}
+
+type DataHistoryPacket struct {
+	SenderID livekit.ParticipantID `json:"senderId,omitempty"`
+	Seq      uint32                `json:"seq,omitempty"`
+	// marshalled livekit.DataPacket
+	Data []byte `json:"data"`
+}
+
+// DataHistoryEntry is a user packet, or all the packets of a data stream received so far
+type DataHistoryEntry struct {
+	Topic     string              `json:"topic"`
+	StreamID  string              `json:"streamId,omitempty"`
+	CreatedAt time.Time           `json:"createdAt"`
+	Packets   []DataHistoryPacket `json:"packets"`
+
+	size int
+}
+
+type dataHistoryStreamKey struct {
+	sender   string
+	streamID string
+}
+
+// DataHistory keeps recent reliable data messages on configured topics,
+// bounded by number of messages, bytes and age.
+type DataHistory struct {
+	config config.DataHistoryConfig
+
+	lock    sync.Mutex
+	entries deque.Deque[*DataHistoryEntry]
+	streams map[dataHistoryStreamKey]*DataHistoryEntry
+	bytes   int
+	dirty   bool
+}
+
+func NewDataHistory(conf config.DataHistoryConfig) *DataHistory {
+	return &DataHistory{
+		config:  conf,
+		streams: make(map[dataHistoryStreamKey]*DataHistoryEntry),
+	}
+}
+
+func (h *DataHistory) IsEnabled() bool {
+	return h.config.IsEnabled()
+}
+
+// Record adds a data packet to the history if it is on a configured topic.
+// Chunks and trailers are added to the entry of their stream.
+func (h *DataHistory) Record(dp *livekit.DataPacket) {
+	if !h.IsEnabled() {
+		return
+	}
+
+	var (
+		topic    string
+		key      dataHistoryStreamKey
+		isStream bool
+	)
+	switch payload := dp.Value.(type) {
+	case *livekit.DataPacket_User:
+		topic = payload.User.GetTopic()
+	case *livekit.DataPacket_StreamHeader:
+		topic = payload.StreamHeader.GetTopic()
+		key = dataHistoryStreamKey{dp.ParticipantIdentity, payload.StreamHeader.GetStreamId()}
+		isStream = true
+	case *livekit.DataPacket_StreamChunk:
+		key = dataHistoryStreamKey{dp.ParticipantIdentity, payload.StreamChunk.GetStreamId()}
+		isStream = true
+	case *livekit.DataPacket_StreamTrailer:
+		key = dataHistoryStreamKey{dp.ParticipantIdentity, payload.StreamTrailer.GetStreamId()}
+		isStream = true
+	default:
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var entry *DataHistoryEntry
+	if topic == "" && isStream {
+		if entry = h.streams[key]; entry == nil {
+			return
+		}
+	} else if !h.matchesTopic(topic) {
+		return
+	}
+
+	data, err := proto.Marshal(dp)
+	if err != nil {
+		return
+	}
+	packet := DataHistoryPacket{
+		SenderID: livekit.ParticipantID(dp.ParticipantSid),
+		Seq:      dp.Sequence,
+		Data:     data,
+	}
+
+	now := time.Now()
+	if entry == nil {
+		entry = &DataHistoryEntry{
+			Topic:     topic,
+			CreatedAt: now,
+		}
+		if isStream {
+			entry.StreamID = key.streamID
+			h.streams[key] = entry
+		}
+		h.entries.PushBack(entry)
+	}
+	entry.Packets = append(entry.Packets, packet)
+	entry.size += len(data)
+	h.bytes += len(data)
+	if _, ok := dp.Value.(*livekit.DataPacket_StreamTrailer); ok {
+		delete(h.streams, key)
+	}
+
+	h.dirty = true
+	h.pruneLocked(now)
+}
+
+// Entries returns the entries currently in the history, oldest first
+func (h *DataHistory) Entries() []*DataHistoryEntry {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.pruneLocked(time.Now())
+	entries := make([]*DataHistoryEntry, 0, h.entries.Len())
+	for i := range h.entries.Len() {
+		entry := *h.entries.At(i)
+		// packets of a stream in progress keep being appended
+		entry.Packets = slices.Clone(entry.Packets)
+		entries = append(entries, &entry)
+	}
+	return entries
+}
+
+// Restore replaces the history with previously stored entries
+func (h *DataHistory) Restore(entries []*DataHistoryEntry) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.clearLocked()
+	for _, e := range entries {
+		if !h.matchesTopic(e.Topic) {
+			continue
+		}
+		entry := *e
+		entry.size = 0
+		for _, p := range entry.Packets {
+			entry.size += len(p.Data)
+		}
+		h.bytes += entry.size
+		h.entries.PushBack(&entry)
+	}
+	h.pruneLocked(time.Now())
+}
+
+// Clear removes the entries of a topic, or all entries when the topic is empty
+func (h *DataHistory) Clear(topic string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if topic == "" {
+		h.clearLocked()
+	} else {
+		for i := h.entries.Len() - 1; i >= 0; i-- {
+			if entry := h.entries.At(i); entry.Topic == topic {
+				h.removeLocked(entry)
+				h.entries.Remove(i)
+			}
+		}
+	}
+	h.dirty = true
+}
+
+// TakeDirty returns true if the history changed since the last call
+func (h *DataHistory) TakeDirty() bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	dirty := h.dirty
+	h.dirty = false
+	return dirty
+}
+
+func (h *DataHistory) matchesTopic(topic string) bool {
+	for _, pattern := range h.config.Topics {
+		if matchDataTopic(pattern, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *DataHistory) pruneLocked(now time.Time) {
+	for h.entries.Len() > 0 {
+		entry := h.entries.Front()
+		if (h.config.MaxMessages <= 0 || h.entries.Len() <= h.config.MaxMessages) &&
+			(h.config.MaxBytes <= 0 || h.bytes <= h.config.MaxBytes) &&
+			(h.config.MaxAge <= 0 || now.Sub(entry.CreatedAt) <= h.config.MaxAge) {
+			return
+		}
+		h.removeLocked(entry)
+		h.entries.PopFront()
+		h.dirty = true
+	}
+}
+
+func (h *DataHistory) removeLocked(entry *DataHistoryEntry) {
+	h.bytes -= entry.size
+	if entry.StreamID != "" {
+		for key, e := range h.streams {
+			if e == entry {
+				delete(h.streams, key)
+				break
+			}
+		}
+	}
+}
+
+func (h *DataHistory) clearLocked() {
+	h.entries.Clear()
+	clear(h.streams)
+	h.bytes = 0
+}
+
+// ------------------------------------------------------------
+
+func (r *Room) OnDataHistoryUpdated(f func(entries []*DataHistoryEntry)) {
+	r.onDataHistoryUpdated = f
+}
+
+// RestoreDataHistory seeds the data history, e.g. with the history persisted by a previous session of the room
+func (r *Room) RestoreDataHistory(entries []*DataHistoryEntry) {
+	r.dataHistory.Restore(entries)
+}
+
+func (r *Room) GetDataHistory() []*DataHistoryEntry {
+	return r.dataHistory.Entries()
+}
+
+// GetDataHistoryForParticipant returns the history packets the participant is allowed to receive
+func (r *Room) GetDataHistoryForParticipant(participant types.LocalParticipant) []*types.DataMessageCache {
+	if !r.dataHistory.IsEnabled() {
+		return nil
+	}
+
+	var msgs []*types.DataMessageCache
+	for _, entry := range r.dataHistory.Entries() {
+		if !r.dataTopicACL.CanSubscribe(entry.Topic, participant) {
+			continue
+		}
+		for _, p := range entry.Packets {
+			msgs = append(msgs, &types.DataMessageCache{
+				SenderID: p.SenderID,
+				Seq:      p.Seq,
+				Data:     p.Data,
+			})
+		}
+	}
+	return msgs
+}
+
+// HandleDataHistoryRequest handles a DataHistoryRequest sent through RoomService.SendData
+func (r *Room) HandleDataHistoryRequest(payload []byte) error {
+	req := &DataHistoryRequest{}
+	if err := json.Unmarshal(payload, req); err != nil {
+		return ErrInvalidDataHistoryRequest
+	}
+
+	switch req.Action {
+	case DataHistoryActionClear:
+		r.logger.Infow("clearing data history", "topic", req.Topic)
+		r.dataHistory.Clear(req.Topic)
+		return nil
+	default:
+		return ErrInvalidDataHistoryRequest
+	}
+}
+
+func (r *Room) flushDataHistory() {
+	if r.onDataHistoryUpdated != nil && r.dataHistory.TakeDirty() {
+		r.onDataHistoryUpdated(r.dataHistory.Entries())
+	}
+}
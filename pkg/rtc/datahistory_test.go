@@ -0,0 +1,123 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+func TestDataHistory(t *testing.T) {
+	userPacket := func(topic string, payload string) *livekit.DataPacket {
+		return &livekit.DataPacket{
+			ParticipantIdentity: "sender",
+			Value: &livekit.DataPacket_User{
+				User: &livekit.UserPacket{Topic: &topic, Payload: []byte(payload)},
+			},
+		}
+	}
+	payloads := func(entries []*DataHistoryEntry) []string {
+		var res []string
+		for _, entry := range entries {
+			for _, p := range entry.Packets {
+				dp := &livekit.DataPacket{}
+				require.NoError(t, proto.Unmarshal(p.Data, dp))
+				res = append(res, string(dp.GetUser().GetPayload()))
+			}
+		}
+		return res
+	}
+
+	t.Run("only configured topics are kept", func(t *testing.T) {
+		h := NewDataHistory(config.DataHistoryConfig{Topics: []string{"chat", "history.*"}})
+		h.Record(userPacket("chat", "1"))
+		h.Record(userPacket("other", "2"))
+		h.Record(userPacket("history.a", "3"))
+		require.Equal(t, []string{"1", "3"}, payloads(h.Entries()))
+		require.True(t, h.TakeDirty())
+		require.False(t, h.TakeDirty())
+	})
+
+	t.Run("bounded by count and bytes", func(t *testing.T) {
+		h := NewDataHistory(config.DataHistoryConfig{Topics: []string{"chat"}, MaxMessages: 2})
+		for _, p := range []string{"1", "2", "3"} {
+			h.Record(userPacket("chat", p))
+		}
+		require.Equal(t, []string{"2", "3"}, payloads(h.Entries()))
+
+		size := len(h.Entries()[0].Packets[0].Data)
+		h = NewDataHistory(config.DataHistoryConfig{Topics: []string{"chat"}, MaxBytes: 2*size + 1})
+		for _, p := range []string{"1", "2", "3"} {
+			h.Record(userPacket("chat", p))
+		}
+		require.Equal(t, []string{"2", "3"}, payloads(h.Entries()))
+	})
+
+	t.Run("bounded by age", func(t *testing.T) {
+		h := NewDataHistory(config.DataHistoryConfig{Topics: []string{"chat"}, MaxAge: time.Minute})
+		h.Restore([]*DataHistoryEntry{
+			{Topic: "chat", CreatedAt: time.Now().Add(-time.Hour)},
+			{Topic: "chat", CreatedAt: time.Now()},
+		})
+		require.Len(t, h.Entries(), 1)
+	})
+
+	t.Run("streams are kept as a whole", func(t *testing.T) {
+		h := NewDataHistory(config.DataHistoryConfig{Topics: []string{"chat"}})
+		h.Record(&livekit.DataPacket{
+			ParticipantIdentity: "sender",
+			Value:               &livekit.DataPacket_StreamHeader{StreamHeader: &livekit.DataStream_Header{StreamId: "s1", Topic: "chat"}},
+		})
+		h.Record(&livekit.DataPacket{
+			ParticipantIdentity: "sender",
+			Value:               &livekit.DataPacket_StreamHeader{StreamHeader: &livekit.DataStream_Header{StreamId: "s2", Topic: "other"}},
+		})
+		for _, streamID := range []string{"s1", "s2"} {
+			h.Record(&livekit.DataPacket{
+				ParticipantIdentity: "sender",
+				Value:               &livekit.DataPacket_StreamChunk{StreamChunk: &livekit.DataStream_Chunk{StreamId: streamID}},
+			})
+			h.Record(&livekit.DataPacket{
+				ParticipantIdentity: "sender",
+				Value:               &livekit.DataPacket_StreamTrailer{StreamTrailer: &livekit.DataStream_Trailer{StreamId: streamID}},
+			})
+		}
+
+		entries := h.Entries()
+		require.Len(t, entries, 1)
+		require.Equal(t, "s1", entries[0].StreamID)
+		require.Len(t, entries[0].Packets, 3)
+		require.Empty(t, h.streams)
+	})
+
+	t.Run("clear by topic", func(t *testing.T) {
+		h := NewDataHistory(config.DataHistoryConfig{Topics: []string{"*"}})
+		h.Record(userPacket("a", "1"))
+		h.Record(userPacket("b", "2"))
+		h.Record(userPacket("a", "3"))
+		h.Clear("a")
+		require.Equal(t, []string{"2"}, payloads(h.Entries()))
+		h.Clear("")
+		require.Empty(t, h.Entries())
+		require.Zero(t, h.bytes)
+	})
+}
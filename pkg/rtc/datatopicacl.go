@@ -0,0 +1,220 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+)
+
+const (
+	dataPacketTypeUser      = "user"
+	dataPacketTypeRPC       = "rpc"
+	dataPacketTypeStream    = "stream"
+	dataPacketTypeEncrypted = "encrypted"
+
+	dataTopicPermissionPublish   = "publish"
+	dataTopicPermissionSubscribe = "subscribe"
+)
+
+type dataStreamKey struct {
+	senderID livekit.ParticipantID
+	streamID string
+}
+
+type dataStreamACL struct {
+	rule     *config.DataTopicACL
+	rejected bool
+}
+
+// DataTopicACL enforces topic level publish/subscribe permissions on data packets.
+// Stream chunks and trailers do not carry a topic, the rule matched by the stream header
+// is remembered until the trailer is received or the sender leaves.
+type DataTopicACL struct {
+	rules []config.DataTopicACL
+
+	lock    sync.Mutex
+	streams map[dataStreamKey]dataStreamACL
+}
+
+func NewDataTopicACL(rules []config.DataTopicACL) *DataTopicACL {
+	return &DataTopicACL{
+		rules:   rules,
+		streams: make(map[dataStreamKey]dataStreamACL),
+	}
+}
+
+// Check returns false when the source is not allowed to publish the packet,
+// otherwise it returns a filter for the participants allowed to receive it, nil when unrestricted.
+// Packets without a source originate from the server and are not subject to publish permissions.
+// Encrypted packets could carry any topic, they are only let through by a rule matching encrypted packets.
+func (a *DataTopicACL) Check(source types.LocalParticipant, dp *livekit.DataPacket) (bool, func(types.LocalParticipant) bool) {
+	if len(a.rules) == 0 {
+		return true, nil
+	}
+
+	var (
+		packetType string
+		rule       *config.DataTopicACL
+	)
+	switch payload := dp.Value.(type) {
+	case *livekit.DataPacket_User:
+		packetType = dataPacketTypeUser
+		rule = a.matchTopic(payload.User.GetTopic())
+	case *livekit.DataPacket_RpcRequest:
+		packetType = dataPacketTypeRPC
+		rule = a.matchRPCMethod(payload.RpcRequest.GetMethod())
+	case *livekit.DataPacket_StreamHeader:
+		packetType = dataPacketTypeStream
+		rule = a.matchTopic(payload.StreamHeader.GetTopic())
+		if rule != nil && source != nil {
+			a.lock.Lock()
+			a.streams[dataStreamKey{source.ID(), payload.StreamHeader.GetStreamId()}] = dataStreamACL{
+				rule:     rule,
+				rejected: !isDataTopicPermitted(rule.Publish, source),
+			}
+			a.lock.Unlock()
+		}
+	case *livekit.DataPacket_StreamChunk:
+		packetType = dataPacketTypeStream
+		if source != nil {
+			a.lock.Lock()
+			stream, ok := a.streams[dataStreamKey{source.ID(), payload.StreamChunk.GetStreamId()}]
+			a.lock.Unlock()
+			if ok && stream.rejected {
+				prometheus.RecordDataPacketRejected(packetType, dataTopicPermissionPublish)
+				return false, nil
+			}
+			rule = stream.rule
+		}
+	case *livekit.DataPacket_StreamTrailer:
+		packetType = dataPacketTypeStream
+		if source != nil {
+			key := dataStreamKey{source.ID(), payload.StreamTrailer.GetStreamId()}
+			a.lock.Lock()
+			stream, ok := a.streams[key]
+			delete(a.streams, key)
+			a.lock.Unlock()
+			if ok && stream.rejected {
+				prometheus.RecordDataPacketRejected(packetType, dataTopicPermissionPublish)
+				return false, nil
+			}
+			rule = stream.rule
+		}
+	case *livekit.DataPacket_EncryptedPacket:
+		packetType = dataPacketTypeEncrypted
+		rule = a.matchEncrypted()
+		if rule == nil && source != nil {
+			prometheus.RecordDataPacketRejected(packetType, dataTopicPermissionPublish)
+			return false, nil
+		}
+	}
+	if rule == nil {
+		return true, nil
+	}
+
+	if source != nil && !isDataTopicPermitted(rule.Publish, source) {
+		prometheus.RecordDataPacketRejected(packetType, dataTopicPermissionPublish)
+		return false, nil
+	}
+	if len(rule.Subscribe.Attributes) == 0 {
+		return true, nil
+	}
+	return true, func(p types.LocalParticipant) bool {
+		if isDataTopicPermitted(rule.Subscribe, p) {
+			return true
+		}
+		prometheus.RecordDataPacketRejected(packetType, dataTopicPermissionSubscribe)
+		return false
+	}
+}
+
+// CanSubscribe returns true if the participant is allowed to receive messages on the topic
+func (a *DataTopicACL) CanSubscribe(topic string, p types.LocalParticipant) bool {
+	rule := a.matchTopic(topic)
+	return rule == nil || isDataTopicPermitted(rule.Subscribe, p)
+}
+
+// RemoveParticipant forgets the streams of a participant that left without sending their trailers
+func (a *DataTopicACL) RemoveParticipant(participantID livekit.ParticipantID) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for key := range a.streams {
+		if key.senderID == participantID {
+			delete(a.streams, key)
+		}
+	}
+}
+
+func (a *DataTopicACL) matchTopic(topic string) *config.DataTopicACL {
+	for i := range a.rules {
+		if a.rules[i].Topic != "" && matchDataTopic(a.rules[i].Topic, topic) {
+			return &a.rules[i]
+		}
+	}
+	return nil
+}
+
+func (a *DataTopicACL) matchRPCMethod(method string) *config.DataTopicACL {
+	for i := range a.rules {
+		if a.rules[i].RPCMethod != "" && matchDataTopic(a.rules[i].RPCMethod, method) {
+			return &a.rules[i]
+		}
+	}
+	return nil
+}
+
+func (a *DataTopicACL) matchEncrypted() *config.DataTopicACL {
+	for i := range a.rules {
+		if a.rules[i].Encrypted {
+			return &a.rules[i]
+		}
+	}
+	return nil
+}
+
+func matchDataTopic(pattern string, topic string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(topic, prefix)
+	}
+	return pattern == topic
+}
+
+func isDataTopicPermitted(permission config.DataTopicPermission, p types.LocalParticipant) bool {
+	if len(permission.Attributes) == 0 {
+		return true
+	}
+
+	grants := p.ClaimGrants()
+	if grants == nil {
+		return false
+	}
+	if grants.Video != nil && grants.Video.RoomAdmin {
+		return true
+	}
+	for k, v := range permission.Attributes {
+		if grants.Attributes[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -16,17 +16,25 @@ package rtc
 
 import (
 	"errors"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/frostbyte73/core"
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/rtc/datatrack"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	sfuutils "github.com/livekit/livekit-server/pkg/sfu/utils"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils"
+	"github.com/livekit/protocol/utils/mono"
 )
 
+// ReliableDataTracksAttribute is set by publishers to a comma separated list of data track names, or * for all,
+// to publish data tracks in reliable mode
+const ReliableDataTracksAttribute = "lk.reliable_data_tracks"
+
 var (
 	errReceiverClosed = errors.New("datatrack is closed")
 )
@@ -37,7 +45,11 @@ type DataTrackParams struct {
 	Logger              logger.Logger
 	ParticipantID       func() livekit.ParticipantID
 	ParticipantIdentity livekit.ParticipantIdentity
+	SubscriberConfig    config.DataTrackSubscriberConfig
 	BytesTrackStats     *BytesTrackStats
+	// in reliable mode, packets are kept for retransmission to subscribers
+	Reliable       bool
+	ReliableConfig config.DataTrackReliableConfig
 }
 
 type DataTrack struct {
@@ -53,6 +65,8 @@ type DataTrack struct {
 
 	stats *dataTrackStats
 
+	retransmissionBuffer *datatrack.RetransmissionBuffer
+
 	closed core.Fuse
 }
 
@@ -68,7 +82,10 @@ func NewDataTrack(params DataTrackParams, dti *livekit.DataTrackInfo) *DataTrack
 		Logger:    d.logger,
 	})
 	d.stats = newDataTrackStats(dataTrackStatsParams{Logger: d.logger})
-	d.logger.Infow("created data track", "dataTrackInfo", logger.Proto(d.dti))
+	if params.Reliable {
+		d.retransmissionBuffer = datatrack.NewRetransmissionBuffer(params.ReliableConfig.BufferSize, params.ReliableConfig.BufferMaxAge)
+	}
+	d.logger.Infow("created data track", "dataTrackInfo", logger.Proto(d.dti), "reliable", params.Reliable)
 	return d
 }
 
@@ -106,6 +123,10 @@ func (d *DataTrack) Name() string {
 	return d.dti.Name
 }
 
+func (d *DataTrack) IsReliable() bool {
+	return d.params.Reliable
+}
+
 func (d *DataTrack) AddSubscriber(sub types.LocalParticipant) (types.DataDownTrack, error) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
@@ -130,6 +151,9 @@ func (d *DataTrack) AddSubscriber(sub types.LocalParticipant) (types.DataDownTra
 		Handle:           sub.GetNextSubscribedDataTrackHandle(),
 		Transport:        sub.GetDataTrackTransport(),
 		BytesTrackStats:  bytesStats,
+		MaxBitrate:       d.params.SubscriberConfig.MaxBitrate,
+		MaxQueuedBytes:   d.params.SubscriberConfig.MaxQueuedBytes,
+		Reliable:         d.params.Reliable,
 	})
 	if err != nil {
 		bytesStats.Stop()
@@ -184,7 +208,40 @@ func (d *DataTrack) HandlePacket(data []byte, packet *datatrack.Packet, arrivalT
 		d.params.BytesTrackStats.AddBytes(uint64(len(data)), false)
 	}
 
+	if d.retransmissionBuffer != nil {
+		d.retransmissionBuffer.Add(packet.SequenceNumber, data, arrivalTime)
+	}
+
 	d.downTrackSpreader.Broadcast(func(dts types.DataTrackSender) {
 		dts.WritePacket(data, packet, arrivalTime)
 	})
 }
+
+// GetRetransmissionPackets returns buffered packets requested by a subscriber,
+// packets which are not buffered anymore are skipped
+func (d *DataTrack) GetRetransmissionPackets(sequenceNumbers []uint16) []*datatrack.Packet {
+	if d.retransmissionBuffer == nil {
+		return nil
+	}
+
+	now := mono.UnixNano()
+	packets := make([]*datatrack.Packet, 0, len(sequenceNumbers))
+	for _, sn := range sequenceNumbers {
+		if packet, ok := d.retransmissionBuffer.Get(sn, now); ok {
+			packets = append(packets, packet)
+		}
+	}
+	d.stats.UpdateNackReceived(len(packets), len(sequenceNumbers)-len(packets))
+	return packets
+}
+
+func isReliableDataTrack(attributes map[string]string, name string) bool {
+	names, ok := attributes[ReliableDataTracksAttribute]
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(strings.Split(names, ","), func(n string) bool {
+		n = strings.TrimSpace(n)
+		return n == "*" || n == name
+	})
+}
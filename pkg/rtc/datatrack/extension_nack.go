@@ -0,0 +1,92 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatrack
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/pion/rtcp"
+)
+
+// ExtensionIDNack is not part of livekit.DataTrackExtensionID,
+// IDs of extensions defined by the server are allocated from the top of the range.
+const ExtensionIDNack uint8 = 0xff
+
+const (
+	nackPairLength = 4
+
+	// extension payload is limited to 255 bytes
+	MaxNackPairs = 255 / nackPairLength
+)
+
+var errTooManyNackPairs = errors.New("too many nack pairs")
+
+// ExtensionNack carries sequence numbers of missing packets of a reliable data track.
+// Subscribers send it on the handle of the subscribed track to request retransmissions from the SFU.
+//
+// Sequence numbers are encoded as pairs of packet ID and bitmask of following lost packets,
+// like RTCP generic NACKs (RFC 4585, Section 6.2.1).
+type ExtensionNack struct {
+	pairs []rtcp.NackPair
+}
+
+func NewExtensionNack(sequenceNumbers []uint16) (*ExtensionNack, error) {
+	pairs := rtcp.NackPairsFromSequenceNumbers(sequenceNumbers)
+	if len(pairs) > MaxNackPairs {
+		return nil, errTooManyNackPairs
+	}
+
+	return &ExtensionNack{pairs}, nil
+}
+
+func (e *ExtensionNack) SequenceNumbers() []uint16 {
+	var sequenceNumbers []uint16
+	for _, pair := range e.pairs {
+		sequenceNumbers = append(sequenceNumbers, pair.PacketList()...)
+	}
+	return sequenceNumbers
+}
+
+func (e *ExtensionNack) Marshal() (Extension, error) {
+	data := make([]byte, len(e.pairs)*nackPairLength)
+	for i, pair := range e.pairs {
+		binary.BigEndian.PutUint16(data[i*nackPairLength:], pair.PacketID)
+		binary.BigEndian.PutUint16(data[i*nackPairLength+2:], uint16(pair.LostPackets))
+	}
+	return Extension{
+		id:   ExtensionIDNack,
+		data: data,
+	}, nil
+}
+
+func (e *ExtensionNack) Unmarshal(ext Extension) error {
+	if ext.id != ExtensionIDNack {
+		return errors.New("invalid extension ID")
+	}
+
+	if len(ext.data) == 0 || len(ext.data)%nackPairLength != 0 {
+		return errors.New("invalid extension data size")
+	}
+
+	e.pairs = make([]rtcp.NackPair, 0, len(ext.data)/nackPairLength)
+	for i := 0; i < len(ext.data); i += nackPairLength {
+		e.pairs = append(e.pairs, rtcp.NackPair{
+			PacketID:    binary.BigEndian.Uint16(ext.data[i:]),
+			LostPackets: rtcp.PacketBitmap(binary.BigEndian.Uint16(ext.data[i+2:])),
+		})
+	}
+	return nil
+}
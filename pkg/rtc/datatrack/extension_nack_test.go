@@ -0,0 +1,90 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatrack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtensionNack(t *testing.T) {
+	sequenceNumbers := []uint16{65534, 65535, 0, 5, 100}
+	extNack, err := NewExtensionNack(sequenceNumbers)
+	require.NoError(t, err)
+	ext, err := extNack.Marshal()
+	require.NoError(t, err)
+	packet := &Packet{Header: Header{Handle: 7}}
+	packet.AddExtension(ext)
+
+	buf, err := packet.Marshal()
+	require.NoError(t, err)
+
+	var unmarshaled Packet
+	require.NoError(t, unmarshaled.Unmarshal(buf))
+	require.Equal(t, uint16(7), unmarshaled.Handle)
+	require.Empty(t, unmarshaled.Payload)
+
+	ext, err = unmarshaled.GetExtension(ExtensionIDNack)
+	require.NoError(t, err)
+
+	var unmarshaledNack ExtensionNack
+	require.NoError(t, unmarshaledNack.Unmarshal(ext))
+	require.Equal(t, sequenceNumbers, unmarshaledNack.SequenceNumbers())
+
+	tooMany := make([]uint16, 0, MaxNackPairs+1)
+	for i := range MaxNackPairs + 1 {
+		tooMany = append(tooMany, uint16(i*100))
+	}
+	_, err = NewExtensionNack(tooMany)
+	require.Error(t, err)
+}
+
+func TestRetransmissionBuffer(t *testing.T) {
+	rawPackets := GenerateRawDataPackets(1, 65530, 1, 10, 256, 10*time.Millisecond)
+	require.Len(t, rawPackets, 10)
+
+	buffer := NewRetransmissionBuffer(8, time.Second)
+	now := time.Now().UnixNano()
+	for _, rawPacket := range rawPackets {
+		var packet Packet
+		require.NoError(t, packet.Unmarshal(rawPacket))
+		buffer.Add(packet.SequenceNumber, rawPacket, now)
+	}
+
+	// overwritten by later packets
+	_, ok := buffer.Get(65530, now)
+	require.False(t, ok)
+	_, ok = buffer.Get(65531, now)
+	require.False(t, ok)
+
+	packet, ok := buffer.Get(65535, now)
+	require.True(t, ok)
+	require.Equal(t, uint16(65535), packet.SequenceNumber)
+	require.Len(t, packet.Payload, 256)
+
+	packet, ok = buffer.Get(3, now)
+	require.True(t, ok)
+	require.Equal(t, uint16(3), packet.SequenceNumber)
+
+	// never received
+	_, ok = buffer.Get(4, now)
+	require.False(t, ok)
+
+	// expired
+	_, ok = buffer.Get(3, now+int64(2*time.Second))
+	require.False(t, ok)
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datatrack
+
+import (
+	"sync"
+	"time"
+)
+
+type retransmissionBufferEntry struct {
+	sequenceNumber uint16
+	arrivalTime    int64
+	data           []byte
+}
+
+// RetransmissionBuffer keeps the most recent packets of a reliable data track,
+// indexed by sequence number, to serve retransmission requests of subscribers.
+type RetransmissionBuffer struct {
+	maxAge int64
+
+	lock    sync.Mutex
+	entries []retransmissionBufferEntry
+}
+
+func NewRetransmissionBuffer(size int, maxAge time.Duration) *RetransmissionBuffer {
+	return &RetransmissionBuffer{
+		maxAge:  maxAge.Nanoseconds(),
+		entries: make([]retransmissionBufferEntry, size),
+	}
+}
+
+// Add stores a copy of a raw packet, replacing the packet stored in its slot
+func (r *RetransmissionBuffer) Add(sequenceNumber uint16, data []byte, arrivalTime int64) {
+	if len(r.entries) == 0 {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entry := &r.entries[int(sequenceNumber)%len(r.entries)]
+	entry.sequenceNumber = sequenceNumber
+	entry.arrivalTime = arrivalTime
+	entry.data = append(entry.data[:0], data...)
+}
+
+// Get returns the packet with the given sequence number,
+// packets which have been overwritten or are older than the max age are not returned
+func (r *RetransmissionBuffer) Get(sequenceNumber uint16, now int64) (*Packet, bool) {
+	if len(r.entries) == 0 {
+		return nil, false
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entry := &r.entries[int(sequenceNumber)%len(r.entries)]
+	if entry.data == nil || entry.sequenceNumber != sequenceNumber {
+		return nil, false
+	}
+	if r.maxAge > 0 && now-entry.arrivalTime > r.maxAge {
+		return nil, false
+	}
+
+	// packet references the copy, entry data is re-used when the slot is overwritten
+	var packet Packet
+	if err := packet.Unmarshal(append([]byte(nil), entry.data...)); err != nil {
+		return nil, false
+	}
+	return &packet, true
+}
@@ -39,6 +39,11 @@ type dataTrackStats struct {
 	numPacketsOutOfOrder  int
 	numFrames             int // count of `F` tagged packets, i. e. packets with final packet of frame marker
 	numBytes              int
+
+	// reliable mode
+	numNacksReceived    int // received from subscribers
+	numRetransmitted    int
+	numRetransmitMissed int // requested packets which were not in the retransmission buffer
 }
 
 func newDataTrackStats(params dataTrackStatsParams) *dataTrackStats {
@@ -86,6 +91,15 @@ func (d *dataTrackStats) Update(packet *datatrack.Packet, arrivalTime int64, pay
 	}
 }
 
+func (d *dataTrackStats) UpdateNackReceived(numRetransmitted int, numRetransmitMissed int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.numNacksReceived++
+	d.numRetransmitted += numRetransmitted
+	d.numRetransmitMissed += numRetransmitMissed
+}
+
 func (d *dataTrackStats) Close() {
 	d.lock.Lock()
 	defer d.lock.Unlock()
@@ -105,6 +119,9 @@ func (d *dataTrackStats) Close() {
 			"numFrames", d.numFrames,
 			"fps", fps,
 			"numBytes", d.numBytes,
+			"numNacksReceived", d.numNacksReceived,
+			"numRetransmitted", d.numRetransmitted,
+			"numRetransmitMissed", d.numRetransmitMissed,
 		)
 	}
 }
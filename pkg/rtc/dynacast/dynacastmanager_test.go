@@ -299,6 +299,63 @@ func TestSubscribedMaxQuality(t *testing.T) {
 	})
 }
 
+func TestQualityLimit(t *testing.T) {
+	lock := sync.RWMutex{}
+	actualSubscribedQualities := make([]*livekit.SubscribedCodec, 0)
+
+	dm := NewDynacastManagerVideo(DynacastManagerVideoParams{
+		Listener: &testDynacastManagerListener{
+			onSubscribedMaxQualityChange: func(subscribedQualities []*livekit.SubscribedCodec) {
+				lock.Lock()
+				actualSubscribedQualities = subscribedQualities
+				lock.Unlock()
+			},
+		},
+	})
+
+	expectQualities := func(low, medium, high bool) {
+		expectedSubscribedQualities := []*livekit.SubscribedCodec{
+			{
+				Codec: mime.MimeTypeVP8.String(),
+				Qualities: []*livekit.SubscribedQuality{
+					{Quality: livekit.VideoQuality_LOW, Enabled: low},
+					{Quality: livekit.VideoQuality_MEDIUM, Enabled: medium},
+					{Quality: livekit.VideoQuality_HIGH, Enabled: high},
+				},
+			},
+		}
+		require.Eventually(t, func() bool {
+			lock.Lock()
+			defer lock.Unlock()
+
+			return subscribedCodecsAsString(expectedSubscribedQualities) == subscribedCodecsAsString(actualSubscribedQualities)
+		}, 10*time.Second, 100*time.Millisecond)
+	}
+
+	dm.NotifySubscriberMaxQuality("s1", mime.MimeTypeVP8, livekit.VideoQuality_HIGH)
+	expectQualities(true, true, true)
+
+	// limit should cap the subscribed quality
+	dm.LimitQuality(mime.MimeTypeVP8, livekit.VideoQuality_LOW)
+	expectQualities(true, false, false)
+
+	// subscribers cannot go above the limit
+	dm.NotifySubscriberMaxQuality("s2", mime.MimeTypeVP8, livekit.VideoQuality_HIGH)
+	dm.LimitQuality(mime.MimeTypeVP8, livekit.VideoQuality_MEDIUM)
+	expectQualities(true, true, false)
+
+	// limit should not turn on a codec that no one is subscribed to
+	dm.NotifySubscriberMaxQuality("s1", mime.MimeTypeVP8, livekit.VideoQuality_OFF)
+	dm.NotifySubscriberMaxQuality("s2", mime.MimeTypeVP8, livekit.VideoQuality_OFF)
+	expectQualities(false, false, false)
+
+	// removing limit should restore subscribed quality
+	dm.NotifySubscriberMaxQuality("s1", mime.MimeTypeVP8, livekit.VideoQuality_HIGH)
+	expectQualities(true, true, false)
+	dm.LimitQuality(mime.MimeTypeVP8, livekit.VideoQuality_HIGH)
+	expectQualities(true, true, true)
+}
+
 func TestCodecRegression(t *testing.T) {
 	t.Run("codec regression video", func(t *testing.T) {
 		var lock sync.Mutex
@@ -40,6 +40,7 @@ type dynacastManagerVideo struct {
 	params DynacastManagerVideoParams
 
 	maxSubscribedQuality          map[mime.MimeType]livekit.VideoQuality
+	qualityLimit                  map[mime.MimeType]livekit.VideoQuality
 	committedMaxSubscribedQuality map[mime.MimeType]livekit.VideoQuality
 
 	maxSubscribedQualityDebounce        func(func())
@@ -57,6 +58,7 @@ func NewDynacastManagerVideo(params DynacastManagerVideoParams) DynacastManager
 	d := &dynacastManagerVideo{
 		params:                        params,
 		maxSubscribedQuality:          make(map[mime.MimeType]livekit.VideoQuality),
+		qualityLimit:                  make(map[mime.MimeType]livekit.VideoQuality),
 		committedMaxSubscribedQuality: make(map[mime.MimeType]livekit.VideoQuality),
 	}
 	if params.DynacastPauseDelay > 0 {
@@ -104,6 +106,31 @@ func (d *dynacastManagerVideo) ForceQuality(quality livekit.VideoQuality) {
 	d.enqueueSubscribedQualityChange()
 }
 
+// LimitQuality caps the quality of a mime type independent of subscriptions,
+// used to pause higher layers when the publisher uplink is congested.
+// A change in limit is committed immediately without debouncing as
+// holding on to the higher layers would make the congestion worse.
+func (d *dynacastManagerVideo) LimitQuality(mime mime.MimeType, quality livekit.VideoQuality) {
+	d.lock.Lock()
+	limit, ok := d.qualityLimit[mime]
+	if !ok {
+		limit = livekit.VideoQuality_HIGH
+	}
+	if limit == quality {
+		d.lock.Unlock()
+		return
+	}
+
+	if quality == livekit.VideoQuality_HIGH {
+		delete(d.qualityLimit, mime)
+	} else {
+		d.qualityLimit[mime] = quality
+	}
+	d.lock.Unlock()
+
+	d.update(true)
+}
+
 func (d *dynacastManagerVideo) NotifySubscriberMaxQuality(
 	subscriberID livekit.ParticipantID,
 	mime mime.MimeType,
@@ -148,6 +175,7 @@ func (d *dynacastManagerVideo) update(force bool) {
 		"force", force,
 		"committedMaxSubscribedQuality", d.committedMaxSubscribedQuality,
 		"maxSubscribedQuality", d.maxSubscribedQuality,
+		"qualityLimit", d.qualityLimit,
 	)
 
 	if len(d.maxSubscribedQuality) == 0 {
@@ -156,11 +184,13 @@ func (d *dynacastManagerVideo) update(force bool) {
 		return
 	}
 
+	maxQuality := d.getLimitedMaxSubscribedQualityLocked()
+
 	// add or remove of a mime triggers an update
-	changed := len(d.maxSubscribedQuality) != len(d.committedMaxSubscribedQuality)
+	changed := len(maxQuality) != len(d.committedMaxSubscribedQuality)
 	downgradesOnly := !changed
 	if !changed {
-		for mime, quality := range d.maxSubscribedQuality {
+		for mime, quality := range maxQuality {
 			if cq, ok := d.committedMaxSubscribedQuality[mime]; ok {
 				if cq != quality {
 					changed = true
@@ -213,16 +243,28 @@ func (d *dynacastManagerVideo) update(force bool) {
 		"force", force,
 		"committedMaxSubscribedQuality", d.committedMaxSubscribedQuality,
 		"maxSubscribedQuality", d.maxSubscribedQuality,
+		"qualityLimit", d.qualityLimit,
 	)
 
 	// commit change
-	d.committedMaxSubscribedQuality = make(map[mime.MimeType]livekit.VideoQuality, len(d.maxSubscribedQuality))
-	maps.Copy(d.committedMaxSubscribedQuality, d.maxSubscribedQuality)
+	d.committedMaxSubscribedQuality = maxQuality
 
 	d.enqueueSubscribedQualityChange()
 	d.lock.Unlock()
 }
 
+func (d *dynacastManagerVideo) getLimitedMaxSubscribedQualityLocked() map[mime.MimeType]livekit.VideoQuality {
+	maxQuality := make(map[mime.MimeType]livekit.VideoQuality, len(d.maxSubscribedQuality))
+	maps.Copy(maxQuality, d.maxSubscribedQuality)
+
+	for mime, limit := range d.qualityLimit {
+		if quality, ok := maxQuality[mime]; ok && quality != livekit.VideoQuality_OFF && quality > limit {
+			maxQuality[mime] = limit
+		}
+	}
+	return maxQuality
+}
+
 func (d *dynacastManagerVideo) enqueueSubscribedQualityChange() {
 	if d.isClosed || d.params.Listener == nil {
 		return
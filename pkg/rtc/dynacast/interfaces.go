@@ -55,6 +55,9 @@ type DynacastManager interface {
 	ForceUpdate()
 	ForceQuality(quality livekit.VideoQuality)
 	ForceEnable(enabled bool)
+	// LimitQuality caps the quality requested from the publisher irrespective of
+	// what subscribers want, VideoQuality_HIGH removes the cap.
+	LimitQuality(mime mime.MimeType, quality livekit.VideoQuality)
 
 	NotifySubscriberMaxQuality(
 		subscriberID livekit.ParticipantID,
@@ -83,13 +86,14 @@ var _ DynacastManager = (*dynacastManagerNull)(nil)
 type dynacastManagerNull struct {
 }
 
-func (d *dynacastManagerNull) AddCodec(mime mime.MimeType)                          {}
-func (d *dynacastManagerNull) HandleCodecRegression(fromMime, toMime mime.MimeType) {}
-func (d *dynacastManagerNull) Restart()                                             {}
-func (d *dynacastManagerNull) Close()                                               {}
-func (d *dynacastManagerNull) ForceUpdate()                                         {}
-func (d *dynacastManagerNull) ForceQuality(quality livekit.VideoQuality)            {}
-func (d *dynacastManagerNull) ForceEnable(enabled bool)                             {}
+func (d *dynacastManagerNull) AddCodec(mime mime.MimeType)                                   {}
+func (d *dynacastManagerNull) HandleCodecRegression(fromMime, toMime mime.MimeType)          {}
+func (d *dynacastManagerNull) Restart()                                                      {}
+func (d *dynacastManagerNull) Close()                                                        {}
+func (d *dynacastManagerNull) ForceUpdate()                                                  {}
+func (d *dynacastManagerNull) ForceQuality(quality livekit.VideoQuality)                     {}
+func (d *dynacastManagerNull) ForceEnable(enabled bool)                                      {}
+func (d *dynacastManagerNull) LimitQuality(mime mime.MimeType, quality livekit.VideoQuality) {}
 func (d *dynacastManagerNull) NotifySubscriberMaxQuality(
 	subscriberID livekit.ParticipantID,
 	mime mime.MimeType,
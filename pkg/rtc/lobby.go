@@ -0,0 +1,396 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/psrpc"
+
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/telemetry"
+)
+
+// LobbyTopic is the reserved data packet topic used to exchange lobby messages between hosts and the server.
+// Hosts receive LobbyUpdate messages and send LobbyRequest messages on this topic. The same request can
+// be sent through RoomService.SendData.
+const LobbyTopic = "lk.lobby"
+
+// LobbyWaitingAttribute is set to "true" on the participant of the join response sent to a participant waiting
+// in the lobby. A second join response without it follows when the participant is admitted.
+const LobbyWaitingAttribute = "lk.lobby.waiting"
+
+const (
+	LobbyActionAdmit  = "admit"
+	LobbyActionReject = "reject"
+)
+
+var (
+	ErrNotInLobby       = psrpc.NewErrorf(psrpc.NotFound, "participant is not waiting in the lobby")
+	ErrNotLobbyHost     = psrpc.NewErrorf(psrpc.PermissionDenied, "participant is not allowed to manage the lobby")
+	ErrInvalidLobbyData = psrpc.NewErrorf(psrpc.InvalidArgument, "invalid lobby request")
+)
+
+type LobbyRequest struct {
+	Action   string `json:"action"`
+	Identity string `json:"identity"`
+}
+
+type LobbyParticipant struct {
+	Sid          string            `json:"sid"`
+	Identity     string            `json:"identity"`
+	Name         string            `json:"name,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	WaitingSince int64             `json:"waitingSince"`
+}
+
+type LobbyUpdate struct {
+	Pending []LobbyParticipant `json:"pending"`
+}
+
+type pendingParticipant struct {
+	participant   types.LocalParticipant
+	requestSource routing.MessageSource
+	opts          *ParticipantOptions
+	iceServers    []*livekit.ICEServer
+	onAdmitted    func()
+	waitingSince  time.Time
+	timer         *time.Timer
+}
+
+// RequiresAdmission returns true if the participant has to wait in the lobby before joining
+func (r *Room) RequiresAdmission(participant types.LocalParticipant) bool {
+	lobbyConfig := r.roomConfig.Lobby
+	if !lobbyConfig.IsEnabled() || participant.IsDependent() {
+		return false
+	}
+
+	grants := participant.ClaimGrants()
+	return grants != nil && grants.Attributes[lobbyConfig.Attribute] == "true"
+}
+
+func (r *Room) isLobbyHost(participant types.LocalParticipant) bool {
+	grants := participant.ClaimGrants()
+	if grants == nil {
+		return false
+	}
+	if grants.Video != nil && grants.Video.RoomAdmin {
+		return true
+	}
+
+	hostAttribute := r.roomConfig.Lobby.HostAttribute
+	return hostAttribute != "" && grants.Attributes[hostAttribute] == "true"
+}
+
+// JoinLobby places the participant in the lobby. The participant joins the room when a host admits them,
+// after which onAdmitted is invoked. A join response marked with LobbyWaitingAttribute is sent right away so
+// that the signal connection is established while waiting, the full join response follows on admission.
+func (r *Room) JoinLobby(
+	participant types.LocalParticipant,
+	requestSource routing.MessageSource,
+	opts *ParticipantOptions,
+	iceServers []*livekit.ICEServer,
+	onAdmitted func(),
+) error {
+	r.lock.Lock()
+	if r.IsClosed() {
+		r.lock.Unlock()
+		return ErrRoomClosed
+	}
+
+	identity := participant.Identity()
+	if r.participants[identity] != nil {
+		r.lock.Unlock()
+		return ErrAlreadyJoined
+	}
+	// a new session replaces the one waiting in the lobby
+	replaced := r.pendingParticipants[identity]
+	if replaced != nil && replaced.timer != nil {
+		replaced.timer.Stop()
+	}
+
+	pp := &pendingParticipant{
+		participant:   participant,
+		requestSource: requestSource,
+		opts:          opts,
+		iceServers:    iceServers,
+		onAdmitted:    onAdmitted,
+		waitingSince:  time.Now(),
+	}
+	if timeout := r.roomConfig.Lobby.Timeout; timeout > 0 {
+		pp.timer = time.AfterFunc(timeout, func() {
+			r.onLobbyTimeout(participant)
+		})
+	}
+	r.pendingParticipants[identity] = pp
+	numPending := len(r.pendingParticipants)
+	r.lock.Unlock()
+
+	if replaced != nil {
+		_ = replaced.participant.Close(true, types.ParticipantCloseReasonDuplicateIdentity, false)
+	}
+
+	participant.GetLogger().Infow("participant waiting in lobby", "numPending", numPending)
+	if err := participant.SendJoinResponse(r.createLobbyJoinResponse(participant)); err != nil {
+		participant.GetLogger().Debugw("could not send lobby join response", "error", err)
+	}
+	r.sendLobbyUpdate()
+	return nil
+}
+
+// createLobbyJoinResponse answers a participant waiting in the lobby, without other participants or ICE servers
+func (r *Room) createLobbyJoinResponse(participant types.LocalParticipant) *livekit.JoinResponse {
+	pi := participant.ToProto()
+	pi.Attributes = maps.Clone(pi.Attributes)
+	if pi.Attributes == nil {
+		pi.Attributes = make(map[string]string, 1)
+	}
+	pi.Attributes[LobbyWaitingAttribute] = "true"
+
+	return &livekit.JoinResponse{
+		Room:                r.ToProto(),
+		Participant:         pi,
+		ClientConfiguration: participant.GetClientConfiguration(),
+		PingInterval:        PingIntervalSeconds,
+		PingTimeout:         PingTimeoutSeconds,
+		ServerInfo:          r.serverInfo,
+		ServerVersion:       r.serverInfo.Version,
+		ServerRegion:        r.serverInfo.Region,
+	}
+}
+
+func (r *Room) IsParticipantPending(identity livekit.ParticipantIdentity) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.pendingParticipants[identity] != nil
+}
+
+func (r *Room) GetPendingParticipants() []types.LocalParticipant {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	participants := make([]types.LocalParticipant, 0, len(r.pendingParticipants))
+	for _, pp := range r.pendingParticipants {
+		participants = append(participants, pp.participant)
+	}
+	return participants
+}
+
+// AdmitParticipant moves a participant waiting in the lobby into the room
+func (r *Room) AdmitParticipant(identity livekit.ParticipantIdentity) error {
+	pp := r.removePendingParticipant(identity)
+	if pp == nil {
+		return ErrNotInLobby
+	}
+	r.sendLobbyUpdate()
+
+	participant := pp.participant
+	if err := r.Join(participant, pp.requestSource, pp.opts, pp.iceServers); err != nil {
+		participant.GetLogger().Warnw("could not admit participant", err)
+		_ = participant.Close(true, types.ParticipantCloseReasonJoinFailed, false)
+		return err
+	}
+
+	participant.GetLogger().Infow("participant admitted from lobby", "waited", time.Since(pp.waitingSince))
+	r.telemetry.NotifyRoomEvent(context.Background(), telemetry.EventParticipantAdmitted, r.ToProto(), participant.ToProto())
+
+	if pp.onAdmitted != nil {
+		pp.onAdmitted()
+	}
+	return nil
+}
+
+// RejectParticipant removes a participant from the lobby without letting them into the room
+func (r *Room) RejectParticipant(identity livekit.ParticipantIdentity) error {
+	return r.rejectPendingParticipant(identity, types.ParticipantCloseReasonUserRejected)
+}
+
+func (r *Room) onLobbyTimeout(participant types.LocalParticipant) {
+	r.lock.RLock()
+	pp := r.pendingParticipants[participant.Identity()]
+	r.lock.RUnlock()
+	if pp == nil || pp.participant != participant {
+		return
+	}
+
+	_ = r.rejectPendingParticipant(participant.Identity(), types.ParticipantCloseReasonLobbyTimeout)
+}
+
+func (r *Room) rejectPendingParticipant(identity livekit.ParticipantIdentity, reason types.ParticipantCloseReason) error {
+	pp := r.removePendingParticipant(identity)
+	if pp == nil {
+		return ErrNotInLobby
+	}
+	r.sendLobbyUpdate()
+
+	participant := pp.participant
+	participant.GetLogger().Infow("participant rejected from lobby", "reason", reason, "waited", time.Since(pp.waitingSince))
+	r.telemetry.NotifyRoomEvent(context.Background(), telemetry.EventParticipantRejected, r.ToProto(), participant.ToProto())
+
+	_ = participant.Close(true, reason, false)
+	return nil
+}
+
+func (r *Room) removePendingParticipant(identity livekit.ParticipantIdentity) *pendingParticipant {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	pp := r.pendingParticipants[identity]
+	if pp == nil {
+		return nil
+	}
+	delete(r.pendingParticipants, identity)
+
+	if pp.timer != nil {
+		pp.timer.Stop()
+	}
+	return pp
+}
+
+// removes a pending participant that has left before being admitted
+func (r *Room) leaveLobby(identity livekit.ParticipantIdentity, pID livekit.ParticipantID) {
+	r.lock.Lock()
+	pp := r.pendingParticipants[identity]
+	if pp == nil || (pID != "" && pp.participant.ID() != pID) {
+		r.lock.Unlock()
+		return
+	}
+	delete(r.pendingParticipants, identity)
+	if pp.timer != nil {
+		pp.timer.Stop()
+	}
+	r.lock.Unlock()
+
+	pp.participant.GetLogger().Infow("participant left lobby", "waited", time.Since(pp.waitingSince))
+	r.sendLobbyUpdate()
+}
+
+func (r *Room) closeLobby(reason types.ParticipantCloseReason) {
+	r.lock.Lock()
+	pending := r.pendingParticipants
+	r.pendingParticipants = make(map[livekit.ParticipantIdentity]*pendingParticipant)
+	r.lock.Unlock()
+
+	for _, pp := range pending {
+		if pp.timer != nil {
+			pp.timer.Stop()
+		}
+		_ = pp.participant.Close(true, reason, false)
+	}
+}
+
+// HandleLobbyRequest handles an admit/reject request from a host, or from the server API when source is nil
+func (r *Room) HandleLobbyRequest(source types.LocalParticipant, payload []byte) error {
+	if source != nil && !r.isLobbyHost(source) {
+		return ErrNotLobbyHost
+	}
+
+	var req LobbyRequest
+	if err := json.Unmarshal(payload, &req); err != nil || req.Identity == "" {
+		return ErrInvalidLobbyData
+	}
+
+	identity := livekit.ParticipantIdentity(req.Identity)
+	switch req.Action {
+	case LobbyActionAdmit:
+		return r.AdmitParticipant(identity)
+	case LobbyActionReject:
+		return r.RejectParticipant(identity)
+	default:
+		return ErrInvalidLobbyData
+	}
+}
+
+func (r *Room) lobbyUpdateLocked() *LobbyUpdate {
+	update := &LobbyUpdate{
+		Pending: make([]LobbyParticipant, 0, len(r.pendingParticipants)),
+	}
+	for _, pp := range r.pendingParticipants {
+		p := pp.participant
+		lp := LobbyParticipant{
+			Sid:          string(p.ID()),
+			Identity:     string(p.Identity()),
+			WaitingSince: pp.waitingSince.UnixMilli(),
+		}
+		if grants := p.ClaimGrants(); grants != nil {
+			lp.Name = grants.Name
+			lp.Attributes = grants.Attributes
+		}
+		update.Pending = append(update.Pending, lp)
+	}
+	slices.SortFunc(update.Pending, func(a, b LobbyParticipant) int {
+		return int(a.WaitingSince - b.WaitingSince)
+	})
+	return update
+}
+
+// sends the current lobby state to all hosts
+func (r *Room) sendLobbyUpdate() {
+	if !r.roomConfig.Lobby.IsEnabled() {
+		return
+	}
+
+	r.lock.RLock()
+	update := r.lobbyUpdateLocked()
+	hosts := make([]types.LocalParticipant, 0)
+	for _, p := range r.participants {
+		if r.isLobbyHost(p) {
+			hosts = append(hosts, p)
+		}
+	}
+	r.lock.RUnlock()
+
+	r.sendLobbyUpdateTo(hosts, update)
+}
+
+func (r *Room) sendLobbyUpdateTo(hosts []types.LocalParticipant, update *LobbyUpdate) {
+	if len(hosts) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(update)
+	if err != nil {
+		r.logger.Errorw("failed to marshal lobby update", err)
+		return
+	}
+	data, err := proto.Marshal(&livekit.DataPacket{
+		Kind: livekit.DataPacket_RELIABLE,
+		Value: &livekit.DataPacket_User{
+			User: &livekit.UserPacket{
+				Payload: payload,
+				Topic:   proto.String(LobbyTopic),
+			},
+		},
+	})
+	if err != nil {
+		r.logger.Errorw("failed to marshal lobby update", err)
+		return
+	}
+
+	for _, host := range hosts {
+		if err := host.SendDataMessage(livekit.DataPacket_RELIABLE, data, "", 0); err != nil {
+			host.GetLogger().Debugw("could not send lobby update", "error", err)
+		}
+	}
+}
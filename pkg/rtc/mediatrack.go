@@ -19,6 +19,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/frostbyte73/core"
 	"github.com/pion/rtcp"
 	"github.com/pion/webrtc/v4"
 	"go.uber.org/atomic"
@@ -36,6 +37,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
 	"github.com/livekit/livekit-server/pkg/sfu/interceptor"
+	"github.com/livekit/livekit-server/pkg/sfu/uplinkcongestion"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	util "github.com/livekit/mediatransportutil"
 )
@@ -54,6 +56,8 @@ type MediaTrack struct {
 
 	dynacastManager dynacast.DynacastManager
 
+	closed core.Fuse
+
 	lock sync.RWMutex
 
 	rttFromXR atomic.Bool
@@ -62,6 +66,9 @@ type MediaTrack struct {
 	regressionTargetCodec         mime.MimeType
 	regressionTargetCodecReceived bool
 
+	// qualities the publisher is limited to because of uplink congestion, only tracked without dynacast
+	uplinkQualityLimits map[mime.MimeType]livekit.VideoQuality
+
 	onSubscribedMaxQualityChange func(
 		trackID livekit.TrackID,
 		trackInfo *livekit.TrackInfo,
@@ -99,6 +106,8 @@ type MediaTrackParams struct {
 	EnableRTPStreamRestartDetection  bool
 	UpdateTrackInfoByVideoSizeChange bool
 	ForceBackupCodecPolicySimulcast  bool
+	// publisher layers are not adapted to subscriptions, they are only limited on uplink congestion
+	DisableDynacast bool
 }
 
 func NewMediaTrack(params MediaTrackParams, ti *livekit.TrackInfo) *MediaTrack {
@@ -151,6 +160,10 @@ func NewMediaTrack(params MediaTrackParams, ti *livekit.TrackInfo) *MediaTrack {
 			Logger:             params.Logger,
 		})
 
+		if params.VideoConfig.UplinkCongestion.Enabled && params.VideoConfig.UplinkCongestion.SampleInterval > 0 {
+			go t.uplinkCongestionWorker()
+		}
+
 	case livekit.TrackType_AUDIO:
 		if len(ti.Codecs) > 1 {
 			t.dynacastManager = dynacast.NewDynacastManagerAudio(dynacast.DynacastManagerAudioParams{
@@ -424,6 +437,7 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track sfu.TrackRe
 			t.MediaTrackReceiver.SetClosing(false)
 			t.MediaTrackReceiver.ClearReceiver(mimeType, false)
 			if t.MediaTrackReceiver.TryClose() {
+				t.closed.Break()
 				if t.dynacastManager != nil {
 					t.dynacastManager.Close()
 				}
@@ -634,6 +648,7 @@ func (t *MediaTrack) Restart() {
 }
 
 func (t *MediaTrack) Close(isExpectedToResume bool) {
+	t.closed.Break()
 	t.MediaTrackReceiver.SetClosing(isExpectedToResume)
 	if t.dynacastManager != nil {
 		t.dynacastManager.Close()
@@ -669,6 +684,110 @@ func (t *MediaTrack) Logger() logger.Logger {
 	return t.params.Logger
 }
 
+// PublishBitrate returns the bitrate received for the track across codecs since the last call, in bits per second
+func (t *MediaTrack) PublishBitrate() float64 {
+	var bitrate float64
+	for _, receiver := range t.MediaTrackReceiver.Receivers() {
+		if wr, ok := receiver.(*sfu.WebRTCReceiver); ok {
+			bitrate += wr.GetPublishBitrate()
+		}
+	}
+	return bitrate
+}
+
+// uplinkCongestionWorker periodically checks the health of the publisher uplink using
+// receive side stats of each simulcast layer. When congested, the publisher is asked
+// to pause higher layers via the same mechanism used by dynacast and the layers are
+// re-enabled once the uplink recovers.
+func (t *MediaTrack) uplinkCongestionWorker() {
+	ticker := time.NewTicker(t.params.VideoConfig.UplinkCongestion.SampleInterval)
+	defer ticker.Stop()
+
+	detectors := make(map[mime.MimeType]*uplinkcongestion.UplinkCongestionDetector)
+	for {
+		select {
+		case <-t.closed.Watch():
+			return
+
+		case <-ticker.C:
+			for _, receiver := range t.MediaTrackReceiver.Receivers() {
+				wr, ok := receiver.(*sfu.WebRTCReceiver)
+				if !ok {
+					continue
+				}
+
+				mimeType := wr.Mime()
+				detector, ok := detectors[mimeType]
+				if !ok {
+					detector = uplinkcongestion.NewUplinkCongestionDetector(uplinkcongestion.UplinkCongestionDetectorParams{
+						Config: t.params.VideoConfig.UplinkCongestion,
+						Logger: LoggerWithCodecMime(t.params.Logger, mimeType),
+					})
+					detectors[mimeType] = detector
+				}
+
+				maxLayer, changed := detector.Update(wr.GetUplinkStats())
+				if !changed {
+					continue
+				}
+
+				quality := livekit.VideoQuality_HIGH
+				if maxLayer != buffer.DefaultMaxLayerSpatial {
+					quality = buffer.GetVideoQualityForSpatialLayer(mimeType, maxLayer, t.MediaTrackReceiver.TrackInfo())
+					if quality == livekit.VideoQuality_OFF {
+						// layer not described in track info, do not turn off the track
+						quality = livekit.VideoQuality_LOW
+					}
+				}
+				t.limitUplinkQuality(mimeType, quality)
+			}
+		}
+	}
+}
+
+// limitUplinkQuality caps the quality the publisher sends. With dynacast, the cap applies on top of the
+// qualities subscribers need. Without dynacast, the publisher is asked to send all layers up to the cap.
+func (t *MediaTrack) limitUplinkQuality(mimeType mime.MimeType, quality livekit.VideoQuality) {
+	if !t.params.DisableDynacast {
+		if t.dynacastManager != nil {
+			t.dynacastManager.LimitQuality(mimeType, quality)
+		}
+		return
+	}
+
+	t.lock.Lock()
+	if t.uplinkQualityLimits == nil {
+		t.uplinkQualityLimits = make(map[mime.MimeType]livekit.VideoQuality)
+	}
+	t.uplinkQualityLimits[mimeType] = quality
+
+	subscribedQualities := make([]*livekit.SubscribedCodec, 0, len(t.uplinkQualityLimits))
+	maxSubscribedQualities := make([]types.SubscribedCodecQuality, 0, len(t.uplinkQualityLimits))
+	for mt, limit := range t.uplinkQualityLimits {
+		qualities := make([]*livekit.SubscribedQuality, 0, livekit.VideoQuality_HIGH+1)
+		for q := livekit.VideoQuality_LOW; q <= livekit.VideoQuality_HIGH; q++ {
+			qualities = append(qualities, &livekit.SubscribedQuality{Quality: q, Enabled: q <= limit})
+		}
+		subscribedQualities = append(subscribedQualities, &livekit.SubscribedCodec{
+			Codec:     mt.String(),
+			Qualities: qualities,
+		})
+		maxSubscribedQualities = append(maxSubscribedQualities, types.SubscribedCodecQuality{CodecMime: mt, Quality: limit})
+	}
+	onSubscribedMaxQualityChange := t.onSubscribedMaxQualityChange
+	t.lock.Unlock()
+
+	t.params.Logger.Infow("limiting publisher quality on uplink congestion", "mime", mimeType, "quality", quality)
+	if onSubscribedMaxQualityChange != nil && !t.IsMuted() {
+		_ = onSubscribedMaxQualityChange(
+			t.ID(),
+			t.ToProto(),
+			subscribedQualities,
+			maxSubscribedQualities,
+		)
+	}
+}
+
 // dynacast.DynacastManagerListtener implementation
 var _ dynacast.DynacastManagerListener = (*MediaTrack)(nil)
 
@@ -680,7 +799,8 @@ func (t *MediaTrack) OnDynacastSubscribedMaxQualityChange(
 	onSubscribedMaxQualityChange := t.onSubscribedMaxQualityChange
 	t.lock.RUnlock()
 
-	if onSubscribedMaxQualityChange != nil && !t.IsMuted() {
+	// without dynacast, the publisher sends all layers unless limited by uplink congestion
+	if onSubscribedMaxQualityChange != nil && !t.IsMuted() && !t.params.DisableDynacast {
 		_ = onSubscribedMaxQualityChange(
 			t.ID(),
 			t.ToProto(),
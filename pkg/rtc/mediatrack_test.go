@@ -22,6 +22,8 @@ import (
 	"github.com/livekit/protocol/codecs/mime"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 )
 
 func TestTrackInfo(t *testing.T) {
@@ -195,3 +197,53 @@ func TestGetQualityForDimension(t *testing.T) {
 	})
 
 }
+
+func TestUplinkQualityLimitWithoutDynacast(t *testing.T) {
+	mt := NewMediaTrack(MediaTrackParams{
+		Logger:          logger.GetLogger(),
+		DisableDynacast: true,
+	}, &livekit.TrackInfo{
+		Sid:  "TR_video",
+		Type: livekit.TrackType_VIDEO,
+	})
+
+	var updates [][]*livekit.SubscribedCodec
+	mt.OnSubscribedMaxQualityChange(func(
+		_ livekit.TrackID,
+		_ *livekit.TrackInfo,
+		subscribedQualities []*livekit.SubscribedCodec,
+		_ []types.SubscribedCodecQuality,
+	) error {
+		updates = append(updates, subscribedQualities)
+		return nil
+	})
+
+	// subscriber driven qualities are not sent to the publisher
+	mt.OnDynacastSubscribedMaxQualityChange(
+		[]*livekit.SubscribedCodec{{Codec: mime.MimeTypeVP8.String()}},
+		[]types.SubscribedCodecQuality{{CodecMime: mime.MimeTypeVP8, Quality: livekit.VideoQuality_LOW}},
+	)
+	require.Empty(t, updates)
+
+	// uplink congestion limits are
+	mt.limitUplinkQuality(mime.MimeTypeVP8, livekit.VideoQuality_LOW)
+	require.Len(t, updates, 1)
+	require.Len(t, updates[0], 1)
+	require.Equal(t, mime.MimeTypeVP8.String(), updates[0][0].Codec)
+	enabled := make(map[livekit.VideoQuality]bool)
+	for _, q := range updates[0][0].Qualities {
+		enabled[q.Quality] = q.Enabled
+	}
+	require.Equal(t, map[livekit.VideoQuality]bool{
+		livekit.VideoQuality_LOW:    true,
+		livekit.VideoQuality_MEDIUM: false,
+		livekit.VideoQuality_HIGH:   false,
+	}, enabled)
+
+	// and lifted once the uplink recovered
+	mt.limitUplinkQuality(mime.MimeTypeVP8, livekit.VideoQuality_HIGH)
+	require.Len(t, updates, 2)
+	for _, q := range updates[1][0].Qualities {
+		require.True(t, q.Enabled)
+	}
+}
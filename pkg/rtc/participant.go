@@ -222,9 +222,14 @@ type ParticipantParams struct {
 	PreferVideoSizeFromMedia        bool
 	UseSinglePeerConnection         bool
 	EnableDataTracks                bool
+	DataTrackSubscriber             config.DataTrackSubscriberConfig
+	DataTrackReliable               config.DataTrackReliableConfig
 	EnableRTPStreamRestartDetection bool
 	ForceBackupCodecPolicySimulcast bool
 	DisableTransceiverReuseForE2EE  bool
+	Timeline                        sutils.TimelineRecorder
+	RelayOnly                       bool
+	RelayAddresses                  []string
 }
 
 type ParticipantImpl struct {
@@ -258,6 +263,10 @@ type ParticipantImpl struct {
 
 	pubRTCPQueue *sutils.TypedOpsQueue[postRtcpOp]
 
+	quota           config.ParticipantQuotaConfig
+	dataRateLimiter *sutils.ByteRateLimiter
+	dataThrottled   atomic.Bool
+
 	// hold reference for MediaTrack
 	twcc *twcc.Responder
 
@@ -300,6 +309,8 @@ type ParticipantImpl struct {
 	telemetryGuard *telemetry.ReferenceGuard
 
 	lock utils.RWMutex
+	// attributes set by the token or the server API, subscription rules are matched against them
+	issuedAttributes map[string]string
 
 	dirty   atomic.Bool
 	version atomic.Uint32
@@ -371,9 +382,18 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 		telemetryGuard:                &telemetry.ReferenceGuard{},
 		nextSubscribedDataTrackHandle: uint16(rand.Intn(256)),
 		requireBroadcast:              params.Grants.Metadata != "" || len(params.Grants.Attributes) != 0,
+		issuedAttributes:              maps.Clone(params.Grants.Attributes),
 	}
 	p.setupSignalling()
 
+	p.quota = params.LimitConfig.GetParticipantQuota(params.Grants.RoomPreset)
+	if p.quota.MaxDataBytesPerSec > 0 {
+		p.dataRateLimiter = sutils.NewByteRateLimiter(p.quota.MaxDataBytesPerSec, minDataRateLimiterBurst)
+	}
+	if p.quota.MaxPublishBitrate > 0 {
+		go p.publishQuotaWorker()
+	}
+
 	p.id.Store(params.SID)
 	p.dataChannelStats = NewBytesTrackStats(
 		p.params.Country,
@@ -509,6 +529,14 @@ func (p *ParticipantImpl) GetDisableSenderReportPassThrough() bool {
 	return p.params.DisableSenderReportPassThrough
 }
 
+func (p *ParticipantImpl) GetTimeline() sutils.TimelineRecorder {
+	return p.params.Timeline
+}
+
+func (p *ParticipantImpl) IsRelayOnly() bool {
+	return p.params.RelayOnly
+}
+
 func (p *ParticipantImpl) ID() livekit.ParticipantID {
 	return p.id.Load().(livekit.ParticipantID)
 }
@@ -689,6 +717,9 @@ func (p *ParticipantImpl) UpdateMetadata(update *livekit.UpdateParticipantMetada
 		p.SetMetadata(update.Metadata)
 	}
 	if update.Attributes != nil {
+		if fromAdmin {
+			p.setIssuedAttributes(update.Attributes)
+		}
 		p.SetAttributes(update.Attributes)
 	}
 	return sendRequestResponse()
@@ -771,6 +802,8 @@ func (p *ParticipantImpl) SetAttributes(attrs map[string]string) {
 	onClaimsChanged := p.onClaimsChanged
 	p.lock.Unlock()
 
+	p.UpTrackManager.UpdateSubscriptionRules(grants.Attributes)
+
 	p.listener().OnParticipantUpdate(p)
 
 	if onClaimsChanged != nil {
@@ -782,6 +815,35 @@ func (p *ParticipantImpl) ClaimGrants() *auth.ClaimGrants {
 	return p.grants.Load()
 }
 
+func (p *ParticipantImpl) IssuedAttributes() map[string]string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.issuedAttributes
+}
+
+// attributes updated by the client are not issued, they cannot grant access through subscription rules
+func (p *ParticipantImpl) setIssuedAttributes(attrs map[string]string) {
+	if len(attrs) == 0 {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	issuedAttributes := maps.Clone(p.issuedAttributes)
+	if issuedAttributes == nil {
+		issuedAttributes = make(map[string]string)
+	}
+	for k, v := range attrs {
+		if v == "" {
+			delete(issuedAttributes, k)
+		} else {
+			issuedAttributes[k] = v
+		}
+	}
+	// replaced rather than modified, callers hold on to the returned map
+	p.issuedAttributes = issuedAttributes
+}
+
 func (p *ParticipantImpl) TokenExpiresAt() time.Time {
 	return p.params.TokenExpiresAt
 }
@@ -1329,6 +1391,18 @@ func (p *ParticipantImpl) AddTrack(req *livekit.AddTrackRequest) {
 	}
 
 	p.pendingTracksLock.Lock()
+	if reason := p.checkPublishQuotaLocked(req); reason != "" {
+		p.pendingTracksLock.Unlock()
+		p.pubLogger.Warnw("track publish quota exceeded", nil, "trackID", req.Sid, "kind", req.Type, "reason", reason)
+		p.sendRequestResponse(&livekit.RequestResponse{
+			Reason:  livekit.RequestResponse_LIMIT_EXCEEDED,
+			Message: reason,
+			Request: &livekit.RequestResponse_AddTrack{
+				AddTrack: utils.CloneProto(req),
+			},
+		})
+		return
+	}
 	ti := p.addPendingTrackLocked(req)
 	p.pendingTracksLock.Unlock()
 	if ti == nil {
@@ -1377,6 +1451,9 @@ func (p *ParticipantImpl) SetMigrateInfo(
 				Logger:              p.params.Logger.WithValues("trackID", dti.Sid),
 				ParticipantID:       p.ID,
 				ParticipantIdentity: p.params.Identity,
+				SubscriberConfig:    p.params.DataTrackSubscriber,
+				Reliable:            isReliableDataTrack(participantAttributes(p), dti.Name),
+				ReliableConfig:      p.params.DataTrackReliable,
 				BytesTrackStats: NewBytesTrackStats(
 					p.params.Country,
 					livekit.TrackID(dti.Sid),
@@ -1937,6 +2014,10 @@ func (h SubscriberTransportHandler) OnDataSendError(err error) {
 	h.p.onDataSendError(err)
 }
 
+func (h SubscriberTransportHandler) OnDataTrackMessage(data []byte, _arrivalTime int64) {
+	h.p.onReceivedSubscriberDataTrackMessage(data)
+}
+
 // ----------------------------------------------------------
 
 type PrimaryTransportHandler struct {
@@ -2014,6 +2095,9 @@ func (p *ParticipantImpl) setupTransportManager() error {
 		UseOneShotSignallingMode:      p.params.UseOneShotSignallingMode,
 		FireOnTrackBySdp:              p.params.FireOnTrackBySdp,
 		EnableDataTracks:              p.params.EnableDataTracks,
+		Timeline:                      p.params.Timeline,
+		RelayOnly:                     p.params.RelayOnly,
+		RelayAddresses:                p.params.RelayAddresses,
 	}
 	if p.params.SyncStreams && p.params.PlayoutDelay.GetEnabled() && p.params.ClientInfo.isFirefox() {
 		// we will disable playout delay for Firefox if the user is expecting
@@ -2032,7 +2116,7 @@ func (p *ParticipantImpl) setupTransportManager() error {
 		if p.params.ClientConf == nil {
 			p.params.ClientConf = &livekit.ClientConfiguration{}
 		}
-		if iceConfig.PreferenceSubscriber == livekit.ICECandidateType_ICT_TLS {
+		if iceConfig.PreferenceSubscriber == livekit.ICECandidateType_ICT_TLS || p.params.RelayOnly {
 			p.params.ClientConf.ForceRelay = livekit.ClientConfigSetting_ENABLED
 		} else {
 			// UNSET indicates that clients could override RTCConfiguration to forceRelay
@@ -2062,6 +2146,7 @@ func (p *ParticipantImpl) setupUpTrackManager() {
 	})
 
 	p.UpTrackManager.OnUpTrackManagerClose(p.onUpTrackManagerClose)
+	p.UpTrackManager.UpdateSubscriptionRules(p.grants.Load().Attributes)
 }
 
 func (p *ParticipantImpl) setupUpDataTrackManager() {
@@ -2087,6 +2172,7 @@ func (p *ParticipantImpl) setupSubscriptionManager() {
 		OnSubscriptionError:      p.onSubscriptionError,
 		SubscriptionLimitVideo:   p.params.SubscriptionLimitVideo,
 		SubscriptionLimitAudio:   p.params.SubscriptionLimitAudio,
+		SubscriptionLimit:        p.quota.MaxSubscriptions,
 		UseOneShotSignallingMode: p.params.UseOneShotSignallingMode,
 	})
 }
@@ -2338,6 +2424,9 @@ func (p *ParticipantImpl) onReceivedDataMessage(kind livekit.DataPacket_Kind, da
 	}
 
 	p.dataChannelStats.AddBytes(uint64(len(data)), false)
+	if !p.allowDataMessage(len(data)) {
+		return
+	}
 
 	dp := &livekit.DataPacket{}
 	if err := proto.Unmarshal(data, dp); err != nil {
@@ -2551,6 +2640,9 @@ func (p *ParticipantImpl) onReceivedDataMessageUnlabeled(data []byte) {
 	}
 
 	p.dataChannelStats.AddBytes(uint64(len(data)), false)
+	if !p.allowDataMessage(len(data)) {
+		return
+	}
 
 	p.listener().OnDataMessageUnlabeled(p, data)
 }
@@ -2744,10 +2836,7 @@ func (p *ParticipantImpl) onSubscribedMaxQualityChange(
 	subscribedQualities []*livekit.SubscribedCodec,
 	maxSubscribedQualities []types.SubscribedCodecQuality,
 ) error {
-	if p.params.DisableDynacast {
-		return nil
-	}
-
+	// without dynacast, tracks only report qualities limited on uplink congestion
 	if len(subscribedQualities) == 0 {
 		return nil
 	}
@@ -3353,6 +3442,7 @@ func (p *ParticipantImpl) addMediaTrack(signalCid string, ti *livekit.TrackInfo)
 		EnableRTPStreamRestartDetection:  p.params.EnableRTPStreamRestartDetection,
 		UpdateTrackInfoByVideoSizeChange: p.params.UseOneShotSignallingMode,
 		ForceBackupCodecPolicySimulcast:  p.params.ForceBackupCodecPolicySimulcast,
+		DisableDynacast:                  p.params.DisableDynacast,
 	}, ti)
 
 	mt.OnSubscribedMaxQualityChange(p.onSubscribedMaxQualityChange)
@@ -3905,6 +3995,15 @@ func (p *ParticipantImpl) setupEnabledCodecs(publishEnabledCodecs []*livekit.Cod
 
 func (p *ParticipantImpl) replayJoiningReliableMessages() {
 	p.reliableDataInfo.joiningMessageLock.Lock()
+	if !p.reliableDataInfo.canWriteReliable && !p.params.Migration {
+		// new joiners catch up with the data history before messages received while joining
+		for _, msg := range p.helper().GetDataHistory(p) {
+			if firstSeq, ok := p.reliableDataInfo.joiningMessageFirstSeqs[msg.SenderID]; ok && msg.Seq >= firstSeq {
+				continue
+			}
+			p.TransportManager.SendDataMessage(livekit.DataPacket_RELIABLE, msg.Data)
+		}
+	}
 	for _, msgCache := range p.helper().GetCachedReliableDataMessage(p.reliableDataInfo.joiningMessageFirstSeqs) {
 		if len(msgCache.DestIdentities) != 0 && !slices.Contains(msgCache.DestIdentities, p.Identity()) {
 			continue
@@ -99,6 +99,9 @@ func (p *ParticipantImpl) HandlePublishDataTrackRequest(req *livekit.PublishData
 			Logger:              p.params.Logger.WithValues("trackID", dti.Sid),
 			ParticipantID:       p.ID,
 			ParticipantIdentity: p.params.Identity,
+			SubscriberConfig:    p.params.DataTrackSubscriber,
+			Reliable:            isReliableDataTrack(participantAttributes(p), dti.Name),
+			ReliableConfig:      p.params.DataTrackReliable,
 			BytesTrackStats: NewBytesTrackStats(
 				p.params.Country,
 				livekit.TrackID(dti.Sid),
@@ -150,12 +153,43 @@ func (p *ParticipantImpl) onReceivedDataTrackMessage(data []byte, arrivalTime in
 		p.params.Logger.Errorw("could not unmarshal data track message", err)
 		return
 	}
+	if p.maybeHandleDataTrackNack(&packet) {
+		return
+	}
 
 	p.UpDataTrackManager.HandleReceivedDataTrackMessage(data, &packet, arrivalTime)
 
 	p.listener().OnDataTrackMessage(p, data, &packet)
 }
 
+// onReceivedSubscriberDataTrackMessage handles messages sent on the subscriber transport, only NACKs of subscribed data tracks are expected
+func (p *ParticipantImpl) onReceivedSubscriberDataTrackMessage(data []byte) {
+	var packet datatrack.Packet
+	if err := packet.Unmarshal(data); err != nil {
+		p.params.Logger.Errorw("could not unmarshal data track message", err)
+		return
+	}
+	p.maybeHandleDataTrackNack(&packet)
+}
+
+// maybeHandleDataTrackNack forwards NACKs sent on the handle of a subscribed data track, returns false if the packet is not a NACK
+func (p *ParticipantImpl) maybeHandleDataTrackNack(packet *datatrack.Packet) bool {
+	ext, err := packet.GetExtension(datatrack.ExtensionIDNack)
+	if err != nil {
+		return false
+	}
+
+	var extNack datatrack.ExtensionNack
+	if err := extNack.Unmarshal(ext); err != nil {
+		p.subLogger.Debugw("could not unmarshal data track nack", "error", err)
+		return true
+	}
+	if dataDownTrack := p.SubscriptionManager.GetDataDownTrackByHandle(packet.Handle); dataDownTrack != nil {
+		dataDownTrack.HandleNack(&extNack)
+	}
+	return true
+}
+
 func (p *ParticipantImpl) GetNextSubscribedDataTrackHandle() uint16 {
 	p.lock.Lock()
 	defer p.lock.Unlock()
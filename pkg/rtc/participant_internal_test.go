@@ -228,6 +228,55 @@ func TestTrackPublishing(t *testing.T) {
 		// an error response for disallowed source should send a `RequestResponse`.
 		require.Equal(t, 2, sink.WriteMessageCallCount())
 	})
+
+	t.Run("should reject tracks exceeding the publish quota", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.quota = config.ParticipantQuotaConfig{
+			MaxPublishedTracks: 2,
+			MaxPublishBitrate:  1_000_000,
+		}
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:  "cid",
+			Name: "webcam",
+			Type: livekit.TrackType_VIDEO,
+			Layers: []*livekit.VideoLayer{
+				{Quality: livekit.VideoQuality_LOW, Bitrate: 200_000},
+				{Quality: livekit.VideoQuality_HIGH, Bitrate: 600_000},
+			},
+		})
+		require.Equal(t, 1, sink.WriteMessageCallCount())
+		require.NotNil(t, p.pendingTracks["cid"])
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:  "cid2",
+			Name: "screen",
+			Type: livekit.TrackType_VIDEO,
+			Layers: []*livekit.VideoLayer{
+				{Quality: livekit.VideoQuality_HIGH, Bitrate: 500_000},
+			},
+		})
+		require.Equal(t, 2, sink.WriteMessageCallCount())
+		require.Nil(t, p.pendingTracks["cid2"])
+		res := sink.WriteMessageArgsForCall(1).(*livekit.SignalResponse)
+		require.Equal(t, livekit.RequestResponse_LIMIT_EXCEEDED, res.GetRequestResponse().GetReason())
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:  "cid3",
+			Name: "mic",
+			Type: livekit.TrackType_AUDIO,
+		})
+		require.NotNil(t, p.pendingTracks["cid3"])
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:  "cid4",
+			Name: "mic2",
+			Type: livekit.TrackType_AUDIO,
+		})
+		require.Nil(t, p.pendingTracks["cid4"])
+		res = sink.WriteMessageArgsForCall(3).(*livekit.SignalResponse)
+		require.Equal(t, livekit.RequestResponse_LIMIT_EXCEEDED, res.GetRequestResponse().GetReason())
+	})
 }
 
 func TestOutOfOrderUpdates(t *testing.T) {
@@ -278,6 +327,22 @@ func TestCorrectJoinedAt(t *testing.T) {
 	require.True(t, time.Now().Unix()-info.JoinedAt <= 1)
 }
 
+func TestIssuedAttributes(t *testing.T) {
+	p := newParticipantForTest("test")
+	grants := p.ClaimGrants().Clone()
+	grants.Video.SetCanUpdateOwnMetadata(true)
+	p.grants.Store(grants)
+
+	require.NoError(t, p.UpdateMetadata(&livekit.UpdateParticipantMetadata{Attributes: map[string]string{"role": "viewer"}}, true))
+	require.NoError(t, p.UpdateMetadata(&livekit.UpdateParticipantMetadata{Attributes: map[string]string{"role": "host", "color": "red"}}, false))
+	require.Equal(t, map[string]string{"role": "host", "color": "red"}, p.ClaimGrants().Attributes)
+	require.Equal(t, map[string]string{"role": "viewer"}, p.IssuedAttributes())
+
+	// removed through the server API
+	require.NoError(t, p.UpdateMetadata(&livekit.UpdateParticipantMetadata{Attributes: map[string]string{"role": ""}}, true))
+	require.Empty(t, p.IssuedAttributes())
+}
+
 func TestMuteSetting(t *testing.T) {
 	t.Run("can set mute when track is pending", func(t *testing.T) {
 		p := newParticipantForTest("test")
@@ -774,7 +839,7 @@ func newParticipantForTestWithOpts(identity livekit.ParticipantIdentity, opts *p
 	conf, _ := config.NewConfig("", true, nil, nil)
 	// disable mux, it doesn't play too well with unit test
 	conf.RTC.TCPPort = 0
-	rtcConf, err := NewWebRTCConfig(conf)
+	rtcConf, err := NewWebRTCConfig(conf, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,161 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const (
+	// allow at least one maximum sized data message through the rate limiter
+	minDataRateLimiterBurst = 64 * 1024
+
+	publishQuotaSampleInterval = 2 * time.Second
+	// consecutive samples above the bitrate quota before a track is unpublished, to let short bursts through
+	publishQuotaMaxViolations = 3
+)
+
+func layersBitrate(layers []*livekit.VideoLayer) uint64 {
+	var bitrate uint64
+	for _, layer := range layers {
+		bitrate += uint64(layer.GetBitrate())
+	}
+	return bitrate
+}
+
+// checks if publishing the requested track would exceed the participant's quota,
+// returns a reason when the request should be rejected. Layer bitrates are declared by the client and
+// only reject requests which are over quota up front, the bitrate quota is enforced on what is received
+// by publishQuotaWorker
+func (p *ParticipantImpl) checkPublishQuotaLocked(req *livekit.AddTrackRequest) string {
+	quota := p.quota
+	if quota.MaxPublishedTracks <= 0 && quota.MaxPublishBitrate == 0 {
+		return ""
+	}
+	// requests for a track that is already known do not add to the quota
+	if p.pendingTracks[req.Cid] != nil || p.getPublishedTrackBySignalCid(req.Cid) != nil {
+		return ""
+	}
+
+	numTracks := int32(1)
+	bitrate := layersBitrate(req.Layers)
+	for _, track := range p.GetPublishedTracks() {
+		numTracks++
+		bitrate += layersBitrate(track.ToProto().Layers)
+	}
+	for _, pti := range p.pendingTracks {
+		if len(pti.trackInfos) == 0 {
+			continue
+		}
+		numTracks++
+		bitrate += layersBitrate(pti.trackInfos[0].Layers)
+	}
+
+	if quota.MaxPublishedTracks > 0 && numTracks > quota.MaxPublishedTracks {
+		return fmt.Sprintf("exceeds published tracks quota of %d", quota.MaxPublishedTracks)
+	}
+	if quota.MaxPublishBitrate > 0 && bitrate > quota.MaxPublishBitrate {
+		return fmt.Sprintf("exceeds publish bitrate quota of %d bps", quota.MaxPublishBitrate)
+	}
+	return ""
+}
+
+// publishQuotaWorker measures the bitrate received from the participant. When it stays above the quota,
+// the track with the highest bitrate is unpublished and the participant is told why
+func (p *ParticipantImpl) publishQuotaWorker() {
+	ticker := time.NewTicker(publishQuotaSampleInterval)
+	defer ticker.Stop()
+
+	violations := 0
+	for {
+		select {
+		case <-p.disconnected:
+			return
+
+		case <-ticker.C:
+			var total float64
+			var largest types.MediaTrack
+			var largestBitrate float64
+			for _, track := range p.GetPublishedTracks() {
+				mt, ok := track.(*MediaTrack)
+				if !ok {
+					continue
+				}
+				bitrate := mt.PublishBitrate()
+				total += bitrate
+				if largest == nil || bitrate > largestBitrate {
+					largest, largestBitrate = track, bitrate
+				}
+			}
+
+			if total <= float64(p.quota.MaxPublishBitrate) {
+				violations = 0
+				continue
+			}
+			violations++
+			if violations < publishQuotaMaxViolations || largest == nil {
+				continue
+			}
+			violations = 0
+
+			reason := fmt.Sprintf("exceeds publish bitrate quota of %d bps", p.quota.MaxPublishBitrate)
+			p.pubLogger.Warnw(
+				"track publish quota exceeded, unpublishing track", nil,
+				"trackID", largest.ID(),
+				"bitrate", int64(total),
+				"trackBitrate", int64(largestBitrate),
+				"reason", reason,
+			)
+			ti := largest.ToProto()
+			p.removePublishedTrack(largest)
+			_ = p.sendRequestResponse(&livekit.RequestResponse{
+				Reason:  livekit.RequestResponse_LIMIT_EXCEEDED,
+				Message: reason,
+				Request: &livekit.RequestResponse_AddTrack{
+					AddTrack: &livekit.AddTrackRequest{
+						Sid:    ti.Sid,
+						Name:   ti.Name,
+						Type:   ti.Type,
+						Source: ti.Source,
+					},
+				},
+			})
+		}
+	}
+}
+
+// returns false if the data message should be dropped to keep the participant within its data quota
+func (p *ParticipantImpl) allowDataMessage(numBytes int) bool {
+	if p.dataRateLimiter == nil {
+		return true
+	}
+
+	if !p.dataRateLimiter.Allow(numBytes) {
+		if !p.dataThrottled.Swap(true) {
+			p.pubLogger.Infow("throttling data messages", "maxBytesPerSec", p.quota.MaxDataBytesPerSec)
+		}
+		return false
+	}
+
+	if p.dataThrottled.Swap(false) {
+		p.pubLogger.Infow("data messages no longer throttled")
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"strconv"
+
+	"github.com/livekit/protocol/auth"
+)
+
+// RelayOnlyAttribute can be set to "true" in the attributes of a token to connect the participant
+// only through the embedded TURN server, hiding its IP address from the SFU's peers and logs
+const RelayOnlyAttribute = "lk.relay_only"
+
+func RequiresRelayOnly(grants *auth.ClaimGrants) bool {
+	if grants == nil {
+		return false
+	}
+	relayOnly, _ := strconv.ParseBool(grants.Attributes[RelayOnlyAttribute])
+	return relayOnly
+}
+
+// SetRelayOnly makes all participants joining the room connect only through the embedded TURN server
+func (r *Room) SetRelayOnly(relayOnly bool) {
+	r.relayOnly.Store(relayOnly)
+}
+
+func (r *Room) IsRelayOnly() bool {
+	return r.relayOnly.Load()
+}
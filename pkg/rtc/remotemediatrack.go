@@ -0,0 +1,193 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"net"
+	"sync"
+
+	"github.com/pion/webrtc/v4"
+
+	"github.com/livekit/protocol/codecs/mime"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/cascade"
+	"github.com/livekit/livekit-server/pkg/rtc/dynacast"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+var _ types.MediaTrack = (*RemoteMediaTrack)(nil)
+
+type RemoteMediaTrackParams struct {
+	Relay               *cascade.Relay
+	OriginNodeID        livekit.NodeID
+	Origin              net.Addr
+	ParticipantID       func() livekit.ParticipantID
+	ParticipantIdentity livekit.ParticipantIdentity
+	ParticipantVersion  uint32
+	ReceiverConfig      ReceiverConfig
+	SubscriberConfig    DirectionConfig
+	AudioConfig         sfu.AudioConfig
+	VideoConfig         config.VideoConfig
+	Logger              logger.Logger
+}
+
+// RemoteMediaTrack is a track published by a participant connected to another node hosting the room.
+// It is relayed from the origin node once for all subscribers of this node, video is relayed up to
+// the highest quality subscribed to on this node as determined by dynacast, audio while the track is mirrored.
+type RemoteMediaTrack struct {
+	params RemoteMediaTrackParams
+
+	*MediaTrackReceiver
+
+	subscription    *cascade.Subscription
+	dynacastManager dynacast.DynacastManager
+
+	lock     sync.Mutex
+	receiver *cascade.Receiver
+	closed   bool
+}
+
+func NewRemoteMediaTrack(params RemoteMediaTrackParams, ti *livekit.TrackInfo) (*RemoteMediaTrack, error) {
+	t := &RemoteMediaTrack{
+		params: params,
+	}
+
+	t.MediaTrackReceiver = NewMediaTrackReceiver(MediaTrackReceiverParams{
+		MediaTrack:          t,
+		IsRelayed:           true,
+		ParticipantID:       params.ParticipantID,
+		ParticipantIdentity: params.ParticipantIdentity,
+		ParticipantVersion:  params.ParticipantVersion,
+		ReceiverConfig:      params.ReceiverConfig,
+		SubscriberConfig:    params.SubscriberConfig,
+		AudioConfig:         params.AudioConfig,
+		TelemetryListener:   &types.NullParticipantTelemetryListener{},
+		Logger:              params.Logger,
+	}, ti)
+
+	quality := livekit.VideoQuality_HIGH
+	if ti.Type == livekit.TrackType_VIDEO {
+		quality = livekit.VideoQuality_OFF
+		t.dynacastManager = dynacast.NewDynacastManagerVideo(dynacast.DynacastManagerVideoParams{
+			DynacastPauseDelay: params.VideoConfig.DynacastPauseDelay,
+			Listener:           t,
+			Logger:             params.Logger,
+		})
+		t.MediaTrackReceiver.OnSetupReceiver(func(mime mime.MimeType) {
+			t.dynacastManager.AddCodec(mime)
+		})
+		t.MediaTrackReceiver.OnSubscriberMaxQualityChange(
+			func(subscriberID livekit.ParticipantID, mimeType mime.MimeType, layer int32) {
+				t.dynacastManager.NotifySubscriberMaxQuality(
+					subscriberID,
+					mimeType,
+					buffer.GetVideoQualityForSpatialLayer(mimeType, layer, t.MediaTrackReceiver.TrackInfo()),
+				)
+			},
+		)
+	}
+
+	subscription, err := params.Relay.Subscribe(params.OriginNodeID, params.Origin, livekit.TrackID(ti.Sid), quality)
+	if err != nil {
+		if t.dynacastManager != nil {
+			t.dynacastManager.Close()
+		}
+		return nil, err
+	}
+	t.subscription = subscription
+	subscription.OnTrackCodec(t.setupReceiver)
+	return t, nil
+}
+
+func (t *RemoteMediaTrack) setupReceiver(codec webrtc.RTPCodecParameters, headerExtensions []webrtc.RTPHeaderExtensionParameter) {
+	t.lock.Lock()
+	if t.closed || t.receiver != nil {
+		t.lock.Unlock()
+		return
+	}
+	receiver := cascade.NewReceiver(cascade.ReceiverParams{
+		Subscription:               t.subscription,
+		TrackInfo:                  t.MediaTrackReceiver.TrackInfoClone(),
+		StreamID:                   t.Stream(),
+		Codec:                      codec,
+		HeaderExtensions:           headerExtensions,
+		MaxVideoPkts:               t.params.ReceiverConfig.PacketBufferSizeVideo,
+		MaxAudioPkts:               t.params.ReceiverConfig.PacketBufferSizeAudio,
+		StreamTrackerManagerConfig: t.params.VideoConfig.StreamTrackerManager,
+		Logger:                     LoggerWithCodecMime(t.params.Logger, mime.NormalizeMimeType(codec.MimeType)),
+	})
+	t.receiver = receiver
+	t.lock.Unlock()
+
+	t.params.Logger.Debugw("relayed track receiver set up", "codec", codec.MimeType)
+	t.MediaTrackReceiver.SetupReceiver(receiver, 0, "")
+}
+
+func (t *RemoteMediaTrack) OnDynacastSubscribedMaxQualityChange(
+	_ []*livekit.SubscribedCodec,
+	maxSubscribedQualities []types.SubscribedCodecQuality,
+) {
+	for _, q := range maxSubscribedQualities {
+		// only the primary codec is relayed
+		t.subscription.SetMaxQuality(q.Quality)
+
+		if receiver := t.Receiver(q.CodecMime); receiver != nil {
+			receiver.SetMaxExpectedSpatialLayer(
+				buffer.GetSpatialLayerForVideoQuality(q.CodecMime, q.Quality, t.MediaTrackReceiver.TrackInfo()),
+			)
+		}
+	}
+}
+
+func (t *RemoteMediaTrack) OnDynacastSubscribedAudioCodecChange(_ []*livekit.SubscribedAudioCodec) {}
+
+func (t *RemoteMediaTrack) ToProto() *livekit.TrackInfo {
+	return t.MediaTrackReceiver.TrackInfoClone()
+}
+
+func (t *RemoteMediaTrack) Logger() logger.Logger {
+	return t.params.Logger
+}
+
+// OnTrackSubscribed is a no-op, the publisher is notified by its own node
+func (t *RemoteMediaTrack) OnTrackSubscribed() {}
+
+func (t *RemoteMediaTrack) Close(isExpectedToResume bool) {
+	t.lock.Lock()
+	if t.closed {
+		t.lock.Unlock()
+		return
+	}
+	t.closed = true
+	receiver := t.receiver
+	t.lock.Unlock()
+
+	t.MediaTrackReceiver.SetClosing(isExpectedToResume)
+	if t.dynacastManager != nil {
+		t.dynacastManager.Close()
+	}
+	t.MediaTrackReceiver.ClearAllReceivers(isExpectedToResume)
+	t.MediaTrackReceiver.Close(isExpectedToResume)
+
+	t.subscription.Close()
+	if receiver != nil {
+		receiver.Close("remote track closed")
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/cascade"
+	"github.com/livekit/livekit-server/pkg/rtc/datatrack"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
+)
+
+var _ types.Participant = (*RemoteParticipant)(nil)
+
+type RemoteParticipantParams struct {
+	NodeID      livekit.NodeID
+	Origin      net.Addr
+	Relay       *cascade.Relay
+	Config      WebRTCConfig
+	AudioConfig sfu.AudioConfig
+	VideoConfig config.VideoConfig
+	Logger      logger.Logger
+}
+
+// RemoteParticipant is a participant connected to another node hosting the room. It mirrors the state
+// the participant's node relays, its tracks are relayed from that node when subscribed to on this node.
+type RemoteParticipant struct {
+	*UpTrackManager
+
+	params RemoteParticipantParams
+
+	lock       sync.RWMutex
+	info       *livekit.ParticipantInfo
+	permission *livekit.SubscriptionPermission
+	closed     bool
+}
+
+func NewRemoteParticipant(params RemoteParticipantParams, info *livekit.ParticipantInfo) *RemoteParticipant {
+	p := &RemoteParticipant{
+		UpTrackManager: NewUpTrackManager(UpTrackManagerParams{
+			Logger:           params.Logger,
+			VersionGenerator: utils.NewDefaultTimedVersionGenerator(),
+		}),
+		params: params,
+		info:   utils.CloneProto(info),
+	}
+	p.UpdateSubscriptionRules(info.Attributes)
+	return p
+}
+
+func (p *RemoteParticipant) NodeID() livekit.NodeID {
+	return p.params.NodeID
+}
+
+func (p *RemoteParticipant) getInfo() *livekit.ParticipantInfo {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.info
+}
+
+func (p *RemoteParticipant) ID() livekit.ParticipantID {
+	return livekit.ParticipantID(p.getInfo().Sid)
+}
+
+func (p *RemoteParticipant) Identity() livekit.ParticipantIdentity {
+	return livekit.ParticipantIdentity(p.getInfo().Identity)
+}
+
+func (p *RemoteParticipant) State() livekit.ParticipantInfo_State {
+	return p.getInfo().State
+}
+
+func (p *RemoteParticipant) ConnectedAt() time.Time {
+	info := p.getInfo()
+	if info.JoinedAtMs != 0 {
+		return time.UnixMilli(info.JoinedAtMs)
+	}
+	return time.Unix(info.JoinedAt, 0)
+}
+
+func (p *RemoteParticipant) CloseReason() types.ParticipantCloseReason {
+	return types.ParticipantCloseReasonNone
+}
+
+func (p *RemoteParticipant) Kind() livekit.ParticipantInfo_Kind {
+	return p.getInfo().Kind
+}
+
+func (p *RemoteParticipant) KindDetails() []livekit.ParticipantInfo_KindDetail {
+	return p.getInfo().KindDetails
+}
+
+func (p *RemoteParticipant) IsRecorder() bool {
+	info := p.getInfo()
+	return info.Kind == livekit.ParticipantInfo_EGRESS || info.Permission.GetRecorder()
+}
+
+func (p *RemoteParticipant) IsAgent() bool {
+	info := p.getInfo()
+	return info.Kind == livekit.ParticipantInfo_AGENT || info.Permission.GetAgent()
+}
+
+func (p *RemoteParticipant) IsDependent() bool {
+	return p.IsAgent() || p.IsRecorder()
+}
+
+func (p *RemoteParticipant) Hidden() bool {
+	return p.getInfo().Permission.GetHidden()
+}
+
+func (p *RemoteParticipant) IsPublisher() bool {
+	return p.getInfo().IsPublisher
+}
+
+// Attributes returns the attributes of the participant, subscription rules are evaluated against them
+func (p *RemoteParticipant) Attributes() map[string]string {
+	return p.getInfo().Attributes
+}
+
+func (p *RemoteParticipant) GetLogger() logger.Logger {
+	return p.params.Logger
+}
+
+// CanSkipBroadcast is false, updates are only relayed for participants the node broadcasts
+func (p *RemoteParticipant) CanSkipBroadcast() bool {
+	return false
+}
+
+func (p *RemoteParticipant) Version() utils.TimedVersion {
+	return utils.TimedVersion(0)
+}
+
+func (p *RemoteParticipant) ToProto() *livekit.ParticipantInfo {
+	return utils.CloneProto(p.getInfo())
+}
+
+func (p *RemoteParticipant) ToProtoWithVersion() (*livekit.ParticipantInfo, utils.TimedVersion) {
+	return p.ToProto(), p.Version()
+}
+
+func (p *RemoteParticipant) MigrateState() types.MigrateState {
+	return types.MigrateStateComplete
+}
+
+func (p *RemoteParticipant) GetPublishedDataTracks() []types.DataTrack {
+	return nil
+}
+
+func (p *RemoteParticipant) GetPublishedDataTrack(_ uint16) types.DataTrack {
+	return nil
+}
+
+func (p *RemoteParticipant) RemovePublishedDataTrack(_ types.DataTrack) {}
+
+func (p *RemoteParticipant) HandleReceivedDataTrackMessage(_ []byte, _ *datatrack.Packet, _ int64) {}
+
+func (p *RemoteParticipant) GetParticipantListener() types.ParticipantListener {
+	return &types.NullParticipantListener{}
+}
+
+func (p *RemoteParticipant) IsClosed() bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.closed
+}
+
+func (p *RemoteParticipant) IsDisconnected() bool {
+	return p.State() == livekit.ParticipantInfo_DISCONNECTED
+}
+
+// Close stops mirroring the participant, closing relayed tracks
+func (p *RemoteParticipant) Close(_ bool, _ types.ParticipantCloseReason, isExpectedToResume bool) error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.info = utils.CloneProto(p.info)
+	p.info.State = livekit.ParticipantInfo_DISCONNECTED
+	p.lock.Unlock()
+
+	p.UpTrackManager.Close(isExpectedToResume)
+	return nil
+}
+
+type remoteParticipantChanges struct {
+	published         []types.MediaTrack
+	unpublished       []types.MediaTrack
+	infoChanged       bool
+	permissionChanged bool
+}
+
+// Update applies a state of the participant relayed by its node, states are relayed periodically so most updates do not change anything
+func (p *RemoteParticipant) Update(update *cascade.ParticipantUpdate) (changes remoteParticipantChanges) {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return
+	}
+	changes.infoChanged = !proto.Equal(p.info, update.Info)
+	changes.permissionChanged = !proto.Equal(p.permission, update.Permission)
+	p.info = utils.CloneProto(update.Info)
+	p.permission = update.Permission
+	p.lock.Unlock()
+
+	if changes.infoChanged {
+		p.UpdateSubscriptionRules(update.Info.Attributes)
+	}
+
+	current := make(map[livekit.TrackID]struct{}, len(update.Info.Tracks))
+	for _, ti := range update.Info.Tracks {
+		trackID := livekit.TrackID(ti.Sid)
+		current[trackID] = struct{}{}
+
+		if track := p.GetPublishedTrack(trackID); track != nil {
+			if changes.infoChanged {
+				track.UpdateTrackInfo(ti)
+			}
+			continue
+		}
+
+		track, err := NewRemoteMediaTrack(RemoteMediaTrackParams{
+			Relay:               p.params.Relay,
+			OriginNodeID:        p.params.NodeID,
+			Origin:              p.params.Origin,
+			ParticipantID:       p.ID,
+			ParticipantIdentity: livekit.ParticipantIdentity(update.Info.Identity),
+			ParticipantVersion:  update.Info.Version,
+			ReceiverConfig:      p.params.Config.Receiver,
+			SubscriberConfig:    p.params.Config.Subscriber,
+			AudioConfig:         p.params.AudioConfig,
+			VideoConfig:         p.params.VideoConfig,
+			Logger:              LoggerWithTrack(p.params.Logger, trackID, true),
+		}, ti)
+		if err != nil {
+			p.params.Logger.Warnw("could not mirror remote track", err, "trackID", trackID)
+			continue
+		}
+		p.AddPublishedTrack(track)
+		changes.published = append(changes.published, track)
+	}
+
+	for _, track := range p.GetPublishedTracks() {
+		if _, ok := current[track.ID()]; !ok {
+			p.RemovePublishedTrack(track, false)
+			changes.unpublished = append(changes.unpublished, track)
+		}
+	}
+
+	if changes.permissionChanged {
+		permission := update.Permission
+		if permission == nil {
+			permission = &livekit.SubscriptionPermission{AllParticipants: true}
+		}
+		// identities of subscribers are resolved by the participant's node
+		if err := p.UpdateSubscriptionPermission(permission, utils.TimedVersion(0), nil); err != nil {
+			p.params.Logger.Warnw("could not update remote subscription permission", err)
+		}
+	}
+	return
+}
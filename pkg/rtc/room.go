@@ -16,6 +16,7 @@ package rtc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
 	"math"
@@ -24,10 +25,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pion/webrtc/v4"
 	"go.uber.org/atomic"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 
 	protoagent "github.com/livekit/protocol/agent"
+	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/codecs/mime"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
@@ -45,6 +49,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
+	"github.com/livekit/livekit-server/pkg/sfu/impairment"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
 	sutils "github.com/livekit/livekit-server/pkg/utils"
@@ -124,6 +129,9 @@ type Room struct {
 	participants              map[livekit.ParticipantIdentity]types.LocalParticipant
 	participantOpts           map[livekit.ParticipantIdentity]*ParticipantOptions
 	participantRequestSources map[livekit.ParticipantIdentity]routing.MessageSource
+	pendingParticipants       map[livekit.ParticipantIdentity]*pendingParticipant
+	schedule                  *RoomSchedule
+	scheduleTimers            []*time.Timer
 	hasPublished              map[livekit.ParticipantIdentity]bool
 	agentParticpants          map[livekit.ParticipantIdentity]*agentJob
 	bufferFactory             *buffer.FactoryOfBufferFactory
@@ -138,6 +146,7 @@ type Room struct {
 
 	onParticipantChanged func(p types.Participant)
 	onRoomUpdated        func()
+	onDataHistoryUpdated func(entries []*DataHistoryEntry)
 	onClose              func()
 
 	simulationLock                                 sync.Mutex
@@ -145,6 +154,25 @@ type Room struct {
 	disconnectSignalOnResumeNoMessagesParticipants map[livekit.ParticipantIdentity]*disconnectSignalOnResumeNoMessages
 
 	userPacketDeduper *UserPacketDeduper
+	dataTopicACL      *DataTopicACL
+	dataHistory       *DataHistory
+	serverRPC         *serverRPC
+
+	// grants and issued attributes of participants when attribute based policies were last evaluated
+	participantGrants           map[livekit.ParticipantID]*auth.ClaimGrants
+	participantIssuedAttributes map[livekit.ParticipantID]map[string]string
+	autoSubscribePolicy         *AutoSubscribePolicy
+	spatial                     *SpatialSubscriptions
+
+	// set when the room is hosted by several nodes, participants of the other nodes are mirrored
+	cascade            *RoomCascadeParams
+	remoteParticipants map[livekit.ParticipantIdentity]*RemoteParticipant
+
+	autoSubscribeReconcilePending atomic.Bool
+
+	timeline           *sutils.Timeline
+	hadQualityIncident atomic.Bool
+	relayOnly          atomic.Bool
 
 	dataMessageCache *utils.TimeSizeCache[types.DataMessageCache]
 
@@ -272,6 +300,7 @@ func NewRoom(
 		participants:                         make(map[livekit.ParticipantIdentity]types.LocalParticipant),
 		participantOpts:                      make(map[livekit.ParticipantIdentity]*ParticipantOptions),
 		participantRequestSources:            make(map[livekit.ParticipantIdentity]routing.MessageSource),
+		pendingParticipants:                  make(map[livekit.ParticipantIdentity]*pendingParticipant),
 		hasPublished:                         make(map[livekit.ParticipantIdentity]bool),
 		agentParticpants:                     make(map[livekit.ParticipantIdentity]*agentJob),
 		bufferFactory:                        buffer.NewFactoryOfBufferFactory(config.Receiver.PacketBufferSizeVideo, config.Receiver.PacketBufferSizeAudio),
@@ -281,10 +310,24 @@ func NewRoom(
 		disconnectSignalOnResumeParticipants: make(map[livekit.ParticipantIdentity]time.Time),
 		disconnectSignalOnResumeNoMessagesParticipants: make(map[livekit.ParticipantIdentity]*disconnectSignalOnResumeNoMessages),
 		userPacketDeduper: NewUserPacketDeduper(),
+		dataTopicACL:      NewDataTopicACL(roomConfig.DataTopicACLs),
+		dataHistory:       NewDataHistory(roomConfig.DataHistory),
 		dataMessageCache: utils.NewTimeSizeCache[types.DataMessageCache](utils.TimeSizeCacheParams{
 			TTL:     dataMessageCacheTTL,
 			MaxSize: dataMessageCacheSize,
 		}),
+		participantGrants:           make(map[livekit.ParticipantID]*auth.ClaimGrants),
+		participantIssuedAttributes: make(map[livekit.ParticipantID]map[string]string),
+		spatial:                     NewSpatialSubscriptions(roomConfig.Spatial),
+		remoteParticipants:          make(map[livekit.ParticipantIdentity]*RemoteParticipant),
+		timeline:                    sutils.NewTimeline(roomConfig.Timeline.MaxEvents),
+	}
+	// policies are validated with the config, which also rejects them in spatial rooms
+	if policy, err := NewAutoSubscribePolicy(roomConfig.AutoSubscribe); err == nil {
+		r.autoSubscribePolicy = policy
+	} else {
+		r.logger.Warnw("ignoring invalid auto subscribe policy", err)
+		r.autoSubscribePolicy, _ = NewAutoSubscribePolicy(config.AutoSubscribePolicy{})
 	}
 	r.trackManager = NewRoomTrackManager(r.logger)
 	r.localParticipantListener = &localParticipantListener{room: r}
@@ -475,7 +518,7 @@ func (r *Room) Join(
 		"new participant joined",
 		"participantID", participant.ID(),
 		"participant", participant.Identity(),
-		"clientInfo", logger.Proto(participant.GetClientInfo()),
+		"clientInfo", logger.Proto(sutils.ClientInfoWithoutAddress(participant.GetClientInfo())),
 		"options", opts,
 		"numParticipants", len(r.participants),
 	)
@@ -487,8 +530,12 @@ func (r *Room) Join(
 		r.protoProxy.MarkDirty(false)
 	}
 
+	r.recordTimeline(participant, "participant_joined", "participantID", participant.ID(), "kind", participant.Kind())
+
 	r.participants[participant.Identity()] = participant
 	r.participantOpts[participant.Identity()] = opts
+	r.participantGrants[participant.ID()] = participant.ClaimGrants()
+	r.participantIssuedAttributes[participant.ID()] = participant.IssuedAttributes()
 	r.participantRequestSources[participant.Identity()] = requestSource
 
 	if r.onParticipantChanged != nil {
@@ -705,9 +752,24 @@ func (r *Room) onUpdateSubscriptionPermission(participant types.LocalParticipant
 	for _, track := range participant.GetPublishedTracks() {
 		r.trackManager.NotifyTrackChanged(track.ID())
 	}
+	// permissions are relayed to other nodes hosting the room along with the participant
+	if r.onParticipantChanged != nil {
+		r.onParticipantChanged(participant)
+	}
 	return nil
 }
 
+// GetLocalMediaTrack returns a media track published in the room, nil when not found or closing
+func (r *Room) GetLocalMediaTrack(trackID livekit.TrackID) types.LocalMediaTrack {
+	info := r.trackManager.GetTrackInfo(trackID)
+	if info == nil {
+		return nil
+	}
+
+	track, _ := info.Track.(types.LocalMediaTrack)
+	return track
+}
+
 func (r *Room) ResolveMediaTrackForSubscriber(sub types.LocalParticipant, trackID livekit.TrackID) types.MediaResolverResult {
 	res := types.MediaResolverResult{}
 
@@ -723,10 +785,16 @@ func (r *Room) ResolveMediaTrackForSubscriber(sub types.LocalParticipant, trackI
 	res.PublisherIdentity = info.PublisherIdentity
 	res.PublisherID = info.PublisherID
 
-	pub := r.GetParticipantByID(info.PublisherID)
+	var pub types.Participant
+	if lp := r.GetParticipantByID(info.PublisherID); lp != nil {
+		pub = lp
+	} else if rp := r.GetRemoteParticipantByID(info.PublisherID); rp != nil {
+		// connected to another node hosting the room
+		pub = rp
+	}
 	// when publisher is not found, we will assume it doesn't have permission to access
 	if pub != nil {
-		res.HasPermission = IsParticipantExemptFromTrackPermissionsRestrictions(sub) || pub.HasPermission(trackID, sub.Identity())
+		res.HasPermission = hasSubscriptionPermission(pub, trackID, sub)
 	}
 
 	return res
@@ -746,6 +814,11 @@ func (r *Room) ResolveDataTrackForSubscriber(sub types.LocalParticipant, trackID
 	res.TrackRemovedNotifier = r.trackManager.GetOrCreateTrackRemoveNotifier(trackID)
 	res.PublisherIdentity = info.PublisherIdentity
 	res.PublisherID = info.PublisherID
+
+	// when publisher is not found, we will assume it doesn't have permission to access
+	if pub := r.GetParticipantByID(info.PublisherID); pub != nil {
+		res.HasPermission = hasDataTrackSubscriptionPermission(pub, sub)
+	}
 	return res
 }
 
@@ -762,7 +835,7 @@ func (r *Room) IsClosed() bool {
 func (r *Room) CloseIfEmpty() {
 	r.lock.Lock()
 
-	if r.IsClosed() || r.holds.Load() > 0 {
+	if r.IsClosed() || r.holds.Load() > 0 || len(r.pendingParticipants) > 0 {
 		r.lock.Unlock()
 		return
 	}
@@ -773,6 +846,15 @@ func (r *Room) CloseIfEmpty() {
 			return
 		}
 	}
+	if r.cascade != nil && r.cascade.IsPrimary {
+		// the primary node keeps the room while participants are connected to other nodes
+		for _, rp := range r.remoteParticipants {
+			if !rp.IsDependent() {
+				r.lock.Unlock()
+				return
+			}
+		}
+	}
 
 	var timeout uint32
 	var elapsed int64
@@ -805,14 +887,18 @@ func (r *Room) Close(reason types.ParticipantCloseReason) {
 		// fall through
 	}
 	close(r.closed)
+	r.stopScheduleTimersLocked()
 	r.lock.Unlock()
 
 	r.logger.Infow("closing room")
+	r.closeLobby(reason)
 	for _, p := range r.GetParticipants() {
 		_ = p.Close(true, reason, false)
 	}
+	r.closeRemoteParticipants()
 
 	r.protoProxy.Stop()
+	r.maybeDumpTimeline()
 
 	if r.onClose != nil {
 		r.onClose()
@@ -945,7 +1031,30 @@ func (r *Room) OnRoomUpdated(f func()) {
 	r.onRoomUpdated = f
 }
 
+// SimulateImpairment impairs packets received from and sent to the participant, empty configs clear impairments.
+// It is exposed through the debug API and the impairment scenario of SimulateScenario in development mode.
+func (r *Room) SimulateImpairment(participant types.LocalParticipant, upstream impairment.Config, downstream impairment.Config) {
+	if upstream.IsEnabled() || downstream.IsEnabled() {
+		participant.GetLogger().Infow("simulating network impairment", "upstream", upstream, "downstream", downstream)
+	} else {
+		participant.GetLogger().Infow("simulating network impairment end")
+	}
+	participant.GetBufferFactory().SetImpairment(impairment.New(upstream), impairment.New(downstream))
+}
+
 func (r *Room) onSimulateScenario(participant types.LocalParticipant, simulateScenario *livekit.SimulateScenario) error {
+	imp, err := getImpairmentScenario(simulateScenario)
+	if err != nil {
+		return err
+	}
+	if imp != nil {
+		if !r.config.Development {
+			return ErrDevelopmentOnly
+		}
+		r.SimulateImpairment(participant, imp.Upstream, imp.Downstream)
+		return nil
+	}
+
 	switch scenario := simulateScenario.Scenario.(type) {
 	case *livekit.SimulateScenario_SpeakerUpdate:
 		r.logger.Infow("simulating speaker update", "participant", participant.Identity(), "duration", scenario.SpeakerUpdate)
@@ -1008,6 +1117,46 @@ func (r *Room) onSimulateScenario(participant types.LocalParticipant, simulateSc
 	return nil
 }
 
+// impairmentScenarioField is the SimulateScenario field of the impairment scenario which the signal
+// protocol does not define, it holds the JSON encoded impairmentScenario
+const impairmentScenarioField protowire.Number = 100
+
+type impairmentScenario struct {
+	Upstream   impairment.Config `json:"upstream"`
+	Downstream impairment.Config `json:"downstream"`
+}
+
+// getImpairmentScenario returns nil when the message does not carry an impairment scenario
+func getImpairmentScenario(simulateScenario *livekit.SimulateScenario) (*impairmentScenario, error) {
+	b := simulateScenario.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if num == impairmentScenarioField && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			imp := &impairmentScenario{}
+			if err := json.Unmarshal(v, imp); err != nil {
+				return nil, err
+			}
+			return imp, nil
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil, nil
+}
+
 // checks if participant should be auto subscribed to new tracks, assumes lock is already acquired
 func (r *Room) autoSubscribe(participant types.LocalParticipant) bool {
 	opts := r.participantOpts[participant.Identity()]
@@ -1040,7 +1189,7 @@ func (r *Room) createJoinResponseLocked(
 		OtherParticipants: GetOtherParticipantInfo(
 			participant,
 			false, // isMigratingIn
-			toParticipants(slices.Collect(maps.Values(r.participants))),
+			append(toParticipants(slices.Collect(maps.Values(r.participants))), r.remoteParticipantsLocked()...),
 			false, // skipSubscriberBroadcast
 		),
 		IceServers: iceServers,
@@ -1062,11 +1211,13 @@ func (r *Room) createJoinResponseLocked(
 // a ParticipantImpl in the room added a new track, subscribe other participants to it
 func (r *Room) onTrackPublished(participant types.Participant, track types.MediaTrack) {
 	r.trackManager.AddTrack(track, participant.Identity(), participant.ID())
+	r.recordTimeline(participant, "track_published", "trackID", track.ID(), "kind", track.Kind(), "source", track.Source())
 
 	// publish participant update, since track state is changed
 	r.broadcastParticipantState(participant, broadcastOptions{skipSource: true})
 
 	r.lock.RLock()
+	autoSubscribePolicy := r.autoSubscribePolicy
 	// subscribe all existing participants to this MediaTrack
 	for _, existingParticipant := range r.participants {
 		if existingParticipant == participant {
@@ -1077,7 +1228,7 @@ func (r *Room) onTrackPublished(participant types.Participant, track types.Media
 			// not fully joined. don't subscribe yet
 			continue
 		}
-		if !r.autoSubscribe(existingParticipant) {
+		if !r.autoSubscribe(existingParticipant) || autoSubscribePolicy.IsEnabled() || r.isSpatialPair(participant, existingParticipant) {
 			continue
 		}
 
@@ -1092,6 +1243,9 @@ func (r *Room) onTrackPublished(participant types.Participant, track types.Media
 	onParticipantChanged := r.onParticipantChanged
 	r.lock.RUnlock()
 
+	r.scheduleAutoSubscriptionsReconcile()
+	r.subscribeSpatialNeighborsToTrack(participant, track)
+
 	if onParticipantChanged != nil {
 		onParticipantChanged(participant)
 	}
@@ -1149,9 +1303,11 @@ func (r *Room) onTrackUpdated(p types.Participant, _ types.MediaTrack) {
 
 func (r *Room) onTrackUnpublished(p types.Participant, track types.MediaTrack) {
 	r.trackManager.RemoveTrack(track)
+	r.recordTimeline(p, "track_unpublished", "trackID", track.ID())
 	if !p.IsClosed() {
 		r.broadcastParticipantState(p, broadcastOptions{skipSource: true})
 	}
+	r.scheduleAutoSubscriptionsReconcile()
 	if r.onParticipantChanged != nil {
 		r.onParticipantChanged(p)
 	}
@@ -1211,6 +1367,41 @@ func (r *Room) onParticipantUpdate(p types.Participant) {
 	if r.onParticipantChanged != nil {
 		r.onParticipantChanged(p)
 	}
+	if lp, ok := p.(types.LocalParticipant); ok {
+		if prevGrants, prevIssuedAttributes, changed := r.updateParticipantGrants(lp); changed {
+			r.onParticipantGrantsChanged(lp, prevGrants, prevIssuedAttributes)
+		}
+	}
+}
+
+func (r *Room) onParticipantGrantsChanged(lp types.LocalParticipant, prevGrants *auth.ClaimGrants, prevIssuedAttributes map[string]string) {
+	var prevAttributes map[string]string
+	if prevGrants != nil {
+		prevAttributes = prevGrants.Attributes
+	}
+	r.reevaluateSubscriptionRules(lp, prevAttributes, prevIssuedAttributes)
+	if r.getAutoSubscribePolicy().isSelectingAttributes() {
+		r.scheduleAutoSubscriptionsReconcile()
+	}
+}
+
+// records grants of the participant, returns the previous grants and issued attributes and true if they changed since the last update
+func (r *Room) updateParticipantGrants(p types.LocalParticipant) (*auth.ClaimGrants, map[string]string, bool) {
+	grants := p.ClaimGrants()
+	issuedAttributes := p.IssuedAttributes()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	prevGrants := r.participantGrants[p.ID()]
+	if prevGrants == grants {
+		// grants are replaced when attributes change, issued attributes only change with them
+		return nil, nil, false
+	}
+	prevIssuedAttributes := r.participantIssuedAttributes[p.ID()]
+	r.participantGrants[p.ID()] = grants
+	r.participantIssuedAttributes[p.ID()] = issuedAttributes
+	return prevGrants, prevIssuedAttributes, true
 }
 
 func (r *Room) onStateChange(p types.LocalParticipant) {
@@ -1222,6 +1413,8 @@ func (r *Room) onStateChange(p types.LocalParticipant) {
 	r.onStateChangeMu.Lock()
 	defer r.onStateChangeMu.Unlock()
 
+	r.recordTimeline(p, "participant_state", "state", p.State())
+
 	switch p.State() {
 	case livekit.ParticipantInfo_ACTIVE:
 		// subscribe participant to existing published tracks
@@ -1261,6 +1454,13 @@ func (r *Room) onStateChange(p types.LocalParticipant) {
 		)
 		p.GetLogger().Infow("participant active", fields...)
 
+		if r.roomConfig.Lobby.IsEnabled() && r.isLobbyHost(p) {
+			r.lock.RLock()
+			update := r.lobbyUpdateLocked()
+			r.lock.RUnlock()
+			r.sendLobbyUpdateTo([]types.LocalParticipant{p}, update)
+		}
+
 	case livekit.ParticipantInfo_DISCONNECTED:
 		// remove participant from room
 		go r.RemoveParticipant(p.Identity(), p.ID(), p.CloseReason())
@@ -1268,6 +1468,30 @@ func (r *Room) onStateChange(p types.LocalParticipant) {
 }
 
 func (r *Room) onDataMessage(source types.LocalParticipant, kind livekit.DataPacket_Kind, dp *livekit.DataPacket) {
+	if up := dp.GetUser(); up != nil && up.GetTopic() == LobbyTopic {
+		if err := r.HandleLobbyRequest(source, up.Payload); err != nil {
+			r.logger.Infow("could not handle lobby request", "error", err)
+		}
+		return
+	}
+	if up := dp.GetUser(); up != nil && up.GetTopic() == SpatialPositionTopic && source != nil {
+		r.updateSpatialPosition(source, string(up.Payload))
+		return
+	}
+	if source != nil && (dp.GetUser().GetTopic() == ScheduleTopic || dp.GetUser().GetTopic() == DataHistoryTopic) {
+		// schedule notices and data history requests can only originate from the server
+		return
+	}
+	allowed, destFilter := r.dataTopicACL.Check(source, dp)
+	if !allowed {
+		r.logger.Debugw("rejecting data packet not permitted by topic ACL", "participant", source.Identity())
+		return
+	}
+	if source != nil && isServerRpcRequest(dp) {
+		r.handleServerRpc(source, dp.GetRpcRequest())
+		return
+	}
+
 	if kind == livekit.DataPacket_RELIABLE && source != nil && dp.GetSequence() > 0 {
 		data, err := proto.Marshal(dp)
 		if err != nil {
@@ -1281,7 +1505,13 @@ func (r *Room) onDataMessage(source types.LocalParticipant, kind livekit.DataPac
 			DestIdentities: livekit.StringsAsIDs[livekit.ParticipantIdentity](dp.DestinationIdentities),
 		}, len(data))
 	}
-	BroadcastDataPacketForRoom(r, source, kind, dp, r.logger)
+	if !BroadcastDataPacketForRoom(r, source, kind, dp, destFilter, r.logger) {
+		return
+	}
+
+	if kind == livekit.DataPacket_RELIABLE && len(dp.DestinationIdentities) == 0 && len(dp.GetUser().GetDestinationSids()) == 0 {
+		r.dataHistory.Record(dp)
+	}
 }
 
 func (r *Room) onDataMessageUnlabeled(source types.LocalParticipant, data []byte) {
@@ -1387,6 +1617,7 @@ func (r *Room) RemoveParticipant(
 	p, ok := r.participants[identity]
 	if !ok {
 		r.lock.Unlock()
+		r.leaveLobby(identity, pID)
 		return
 	}
 
@@ -1403,9 +1634,12 @@ func (r *Room) RemoveParticipant(
 	delete(r.participantRequestSources, identity)
 	delete(r.hasPublished, identity)
 	delete(r.agentParticpants, identity)
+	delete(r.participantGrants, p.ID())
+	delete(r.participantIssuedAttributes, p.ID())
 	if !p.Hidden() {
 		r.protoRoom.NumParticipants--
 	}
+	r.dataTopicACL.RemoveParticipant(p.ID())
 
 	immediateChange := false
 	if p.IsRecorder() {
@@ -1424,6 +1658,7 @@ func (r *Room) RemoveParticipant(
 	}
 	r.lock.Unlock()
 	r.protoProxy.MarkDirty(immediateChange)
+	r.recordTimeline(p, "participant_left", "participantID", p.ID(), "reason", reason)
 
 	if !p.HasConnected() {
 		fields := append(
@@ -1447,6 +1682,12 @@ func (r *Room) RemoveParticipant(
 		r.trackManager.RemoveDataTrack(t)
 	}
 
+	// other tracks may be selected by the auto subscribe policy in place of the removed ones
+	r.getAutoSubscribePolicy().RemoveParticipant(p.ID())
+	r.scheduleAutoSubscriptionsReconcile()
+	// subscriptions to and from the participant are gone along with it
+	r.removeSpatialParticipant(p.ID())
+
 	if agentJob != nil {
 		agentJob.participantLeft()
 
@@ -1477,17 +1718,26 @@ func (r *Room) subscribeToExistingTracks(p types.LocalParticipant, isSync bool)
 	r.lock.RLock()
 	autoSubscribe := r.autoSubscribe(p)
 	autoSubscribeDataTrack := r.autoSubscribeDataTrack(p)
+	autoSubscribePolicy := r.autoSubscribePolicy
 	r.lock.RUnlock()
 
+	participants := r.GetParticipants()
+	if autoSubscribe && autoSubscribePolicy.IsEnabled() {
+		// subscribe to tracks selected by the policy, including tracks of other nodes
+		autoSubscribe = false
+		publishers := r.autoSubscribePublishers()
+		r.reconcileAutoSubscription(autoSubscribePolicy, p, autoSubscribePolicy.candidates(publishers), publishedTrackIDs(publishers), isSync)
+	}
+
 	var trackIDs []livekit.TrackID
-	for _, op := range r.GetParticipants() {
+	for _, op := range participants {
 		if p.ID() == op.ID() {
 			// don't send to itself
 			continue
 		}
 
 		// subscribe to all
-		if autoSubscribe {
+		if autoSubscribe && !r.isSpatialPair(p, op) {
 			for _, track := range op.GetPublishedTracks() {
 				trackIDs = append(trackIDs, track.ID())
 				p.SubscribeToTrack(track.ID(), isSync)
@@ -1501,9 +1751,20 @@ func (r *Room) subscribeToExistingTracks(p types.LocalParticipant, isSync bool)
 			}
 		}
 	}
+	if autoSubscribe {
+		trackIDs = append(trackIDs, r.subscribeToRemoteTracks(p, isSync)...)
+	}
 	if len(trackIDs) > 0 {
 		p.GetLogger().Debugw("subscribed participant to existing tracks", "trackID", trackIDs)
 	}
+
+	if !r.spatial.IsPositioned(p.ID()) {
+		// initial position, later positions are reported on the position topic
+		r.updateSpatialPosition(p, participantAttributes(p)[SpatialPositionAttribute])
+	}
+	if autoSubscribe {
+		r.subscribeToSpatialNeighbors(p, isSync)
+	}
 }
 
 // broadcast an update about participant p
@@ -1572,6 +1833,14 @@ func (r *Room) updateProto() *livekit.Room {
 			room.NumPublishers++
 		}
 	}
+	for _, rp := range r.GetRemoteParticipants() {
+		if !rp.IsDependent() {
+			room.NumParticipants++
+		}
+		if rp.IsPublisher() {
+			room.NumPublishers++
+		}
+	}
 
 	return room
 }
@@ -1605,6 +1874,7 @@ func (r *Room) changeUpdateWorker() {
 
 		case <-cleanDataMessageTicker.C:
 			r.dataMessageCache.Prune()
+			r.flushDataHistory()
 		}
 	}
 }
@@ -1616,6 +1886,8 @@ func (r *Room) audioUpdateWorker() {
 			return
 		}
 
+		autoSubscribePolicy := r.getAutoSubscribePolicy()
+		reconcileAutoSubscriptions := false
 		activeSpeakers := r.GetActiveSpeakers()
 		changedSpeakers := make([]*livekit.SpeakerInfo, 0, len(activeSpeakers))
 		nextActiveMap := make(map[livekit.ParticipantID]*livekit.SpeakerInfo, len(activeSpeakers))
@@ -1624,6 +1896,9 @@ func (r *Room) audioUpdateWorker() {
 			if prev == nil || prev.Level != speaker.Level {
 				changedSpeakers = append(changedSpeakers, speaker)
 			}
+			if prev == nil && autoSubscribePolicy.OnSpeakingStarted(livekit.ParticipantID(speaker.Sid)) {
+				reconcileAutoSubscriptions = true
+			}
 			nextActiveMap[livekit.ParticipantID(speaker.Sid)] = speaker
 		}
 
@@ -1641,6 +1916,9 @@ func (r *Room) audioUpdateWorker() {
 		if len(changedSpeakers) > 0 {
 			r.sendSpeakerChanges(changedSpeakers)
 		}
+		if reconcileAutoSubscriptions {
+			r.scheduleAutoSubscriptionsReconcile()
+		}
 
 		lastActiveMap = nextActiveMap
 
@@ -1667,6 +1945,7 @@ func (r *Room) connectionQualityWorker() {
 
 			if q := p.GetConnectionQuality(); q != nil {
 				nowConnectionInfos[p.ID()] = q
+				r.recordConnectionQuality(p, prevConnectionInfos[p.ID()], q)
 			}
 		}
 
@@ -2066,19 +2345,22 @@ func (l participantTelemetryListener) OnTrackStats(key telemetry.StatsKey, stat
 
 // ------------------------------------------------------------
 
+// BroadcastDataPacketForRoom forwards a data packet to its destinations in the room,
+// returns false if the packet was dropped
 func BroadcastDataPacketForRoom(
 	r types.Room,
 	source types.LocalParticipant,
 	kind livekit.DataPacket_Kind,
 	dp *livekit.DataPacket,
+	destFilter func(types.LocalParticipant) bool,
 	logger logger.Logger,
-) {
+) bool {
 	dp.Kind = kind // backward compatibility
 	dest := dp.GetUser().GetDestinationSids()
 	if u := dp.GetUser(); u != nil {
 		if r.IsDataMessageUserPacketDuplicate(u) {
 			logger.Infow("dropping duplicate data message", "nonce", u.Nonce)
-			return
+			return false
 		}
 		if len(dp.DestinationIdentities) == 0 {
 			dp.DestinationIdentities = u.DestinationIdentities
@@ -2113,12 +2395,15 @@ func BroadcastDataPacketForRoom(
 				continue
 			}
 		}
+		if destFilter != nil && !destFilter(op) {
+			continue
+		}
 		if dpData == nil {
 			var err error
 			dpData, err = proto.Marshal(dp)
 			if err != nil {
 				logger.Errorw("failed to marshal data packet", err)
-				return
+				return false
 			}
 		}
 		destParticipants = append(destParticipants, op)
@@ -2127,6 +2412,7 @@ func BroadcastDataPacketForRoom(
 	utils.ParallelExec(destParticipants, dataForwardLoadBalanceThreshold, 1, func(op types.LocalParticipant) {
 		op.SendDataMessage(kind, dpData, livekit.ParticipantID(dp.GetParticipantSid()), dp.GetSequence())
 	})
+	return true
 }
 
 func BroadcastDataMessageForRoom(r types.Room, source types.LocalParticipant, data []byte, logger logger.Logger) {
@@ -2350,8 +2636,14 @@ func connectionDetailsFields(infos []*types.ICEConnectionInfo) []any {
 			if c.Trickle {
 				cStr += "[trickle]"
 			}
+			if info.RelayOnly && c.Candidate.Typ != webrtc.ICECandidateTypeRelay {
+				// addresses of the participant are not logged in relay only mode
+				cStr += " " + fmt.Sprintf("%s %s [masked]", c.Candidate.Protocol.String(), c.Candidate.Typ.String())
+				candidates = append(candidates, cStr)
+				continue
+			}
 			cStr += " " + fmt.Sprintf("%s %s %s:%d", c.Candidate.Protocol.String(), c.Candidate.Typ.String(), MaybeTruncateIP(c.Candidate.Address), c.Candidate.Port)
-			if relatedAddress := c.Candidate.RelatedAddress; relatedAddress != "" {
+			if relatedAddress := c.Candidate.RelatedAddress; relatedAddress != "" && !info.RelayOnly {
 				relatedAddr := MaybeTruncateIP(relatedAddress)
 				if relatedAddr != "" {
 					cStr += " " + fmt.Sprintf(" related %s:%d", relatedAddr, c.Candidate.RelatedPort)
@@ -2367,6 +2659,9 @@ func connectionDetailsFields(infos []*types.ICEConnectionInfo) []any {
 		}
 	}
 	fields = append(fields, "connectionType", connectionType)
+	if slices.ContainsFunc(infos, func(info *types.ICEConnectionInfo) bool { return info.RelayOnly }) {
+		fields = append(fields, "relayOnly", true)
+	}
 	return fields
 }
 
@@ -15,13 +15,18 @@
 package rtc
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/auth/authfakes"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/utils"
@@ -53,6 +58,7 @@ const (
 func init() {
 	config.InitLoggerFromConfig(&config.DefaultConfig.Logging)
 	roomUpdateInterval = defaultDelay
+	autoSubscribeReconcileInterval = defaultDelay
 }
 
 var iceServersForRoom = []*livekit.ICEServer{{Urls: []string{"stun:stun.l.google.com:19302"}}}
@@ -167,6 +173,190 @@ func TestRoomJoin(t *testing.T) {
 	})
 }
 
+func TestRoomSchedule(t *testing.T) {
+	t.Run("participants are warned before the room closes", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+		now := time.Now()
+		rm.SetSchedule(&RoomSchedule{
+			Room:        string(rm.Name()),
+			NotBefore:   now.Add(-time.Hour),
+			NotAfter:    now.Add(20 * time.Millisecond),
+			GracePeriod: 100 * time.Millisecond,
+		})
+		require.False(t, rm.IsClosed())
+
+		testutils.WithTimeout(t, func() string {
+			for _, p := range rm.GetParticipants() {
+				if p.(*typesfakes.FakeLocalParticipant).SendDataMessageCallCount() == 0 {
+					return "schedule notice not sent"
+				}
+			}
+			return ""
+		})
+		require.False(t, rm.IsClosed())
+
+		testutils.WithTimeout(t, func() string {
+			if !rm.IsClosed() {
+				return "room not closed"
+			}
+			return ""
+		})
+	})
+
+	t.Run("replaced schedule does not close the room", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		now := time.Now()
+		rm.SetSchedule(&RoomSchedule{
+			Room:     string(rm.Name()),
+			NotAfter: now.Add(20 * time.Millisecond),
+		})
+		rm.SetSchedule(&RoomSchedule{
+			Room:     string(rm.Name()),
+			NotAfter: now.Add(time.Hour),
+		})
+
+		time.Sleep(100 * time.Millisecond)
+		require.False(t, rm.IsClosed())
+		rm.Close(types.ParticipantCloseReasonNone)
+	})
+
+	t.Run("cleared schedule does not close the room", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		rm.SetSchedule(&RoomSchedule{
+			Room:     string(rm.Name()),
+			NotAfter: time.Now().Add(20 * time.Millisecond),
+		})
+		rm.ClearSchedule()
+		require.Nil(t, rm.GetSchedule())
+
+		time.Sleep(100 * time.Millisecond)
+		require.False(t, rm.IsClosed())
+		rm.Close(types.ParticipantCloseReasonNone)
+	})
+}
+
+func TestRoomScheduleCheckRejoin(t *testing.T) {
+	now := time.Now()
+	schedule := &RoomSchedule{
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(-time.Minute),
+		GracePeriod: 5 * time.Minute,
+	}
+	require.ErrorIs(t, schedule.CheckJoin(now), ErrRoomScheduleEnded)
+	require.NoError(t, schedule.CheckRejoin(now))
+	require.False(t, schedule.Expired(now))
+
+	require.ErrorIs(t, schedule.CheckRejoin(now.Add(5*time.Minute)), ErrRoomScheduleEnded)
+	require.True(t, schedule.Expired(now.Add(5*time.Minute)))
+}
+
+func TestRoomLobby(t *testing.T) {
+	newLobbyRoom := func(t *testing.T, timeout time.Duration) *Room {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		rm.roomConfig.Lobby = config.LobbyConfig{
+			Attribute: "lobby",
+			Timeout:   timeout,
+		}
+		return rm
+	}
+	newLobbyParticipant := func(rm *Room, identity livekit.ParticipantIdentity) *typesfakes.FakeLocalParticipant {
+		p := NewMockParticipant(identity, types.CurrentProtocol, false, false, rm.LocalParticipantListener())
+		p.ClaimGrantsReturns(&auth.ClaimGrants{
+			Attributes: map[string]string{"lobby": "true"},
+		})
+		return p
+	}
+
+	t.Run("participant waits until admitted", func(t *testing.T) {
+		rm := newLobbyRoom(t, time.Minute)
+		p := newLobbyParticipant(rm, "guest")
+		require.True(t, rm.RequiresAdmission(p))
+
+		admitted := false
+		require.NoError(t, rm.JoinLobby(p, nil, nil, iceServersForRoom, func() {
+			admitted = true
+		}))
+		require.True(t, rm.IsParticipantPending(p.Identity()))
+		require.Nil(t, rm.GetParticipant(p.Identity()))
+		// waiting participants get a join response so that their signal connection is established
+		require.Equal(t, 1, p.SendJoinResponseCallCount())
+		res := p.SendJoinResponseArgsForCall(0)
+		require.Equal(t, "true", res.Participant.Attributes[LobbyWaitingAttribute])
+		require.Empty(t, res.OtherParticipants)
+		require.Empty(t, res.IceServers)
+
+		require.NoError(t, rm.AdmitParticipant(p.Identity()))
+		require.True(t, admitted)
+		require.False(t, rm.IsParticipantPending(p.Identity()))
+		require.Equal(t, p, rm.GetParticipant(p.Identity()))
+		require.Equal(t, 2, p.SendJoinResponseCallCount())
+		res = p.SendJoinResponseArgsForCall(1)
+		require.Empty(t, res.Participant.Attributes[LobbyWaitingAttribute])
+		require.NotEmpty(t, res.OtherParticipants)
+	})
+
+	t.Run("rejected participant is closed", func(t *testing.T) {
+		rm := newLobbyRoom(t, time.Minute)
+		p := newLobbyParticipant(rm, "guest")
+		require.NoError(t, rm.JoinLobby(p, nil, nil, iceServersForRoom, nil))
+
+		require.NoError(t, rm.HandleLobbyRequest(nil, []byte(`{"action":"reject","identity":"guest"}`)))
+		require.False(t, rm.IsParticipantPending(p.Identity()))
+		require.Equal(t, 1, p.CloseCallCount())
+		_, reason, _ := p.CloseArgsForCall(0)
+		require.Equal(t, types.ParticipantCloseReasonUserRejected, reason)
+		require.ErrorIs(t, rm.AdmitParticipant(p.Identity()), ErrNotInLobby)
+	})
+
+	t.Run("participant leaves the lobby", func(t *testing.T) {
+		rm := newLobbyRoom(t, time.Minute)
+		p := newLobbyParticipant(rm, "guest")
+		require.NoError(t, rm.JoinLobby(p, nil, nil, iceServersForRoom, nil))
+
+		rm.RemoveParticipant(p.Identity(), "PA_other", types.ParticipantCloseReasonSignalSourceClose)
+		require.True(t, rm.IsParticipantPending(p.Identity()))
+		rm.RemoveParticipant(p.Identity(), p.ID(), types.ParticipantCloseReasonSignalSourceClose)
+		require.False(t, rm.IsParticipantPending(p.Identity()))
+	})
+
+	t.Run("participant times out in the lobby", func(t *testing.T) {
+		rm := newLobbyRoom(t, defaultDelay)
+		p := newLobbyParticipant(rm, "guest")
+		require.NoError(t, rm.JoinLobby(p, nil, nil, iceServersForRoom, nil))
+
+		testutils.WithTimeout(t, func() string {
+			if rm.IsParticipantPending(p.Identity()) || p.CloseCallCount() == 0 {
+				return "participant still waiting in lobby"
+			}
+			return ""
+		})
+		_, reason, _ := p.CloseArgsForCall(0)
+		require.Equal(t, types.ParticipantCloseReasonLobbyTimeout, reason)
+	})
+
+	t.Run("only hosts can manage the lobby and receive updates", func(t *testing.T) {
+		rm := newLobbyRoom(t, time.Minute)
+		host := rm.GetParticipants()[0].(*typesfakes.FakeLocalParticipant)
+		host.ClaimGrantsReturns(&auth.ClaimGrants{
+			Video: &auth.VideoGrant{RoomAdmin: true},
+		})
+		other := NewMockParticipant("other", types.CurrentProtocol, false, false, rm.LocalParticipantListener())
+		other.ClaimGrantsReturns(&auth.ClaimGrants{})
+
+		p := newLobbyParticipant(rm, "guest")
+		require.NoError(t, rm.JoinLobby(p, nil, nil, iceServersForRoom, nil))
+		require.Equal(t, 1, host.SendDataMessageCallCount())
+
+		req := []byte(`{"action":"admit","identity":"guest"}`)
+		require.ErrorIs(t, rm.HandleLobbyRequest(other, req), ErrNotLobbyHost)
+		require.True(t, rm.IsParticipantPending(p.Identity()))
+
+		require.NoError(t, rm.HandleLobbyRequest(host, req))
+		require.NotNil(t, rm.GetParticipant(p.Identity()))
+		require.Equal(t, 2, host.SendDataMessageCallCount())
+	})
+}
+
 // various state changes to participant and that others are receiving update
 func TestParticipantUpdate(t *testing.T) {
 	tests := []struct {
@@ -446,6 +636,187 @@ func TestNewTrack(t *testing.T) {
 	})
 }
 
+func TestSubscriptionRules(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 3})
+	defer rm.Close(types.ParticipantCloseReasonNone)
+	lpl := rm.LocalParticipantListener()
+
+	participants := rm.GetParticipants()
+	pub := participants[0].(*typesfakes.FakeLocalParticipant)
+	pubTracks := NewUpTrackManager(defaultUptrackManagerParams)
+	pub.HasPermissionCalls(pubTracks.HasPermission)
+	pub.SubscriptionRulesAllowCalls(pubTracks.SubscriptionRulesAllow)
+	setPubAttributes := func(attributes map[string]string) {
+		pubTracks.UpdateSubscriptionRules(attributes)
+		pub.ClaimGrantsReturns(&auth.ClaimGrants{Attributes: attributes})
+	}
+	setPubAttributes(map[string]string{
+		"team":                     "a",
+		SubscriptionRulesAttribute: `[{"attribute":"team","sameAsPublisher":true},{"attribute":"role","in":["host","moderator"]}]`,
+	})
+	setIssuedAttributes := func(p *typesfakes.FakeLocalParticipant, attributes map[string]string) {
+		p.IssuedAttributesReturns(attributes)
+		p.ClaimGrantsReturns(&auth.ClaimGrants{Attributes: attributes})
+	}
+	teammate := participants[1].(*typesfakes.FakeLocalParticipant)
+	setIssuedAttributes(teammate, map[string]string{"team": "a"})
+	other := participants[2].(*typesfakes.FakeLocalParticipant)
+	setIssuedAttributes(other, map[string]string{"team": "b"})
+
+	track := NewMockTrack(livekit.TrackType_VIDEO, "webcam")
+	lpl.OnTrackPublished(pub, track)
+	pub.GetPublishedTracksReturns([]types.MediaTrack{track})
+
+	require.True(t, rm.ResolveMediaTrackForSubscriber(teammate, track.ID()).HasPermission)
+	require.False(t, rm.ResolveMediaTrackForSubscriber(other, track.ID()).HasPermission)
+
+	// identity based permissions still apply
+	pubTracks.UpdateSubscriptionPermission(&livekit.SubscriptionPermission{}, utils.TimedVersion(0), nil)
+	require.False(t, rm.ResolveMediaTrackForSubscriber(teammate, track.ID()).HasPermission)
+	pubTracks.UpdateSubscriptionPermission(&livekit.SubscriptionPermission{AllParticipants: true}, utils.TimedVersion(0), nil)
+
+	setIssuedAttributes(other, map[string]string{"team": "b", "role": "moderator"})
+	require.True(t, rm.ResolveMediaTrackForSubscriber(other, track.ID()).HasPermission)
+
+	// attributes set by the client do not allow subscribing
+	other.IssuedAttributesReturns(map[string]string{"team": "b"})
+	require.False(t, rm.ResolveMediaTrackForSubscriber(other, track.ID()).HasPermission)
+	setIssuedAttributes(other, map[string]string{"team": "b", "role": "moderator"})
+
+	// subscription is revoked when the subscriber changes team
+	track.GetAllSubscribersReturns([]livekit.ParticipantID{teammate.ID(), other.ID()})
+	track.IsSubscriberCalls(func(id livekit.ParticipantID) bool {
+		return id == teammate.ID() || id == other.ID()
+	})
+	setIssuedAttributes(teammate, map[string]string{"team": "b"})
+	lpl.OnParticipantUpdate(teammate)
+	require.Equal(t, 1, track.RemoveSubscriberCallCount())
+	subID, _ := track.RemoveSubscriberArgsForCall(0)
+	require.Equal(t, teammate.ID(), subID)
+
+	// updates not changing attributes are ignored
+	lpl.OnParticipantUpdate(teammate)
+	require.Equal(t, 1, track.RemoveSubscriberCallCount())
+
+	// changes of attributes not referenced by the rules are ignored
+	setIssuedAttributes(teammate, map[string]string{"team": "b", "color": "red"})
+	lpl.OnParticipantUpdate(teammate)
+	require.Equal(t, 1, track.RemoveSubscriberCallCount())
+
+	// subscriptions are revoked when the publisher changes its rules
+	setPubAttributes(map[string]string{
+		"team":                     "a",
+		SubscriptionRulesAttribute: `[{"attribute":"role","in":["host"]}]`,
+	})
+	lpl.OnParticipantUpdate(pub)
+	require.Equal(t, 3, track.RemoveSubscriberCallCount())
+
+	// data tracks are subject to the rules
+	dataTrack := &typesfakes.FakeDataTrack{}
+	dataTrack.IDReturns("DTR_data")
+	dataTrack.PublisherIDReturns(pub.ID())
+	dataTrack.PublisherIdentityReturns(pub.Identity())
+	lpl.OnDataTrackPublished(pub, dataTrack)
+	setIssuedAttributes(other, map[string]string{"role": "host"})
+	require.True(t, rm.ResolveDataTrackForSubscriber(other, dataTrack.ID()).HasPermission)
+	require.False(t, rm.ResolveDataTrackForSubscriber(teammate, dataTrack.ID()).HasPermission)
+
+	// invalid rules do not allow anyone
+	setPubAttributes(map[string]string{SubscriptionRulesAttribute: "invalid"})
+	require.False(t, rm.ResolveMediaTrackForSubscriber(other, track.ID()).HasPermission)
+	require.False(t, rm.ResolveDataTrackForSubscriber(other, dataTrack.ID()).HasPermission)
+}
+
+func TestAutoSubscribePolicy(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 4})
+	defer rm.Close(types.ParticipantCloseReasonNone)
+	lpl := rm.LocalParticipantListener()
+
+	participants := rm.GetParticipants()
+	slices.SortFunc(participants, func(a, b types.LocalParticipant) int {
+		return strings.Compare(string(a.Identity()), string(b.Identity()))
+	})
+	fakes := make([]*typesfakes.FakeLocalParticipant, 0, len(participants))
+	for i, p := range participants {
+		fp := p.(*typesfakes.FakeLocalParticipant)
+		fp.ToProtoReturns(&livekit.ParticipantInfo{Sid: string(fp.ID()), Identity: string(fp.Identity()), JoinedAt: int64(i + 1)})
+		fakes = append(fakes, fp)
+	}
+
+	newTrack := func(source livekit.TrackSource) *typesfakes.FakeMediaTrack {
+		track := NewMockTrack(livekit.TrackType_VIDEO, source.String())
+		track.SourceReturns(source)
+		return track
+	}
+	cam0 := newTrack(livekit.TrackSource_CAMERA)
+	screen0 := newTrack(livekit.TrackSource_SCREEN_SHARE)
+	fakes[0].GetPublishedTracksReturns([]types.MediaTrack{cam0, screen0})
+	cam1 := newTrack(livekit.TrackSource_CAMERA)
+	fakes[1].GetPublishedTracksReturns([]types.MediaTrack{cam1})
+
+	require.Error(t, rm.SetAutoSubscribePolicy(config.AutoSubscribePolicy{Sources: []string{"hologram"}}))
+	require.NoError(t, rm.SetAutoSubscribePolicy(config.AutoSubscribePolicy{
+		PublisherAttributes: map[string]string{"role": "stage"},
+		Sources:             []string{"camera"},
+		MaxVideoTracks:      1,
+		VideoSelection:      config.AutoSubscribeVideoSelectionActiveSpeaker,
+	}))
+
+	subscribed := func(p *typesfakes.FakeLocalParticipant) []livekit.TrackID {
+		var trackIDs []livekit.TrackID
+		for i := 0; i < p.SubscribeToTrackCallCount(); i++ {
+			trackID, _ := p.SubscribeToTrackArgsForCall(i)
+			trackIDs = append(trackIDs, trackID)
+		}
+		return trackIDs
+	}
+	unsubscribed := func(p *typesfakes.FakeLocalParticipant) []livekit.TrackID {
+		var trackIDs []livekit.TrackID
+		for i := 0; i < p.UnsubscribeFromTrackCallCount(); i++ {
+			trackIDs = append(trackIDs, p.UnsubscribeFromTrackArgsForCall(i))
+		}
+		return trackIDs
+	}
+
+	// only stage participants are subscribed to
+	require.Empty(t, subscribed(fakes[2]))
+
+	// room changes are applied together after the reconcile interval
+	requireTracks := func(expected []livekit.TrackID, tracks func(p *typesfakes.FakeLocalParticipant) []livekit.TrackID, p *typesfakes.FakeLocalParticipant) {
+		require.Eventually(t, func() bool {
+			return slices.Equal(expected, tracks(p))
+		}, time.Second, defaultDelay)
+	}
+
+	for _, p := range fakes[:2] {
+		p.ClaimGrantsReturns(&auth.ClaimGrants{Attributes: map[string]string{"role": "stage"}})
+		lpl.OnParticipantUpdate(p)
+	}
+	// screen share is not selected, video is limited to the publisher that joined first
+	requireTracks([]livekit.TrackID{cam0.ID()}, subscribed, fakes[2])
+	requireTracks([]livekit.TrackID{cam0.ID()}, subscribed, fakes[3])
+	requireTracks([]livekit.TrackID{cam1.ID()}, subscribed, fakes[0])
+	requireTracks([]livekit.TrackID{cam0.ID()}, subscribed, fakes[1])
+
+	// video follows the latest speaker
+	require.True(t, rm.getAutoSubscribePolicy().OnSpeakingStarted(fakes[1].ID()))
+	rm.reconcileAutoSubscriptions(false)
+	require.Equal(t, []livekit.TrackID{cam0.ID(), cam1.ID()}, subscribed(fakes[2]))
+	require.Equal(t, []livekit.TrackID{cam0.ID()}, unsubscribed(fakes[2]))
+
+	// leaving the stage
+	fakes[1].ClaimGrantsReturns(&auth.ClaimGrants{Attributes: map[string]string{"role": "audience"}})
+	lpl.OnParticipantUpdate(fakes[1])
+	requireTracks([]livekit.TrackID{cam0.ID(), cam1.ID(), cam0.ID()}, subscribed, fakes[2])
+	requireTracks([]livekit.TrackID{cam0.ID(), cam1.ID()}, unsubscribed, fakes[2])
+
+	// tracks that are not published anymore are not unsubscribed from
+	fakes[0].GetPublishedTracksReturns(nil)
+	lpl.OnTrackUnpublished(fakes[0], cam0)
+	time.Sleep(3 * defaultDelay)
+	require.Equal(t, []livekit.TrackID{cam0.ID(), cam1.ID()}, unsubscribed(fakes[2]))
+}
+
 func TestActiveSpeakers(t *testing.T) {
 	t.Parallel()
 	getActiveSpeakerUpdates := func(p *typesfakes.FakeLocalParticipant) [][]*livekit.SpeakerInfo {
@@ -738,6 +1109,148 @@ func TestDataChannel(t *testing.T) {
 			require.Zero(t, fp.SendDataMessageCallCount())
 		}
 	})
+
+	t.Run("topic ACLs", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 3})
+		defer rm.Close(types.ParticipantCloseReasonNone)
+		rm.dataTopicACL = NewDataTopicACL([]config.DataTopicACL{
+			{
+				Topic:   "moderation.*",
+				Publish: config.DataTopicPermission{Attributes: map[string]string{"role": "host"}},
+			},
+			{
+				RPCMethod: "admin",
+				Subscribe: config.DataTopicPermission{Attributes: map[string]string{"role": "host"}},
+			},
+		})
+
+		lpl := rm.LocalParticipantListener()
+		participants := rm.GetParticipants()
+		host := participants[0].(*typesfakes.FakeLocalParticipant)
+		host.ClaimGrantsReturns(&auth.ClaimGrants{Attributes: map[string]string{"role": "host"}})
+		guest := participants[1].(*typesfakes.FakeLocalParticipant)
+		guest.ClaimGrantsReturns(&auth.ClaimGrants{})
+		other := participants[2].(*typesfakes.FakeLocalParticipant)
+		other.ClaimGrantsReturns(&auth.ClaimGrants{})
+
+		userPacket := func(topic string) *livekit.DataPacket {
+			return &livekit.DataPacket{
+				Kind:  livekit.DataPacket_RELIABLE,
+				Value: &livekit.DataPacket_User{User: &livekit.UserPacket{Topic: &topic}},
+			}
+		}
+
+		// guests cannot publish on restricted topics
+		lpl.OnDataMessage(guest, livekit.DataPacket_RELIABLE, userPacket("moderation.mute"))
+		require.Zero(t, host.SendDataMessageCallCount())
+		require.Zero(t, other.SendDataMessageCallCount())
+
+		lpl.OnDataMessage(guest, livekit.DataPacket_RELIABLE, userPacket("chat"))
+		require.Equal(t, 1, host.SendDataMessageCallCount())
+		require.Equal(t, 1, other.SendDataMessageCallCount())
+
+		lpl.OnDataMessage(host, livekit.DataPacket_RELIABLE, userPacket("moderation.mute"))
+		require.Equal(t, 1, guest.SendDataMessageCallCount())
+		require.Equal(t, 2, other.SendDataMessageCallCount())
+
+		// chunks of a rejected stream are dropped
+		streamPackets := []*livekit.DataPacket{
+			{Value: &livekit.DataPacket_StreamHeader{StreamHeader: &livekit.DataStream_Header{StreamId: "s1", Topic: "moderation.log"}}},
+			{Value: &livekit.DataPacket_StreamChunk{StreamChunk: &livekit.DataStream_Chunk{StreamId: "s1"}}},
+			{Value: &livekit.DataPacket_StreamTrailer{StreamTrailer: &livekit.DataStream_Trailer{StreamId: "s1"}}},
+		}
+		for _, dp := range streamPackets {
+			lpl.OnDataMessage(guest, livekit.DataPacket_RELIABLE, dp)
+		}
+		require.Equal(t, 1, host.SendDataMessageCallCount())
+		require.Empty(t, rm.dataTopicACL.streams)
+
+		// only hosts receive restricted RPC requests
+		lpl.OnDataMessage(guest, livekit.DataPacket_RELIABLE, &livekit.DataPacket{
+			Value: &livekit.DataPacket_RpcRequest{RpcRequest: &livekit.RpcRequest{Id: "r1", Method: "admin"}},
+		})
+		require.Equal(t, 2, host.SendDataMessageCallCount())
+		require.Equal(t, 2, other.SendDataMessageCallCount())
+
+		// encrypted packets cannot be inspected and are rejected without an encrypted rule
+		encryptedPacket := &livekit.DataPacket{
+			Value: &livekit.DataPacket_EncryptedPacket{EncryptedPacket: &livekit.EncryptedPacket{}},
+		}
+		lpl.OnDataMessage(host, livekit.DataPacket_RELIABLE, encryptedPacket)
+		require.Equal(t, 1, guest.SendDataMessageCallCount())
+		require.Equal(t, 2, other.SendDataMessageCallCount())
+
+		rm.dataTopicACL = NewDataTopicACL([]config.DataTopicACL{
+			{Topic: "moderation.*"},
+			{
+				Encrypted: true,
+				Publish:   config.DataTopicPermission{Attributes: map[string]string{"role": "host"}},
+			},
+		})
+		lpl.OnDataMessage(guest, livekit.DataPacket_RELIABLE, encryptedPacket)
+		require.Equal(t, 2, host.SendDataMessageCallCount())
+		lpl.OnDataMessage(host, livekit.DataPacket_RELIABLE, encryptedPacket)
+		require.Equal(t, 2, guest.SendDataMessageCallCount())
+		require.Equal(t, 3, other.SendDataMessageCallCount())
+	})
+
+	t.Run("server rpc pending limit", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+		defer rm.Close(types.ParticipantCloseReasonNone)
+		handler := &blockingServerRPCHandler{release: make(chan struct{})}
+		rm.SetServerRPCHandler(handler, config.ParticipantRPCConfig{MaxPendingPerParticipant: 1})
+
+		lpl := rm.LocalParticipantListener()
+		p := rm.GetParticipants()[0].(*typesfakes.FakeLocalParticipant)
+		rpcRequest := func(id string) *livekit.DataPacket {
+			return &livekit.DataPacket{
+				DestinationIdentities: []string{ServerRPCIdentity},
+				Value:                 &livekit.DataPacket_RpcRequest{RpcRequest: &livekit.RpcRequest{Id: id, Method: "m"}},
+			}
+		}
+		response := func(i int) *livekit.RpcResponse {
+			_, data, _, _ := p.SendDataMessageArgsForCall(i)
+			var dp livekit.DataPacket
+			require.NoError(t, proto.Unmarshal(data, &dp))
+			return dp.GetRpcResponse()
+		}
+
+		lpl.OnDataMessage(p, livekit.DataPacket_RELIABLE, rpcRequest("r1"))
+		lpl.OnDataMessage(p, livekit.DataPacket_RELIABLE, rpcRequest("r2"))
+		// both acknowledged, the second one is rejected while the first is pending
+		require.Equal(t, 3, p.SendDataMessageCallCount())
+		res := response(2)
+		require.Equal(t, "r2", res.GetRequestId())
+		require.Equal(t, uint32(utils.DataChannelRpcApplicationError), res.GetError().GetCode())
+
+		close(handler.release)
+		require.Eventually(t, func() bool {
+			return p.SendDataMessageCallCount() == 4
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, "ok", response(3).GetPayload())
+
+		lpl.OnDataMessage(p, livekit.DataPacket_RELIABLE, rpcRequest("r3"))
+		require.Eventually(t, func() bool {
+			return p.SendDataMessageCallCount() == 6
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, "ok", response(5).GetPayload())
+		require.Equal(t, 2, int(handler.calls.Load()))
+	})
+}
+
+type blockingServerRPCHandler struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (h *blockingServerRPCHandler) HandleRpc(ctx context.Context, _ *livekit.Room, _ types.LocalParticipant, _ *livekit.RpcRequest) (string, *utils.DataChannelRpcError) {
+	h.calls.Inc()
+	select {
+	case <-h.release:
+		return "ok", nil
+	case <-ctx.Done():
+		return "", utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcResponseTimeout, "")
+	}
 }
 
 func TestHiddenParticipants(t *testing.T) {
@@ -0,0 +1,286 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"net"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/cascade"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
+)
+
+type RoomCascadeParams struct {
+	Relay *cascade.Relay
+	// set on the node the room is assigned to, other nodes host the room as edges
+	IsPrimary   bool
+	VideoConfig config.VideoConfig
+}
+
+// SetCascade makes the room mirror participants of other nodes hosting it, it needs to be called before participants join
+func (r *Room) SetCascade(params RoomCascadeParams) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.cascade = &params
+}
+
+func (r *Room) getCascade() *RoomCascadeParams {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.cascade
+}
+
+// IsCascadeEdge returns true when the room is assigned to another node, its state is kept by that node
+func (r *Room) IsCascadeEdge() bool {
+	c := r.getCascade()
+	return c != nil && !c.IsPrimary
+}
+
+// GetRemoteParticipantByID returns a participant connected to another node hosting the room
+func (r *Room) GetRemoteParticipantByID(participantID livekit.ParticipantID) *RemoteParticipant {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, rp := range r.remoteParticipants {
+		if rp.ID() == participantID {
+			return rp
+		}
+	}
+	return nil
+}
+
+func (r *Room) GetRemoteParticipants() []*RemoteParticipant {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	remoteParticipants := make([]*RemoteParticipant, 0, len(r.remoteParticipants))
+	for _, rp := range r.remoteParticipants {
+		remoteParticipants = append(remoteParticipants, rp)
+	}
+	return remoteParticipants
+}
+
+func (r *Room) remoteParticipantsLocked() []types.Participant {
+	participants := make([]types.Participant, 0, len(r.remoteParticipants))
+	for _, rp := range r.remoteParticipants {
+		participants = append(participants, rp)
+	}
+	return participants
+}
+
+// CascadeParticipants returns the participants connected to this node, as relayed to other nodes hosting the room
+func (r *Room) CascadeParticipants() []*cascade.ParticipantUpdate {
+	participants := r.GetParticipants()
+	updates := make([]*cascade.ParticipantUpdate, 0, len(participants))
+	for _, p := range participants {
+		if !p.IsDisconnected() {
+			updates = append(updates, r.CascadeParticipantUpdate(p))
+		}
+	}
+	return updates
+}
+
+// CascadeParticipantUpdate returns the state of a participant connected to this node as relayed to other nodes hosting the room
+func (r *Room) CascadeParticipantUpdate(p types.LocalParticipant) *cascade.ParticipantUpdate {
+	permission, _ := p.SubscriptionPermission()
+	if permission != nil {
+		// other nodes cannot resolve participants by ID
+		permission = utils.CloneProto(permission)
+		for _, tp := range permission.TrackPermissions {
+			if tp.ParticipantIdentity != "" || tp.ParticipantSid == "" {
+				continue
+			}
+			if sub := r.GetParticipantByID(livekit.ParticipantID(tp.ParticipantSid)); sub != nil {
+				tp.ParticipantIdentity = string(sub.Identity())
+			} else if rp := r.GetRemoteParticipantByID(livekit.ParticipantID(tp.ParticipantSid)); rp != nil {
+				tp.ParticipantIdentity = string(rp.Identity())
+			}
+		}
+	}
+	return &cascade.ParticipantUpdate{
+		Info:       p.ToProto(),
+		Permission: permission,
+	}
+}
+
+// UpdateRemoteParticipant applies the state of a participant connected to another node hosting the room
+func (r *Room) UpdateRemoteParticipant(nodeID livekit.NodeID, origin net.Addr, update *cascade.ParticipantUpdate) {
+	identity := livekit.ParticipantIdentity(update.Info.Identity)
+
+	r.lock.Lock()
+	if r.IsClosed() || r.cascade == nil {
+		r.lock.Unlock()
+		return
+	}
+	if _, ok := r.participants[identity]; ok {
+		// the participant is connected to this node, e.g. after moving from the other node
+		r.lock.Unlock()
+		return
+	}
+
+	rp := r.remoteParticipants[identity]
+	var replaced *RemoteParticipant
+	if rp != nil && rp.ID() != livekit.ParticipantID(update.Info.Sid) {
+		if CompareParticipant(rp.ToProto(), update.Info) > 0 {
+			// update of an older session
+			r.lock.Unlock()
+			return
+		}
+		replaced = rp
+		rp = nil
+		delete(r.remoteParticipants, identity)
+	}
+
+	if update.Info.State == livekit.ParticipantInfo_DISCONNECTED {
+		if rp != nil {
+			delete(r.remoteParticipants, identity)
+		}
+		r.lock.Unlock()
+
+		if replaced != nil {
+			r.closeRemoteParticipant(replaced)
+		}
+		if rp != nil {
+			r.closeRemoteParticipant(rp)
+		}
+		return
+	}
+
+	created := rp == nil
+	if created {
+		var audioConfig sfu.AudioConfig
+		if r.audioConfig != nil {
+			audioConfig = *r.audioConfig
+		}
+		rp = NewRemoteParticipant(RemoteParticipantParams{
+			NodeID:      nodeID,
+			Origin:      origin,
+			Relay:       r.cascade.Relay,
+			Config:      r.config,
+			AudioConfig: audioConfig,
+			VideoConfig: r.cascade.VideoConfig,
+			Logger:      LoggerWithParticipant(r.logger, identity, livekit.ParticipantID(update.Info.Sid), true),
+		}, update.Info)
+		r.remoteParticipants[identity] = rp
+	}
+	r.lock.Unlock()
+
+	if replaced != nil {
+		r.closeRemoteParticipant(replaced)
+	}
+
+	prevAttributes := rp.Attributes()
+	changes := rp.Update(update)
+	for _, track := range changes.unpublished {
+		r.trackManager.RemoveTrack(track)
+	}
+	for _, track := range changes.published {
+		r.onRemoteTrackPublished(rp, track)
+	}
+	if changes.permissionChanged {
+		for _, track := range rp.GetPublishedTracks() {
+			r.trackManager.NotifyTrackChanged(track.ID())
+		}
+	}
+	if !created {
+		r.reevaluatePublisherSubscriptionRules(rp, changedAttributes(prevAttributes, rp.Attributes()))
+	}
+	if len(changes.unpublished) != 0 || changes.infoChanged {
+		// tracks selected by the auto subscribe policy may have changed
+		r.scheduleAutoSubscriptionsReconcile()
+	}
+
+	if created || changes.infoChanged {
+		if created {
+			rp.GetLogger().Infow("remote participant joined", "nodeID", nodeID)
+		}
+		r.broadcastParticipantState(rp, broadcastOptions{skipSource: true, immediate: created})
+		r.protoProxy.MarkDirty(created)
+	}
+}
+
+func (r *Room) onRemoteTrackPublished(rp *RemoteParticipant, track types.MediaTrack) {
+	r.trackManager.AddTrack(track, rp.Identity(), rp.ID())
+
+	r.lock.RLock()
+	if r.autoSubscribePolicy.IsEnabled() {
+		r.lock.RUnlock()
+		r.scheduleAutoSubscriptionsReconcile()
+		return
+	}
+	for _, p := range r.participants {
+		if p.State() != livekit.ParticipantInfo_ACTIVE || !r.autoSubscribe(p) || r.isSpatialPair(rp, p) {
+			continue
+		}
+
+		p.GetLogger().Debugw(
+			"subscribing to new remote track",
+			"publisher", rp.Identity(),
+			"publisherID", rp.ID(),
+			"trackID", track.ID(),
+		)
+		p.SubscribeToTrack(track.ID(), false)
+	}
+	r.lock.RUnlock()
+}
+
+// subscribeToRemoteTracks subscribes a participant joining this node to tracks of participants of other nodes
+func (r *Room) subscribeToRemoteTracks(p types.LocalParticipant, isSync bool) []livekit.TrackID {
+	var trackIDs []livekit.TrackID
+	for _, rp := range r.GetRemoteParticipants() {
+		if r.isSpatialPair(rp, p) {
+			continue
+		}
+		for _, track := range rp.GetPublishedTracks() {
+			trackIDs = append(trackIDs, track.ID())
+			p.SubscribeToTrack(track.ID(), isSync)
+		}
+	}
+	return trackIDs
+}
+
+func (r *Room) closeRemoteParticipant(rp *RemoteParticipant) {
+	tracks := rp.GetPublishedTracks()
+	_ = rp.Close(false, types.ParticipantCloseReasonNone, false)
+	for _, track := range tracks {
+		r.trackManager.RemoveTrack(track)
+	}
+	rp.GetLogger().Infow("remote participant left", "nodeID", rp.NodeID())
+
+	r.broadcastParticipantState(rp, broadcastOptions{skipSource: true, immediate: true})
+	r.protoProxy.MarkDirty(true)
+	r.scheduleAutoSubscriptionsReconcile()
+}
+
+func (r *Room) closeRemoteParticipants() {
+	r.lock.Lock()
+	remoteParticipants := r.remoteParticipants
+	r.remoteParticipants = make(map[livekit.ParticipantIdentity]*RemoteParticipant)
+	r.lock.Unlock()
+
+	for _, rp := range remoteParticipants {
+		tracks := rp.GetPublishedTracks()
+		_ = rp.Close(false, types.ParticipantCloseReasonRoomClosed, false)
+		for _, track := range tracks {
+			r.trackManager.RemoveTrack(track)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/cascade"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+func TestRoomCascade(t *testing.T) {
+	origin := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 7890}
+	remoteInfo := func(sid string, joinedAt int64, state livekit.ParticipantInfo_State) *livekit.ParticipantInfo {
+		return &livekit.ParticipantInfo{Sid: sid, Identity: "remote", JoinedAt: joinedAt, State: state}
+	}
+
+	t.Run("mirrors participants of other nodes", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		defer rm.Close(types.ParticipantCloseReasonNone)
+
+		// ignored before the room is cascaded
+		rm.UpdateRemoteParticipant("ND_edge", origin, &cascade.ParticipantUpdate{Info: remoteInfo("PA_a", 1, livekit.ParticipantInfo_ACTIVE)})
+		require.Empty(t, rm.GetRemoteParticipants())
+
+		rm.SetCascade(RoomCascadeParams{IsPrimary: true})
+		rm.UpdateRemoteParticipant("ND_edge", origin, &cascade.ParticipantUpdate{Info: remoteInfo("PA_a", 1, livekit.ParticipantInfo_ACTIVE)})
+		rp := rm.GetRemoteParticipantByID("PA_a")
+		require.NotNil(t, rp)
+		require.Equal(t, livekit.NodeID("ND_edge"), rp.NodeID())
+
+		// participants connected to this node are not mirrored
+		local := rm.GetParticipants()[0]
+		rm.UpdateRemoteParticipant("ND_edge", origin, &cascade.ParticipantUpdate{Info: &livekit.ParticipantInfo{
+			Sid:      "PA_b",
+			Identity: string(local.Identity()),
+			State:    livekit.ParticipantInfo_ACTIVE,
+		}})
+		require.Len(t, rm.GetRemoteParticipants(), 1)
+
+		// an update of an older session is ignored, a newer one replaces the participant
+		rm.UpdateRemoteParticipant("ND_edge", origin, &cascade.ParticipantUpdate{Info: remoteInfo("PA_0", 0, livekit.ParticipantInfo_ACTIVE)})
+		require.NotNil(t, rm.GetRemoteParticipantByID("PA_a"))
+		rm.UpdateRemoteParticipant("ND_other", origin, &cascade.ParticipantUpdate{Info: remoteInfo("PA_c", 2, livekit.ParticipantInfo_ACTIVE)})
+		require.Nil(t, rm.GetRemoteParticipantByID("PA_a"))
+		require.True(t, rp.IsClosed())
+		require.Equal(t, livekit.NodeID("ND_other"), rm.GetRemoteParticipantByID("PA_c").NodeID())
+
+		rm.UpdateRemoteParticipant("ND_other", origin, &cascade.ParticipantUpdate{Info: remoteInfo("PA_c", 2, livekit.ParticipantInfo_DISCONNECTED)})
+		require.Empty(t, rm.GetRemoteParticipants())
+	})
+
+	t.Run("primary keeps the room while remote participants are connected", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 0})
+		isClosed := false
+		rm.OnClose(func() {
+			isClosed = true
+		})
+		rm.lock.Lock()
+		rm.protoRoom.EmptyTimeout = 0
+		rm.lock.Unlock()
+		rm.SetCascade(RoomCascadeParams{IsPrimary: true})
+
+		rm.UpdateRemoteParticipant("ND_edge", origin, &cascade.ParticipantUpdate{Info: remoteInfo("PA_a", 1, livekit.ParticipantInfo_ACTIVE)})
+		rm.CloseIfEmpty()
+		require.False(t, isClosed)
+
+		rm.UpdateRemoteParticipant("ND_edge", origin, &cascade.ParticipantUpdate{Info: remoteInfo("PA_a", 1, livekit.ParticipantInfo_DISCONNECTED)})
+		rm.CloseIfEmpty()
+		require.True(t, isClosed)
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
+)
+
+// Timeline returns the event timeline of the room, nil when disabled
+func (r *Room) Timeline() *sutils.Timeline {
+	return r.timeline
+}
+
+func (r *Room) recordTimeline(p types.Participant, eventType string, keysAndValues ...any) {
+	if r.timeline == nil {
+		return
+	}
+	r.timeline.WithParticipant(string(p.Identity())).Record(eventType, keysAndValues...)
+}
+
+// recordConnectionQuality records quality transitions and flags the room when a participant
+// had a poor or lost connection, so that the timeline is kept when the room closes
+func (r *Room) recordConnectionQuality(p types.Participant, prev *livekit.ConnectionQualityInfo, now *livekit.ConnectionQualityInfo) {
+	if prev != nil && prev.Quality == now.Quality {
+		return
+	}
+	r.recordTimeline(p, "connection_quality", "quality", now.Quality, "score", now.Score)
+
+	if now.Quality == livekit.ConnectionQuality_POOR || now.Quality == livekit.ConnectionQuality_LOST {
+		r.hadQualityIncident.Store(true)
+	}
+}
+
+func (r *Room) maybeDumpTimeline() {
+	dumpDir := r.roomConfig.Timeline.DumpDir
+	if r.timeline == nil || dumpDir == "" || !r.hadQualityIncident.Load() {
+		return
+	}
+
+	data, err := json.MarshalIndent(r.timeline.Events(), "", "  ")
+	if err != nil {
+		r.logger.Warnw("could not marshal room timeline", err)
+		return
+	}
+
+	roomName := strings.NewReplacer("/", "_", "\\", "_").Replace(string(r.Name()))
+	path := filepath.Join(dumpDir, fmt.Sprintf("%s_%s_%s.json", roomName, r.ID(), time.Now().UTC().Format("20060102T150405Z")))
+	if err := os.MkdirAll(dumpDir, 0o755); err != nil {
+		r.logger.Warnw("could not create room timeline directory", err, "dir", dumpDir)
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		r.logger.Warnw("could not write room timeline", err, "path", path)
+		return
+	}
+	r.logger.Infow("dumped room timeline after connection quality incident", "path", path)
+}
@@ -0,0 +1,224 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/psrpc"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/telemetry"
+)
+
+// ScheduleTopic is the reserved data packet topic used to notify participants about the room schedule
+const ScheduleTopic = "lk.schedule"
+
+const (
+	ScheduleEventEnding = "ending"
+)
+
+var (
+	ErrRoomNotYetOpen       = psrpc.NewErrorf(psrpc.FailedPrecondition, "room is not open yet")
+	ErrRoomScheduleEnded    = psrpc.NewErrorf(psrpc.FailedPrecondition, "room schedule has ended")
+	ErrInvalidRoomSchedule  = psrpc.NewErrorf(psrpc.InvalidArgument, "invalid room schedule")
+	ErrRoomScheduleNotFound = psrpc.NewErrorf(psrpc.NotFound, "room schedule does not exist")
+)
+
+// RoomSchedule limits the time window during which participants can join a room.
+// When NotAfter is reached, participants are warned and the room is closed once GracePeriod elapses.
+type RoomSchedule struct {
+	Room        string        `json:"room"`
+	NotBefore   time.Time     `json:"notBefore"`
+	NotAfter    time.Time     `json:"notAfter"`
+	GracePeriod time.Duration `json:"gracePeriod,omitempty"`
+}
+
+func (s *RoomSchedule) Validate() error {
+	if s.Room == "" || s.NotAfter.IsZero() || s.GracePeriod < 0 {
+		return ErrInvalidRoomSchedule
+	}
+	if !s.NotBefore.IsZero() && !s.NotBefore.Before(s.NotAfter) {
+		return ErrInvalidRoomSchedule
+	}
+	return nil
+}
+
+// CheckJoin returns an error if participants are not allowed to join at the given time
+func (s *RoomSchedule) CheckJoin(at time.Time) error {
+	if at.Before(s.NotBefore) {
+		return ErrRoomNotYetOpen
+	}
+	if !at.Before(s.NotAfter) {
+		return ErrRoomScheduleEnded
+	}
+	return nil
+}
+
+// CheckRejoin returns an error if participants reconnecting to the room are not allowed to at the given time,
+// sessions started within the window can resume until the room closes
+func (s *RoomSchedule) CheckRejoin(at time.Time) error {
+	if at.Before(s.NotBefore) {
+		return ErrRoomNotYetOpen
+	}
+	if !at.Before(s.ClosesAt()) {
+		return ErrRoomScheduleEnded
+	}
+	return nil
+}
+
+// Expired returns true once the room has been closed, the schedule is not needed anymore
+func (s *RoomSchedule) Expired(at time.Time) bool {
+	return !at.Before(s.ClosesAt())
+}
+
+// ClosesAt returns the time at which the room is closed
+func (s *RoomSchedule) ClosesAt() time.Time {
+	return s.NotAfter.Add(s.GracePeriod)
+}
+
+type ScheduleNotice struct {
+	Event    string `json:"event"`
+	NotAfter int64  `json:"notAfter"`
+	ClosesAt int64  `json:"closesAt"`
+}
+
+// SetSchedule applies a schedule to the room, arming timers for each of its transitions.
+// It replaces the schedule of a running room when the schedule is updated
+func (r *Room) SetSchedule(schedule *RoomSchedule) {
+	r.lock.Lock()
+	if r.IsClosed() {
+		r.lock.Unlock()
+		return
+	}
+	r.stopScheduleTimersLocked()
+
+	now := time.Now()
+	// an update does not start the schedule again
+	wasStarted := r.schedule != nil && !r.schedule.NotBefore.After(now)
+	r.schedule = schedule
+
+	started := false
+	if schedule.NotBefore.After(now) {
+		r.scheduleTimers = append(r.scheduleTimers, time.AfterFunc(schedule.NotBefore.Sub(now), func() {
+			r.onScheduleTransition(schedule, telemetry.EventRoomScheduleStarted)
+		}))
+	} else if schedule.NotAfter.After(now) {
+		started = !wasStarted
+	}
+	r.scheduleTimers = append(r.scheduleTimers, time.AfterFunc(max(schedule.NotAfter.Sub(now), 0), func() {
+		r.onScheduleEnding(schedule)
+	}))
+	r.lock.Unlock()
+
+	r.logger.Infow(
+		"room schedule set",
+		"notBefore", schedule.NotBefore,
+		"notAfter", schedule.NotAfter,
+		"gracePeriod", schedule.GracePeriod,
+	)
+	if started {
+		r.onScheduleTransition(schedule, telemetry.EventRoomScheduleStarted)
+	}
+}
+
+// ClearSchedule removes the schedule of the room, which then stays open until it is empty
+func (r *Room) ClearSchedule() {
+	r.lock.Lock()
+	if r.schedule == nil {
+		r.lock.Unlock()
+		return
+	}
+	r.stopScheduleTimersLocked()
+	r.schedule = nil
+	r.lock.Unlock()
+
+	r.logger.Infow("room schedule cleared")
+}
+
+func (r *Room) GetSchedule() *RoomSchedule {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.schedule
+}
+
+func (r *Room) stopScheduleTimersLocked() {
+	for _, t := range r.scheduleTimers {
+		t.Stop()
+	}
+	r.scheduleTimers = nil
+}
+
+// returns false if the schedule has been replaced or the room has closed
+func (r *Room) onScheduleTransition(schedule *RoomSchedule, event string) bool {
+	r.lock.RLock()
+	current := r.schedule
+	r.lock.RUnlock()
+	if current != schedule || r.IsClosed() {
+		return false
+	}
+
+	r.logger.Infow("room schedule transition", "event", event)
+	r.telemetry.NotifyRoomEvent(context.Background(), event, r.ToProto(), nil)
+	return true
+}
+
+func (r *Room) onScheduleEnding(schedule *RoomSchedule) {
+	if !r.onScheduleTransition(schedule, telemetry.EventRoomScheduleEnding) {
+		return
+	}
+
+	// closing is armed only after the warning to keep transitions in order
+	r.lock.Lock()
+	if r.schedule == schedule {
+		r.scheduleTimers = append(r.scheduleTimers, time.AfterFunc(max(time.Until(schedule.ClosesAt()), 0), func() {
+			r.onScheduleEnded(schedule)
+		}))
+	}
+	r.lock.Unlock()
+
+	payload, err := json.Marshal(&ScheduleNotice{
+		Event:    ScheduleEventEnding,
+		NotAfter: schedule.NotAfter.UnixMilli(),
+		ClosesAt: schedule.ClosesAt().UnixMilli(),
+	})
+	if err != nil {
+		r.logger.Errorw("failed to marshal schedule notice", err)
+		return
+	}
+	r.SendDataPacket(&livekit.DataPacket{
+		Kind: livekit.DataPacket_RELIABLE,
+		Value: &livekit.DataPacket_User{
+			User: &livekit.UserPacket{
+				Payload: payload,
+				Topic:   proto.String(ScheduleTopic),
+			},
+		},
+	}, livekit.DataPacket_RELIABLE)
+}
+
+func (r *Room) onScheduleEnded(schedule *RoomSchedule) {
+	if !r.onScheduleTransition(schedule, telemetry.EventRoomScheduleEnded) {
+		return
+	}
+
+	r.Close(types.ParticipantCloseReasonRoomClosed)
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// ServerRPCIdentity is the reserved destination identity of RPC requests handled by the server
+const ServerRPCIdentity = "lk.server"
+
+// ServerRPCHandler handles RPC requests sent by participants to the server
+type ServerRPCHandler interface {
+	HandleRpc(ctx context.Context, room *livekit.Room, caller types.LocalParticipant, req *livekit.RpcRequest) (string, *utils.DataChannelRpcError)
+}
+
+// serverRPC tracks requests pending with the handler, bounding how many a participant or the room can have
+type serverRPC struct {
+	handler                  ServerRPCHandler
+	maxPendingPerParticipant int
+	maxPendingPerRoom        int
+
+	lock         sync.Mutex
+	pending      map[livekit.ParticipantID]int
+	pendingTotal int
+}
+
+func (r *Room) SetServerRPCHandler(h ServerRPCHandler, conf config.ParticipantRPCConfig) {
+	r.serverRPC = &serverRPC{
+		handler:                  h,
+		maxPendingPerParticipant: conf.MaxPendingPerParticipant,
+		maxPendingPerRoom:        conf.MaxPendingPerRoom,
+		pending:                  make(map[livekit.ParticipantID]int),
+	}
+}
+
+func (s *serverRPC) acquire(participantID livekit.ParticipantID) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.maxPendingPerParticipant > 0 && s.pending[participantID] >= s.maxPendingPerParticipant {
+		return false
+	}
+	if s.maxPendingPerRoom > 0 && s.pendingTotal >= s.maxPendingPerRoom {
+		return false
+	}
+	s.pending[participantID]++
+	s.pendingTotal++
+	return true
+}
+
+func (s *serverRPC) release(participantID livekit.ParticipantID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.pending[participantID] <= 1 {
+		delete(s.pending, participantID)
+	} else {
+		s.pending[participantID]--
+	}
+	s.pendingTotal--
+}
+
+func isServerRpcRequest(dp *livekit.DataPacket) bool {
+	return dp.GetRpcRequest() != nil && len(dp.DestinationIdentities) == 1 && dp.DestinationIdentities[0] == ServerRPCIdentity
+}
+
+// handleServerRpc acknowledges the request and responds once the handler returns,
+// following the same ack/response exchange as RPC between participants.
+// Requests beyond the pending limits are answered with an error right away.
+func (r *Room) handleServerRpc(source types.LocalParticipant, req *livekit.RpcRequest) {
+	r.sendServerRpcPacket(source, &livekit.DataPacket{
+		Value: &livekit.DataPacket_RpcAck{
+			RpcAck: &livekit.RpcAck{RequestId: req.Id},
+		},
+	})
+
+	if r.serverRPC == nil {
+		r.sendServerRpcResponse(source, req.Id, "", utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcUnsupportedMethod, ""))
+		return
+	}
+	if !r.serverRPC.acquire(source.ID()) {
+		r.logger.Infow("rejecting server rpc request, too many pending requests", "participant", source.Identity(), "method", req.Method)
+		r.sendServerRpcResponse(source, req.Id, "", &utils.DataChannelRpcError{
+			Code:    utils.DataChannelRpcApplicationError,
+			Message: "Too many pending requests",
+		})
+		return
+	}
+
+	timeout := utils.DataChannelRpcDefaultResponseTimeout
+	if req.ResponseTimeoutMs > 0 {
+		timeout = time.Duration(req.ResponseTimeoutMs) * time.Millisecond
+	}
+	roomInfo := r.ToProto()
+	go func() {
+		defer r.serverRPC.release(source.ID())
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		payload, rpcErr := r.serverRPC.handler.HandleRpc(ctx, roomInfo, source, req)
+		if rpcErr == nil && ctx.Err() != nil {
+			rpcErr = utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcResponseTimeout, "")
+		}
+		r.sendServerRpcResponse(source, req.Id, payload, rpcErr)
+	}()
+}
+
+func (r *Room) sendServerRpcResponse(source types.LocalParticipant, requestID string, payload string, rpcErr *utils.DataChannelRpcError) {
+	res := &livekit.RpcResponse{RequestId: requestID}
+	if rpcErr != nil {
+		res.Value = &livekit.RpcResponse_Error{
+			Error: &livekit.RpcError{
+				Code:    uint32(rpcErr.Code),
+				Message: rpcErr.Message,
+				Data:    rpcErr.Data,
+			},
+		}
+	} else {
+		res.Value = &livekit.RpcResponse_Payload{Payload: payload}
+	}
+	r.sendServerRpcPacket(source, &livekit.DataPacket{
+		Value: &livekit.DataPacket_RpcResponse{RpcResponse: res},
+	})
+}
+
+func (r *Room) sendServerRpcPacket(source types.LocalParticipant, dp *livekit.DataPacket) {
+	dp.Kind = livekit.DataPacket_RELIABLE
+	dp.ParticipantIdentity = ServerRPCIdentity
+	data, err := proto.Marshal(dp)
+	if err != nil {
+		r.logger.Errorw("could not marshal rpc packet", err, "participant", source.Identity())
+		return
+	}
+	if err := source.SendDataMessage(livekit.DataPacket_RELIABLE, data, "", 0); err != nil {
+		r.logger.Debugw("could not send rpc packet", "error", err, "participant", source.Identity())
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const (
+	// SpatialPositionTopic is the reserved data topic participants of spatial rooms report their position on,
+	// as a user packet with "x,y" as payload. Positions only update the spatial index of the room,
+	// they are not forwarded to other participants.
+	SpatialPositionTopic = "lk.position"
+
+	// SpatialPositionAttribute is the reserved participant attribute holding the initial position
+	// of a participant in spatial rooms, as "x,y". It is read when the participant joins.
+	SpatialPositionAttribute = "lk.position"
+
+	// coordinates are bounded to keep grid cells within range
+	maxSpatialCoordinate = 1e9
+)
+
+type spatialCell struct {
+	x, y int64
+}
+
+type spatialRange struct {
+	audio bool
+	video bool
+}
+
+func (s spatialRange) includes(kind livekit.TrackType) bool {
+	switch kind {
+	case livekit.TrackType_AUDIO:
+		return s.audio
+	case livekit.TrackType_VIDEO:
+		return s.video
+	default:
+		return false
+	}
+}
+
+type spatialParticipant struct {
+	participant types.LocalParticipant
+	x, y        float64
+	cell        spatialCell
+	// participants in range, ranges are symmetric
+	inRange map[livekit.ParticipantID]spatialRange
+}
+
+type spatialNeighbor struct {
+	participant types.LocalParticipant
+	inRange     spatialRange
+}
+
+// spatialChange is a change of the range between two participants,
+// they are expected to be subscribed to each other's tracks of the kinds in range
+type spatialChange struct {
+	a, b     types.LocalParticipant
+	from, to spatialRange
+}
+
+// SpatialSubscriptions tracks participants in range of each other.
+//
+// Participants are indexed in a grid with cells as large as the largest range,
+// so that only participants in adjacent cells need to be considered when one moves.
+type SpatialSubscriptions struct {
+	conf     config.SpatialConfig
+	cellSize float64
+
+	lock             sync.Mutex
+	participants     map[livekit.ParticipantID]*spatialParticipant
+	cells            map[spatialCell]map[livekit.ParticipantID]*spatialParticipant
+	participantLocks map[livekit.ParticipantID]*spatialParticipantLock
+}
+
+type spatialParticipantLock struct {
+	sync.Mutex
+	refs int
+}
+
+func NewSpatialSubscriptions(conf config.SpatialConfig) *SpatialSubscriptions {
+	return &SpatialSubscriptions{
+		conf:             conf,
+		cellSize:         max(conf.AudioRadius, conf.VideoRadius) + max(conf.Hysteresis, 0),
+		participants:     make(map[livekit.ParticipantID]*spatialParticipant),
+		cells:            make(map[spatialCell]map[livekit.ParticipantID]*spatialParticipant),
+		participantLocks: make(map[livekit.ParticipantID]*spatialParticipantLock),
+	}
+}
+
+func (s *SpatialSubscriptions) IsEnabled() bool {
+	return s.conf.IsEnabled()
+}
+
+// Move places the participant at the position, returning changes of ranges with other participants
+func (s *SpatialSubscriptions) Move(p types.LocalParticipant, x, y float64) []spatialChange {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sp := s.participants[p.ID()]
+	if sp == nil {
+		sp = &spatialParticipant{
+			participant: p,
+			inRange:     make(map[livekit.ParticipantID]spatialRange),
+		}
+		s.participants[p.ID()] = sp
+	} else {
+		s.removeFromCellLocked(sp)
+	}
+	sp.x, sp.y = x, y
+	sp.cell = spatialCell{
+		x: int64(math.Floor(x / s.cellSize)),
+		y: int64(math.Floor(y / s.cellSize)),
+	}
+	cell := s.cells[sp.cell]
+	if cell == nil {
+		cell = make(map[livekit.ParticipantID]*spatialParticipant)
+		s.cells[sp.cell] = cell
+	}
+	cell[p.ID()] = sp
+
+	var changes []spatialChange
+	// participants in range may move out of range
+	for otherID, prev := range sp.inRange {
+		other := s.participants[otherID]
+		if next := s.rangeAt(sp, other, prev); next != prev {
+			changes = append(changes, s.setRangeLocked(sp, other, prev, next))
+		}
+	}
+	// participants in adjacent cells may move into range
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for otherID, other := range s.cells[spatialCell{x: sp.cell.x + dx, y: sp.cell.y + dy}] {
+				if other == sp {
+					continue
+				}
+				if _, ok := sp.inRange[otherID]; ok {
+					continue
+				}
+				if next := s.rangeAt(sp, other, spatialRange{}); next != (spatialRange{}) {
+					changes = append(changes, s.setRangeLocked(sp, other, spatialRange{}, next))
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// Remove takes the participant out of the space, returning changes of ranges with other participants
+func (s *SpatialSubscriptions) Remove(participantID livekit.ParticipantID) []spatialChange {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sp := s.participants[participantID]
+	if sp == nil {
+		return nil
+	}
+
+	var changes []spatialChange
+	for otherID, prev := range sp.inRange {
+		changes = append(changes, s.setRangeLocked(sp, s.participants[otherID], prev, spatialRange{}))
+	}
+	s.removeFromCellLocked(sp)
+	delete(s.participants, participantID)
+	return changes
+}
+
+// LockParticipant serializes changes of the participant's position along with applying them,
+// the returned function releases the lock
+func (s *SpatialSubscriptions) LockParticipant(participantID livekit.ParticipantID) func() {
+	s.lock.Lock()
+	pl := s.participantLocks[participantID]
+	if pl == nil {
+		pl = &spatialParticipantLock{}
+		s.participantLocks[participantID] = pl
+	}
+	pl.refs++
+	s.lock.Unlock()
+
+	pl.Lock()
+	return func() {
+		pl.Unlock()
+
+		s.lock.Lock()
+		if pl.refs--; pl.refs == 0 {
+			delete(s.participantLocks, participantID)
+		}
+		s.lock.Unlock()
+	}
+}
+
+// Range returns the current range between two participants
+func (s *SpatialSubscriptions) Range(participantID livekit.ParticipantID, otherID livekit.ParticipantID) spatialRange {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if sp := s.participants[participantID]; sp != nil {
+		return sp.inRange[otherID]
+	}
+	return spatialRange{}
+}
+
+func (s *SpatialSubscriptions) IsPositioned(participantID livekit.ParticipantID) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.participants[participantID] != nil
+}
+
+// Neighbors returns participants in range of the participant
+func (s *SpatialSubscriptions) Neighbors(participantID livekit.ParticipantID) []spatialNeighbor {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sp := s.participants[participantID]
+	if sp == nil {
+		return nil
+	}
+
+	neighbors := make([]spatialNeighbor, 0, len(sp.inRange))
+	for otherID, r := range sp.inRange {
+		neighbors = append(neighbors, spatialNeighbor{
+			participant: s.participants[otherID].participant,
+			inRange:     r,
+		})
+	}
+	return neighbors
+}
+
+func (s *SpatialSubscriptions) rangeAt(sp, other *spatialParticipant, prev spatialRange) spatialRange {
+	distance := math.Hypot(sp.x-other.x, sp.y-other.y)
+	return spatialRange{
+		audio: s.isInRadius(distance, s.conf.AudioRadius, prev.audio),
+		video: s.isInRadius(distance, s.conf.VideoRadius, prev.video),
+	}
+}
+
+func (s *SpatialSubscriptions) isInRadius(distance float64, radius float64, wasInRange bool) bool {
+	if radius <= 0 {
+		return false
+	}
+	if wasInRange {
+		return distance <= radius+s.conf.Hysteresis
+	}
+	return distance <= radius
+}
+
+func (s *SpatialSubscriptions) setRangeLocked(sp, other *spatialParticipant, prev, next spatialRange) spatialChange {
+	if next == (spatialRange{}) {
+		delete(sp.inRange, other.participant.ID())
+		delete(other.inRange, sp.participant.ID())
+	} else {
+		sp.inRange[other.participant.ID()] = next
+		other.inRange[sp.participant.ID()] = next
+	}
+	return spatialChange{a: sp.participant, b: other.participant, from: prev, to: next}
+}
+
+func (s *SpatialSubscriptions) removeFromCellLocked(sp *spatialParticipant) {
+	cell := s.cells[sp.cell]
+	delete(cell, sp.participant.ID())
+	if len(cell) == 0 {
+		delete(s.cells, sp.cell)
+	}
+}
+
+// agents and recorders are not positioned, they subscribe to and are subscribed by everyone
+func isSpatialExempt(p types.Participant) bool {
+	return p.IsAgent() || p.IsRecorder()
+}
+
+func parseSpatialPosition(position string) (x float64, y float64, ok bool) {
+	xs, ys, found := strings.Cut(position, ",")
+	if !found {
+		return 0, 0, false
+	}
+
+	var err error
+	if x, err = strconv.ParseFloat(strings.TrimSpace(xs), 64); err != nil {
+		return 0, 0, false
+	}
+	if y, err = strconv.ParseFloat(strings.TrimSpace(ys), 64); err != nil {
+		return 0, 0, false
+	}
+	if !isValidSpatialCoordinate(x) || !isValidSpatialCoordinate(y) {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+func isValidSpatialCoordinate(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0) && math.Abs(v) <= maxSpatialCoordinate
+}
+
+// isSpatialPair returns true when subscriptions between the participants are maintained by position
+func (r *Room) isSpatialPair(p1 types.Participant, p2 types.Participant) bool {
+	return r.spatial.IsEnabled() && !isSpatialExempt(p1) && !isSpatialExempt(p2)
+}
+
+// updateSpatialPosition applies the position reported by the participant, participants without a valid
+// position are not subscribed to positioned participants
+func (r *Room) updateSpatialPosition(p types.LocalParticipant, position string) {
+	if !r.spatial.IsEnabled() || isSpatialExempt(p) {
+		return
+	}
+
+	unlock := r.spatial.LockParticipant(p.ID())
+	defer unlock()
+
+	if x, y, ok := parseSpatialPosition(position); ok {
+		r.applySpatialChanges(r.spatial.Move(p, x, y))
+	} else {
+		r.applySpatialChanges(r.spatial.Remove(p.ID()))
+	}
+}
+
+// removeSpatialParticipant takes the participant out of range of everyone
+func (r *Room) removeSpatialParticipant(participantID livekit.ParticipantID) {
+	unlock := r.spatial.LockParticipant(participantID)
+	defer unlock()
+
+	r.applySpatialChanges(r.spatial.Remove(participantID))
+}
+
+func (r *Room) applySpatialChanges(changes []spatialChange) {
+	for _, c := range changes {
+		// the other participant may have moved since, subscriptions follow the current range
+		to := r.spatial.Range(c.a.ID(), c.b.ID())
+		r.applySpatialRange(c.a, c.b, c.from, to)
+		r.applySpatialRange(c.b, c.a, c.from, to)
+	}
+}
+
+func (r *Room) applySpatialRange(sub types.LocalParticipant, pub types.LocalParticipant, from spatialRange, to spatialRange) {
+	r.lock.RLock()
+	autoSubscribe := r.autoSubscribe(sub)
+	r.lock.RUnlock()
+	if !autoSubscribe {
+		return
+	}
+
+	for _, track := range pub.GetPublishedTracks() {
+		was, is := from.includes(track.Kind()), to.includes(track.Kind())
+		switch {
+		case is && !was && sub.State() == livekit.ParticipantInfo_ACTIVE:
+			sub.SubscribeToTrack(track.ID(), false)
+		case was && !is:
+			sub.UnsubscribeFromTrack(track.ID())
+		}
+	}
+}
+
+// subscribeToSpatialNeighbors subscribes the participant to tracks of participants in range
+func (r *Room) subscribeToSpatialNeighbors(p types.LocalParticipant, isSync bool) {
+	for _, neighbor := range r.spatial.Neighbors(p.ID()) {
+		for _, track := range neighbor.participant.GetPublishedTracks() {
+			if neighbor.inRange.includes(track.Kind()) {
+				p.SubscribeToTrack(track.ID(), isSync)
+			}
+		}
+	}
+}
+
+// subscribeSpatialNeighborsToTrack subscribes participants in range of the publisher to the new track
+func (r *Room) subscribeSpatialNeighborsToTrack(pub types.Participant, track types.MediaTrack) {
+	if !r.spatial.IsEnabled() || isSpatialExempt(pub) {
+		return
+	}
+
+	for _, neighbor := range r.spatial.Neighbors(pub.ID()) {
+		sub := neighbor.participant
+		if sub.State() != livekit.ParticipantInfo_ACTIVE || !neighbor.inRange.includes(track.Kind()) {
+			continue
+		}
+		r.lock.RLock()
+		autoSubscribe := r.autoSubscribe(sub)
+		r.lock.RUnlock()
+		if autoSubscribe {
+			sub.SubscribeToTrack(track.ID(), false)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+)
+
+func TestSpatialSubscriptions(t *testing.T) {
+	newParticipants := func(n int) []types.LocalParticipant {
+		participants := make([]types.LocalParticipant, 0, n)
+		for i := 0; i < n; i++ {
+			participants = append(participants, NewMockParticipant(livekit.ParticipantIdentity(fmt.Sprintf("p%d", i)), types.CurrentProtocol, false, true, nil))
+		}
+		return participants
+	}
+	ranges := func(changes []spatialChange) map[livekit.ParticipantIdentity]spatialRange {
+		res := make(map[livekit.ParticipantIdentity]spatialRange)
+		for _, c := range changes {
+			res[c.b.Identity()] = c.to
+		}
+		return res
+	}
+
+	t.Run("audio and video radius", func(t *testing.T) {
+		s := NewSpatialSubscriptions(config.SpatialConfig{AudioRadius: 20, VideoRadius: 10, Hysteresis: 2})
+		p := newParticipants(4)
+		s.Move(p[1], 5, 0)
+		s.Move(p[2], 15, 0)
+		require.Empty(t, s.Move(p[3], 100, 100))
+
+		require.Equal(t, map[livekit.ParticipantIdentity]spatialRange{
+			"p1": {audio: true, video: true},
+			"p2": {audio: true},
+		}, ranges(s.Move(p[0], 0, 0)))
+		require.Len(t, s.Neighbors(p[2].ID()), 2)
+		require.Empty(t, s.Neighbors(p[3].ID()))
+	})
+
+	t.Run("hysteresis", func(t *testing.T) {
+		s := NewSpatialSubscriptions(config.SpatialConfig{AudioRadius: 10, Hysteresis: 2})
+		p := newParticipants(2)
+		s.Move(p[1], 0, 0)
+		require.Equal(t, map[livekit.ParticipantIdentity]spatialRange{"p1": {audio: true}}, ranges(s.Move(p[0], 10, 0)))
+
+		// still in range within hysteresis
+		require.Empty(t, s.Move(p[0], 11.5, 0))
+		require.Equal(t, map[livekit.ParticipantIdentity]spatialRange{"p1": {}}, ranges(s.Move(p[0], 12.5, 0)))
+
+		// not back in range until within radius
+		require.Empty(t, s.Move(p[0], 11, 0))
+		require.Equal(t, map[livekit.ParticipantIdentity]spatialRange{"p1": {audio: true}}, ranges(s.Move(p[0], 0, 9)))
+	})
+
+	t.Run("across cells", func(t *testing.T) {
+		s := NewSpatialSubscriptions(config.SpatialConfig{AudioRadius: 10})
+		p := newParticipants(2)
+		s.Move(p[1], -1, -1)
+		require.Equal(t, map[livekit.ParticipantIdentity]spatialRange{"p1": {audio: true}}, ranges(s.Move(p[0], 1, 1)))
+		// moving far away in a single step
+		require.Equal(t, map[livekit.ParticipantIdentity]spatialRange{"p1": {}}, ranges(s.Move(p[0], 1000, 1000)))
+		require.Empty(t, s.Neighbors(p[1].ID()))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		s := NewSpatialSubscriptions(config.SpatialConfig{AudioRadius: 10, VideoRadius: 10})
+		p := newParticipants(3)
+		s.Move(p[0], 0, 0)
+		s.Move(p[1], 1, 0)
+		s.Move(p[2], 0, 1)
+		require.Len(t, s.Remove(p[0].ID()), 2)
+		require.Len(t, s.Neighbors(p[1].ID()), 1)
+		require.Empty(t, s.Remove(p[0].ID()))
+	})
+}
+
+func TestRoomSpatialPosition(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+	defer rm.Close(types.ParticipantCloseReasonNone)
+	rm.spatial = NewSpatialSubscriptions(config.SpatialConfig{AudioRadius: 10})
+	lpl := rm.LocalParticipantListener()
+
+	participants := rm.GetParticipants()
+	p0 := participants[0].(*typesfakes.FakeLocalParticipant)
+	p1 := participants[1].(*typesfakes.FakeLocalParticipant)
+	numSubscribed := p0.SubscribeToTrackCallCount()
+	numUnsubscribed := p0.UnsubscribeFromTrackCallCount()
+
+	move := func(p *typesfakes.FakeLocalParticipant, position string) {
+		lpl.OnDataMessage(p, livekit.DataPacket_RELIABLE, &livekit.DataPacket{
+			Value: &livekit.DataPacket_User{User: &livekit.UserPacket{Topic: proto.String(SpatialPositionTopic), Payload: []byte(position)}},
+		})
+	}
+
+	move(p0, "0,0")
+	move(p1, "5,0")
+	require.Equal(t, numSubscribed+1, p0.SubscribeToTrackCallCount())
+
+	// positions are not forwarded
+	require.Zero(t, p0.SendDataMessageCallCount())
+	require.Zero(t, p1.SendDataMessageCallCount())
+
+	move(p1, "50,0")
+	require.Equal(t, numUnsubscribed+1, p0.UnsubscribeFromTrackCallCount())
+
+	// invalid positions take the participant out of the space
+	move(p1, "1,0")
+	require.Equal(t, numSubscribed+2, p0.SubscribeToTrackCallCount())
+	move(p1, "NaN,0")
+	require.Equal(t, numUnsubscribed+2, p0.UnsubscribeFromTrackCallCount())
+	require.False(t, rm.spatial.IsPositioned(p1.ID()))
+	move(p1, "1e300,0")
+	require.False(t, rm.spatial.IsPositioned(p1.ID()))
+
+	// leaving the room takes the participant out of range
+	move(p1, "1,0")
+	require.Equal(t, numSubscribed+3, p0.SubscribeToTrackCallCount())
+	rm.RemoveParticipant(p1.Identity(), p1.ID(), types.ParticipantCloseReasonClientRequestLeave)
+	require.Equal(t, numUnsubscribed+3, p0.UnsubscribeFromTrackCallCount())
+	require.False(t, rm.spatial.IsPositioned(p1.ID()))
+}
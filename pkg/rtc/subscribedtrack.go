@@ -155,6 +155,7 @@ func NewSubscribedTrack(params SubscribedTrackParams) (*SubscribedTrack, error)
 		DisableSenderReportPassThrough: params.Subscriber.GetDisableSenderReportPassThrough(),
 		SupportsCodecChange:            params.Subscriber.SupportsCodecChange(),
 		Listener:                       s,
+		Timeline:                       params.Subscriber.GetTimeline().WithTrack(string(params.MediaTrack.ID())),
 	})
 	if err != nil {
 		return nil, err
@@ -60,6 +60,8 @@ type SubscriptionManagerParams struct {
 	TelemetryListener   types.ParticipantTelemetryListener
 
 	SubscriptionLimitVideo, SubscriptionLimitAudio int32
+	// limit on the total number of subscribed media tracks, regardless of kind
+	SubscriptionLimit int32
 
 	DataTrackResolver types.DataTrackResolver
 
@@ -607,13 +609,27 @@ func (m *SubscriptionManager) reconcileDataTrackSubscription(s *dataTrackSubscri
 	if !m.canReconcile() {
 		return
 	}
+	if s.isDesired() && s.getDataDownTrack() != nil {
+		// reconciled when the track changed, publisher may have revoked permission
+		if res := m.params.DataTrackResolver(m.params.Participant, s.trackID); res.DataTrack != nil && !res.HasPermission {
+			s.logger.Infow("unsubscribing from data track, permission revoked")
+			if err := m.unsubscribeDataTrack(s); err != nil {
+				s.logger.Warnw("failed to unsubscribe", err)
+			}
+			// stays desired, subscribed again once permitted
+			s.setDataDownTrack(nil)
+			m.notifyDataTrackSubscriberHandles()
+			return
+		}
+	}
 	if s.needsSubscribe() {
 		if err := m.subscribeDataTrack(s); err != nil {
 			s.recordAttempt(false)
 
 			switch err {
-			case ErrNoSubscribePermission:
+			case ErrNoTrackPermission, ErrNoSubscribePermission:
 				// these are errors that are outside of our control, so we'll keep trying
+				// - ErrNoTrackPermission: publisher's subscription rules do not allow subscriber, may change any moment
 				// - ErrNoSubscribePermission: participant was not granted canSubscribe, may change any moment
 			case ErrTrackNotFound:
 				// source track was never published or closed
@@ -735,6 +751,10 @@ func (m *SubscriptionManager) hasCapacityForSubscription(kind livekit.TrackType)
 			return false
 		}
 	}
+
+	if m.params.SubscriptionLimit > 0 && m.subscribedVideoCount.Load()+m.subscribedAudioCount.Load() >= m.params.SubscriptionLimit {
+		return false
+	}
 	return true
 }
 
@@ -981,6 +1001,9 @@ func (m *SubscriptionManager) handleSubscribedTrackClose(s *mediaTrackSubscripti
 		audioCount := m.subscribedAudioCount.Dec()
 		relieveFromLimits = m.params.SubscriptionLimitAudio > 0 && audioCount == m.params.SubscriptionLimitAudio-1
 	}
+	if m.params.SubscriptionLimit > 0 && m.subscribedVideoCount.Load()+m.subscribedAudioCount.Load() == m.params.SubscriptionLimit-1 {
+		relieveFromLimits = true
+	}
 
 	m.unmarkSubscribedTo(s.getPublisherID(), s.trackID)
 
@@ -1083,6 +1106,10 @@ func (m *SubscriptionManager) subscribeDataTrack(sub *dataTrackSubscription) err
 
 	sub.setPublisher(res.PublisherIdentity, res.PublisherID)
 
+	if !res.HasPermission {
+		return ErrNoTrackPermission
+	}
+
 	dataDownTrack, err := dataTrack.AddSubscriber(m.params.Participant)
 	if err != nil && !errors.Is(err, errAlreadySubscribed) {
 		return err
@@ -1118,6 +1145,18 @@ func (m *SubscriptionManager) unsubscribeDataTrack(s *dataTrackSubscription) err
 	return nil
 }
 
+func (m *SubscriptionManager) GetDataDownTrackByHandle(handle uint16) types.DataDownTrack {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	for _, sub := range m.dataTrackSubscriptions {
+		if dataDownTrack := sub.getDataDownTrack(); dataDownTrack != nil && dataDownTrack.Handle() == handle {
+			return dataDownTrack
+		}
+	}
+	return nil
+}
+
 func (m *SubscriptionManager) notifyDataTrackSubscriberHandles() {
 	m.lock.Lock()
 	handles := make(map[uint32]*livekit.DataTrackSubscriberHandles_PublishedDataTrack, len(m.dataTrackSubscriptions))
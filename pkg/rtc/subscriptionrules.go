@@ -0,0 +1,231 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// SubscriptionRulesAttribute is the reserved participant attribute holding the attribute based
+// subscription rules of a publisher, as a JSON list of SubscriptionRule.
+//
+// Rules restrict subscriptions in addition to the identity based SubscriptionPermission,
+// a subscriber is allowed when it matches any of the rules. They are evaluated against the issued attributes
+// of subscribers, set by their token or the server API, as clients with CanUpdateOwnMetadata can update
+// their own attributes. Subscriptions are revoked when attributes of the publisher or subscriber change.
+const SubscriptionRulesAttribute = "lk.subscription_rules"
+
+// SubscriptionRule matches subscribers on one of their attributes, either against a list of values,
+// e.g. role in [host, moderator], or against the value of the same attribute of the publisher, e.g. same team.
+type SubscriptionRule struct {
+	Attribute       string   `json:"attribute"`
+	In              []string `json:"in,omitempty"`
+	SameAsPublisher bool     `json:"sameAsPublisher,omitempty"`
+}
+
+func (s SubscriptionRule) matches(pubAttributes, subAttributes map[string]string) bool {
+	value, ok := subAttributes[s.Attribute]
+	if !ok {
+		return false
+	}
+	if s.SameAsPublisher && value != "" && value == pubAttributes[s.Attribute] {
+		return true
+	}
+	return slices.Contains(s.In, value)
+}
+
+func participantAttributes(p types.LocalParticipant) map[string]string {
+	if grants := p.ClaimGrants(); grants != nil {
+		return grants.Attributes
+	}
+	return nil
+}
+
+// publisherAttributes returns the attributes of a publisher connected to this node or to another node hosting the room
+func publisherAttributes(pub types.Participant) map[string]string {
+	switch p := pub.(type) {
+	case types.LocalParticipant:
+		return participantAttributes(p)
+	case *RemoteParticipant:
+		return p.Attributes()
+	}
+	return nil
+}
+
+// subscriptionRulesOf returns the rules set by the publisher, nil when subscriptions are not restricted by attributes.
+// Invalid rules do not allow any subscriber.
+func subscriptionRulesOf(pub types.Participant) []SubscriptionRule {
+	return parseSubscriptionRules(publisherAttributes(pub))
+}
+
+func parseSubscriptionRules(attributes map[string]string) []SubscriptionRule {
+	raw, ok := attributes[SubscriptionRulesAttribute]
+	if !ok || raw == "" {
+		return nil
+	}
+
+	var rules []SubscriptionRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil || len(rules) == 0 {
+		return []SubscriptionRule{}
+	}
+	return rules
+}
+
+// hasSubscriptionPermission checks the identity based permission and the subscription rules of the publisher
+func hasSubscriptionPermission(pub types.Participant, trackID livekit.TrackID, sub types.LocalParticipant) bool {
+	return IsParticipantExemptFromTrackPermissionsRestrictions(sub) ||
+		pub.HasPermission(trackID, sub.Identity(), sub.IssuedAttributes())
+}
+
+// data tracks are not covered by identity based permissions, only by subscription rules
+func hasDataTrackSubscriptionPermission(pub types.Participant, sub types.LocalParticipant) bool {
+	return IsParticipantExemptFromTrackPermissionsRestrictions(sub) ||
+		pub.SubscriptionRulesAllow(sub.IssuedAttributes())
+}
+
+func subscriptionRulesMatch(rules []SubscriptionRule, pubAttributes, subAttributes map[string]string) bool {
+	if rules == nil {
+		return true
+	}
+	for _, rule := range rules {
+		if rule.matches(pubAttributes, subAttributes) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriptionRulesReference returns true if any of the rules depends on one of the attributes,
+// as an attribute of the subscriber, or of the publisher when asPublisher is set
+func subscriptionRulesReference(rules []SubscriptionRule, attributes map[string]struct{}, asPublisher bool) bool {
+	for _, rule := range rules {
+		if asPublisher && !rule.SameAsPublisher {
+			continue
+		}
+		if _, ok := attributes[rule.Attribute]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func changedAttributes(prev, curr map[string]string) map[string]struct{} {
+	changed := make(map[string]struct{})
+	for k, v := range curr {
+		if pv, ok := prev[k]; !ok || pv != v {
+			changed[k] = struct{}{}
+		}
+	}
+	for k := range prev {
+		if _, ok := curr[k]; !ok {
+			changed[k] = struct{}{}
+		}
+	}
+	return changed
+}
+
+// reevaluateSubscriptionRules applies subscription rules after attributes of the participant changed from prevAttributes,
+// and its issued attributes from prevIssuedAttributes. Only rules depending on a changed attribute are evaluated,
+// disallowed subscriptions are revoked and tracks are marked as changed for subscribers that may have become allowed.
+func (r *Room) reevaluateSubscriptionRules(participant types.LocalParticipant, prevAttributes, prevIssuedAttributes map[string]string) {
+	r.reevaluatePublisherSubscriptionRules(participant, changedAttributes(prevAttributes, participantAttributes(participant)))
+
+	// as a subscriber, of publishers with rules depending on a changed attribute
+	if IsParticipantExemptFromTrackPermissionsRestrictions(participant) {
+		return
+	}
+	issuedAttributes := participant.IssuedAttributes()
+	changed := changedAttributes(prevIssuedAttributes, issuedAttributes)
+	if len(changed) == 0 {
+		return
+	}
+	r.lock.RLock()
+	publishers := make([]types.Participant, 0, len(r.participants)+len(r.remoteParticipants))
+	for _, p := range r.participants {
+		publishers = append(publishers, p)
+	}
+	for _, rp := range r.remoteParticipants {
+		publishers = append(publishers, rp)
+	}
+	r.lock.RUnlock()
+
+	for _, pub := range publishers {
+		if pub.ID() == participant.ID() {
+			continue
+		}
+		pubRules := subscriptionRulesOf(pub)
+		if pubRules == nil || !subscriptionRulesReference(pubRules, changed, false) {
+			continue
+		}
+		allowed := pub.SubscriptionRulesAllow(issuedAttributes)
+		if allowed && subscriptionRulesMatch(pubRules, publisherAttributes(pub), prevIssuedAttributes) {
+			// was already allowed
+			continue
+		}
+		for _, track := range pub.GetPublishedTracks() {
+			if allowed {
+				r.trackManager.NotifyTrackChanged(track.ID())
+			} else if track.IsSubscriber(participant.ID()) {
+				r.logger.Infow("revoking subscription disallowed by subscription rules", "subscriber", participant.Identity(), "trackID", track.ID())
+				track.RemoveSubscriber(participant.ID(), false)
+			}
+		}
+		// subscribers re-resolve data tracks when notified, revoking disallowed subscriptions
+		for _, dataTrack := range pub.GetPublishedDataTracks() {
+			if allowed || dataTrack.IsSubscriber(participant.ID()) {
+				r.trackManager.NotifyTrackChanged(dataTrack.ID())
+			}
+		}
+	}
+}
+
+// reevaluatePublisherSubscriptionRules applies subscription rules of a publisher after its attributes changed,
+// when its rules or the attributes its rules compare subscribers against changed
+func (r *Room) reevaluatePublisherSubscriptionRules(pub types.Participant, changed map[string]struct{}) {
+	rules := subscriptionRulesOf(pub)
+	if _, ok := changed[SubscriptionRulesAttribute]; !ok && !subscriptionRulesReference(rules, changed, true) {
+		return
+	}
+
+	if rules != nil {
+		r.lock.RLock()
+		participants := make(map[livekit.ParticipantID]types.LocalParticipant, len(r.participants))
+		for _, p := range r.participants {
+			participants[p.ID()] = p
+		}
+		r.lock.RUnlock()
+
+		for _, track := range pub.GetPublishedTracks() {
+			for _, subID := range track.GetAllSubscribers() {
+				if sub := participants[subID]; sub != nil && !hasSubscriptionPermission(pub, track.ID(), sub) {
+					r.logger.Infow("revoking subscription disallowed by subscription rules", "subscriber", sub.Identity(), "trackID", track.ID())
+					track.RemoveSubscriber(subID, false)
+				}
+			}
+		}
+	}
+	// rules may have been loosened or removed, data track subscribers re-resolve their permission
+	for _, track := range pub.GetPublishedTracks() {
+		r.trackManager.NotifyTrackChanged(track.ID())
+	}
+	for _, dataTrack := range pub.GetPublishedDataTracks() {
+		r.trackManager.NotifyTrackChanged(dataTrack.ID())
+	}
+}
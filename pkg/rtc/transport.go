@@ -328,6 +328,13 @@ type TransportParams struct {
 	DatachannelMaxReceiverBufferSize int
 
 	EnableDataTracks bool
+
+	Timeline utils.TimelineRecorder
+
+	// only relay candidates allocated by the embedded TURN server on one of RelayAddresses are accepted
+	// and local candidates are limited to RelayAddresses, so that peers do not learn other addresses
+	RelayOnly      bool
+	RelayAddresses []string
 }
 
 func newPeerConnection(
@@ -555,6 +562,7 @@ func NewPCTransport(params TransportParams) (*PCTransport, error) {
 		lastNegotiate:            time.Now(),
 	}
 	t.localOfferId.Store(uint32(rand.Intn(1<<8) + 1))
+	t.connectionDetails.RelayOnly = params.RelayOnly
 
 	bwe, err := t.createPeerConnection()
 	if err != nil {
@@ -590,6 +598,7 @@ func NewPCTransport(params TransportParams) (*PCTransport, error) {
 			Pacer:     t.pacer,
 			RTTGetter: t.GetRTT,
 			Logger:    params.Logger.WithComponent(utils.ComponentCongestionControl),
+			Timeline:  params.Timeline,
 		}, params.CongestionControlConfig.Enabled, params.CongestionControlConfig.AllowPause)
 		t.streamAllocator.OnStreamStateChange(params.Handler.OnStreamStateChange)
 		t.streamAllocator.Start()
@@ -632,6 +641,14 @@ func (t *PCTransport) createPeerConnection() (cc.BandwidthEstimator, error) {
 	t.iceTransport.OnSelectedCandidatePairChange(func(pair *webrtc.ICECandidatePair) {
 		t.params.Logger.Debugw("selected ICE candidate pair changed", "pair", wrappedICECandidatePairLogger{pair})
 		t.connectionDetails.SetSelectedPair(pair)
+		if t.params.RelayOnly && !t.isRelayedRemoteCandidate(pair.Remote) {
+			t.params.Logger.Warnw(
+				"failing connection not relayed by TURN server in relay only mode", nil,
+				"pair", wrappedICECandidatePairLogger{pair},
+			)
+			t.handleConnectionFailed(false)
+			return
+		}
 		existingPair := t.selectedPair.Load()
 		if existingPair != nil {
 			t.params.Logger.Infow(
@@ -843,6 +860,7 @@ func (t *PCTransport) handleConnectionFailed(forceShortConn bool) {
 
 func (t *PCTransport) onICEConnectionStateChange(state webrtc.ICEConnectionState) {
 	t.params.Logger.Debugw("ice connection state change", "state", state.String())
+	t.params.Timeline.Record("ice_connection_state", "transport", t.params.Transport, "state", state)
 	switch state {
 	case webrtc.ICEConnectionStateConnected:
 		t.setICEConnectedAt(time.Now())
@@ -989,7 +1007,8 @@ func (t *PCTransport) isFullyEstablished() bool {
 }
 
 func (t *PCTransport) SetPreferTCP(preferTCP bool) {
-	t.preferTCP.Store(preferTCP)
+	// relayed traffic reaches the SFU from the embedded TURN server over UDP
+	t.preferTCP.Store(preferTCP && !t.params.RelayOnly)
 }
 
 func (t *PCTransport) AddICECandidate(candidate webrtc.ICECandidateInit) {
@@ -2383,6 +2402,10 @@ func (t *PCTransport) handleLocalICECandidate(e event) error {
 			t.params.Logger.Debugw("filtering out local candidate", "candidate", c.String())
 			filtered = true
 		}
+		if t.params.RelayOnly && !t.isRelayOnlyLocalAddress(c.Address) {
+			t.params.Logger.Debugw("filtering out local candidate in relay only mode", "candidate", c.String())
+			filtered = true
+		}
 		t.connectionDetails.AddLocalCandidate(c, filtered, true)
 	}
 
@@ -2417,6 +2440,11 @@ func (t *PCTransport) handleRemoteICECandidate(e event) error {
 		filtered = true
 	}
 
+	if t.params.RelayOnly && c.Candidate != "" && !types.IsCandidateRelayedBy(*c, t.params.RelayAddresses) {
+		t.params.Logger.Debugw("filtering out remote candidate in relay only mode")
+		filtered = true
+	}
+
 	t.connectionDetails.AddRemoteCandidate(*c, filtered, true, false)
 	if filtered {
 		return nil
@@ -2470,6 +2498,13 @@ func (t *PCTransport) filterCandidates(sd webrtc.SessionDescription, preferTCP,
 						excluded = true
 					}
 				}
+				if !excluded && t.params.RelayOnly {
+					if isLocal {
+						excluded = !t.isRelayOnlyLocalAddress(c.Address())
+					} else {
+						excluded = !types.IsICECandidateRelayedBy(c, t.params.RelayAddresses)
+					}
+				}
 				if !excluded {
 					filteredAttrs = append(filteredAttrs, a)
 				}
@@ -2501,6 +2536,29 @@ func (t *PCTransport) filterCandidates(sd webrtc.SessionDescription, preferTCP,
 	return sd
 }
 
+// isRelayOnlyLocalAddress returns true for local addresses which can be revealed in relay only mode,
+// the embedded TURN server relays to the SFU on the addresses it allocates relays on
+func (t *PCTransport) isRelayOnlyLocalAddress(address string) bool {
+	return len(t.params.RelayAddresses) == 0 || slices.Contains(t.params.RelayAddresses, address)
+}
+
+// isRelayedRemoteCandidate returns true when the remote candidate of a selected pair goes through the TURN server,
+// peer reflexive candidates learnt from relayed connectivity checks have the relay address
+func (t *PCTransport) isRelayedRemoteCandidate(c *webrtc.ICECandidate) bool {
+	if c == nil {
+		return false
+	}
+
+	switch c.Typ {
+	case webrtc.ICECandidateTypeRelay:
+		return len(t.params.RelayAddresses) == 0 || slices.Contains(t.params.RelayAddresses, c.Address)
+	case webrtc.ICECandidateTypePrflx:
+		return slices.Contains(t.params.RelayAddresses, c.Address)
+	default:
+		return false
+	}
+}
+
 func (t *PCTransport) clearSignalStateCheckTimer() {
 	if t.signalStateCheckTimer != nil {
 		t.signalStateCheckTimer.Stop()
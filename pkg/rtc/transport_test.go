@@ -28,6 +28,7 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/rtc/transport"
 	"github.com/livekit/livekit-server/pkg/rtc/transport/transportfakes"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/testutils"
 	"github.com/livekit/protocol/codecs/mime"
 	"github.com/livekit/protocol/livekit"
@@ -598,6 +599,45 @@ func untilTransportsConnected(transports ...*transportfakes.FakeHandler) *sync.W
 	return &triggered
 }
 
+func TestRelayOnlyCandidates(t *testing.T) {
+	relayAddresses := []string{"159.203.70.248"}
+
+	relay := webrtc.ICECandidateInit{Candidate: "candidate:1 1 udp 16777215 159.203.70.248 50000 typ relay raddr 203.0.113.10 rport 55000"}
+	otherRelay := webrtc.ICECandidateInit{Candidate: "candidate:2 1 udp 16777215 198.51.100.20 50000 typ relay raddr 203.0.113.10 rport 55000"}
+	host := webrtc.ICECandidateInit{Candidate: "candidate:3 1 udp 2130706431 203.0.113.10 55000 typ host"}
+	srflx := webrtc.ICECandidateInit{Candidate: "candidate:4 1 udp 1694498815 203.0.113.10 55000 typ srflx raddr 10.0.0.2 rport 55000"}
+
+	require.True(t, types.IsCandidateRelayedBy(relay, relayAddresses))
+	require.False(t, types.IsCandidateRelayedBy(otherRelay, relayAddresses))
+	require.True(t, types.IsCandidateRelayedBy(otherRelay, nil))
+	require.False(t, types.IsCandidateRelayedBy(host, relayAddresses))
+	require.False(t, types.IsCandidateRelayedBy(srflx, nil))
+
+	transport, err := NewPCTransport(TransportParams{
+		Config:         &WebRTCConfig{},
+		Handler:        &transportfakes.FakeHandler{},
+		RelayOnly:      true,
+		RelayAddresses: relayAddresses,
+	})
+	require.NoError(t, err)
+	defer transport.Close()
+
+	require.True(t, transport.GetICEConnectionInfo().RelayOnly)
+
+	// relayed traffic arrives over UDP
+	transport.SetPreferTCP(true)
+	require.False(t, transport.preferTCP.Load())
+
+	require.True(t, transport.isRelayOnlyLocalAddress("159.203.70.248"))
+	require.False(t, transport.isRelayOnlyLocalAddress("10.0.0.1"))
+
+	require.True(t, transport.isRelayedRemoteCandidate(&webrtc.ICECandidate{Typ: webrtc.ICECandidateTypeRelay, Address: "159.203.70.248"}))
+	require.True(t, transport.isRelayedRemoteCandidate(&webrtc.ICECandidate{Typ: webrtc.ICECandidateTypePrflx, Address: "159.203.70.248"}))
+	require.False(t, transport.isRelayedRemoteCandidate(&webrtc.ICECandidate{Typ: webrtc.ICECandidateTypePrflx, Address: "203.0.113.10"}))
+	require.False(t, transport.isRelayedRemoteCandidate(&webrtc.ICECandidate{Typ: webrtc.ICECandidateTypeHost, Address: "203.0.113.10"}))
+	require.False(t, transport.isRelayedRemoteCandidate(nil))
+}
+
 func TestConfigureAudioTransceiver(t *testing.T) {
 	for _, testcase := range []struct {
 		nack   bool
@@ -41,6 +41,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/datachannel"
 	"github.com/livekit/livekit-server/pkg/sfu/interceptor"
 	"github.com/livekit/livekit-server/pkg/sfu/pacer"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 const (
@@ -98,6 +99,9 @@ type TransportManagerParams struct {
 	UseOneShotSignallingMode      bool
 	FireOnTrackBySdp              bool
 	EnableDataTracks              bool
+	Timeline                      sutils.TimelineRecorder
+	RelayOnly                     bool
+	RelayAddresses                []string
 }
 
 type TransportManager struct {
@@ -168,6 +172,9 @@ func NewTransportManager(params TransportManagerParams) (*TransportManager, erro
 		DatachannelLossyTargetLatency: params.DatachannelLossyTargetLatency,
 		FireOnTrackBySdp:              params.FireOnTrackBySdp,
 		EnableDataTracks:              params.EnableDataTracks,
+		Timeline:                      params.Timeline,
+		RelayOnly:                     params.RelayOnly,
+		RelayAddresses:                params.RelayAddresses,
 	})
 	if err != nil {
 		return nil, err
@@ -194,6 +201,9 @@ func NewTransportManager(params TransportManagerParams) (*TransportManager, erro
 			Handler:                       TransportManagerTransportHandler{params.SubscriberHandler, t, lgr},
 			FireOnTrackBySdp:              params.FireOnTrackBySdp,
 			EnableDataTracks:              params.EnableDataTracks,
+			Timeline:                      params.Timeline,
+			RelayOnly:                     params.RelayOnly,
+			RelayAddresses:                params.RelayAddresses,
 		})
 		if err != nil {
 			return nil, err
@@ -87,6 +87,8 @@ type ICEConnectionInfo struct {
 	Remote    []*ICECandidateExtended
 	Transport livekit.SignalTarget
 	Type      ICEConnectionType
+	// only relay candidates of the embedded TURN server are accepted
+	RelayOnly bool
 }
 
 func (i *ICEConnectionInfo) HasCandidates() bool {
@@ -128,6 +130,7 @@ func (d *ICEConnectionDetails) GetInfo() *ICEConnectionInfo {
 	info := &ICEConnectionInfo{
 		Transport: d.Transport,
 		Type:      d.Type,
+		RelayOnly: d.RelayOnly,
 		Local:     make([]*ICECandidateExtended, 0, len(d.Local)),
 		Remote:    make([]*ICECandidateExtended, 0, len(d.Remote)),
 	}
@@ -404,6 +407,25 @@ func IsCandidateMDNS(candidate webrtc.ICECandidateInit) bool {
 	return IsICECandidateMDNS(c)
 }
 
+// IsCandidateRelayedBy returns true for relay candidates allocated on one of the given relay addresses,
+// any relay candidate is accepted when no relay address is given
+func IsCandidateRelayedBy(candidate webrtc.ICECandidateInit, relayAddresses []string) bool {
+	c, err := unmarshalICECandidate(candidate)
+	if err != nil {
+		return false
+	}
+
+	return IsICECandidateRelayedBy(c, relayAddresses)
+}
+
+func IsICECandidateRelayedBy(candidate ice.Candidate, relayAddresses []string) bool {
+	if candidate == nil || candidate.Type() != ice.CandidateTypeRelay {
+		return false
+	}
+
+	return len(relayAddresses) == 0 || slices.Contains(relayAddresses, candidate.Address())
+}
+
 func IsICECandidateMDNS(candidate ice.Candidate) bool {
 	if candidate == nil {
 		// end-of-candidates candidate
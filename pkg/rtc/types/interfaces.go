@@ -34,6 +34,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/pacer"
 	"github.com/livekit/livekit-server/pkg/telemetry"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 
 	"google.golang.org/protobuf/proto"
 )
@@ -118,6 +119,8 @@ const (
 	ParticipantCloseReasonUserRejected
 	ParticipantCloseReasonMoveFailed
 	ParticipantCloseReasonAgentError
+	ParticipantCloseReasonLobbyTimeout
+	ParticipantCloseReasonHandedOver
 )
 
 func (p ParticipantCloseReason) String() string {
@@ -180,6 +183,10 @@ func (p ParticipantCloseReason) String() string {
 		return "MOVE_FAILED"
 	case ParticipantCloseReasonAgentError:
 		return "AGENT_ERROR"
+	case ParticipantCloseReasonLobbyTimeout:
+		return "LOBBY_TIMEOUT"
+	case ParticipantCloseReasonHandedOver:
+		return "HANDED_OVER"
 	default:
 		return fmt.Sprintf("%d", int(p))
 	}
@@ -191,14 +198,16 @@ func (p ParticipantCloseReason) ToDisconnectReason() livekit.DisconnectReason {
 		return livekit.DisconnectReason_CLIENT_INITIATED
 	case ParticipantCloseReasonRoomManagerStop:
 		return livekit.DisconnectReason_SERVER_SHUTDOWN
-	case ParticipantCloseReasonVerifyFailed, ParticipantCloseReasonJoinFailed, ParticipantCloseReasonJoinTimeout, ParticipantCloseReasonMessageBusFailed:
+	case ParticipantCloseReasonVerifyFailed, ParticipantCloseReasonJoinFailed, ParticipantCloseReasonJoinTimeout, ParticipantCloseReasonMessageBusFailed,
+		ParticipantCloseReasonLobbyTimeout:
 		// expected to be connected but is not
 		return livekit.DisconnectReason_JOIN_FAILURE
 	case ParticipantCloseReasonPeerConnectionDisconnected:
 		return livekit.DisconnectReason_CONNECTION_TIMEOUT
 	case ParticipantCloseReasonDuplicateIdentity, ParticipantCloseReasonStale:
 		return livekit.DisconnectReason_DUPLICATE_IDENTITY
-	case ParticipantCloseReasonMigrationRequested, ParticipantCloseReasonMigrationComplete, ParticipantCloseReasonSimulateMigration:
+	case ParticipantCloseReasonMigrationRequested, ParticipantCloseReasonMigrationComplete, ParticipantCloseReasonSimulateMigration,
+		ParticipantCloseReasonHandedOver:
 		return livekit.DisconnectReason_MIGRATION
 	case ParticipantCloseReasonServiceRequestRemoveParticipant:
 		return livekit.DisconnectReason_PARTICIPANT_REMOVED
@@ -328,9 +337,11 @@ type Participant interface {
 
 	GetAudioLevel() (smoothedLevel float64, active bool)
 
-	// HasPermission checks permission of the subscriber by identity. Returns true if subscriber is allowed to subscribe
-	// to the track with trackID
-	HasPermission(trackID livekit.TrackID, subIdentity livekit.ParticipantIdentity) bool
+	// HasPermission checks permission of the subscriber by identity and the publisher's subscription rules against
+	// the subscriber's attributes. Returns true if subscriber is allowed to subscribe to the track with trackID
+	HasPermission(trackID livekit.TrackID, subIdentity livekit.ParticipantIdentity, subAttributes map[string]string) bool
+	// SubscriptionRulesAllow returns true if the publisher's subscription rules allow a subscriber with the attributes
+	SubscriptionRulesAllow(subAttributes map[string]string) bool
 
 	// permissions
 	Hidden() bool
@@ -387,6 +398,7 @@ type LocalParticipantHelper interface {
 	GetSubscriberForwarderState(p LocalParticipant) (map[livekit.TrackID]*livekit.RTPForwarderState, error)
 	ShouldRegressCodec() bool
 	GetCachedReliableDataMessage(seqs map[livekit.ParticipantID]uint32) []*DataMessageCache
+	GetDataHistory(p LocalParticipant) []*DataMessageCache
 }
 
 //counterfeiter:generate . LocalParticipant
@@ -441,6 +453,8 @@ type LocalParticipant interface {
 
 	// permissions
 	ClaimGrants() *auth.ClaimGrants
+	// IssuedAttributes returns the attributes set by the token or by the server API, excluding the ones set by the client
+	IssuedAttributes() map[string]string
 	TokenExpiresAt() time.Time
 	SetPermission(permission *livekit.ParticipantPermission) bool
 	CanPublish() bool
@@ -547,6 +561,9 @@ type LocalParticipant interface {
 
 	GetDisableSenderReportPassThrough() bool
 
+	GetTimeline() sutils.TimelineRecorder
+	IsRelayOnly() bool
+
 	HandleMetrics(senderParticipantID livekit.ParticipantID, batch *livekit.MetricsBatch) error
 	HandleUpdateSubscriptions(
 		[]livekit.TrackID,
@@ -832,6 +849,9 @@ type DataTrack interface {
 
 	HandlePacket(data []byte, packet *datatrack.Packet, arrivalTime int64)
 
+	IsReliable() bool
+	GetRetransmissionPackets(sequenceNumbers []uint16) []*datatrack.Packet
+
 	Close()
 }
 
@@ -843,6 +863,7 @@ type DataDownTrack interface {
 	PublishDataTrack() DataTrack
 
 	UpdateSubscriptionOptions(subscriptionOptions *livekit.DataTrackSubscriptionOptions)
+	HandleNack(nack *datatrack.ExtensionNack)
 }
 
 //counterfeiter:generate . DataTrackSender
@@ -902,8 +923,10 @@ type DataResolverResult struct {
 	TrackChangedNotifier ChangeNotifier
 	TrackRemovedNotifier ChangeNotifier
 	DataTrack            DataTrack
-	PublisherID          livekit.ParticipantID
-	PublisherIdentity    livekit.ParticipantIdentity
+	// is permission given to the requesting participant
+	HasPermission     bool
+	PublisherID       livekit.ParticipantID
+	PublisherIdentity livekit.ParticipantIdentity
 }
 
 // MediaTrackResolver locates a specific media track for a subscriber
@@ -4,6 +4,7 @@ package typesfakes
 import (
 	"sync"
 
+	"github.com/livekit/livekit-server/pkg/rtc/datatrack"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/protocol/livekit"
 )
@@ -23,6 +24,11 @@ type FakeDataDownTrack struct {
 	handleReturnsOnCall map[int]struct {
 		result1 uint16
 	}
+	HandleNackStub        func(*datatrack.ExtensionNack)
+	handleNackMutex       sync.RWMutex
+	handleNackArgsForCall []struct {
+		arg1 *datatrack.ExtensionNack
+	}
 	PublishDataTrackStub        func() types.DataTrack
 	publishDataTrackMutex       sync.RWMutex
 	publishDataTrackArgsForCall []struct {
@@ -96,6 +102,38 @@ func (fake *FakeDataDownTrack) HandleCalls(stub func() uint16) {
 	fake.HandleStub = stub
 }
 
+func (fake *FakeDataDownTrack) HandleNack(arg1 *datatrack.ExtensionNack) {
+	fake.handleNackMutex.Lock()
+	fake.handleNackArgsForCall = append(fake.handleNackArgsForCall, struct {
+		arg1 *datatrack.ExtensionNack
+	}{arg1})
+	stub := fake.HandleNackStub
+	fake.recordInvocation("HandleNack", []interface{}{arg1})
+	fake.handleNackMutex.Unlock()
+	if stub != nil {
+		fake.HandleNackStub(arg1)
+	}
+}
+
+func (fake *FakeDataDownTrack) HandleNackCallCount() int {
+	fake.handleNackMutex.RLock()
+	defer fake.handleNackMutex.RUnlock()
+	return len(fake.handleNackArgsForCall)
+}
+
+func (fake *FakeDataDownTrack) HandleNackCalls(stub func(*datatrack.ExtensionNack)) {
+	fake.handleNackMutex.Lock()
+	defer fake.handleNackMutex.Unlock()
+	fake.HandleNackStub = stub
+}
+
+func (fake *FakeDataDownTrack) HandleNackArgsForCall(i int) *datatrack.ExtensionNack {
+	fake.handleNackMutex.RLock()
+	defer fake.handleNackMutex.RUnlock()
+	argsForCall := fake.handleNackArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeDataDownTrack) HandleReturns(result1 uint16) {
 	fake.handleMutex.Lock()
 	defer fake.handleMutex.Unlock()
@@ -43,6 +43,17 @@ type FakeDataTrack struct {
 	deleteDataDownTrackArgsForCall []struct {
 		arg1 livekit.ParticipantID
 	}
+	GetRetransmissionPacketsStub        func([]uint16) []*datatrack.Packet
+	getRetransmissionPacketsMutex       sync.RWMutex
+	getRetransmissionPacketsArgsForCall []struct {
+		arg1 []uint16
+	}
+	getRetransmissionPacketsReturns struct {
+		result1 []*datatrack.Packet
+	}
+	getRetransmissionPacketsReturnsOnCall map[int]struct {
+		result1 []*datatrack.Packet
+	}
 	HandlePacketStub        func([]byte, *datatrack.Packet, int64)
 	handlePacketMutex       sync.RWMutex
 	handlePacketArgsForCall []struct {
@@ -60,6 +71,16 @@ type FakeDataTrack struct {
 	iDReturnsOnCall map[int]struct {
 		result1 livekit.TrackID
 	}
+	IsReliableStub        func() bool
+	isReliableMutex       sync.RWMutex
+	isReliableArgsForCall []struct {
+	}
+	isReliableReturns struct {
+		result1 bool
+	}
+	isReliableReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsSubscriberStub        func(livekit.ParticipantID) bool
 	isSubscriberMutex       sync.RWMutex
 	isSubscriberArgsForCall []struct {
@@ -311,6 +332,72 @@ func (fake *FakeDataTrack) DeleteDataDownTrackArgsForCall(i int) livekit.Partici
 	return argsForCall.arg1
 }
 
+func (fake *FakeDataTrack) GetRetransmissionPackets(arg1 []uint16) []*datatrack.Packet {
+	var arg1Copy []uint16
+	if arg1 != nil {
+		arg1Copy = make([]uint16, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.getRetransmissionPacketsMutex.Lock()
+	ret, specificReturn := fake.getRetransmissionPacketsReturnsOnCall[len(fake.getRetransmissionPacketsArgsForCall)]
+	fake.getRetransmissionPacketsArgsForCall = append(fake.getRetransmissionPacketsArgsForCall, struct {
+		arg1 []uint16
+	}{arg1Copy})
+	stub := fake.GetRetransmissionPacketsStub
+	fakeReturns := fake.getRetransmissionPacketsReturns
+	fake.recordInvocation("GetRetransmissionPackets", []interface{}{arg1Copy})
+	fake.getRetransmissionPacketsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDataTrack) GetRetransmissionPacketsCallCount() int {
+	fake.getRetransmissionPacketsMutex.RLock()
+	defer fake.getRetransmissionPacketsMutex.RUnlock()
+	return len(fake.getRetransmissionPacketsArgsForCall)
+}
+
+func (fake *FakeDataTrack) GetRetransmissionPacketsCalls(stub func([]uint16) []*datatrack.Packet) {
+	fake.getRetransmissionPacketsMutex.Lock()
+	defer fake.getRetransmissionPacketsMutex.Unlock()
+	fake.GetRetransmissionPacketsStub = stub
+}
+
+func (fake *FakeDataTrack) GetRetransmissionPacketsArgsForCall(i int) []uint16 {
+	fake.getRetransmissionPacketsMutex.RLock()
+	defer fake.getRetransmissionPacketsMutex.RUnlock()
+	argsForCall := fake.getRetransmissionPacketsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDataTrack) GetRetransmissionPacketsReturns(result1 []*datatrack.Packet) {
+	fake.getRetransmissionPacketsMutex.Lock()
+	defer fake.getRetransmissionPacketsMutex.Unlock()
+	fake.GetRetransmissionPacketsStub = nil
+	fake.getRetransmissionPacketsReturns = struct {
+		result1 []*datatrack.Packet
+	}{result1}
+}
+
+func (fake *FakeDataTrack) GetRetransmissionPacketsReturnsOnCall(i int, result1 []*datatrack.Packet) {
+	fake.getRetransmissionPacketsMutex.Lock()
+	defer fake.getRetransmissionPacketsMutex.Unlock()
+	fake.GetRetransmissionPacketsStub = nil
+	if fake.getRetransmissionPacketsReturnsOnCall == nil {
+		fake.getRetransmissionPacketsReturnsOnCall = make(map[int]struct {
+			result1 []*datatrack.Packet
+		})
+	}
+	fake.getRetransmissionPacketsReturnsOnCall[i] = struct {
+		result1 []*datatrack.Packet
+	}{result1}
+}
+
 func (fake *FakeDataTrack) HandlePacket(arg1 []byte, arg2 *datatrack.Packet, arg3 int64) {
 	var arg1Copy []byte
 	if arg1 != nil {
@@ -403,6 +490,59 @@ func (fake *FakeDataTrack) IDReturnsOnCall(i int, result1 livekit.TrackID) {
 	}{result1}
 }
 
+func (fake *FakeDataTrack) IsReliable() bool {
+	fake.isReliableMutex.Lock()
+	ret, specificReturn := fake.isReliableReturnsOnCall[len(fake.isReliableArgsForCall)]
+	fake.isReliableArgsForCall = append(fake.isReliableArgsForCall, struct {
+	}{})
+	stub := fake.IsReliableStub
+	fakeReturns := fake.isReliableReturns
+	fake.recordInvocation("IsReliable", []interface{}{})
+	fake.isReliableMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDataTrack) IsReliableCallCount() int {
+	fake.isReliableMutex.RLock()
+	defer fake.isReliableMutex.RUnlock()
+	return len(fake.isReliableArgsForCall)
+}
+
+func (fake *FakeDataTrack) IsReliableCalls(stub func() bool) {
+	fake.isReliableMutex.Lock()
+	defer fake.isReliableMutex.Unlock()
+	fake.IsReliableStub = stub
+}
+
+func (fake *FakeDataTrack) IsReliableReturns(result1 bool) {
+	fake.isReliableMutex.Lock()
+	defer fake.isReliableMutex.Unlock()
+	fake.IsReliableStub = nil
+	fake.isReliableReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeDataTrack) IsReliableReturnsOnCall(i int, result1 bool) {
+	fake.isReliableMutex.Lock()
+	defer fake.isReliableMutex.Unlock()
+	fake.IsReliableStub = nil
+	if fake.isReliableReturnsOnCall == nil {
+		fake.isReliableReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isReliableReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeDataTrack) IsSubscriber(arg1 livekit.ParticipantID) bool {
 	fake.isSubscriberMutex.Lock()
 	ret, specificReturn := fake.isSubscriberReturnsOnCall[len(fake.isSubscriberArgsForCall)]
@@ -12,6 +12,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/pacer"
 	"github.com/livekit/livekit-server/pkg/telemetry"
+	utilsa "github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
@@ -551,6 +552,16 @@ type FakeLocalParticipant struct {
 	getTelemetryListenerReturnsOnCall map[int]struct {
 		result1 types.ParticipantTelemetryListener
 	}
+	GetTimelineStub        func() utilsa.TimelineRecorder
+	getTimelineMutex       sync.RWMutex
+	getTimelineArgsForCall []struct {
+	}
+	getTimelineReturns struct {
+		result1 utilsa.TimelineRecorder
+	}
+	getTimelineReturnsOnCall map[int]struct {
+		result1 utilsa.TimelineRecorder
+	}
 	GetTrailerStub        func() []byte
 	getTrailerMutex       sync.RWMutex
 	getTrailerArgsForCall []struct {
@@ -728,11 +739,12 @@ type FakeLocalParticipant struct {
 	hasConnectedReturnsOnCall map[int]struct {
 		result1 bool
 	}
-	HasPermissionStub        func(livekit.TrackID, livekit.ParticipantIdentity) bool
+	HasPermissionStub        func(livekit.TrackID, livekit.ParticipantIdentity, map[string]string) bool
 	hasPermissionMutex       sync.RWMutex
 	hasPermissionArgsForCall []struct {
 		arg1 livekit.TrackID
 		arg2 livekit.ParticipantIdentity
+		arg3 map[string]string
 	}
 	hasPermissionReturns struct {
 		result1 bool
@@ -865,6 +877,16 @@ type FakeLocalParticipant struct {
 	isRecorderReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	IsRelayOnlyStub        func() bool
+	isRelayOnlyMutex       sync.RWMutex
+	isRelayOnlyArgsForCall []struct {
+	}
+	isRelayOnlyReturns struct {
+		result1 bool
+	}
+	isRelayOnlyReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsSubscribedToStub        func(livekit.ParticipantID) bool
 	isSubscribedToMutex       sync.RWMutex
 	isSubscribedToArgsForCall []struct {
@@ -903,6 +925,16 @@ type FakeLocalParticipant struct {
 	issueFullReconnectArgsForCall []struct {
 		arg1 types.ParticipantCloseReason
 	}
+	IssuedAttributesStub        func() map[string]string
+	issuedAttributesMutex       sync.RWMutex
+	issuedAttributesArgsForCall []struct {
+	}
+	issuedAttributesReturns struct {
+		result1 map[string]string
+	}
+	issuedAttributesReturnsOnCall map[int]struct {
+		result1 map[string]string
+	}
 	KindStub        func() livekit.ParticipantInfo_Kind
 	kindMutex       sync.RWMutex
 	kindArgsForCall []struct {
@@ -1263,6 +1295,17 @@ type FakeLocalParticipant struct {
 		result1 *livekit.SubscriptionPermission
 		result2 utils.TimedVersion
 	}
+	SubscriptionRulesAllowStub        func(map[string]string) bool
+	subscriptionRulesAllowMutex       sync.RWMutex
+	subscriptionRulesAllowArgsForCall []struct {
+		arg1 map[string]string
+	}
+	subscriptionRulesAllowReturns struct {
+		result1 bool
+	}
+	subscriptionRulesAllowReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	SupportsCodecChangeStub        func() bool
 	supportsCodecChangeMutex       sync.RWMutex
 	supportsCodecChangeArgsForCall []struct {
@@ -4280,6 +4323,59 @@ func (fake *FakeLocalParticipant) GetTelemetryListenerReturnsOnCall(i int, resul
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) GetTimeline() utilsa.TimelineRecorder {
+	fake.getTimelineMutex.Lock()
+	ret, specificReturn := fake.getTimelineReturnsOnCall[len(fake.getTimelineArgsForCall)]
+	fake.getTimelineArgsForCall = append(fake.getTimelineArgsForCall, struct {
+	}{})
+	stub := fake.GetTimelineStub
+	fakeReturns := fake.getTimelineReturns
+	fake.recordInvocation("GetTimeline", []interface{}{})
+	fake.getTimelineMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) GetTimelineCallCount() int {
+	fake.getTimelineMutex.RLock()
+	defer fake.getTimelineMutex.RUnlock()
+	return len(fake.getTimelineArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) GetTimelineCalls(stub func() utilsa.TimelineRecorder) {
+	fake.getTimelineMutex.Lock()
+	defer fake.getTimelineMutex.Unlock()
+	fake.GetTimelineStub = stub
+}
+
+func (fake *FakeLocalParticipant) GetTimelineReturns(result1 utilsa.TimelineRecorder) {
+	fake.getTimelineMutex.Lock()
+	defer fake.getTimelineMutex.Unlock()
+	fake.GetTimelineStub = nil
+	fake.getTimelineReturns = struct {
+		result1 utilsa.TimelineRecorder
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) GetTimelineReturnsOnCall(i int, result1 utilsa.TimelineRecorder) {
+	fake.getTimelineMutex.Lock()
+	defer fake.getTimelineMutex.Unlock()
+	fake.GetTimelineStub = nil
+	if fake.getTimelineReturnsOnCall == nil {
+		fake.getTimelineReturnsOnCall = make(map[int]struct {
+			result1 utilsa.TimelineRecorder
+		})
+	}
+	fake.getTimelineReturnsOnCall[i] = struct {
+		result1 utilsa.TimelineRecorder
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) GetTrailer() []byte {
 	fake.getTrailerMutex.Lock()
 	ret, specificReturn := fake.getTrailerReturnsOnCall[len(fake.getTrailerArgsForCall)]
@@ -5272,19 +5368,20 @@ func (fake *FakeLocalParticipant) HasConnectedReturnsOnCall(i int, result1 bool)
 	}{result1}
 }
 
-func (fake *FakeLocalParticipant) HasPermission(arg1 livekit.TrackID, arg2 livekit.ParticipantIdentity) bool {
+func (fake *FakeLocalParticipant) HasPermission(arg1 livekit.TrackID, arg2 livekit.ParticipantIdentity, arg3 map[string]string) bool {
 	fake.hasPermissionMutex.Lock()
 	ret, specificReturn := fake.hasPermissionReturnsOnCall[len(fake.hasPermissionArgsForCall)]
 	fake.hasPermissionArgsForCall = append(fake.hasPermissionArgsForCall, struct {
 		arg1 livekit.TrackID
 		arg2 livekit.ParticipantIdentity
-	}{arg1, arg2})
+		arg3 map[string]string
+	}{arg1, arg2, arg3})
 	stub := fake.HasPermissionStub
 	fakeReturns := fake.hasPermissionReturns
-	fake.recordInvocation("HasPermission", []interface{}{arg1, arg2})
+	fake.recordInvocation("HasPermission", []interface{}{arg1, arg2, arg3})
 	fake.hasPermissionMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -5298,17 +5395,17 @@ func (fake *FakeLocalParticipant) HasPermissionCallCount() int {
 	return len(fake.hasPermissionArgsForCall)
 }
 
-func (fake *FakeLocalParticipant) HasPermissionCalls(stub func(livekit.TrackID, livekit.ParticipantIdentity) bool) {
+func (fake *FakeLocalParticipant) HasPermissionCalls(stub func(livekit.TrackID, livekit.ParticipantIdentity, map[string]string) bool) {
 	fake.hasPermissionMutex.Lock()
 	defer fake.hasPermissionMutex.Unlock()
 	fake.HasPermissionStub = stub
 }
 
-func (fake *FakeLocalParticipant) HasPermissionArgsForCall(i int) (livekit.TrackID, livekit.ParticipantIdentity) {
+func (fake *FakeLocalParticipant) HasPermissionArgsForCall(i int) (livekit.TrackID, livekit.ParticipantIdentity, map[string]string) {
 	fake.hasPermissionMutex.RLock()
 	defer fake.hasPermissionMutex.RUnlock()
 	argsForCall := fake.hasPermissionArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeLocalParticipant) HasPermissionReturns(result1 bool) {
@@ -6002,6 +6099,59 @@ func (fake *FakeLocalParticipant) IsRecorderReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) IsRelayOnly() bool {
+	fake.isRelayOnlyMutex.Lock()
+	ret, specificReturn := fake.isRelayOnlyReturnsOnCall[len(fake.isRelayOnlyArgsForCall)]
+	fake.isRelayOnlyArgsForCall = append(fake.isRelayOnlyArgsForCall, struct {
+	}{})
+	stub := fake.IsRelayOnlyStub
+	fakeReturns := fake.isRelayOnlyReturns
+	fake.recordInvocation("IsRelayOnly", []interface{}{})
+	fake.isRelayOnlyMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) IsRelayOnlyCallCount() int {
+	fake.isRelayOnlyMutex.RLock()
+	defer fake.isRelayOnlyMutex.RUnlock()
+	return len(fake.isRelayOnlyArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) IsRelayOnlyCalls(stub func() bool) {
+	fake.isRelayOnlyMutex.Lock()
+	defer fake.isRelayOnlyMutex.Unlock()
+	fake.IsRelayOnlyStub = stub
+}
+
+func (fake *FakeLocalParticipant) IsRelayOnlyReturns(result1 bool) {
+	fake.isRelayOnlyMutex.Lock()
+	defer fake.isRelayOnlyMutex.Unlock()
+	fake.IsRelayOnlyStub = nil
+	fake.isRelayOnlyReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) IsRelayOnlyReturnsOnCall(i int, result1 bool) {
+	fake.isRelayOnlyMutex.Lock()
+	defer fake.isRelayOnlyMutex.Unlock()
+	fake.IsRelayOnlyStub = nil
+	if fake.isRelayOnlyReturnsOnCall == nil {
+		fake.isRelayOnlyReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isRelayOnlyReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) IsSubscribedTo(arg1 livekit.ParticipantID) bool {
 	fake.isSubscribedToMutex.Lock()
 	ret, specificReturn := fake.isSubscribedToReturnsOnCall[len(fake.isSubscribedToArgsForCall)]
@@ -6210,6 +6360,59 @@ func (fake *FakeLocalParticipant) IssueFullReconnectArgsForCall(i int) types.Par
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) IssuedAttributes() map[string]string {
+	fake.issuedAttributesMutex.Lock()
+	ret, specificReturn := fake.issuedAttributesReturnsOnCall[len(fake.issuedAttributesArgsForCall)]
+	fake.issuedAttributesArgsForCall = append(fake.issuedAttributesArgsForCall, struct {
+	}{})
+	stub := fake.IssuedAttributesStub
+	fakeReturns := fake.issuedAttributesReturns
+	fake.recordInvocation("IssuedAttributes", []interface{}{})
+	fake.issuedAttributesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) IssuedAttributesCallCount() int {
+	fake.issuedAttributesMutex.RLock()
+	defer fake.issuedAttributesMutex.RUnlock()
+	return len(fake.issuedAttributesArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) IssuedAttributesCalls(stub func() map[string]string) {
+	fake.issuedAttributesMutex.Lock()
+	defer fake.issuedAttributesMutex.Unlock()
+	fake.IssuedAttributesStub = stub
+}
+
+func (fake *FakeLocalParticipant) IssuedAttributesReturns(result1 map[string]string) {
+	fake.issuedAttributesMutex.Lock()
+	defer fake.issuedAttributesMutex.Unlock()
+	fake.IssuedAttributesStub = nil
+	fake.issuedAttributesReturns = struct {
+		result1 map[string]string
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) IssuedAttributesReturnsOnCall(i int, result1 map[string]string) {
+	fake.issuedAttributesMutex.Lock()
+	defer fake.issuedAttributesMutex.Unlock()
+	fake.IssuedAttributesStub = nil
+	if fake.issuedAttributesReturnsOnCall == nil {
+		fake.issuedAttributesReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+		})
+	}
+	fake.issuedAttributesReturnsOnCall[i] = struct {
+		result1 map[string]string
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) Kind() livekit.ParticipantInfo_Kind {
 	fake.kindMutex.Lock()
 	ret, specificReturn := fake.kindReturnsOnCall[len(fake.kindArgsForCall)]
@@ -8211,6 +8414,67 @@ func (fake *FakeLocalParticipant) SubscriptionPermissionReturnsOnCall(i int, res
 	}{result1, result2}
 }
 
+func (fake *FakeLocalParticipant) SubscriptionRulesAllow(arg1 map[string]string) bool {
+	fake.subscriptionRulesAllowMutex.Lock()
+	ret, specificReturn := fake.subscriptionRulesAllowReturnsOnCall[len(fake.subscriptionRulesAllowArgsForCall)]
+	fake.subscriptionRulesAllowArgsForCall = append(fake.subscriptionRulesAllowArgsForCall, struct {
+		arg1 map[string]string
+	}{arg1})
+	stub := fake.SubscriptionRulesAllowStub
+	fakeReturns := fake.subscriptionRulesAllowReturns
+	fake.recordInvocation("SubscriptionRulesAllow", []interface{}{arg1})
+	fake.subscriptionRulesAllowMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) SubscriptionRulesAllowCallCount() int {
+	fake.subscriptionRulesAllowMutex.RLock()
+	defer fake.subscriptionRulesAllowMutex.RUnlock()
+	return len(fake.subscriptionRulesAllowArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SubscriptionRulesAllowCalls(stub func(map[string]string) bool) {
+	fake.subscriptionRulesAllowMutex.Lock()
+	defer fake.subscriptionRulesAllowMutex.Unlock()
+	fake.SubscriptionRulesAllowStub = stub
+}
+
+func (fake *FakeLocalParticipant) SubscriptionRulesAllowArgsForCall(i int) map[string]string {
+	fake.subscriptionRulesAllowMutex.RLock()
+	defer fake.subscriptionRulesAllowMutex.RUnlock()
+	argsForCall := fake.subscriptionRulesAllowArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) SubscriptionRulesAllowReturns(result1 bool) {
+	fake.subscriptionRulesAllowMutex.Lock()
+	defer fake.subscriptionRulesAllowMutex.Unlock()
+	fake.SubscriptionRulesAllowStub = nil
+	fake.subscriptionRulesAllowReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) SubscriptionRulesAllowReturnsOnCall(i int, result1 bool) {
+	fake.subscriptionRulesAllowMutex.Lock()
+	defer fake.subscriptionRulesAllowMutex.Unlock()
+	fake.SubscriptionRulesAllowStub = nil
+	if fake.subscriptionRulesAllowReturnsOnCall == nil {
+		fake.subscriptionRulesAllowReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.subscriptionRulesAllowReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) SupportsCodecChange() bool {
 	fake.supportsCodecChangeMutex.Lock()
 	ret, specificReturn := fake.supportsCodecChangeReturnsOnCall[len(fake.supportsCodecChangeArgsForCall)]
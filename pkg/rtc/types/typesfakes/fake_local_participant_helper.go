@@ -20,6 +20,17 @@ type FakeLocalParticipantHelper struct {
 	getCachedReliableDataMessageReturnsOnCall map[int]struct {
 		result1 []*types.DataMessageCache
 	}
+	GetDataHistoryStub        func(types.LocalParticipant) []*types.DataMessageCache
+	getDataHistoryMutex       sync.RWMutex
+	getDataHistoryArgsForCall []struct {
+		arg1 types.LocalParticipant
+	}
+	getDataHistoryReturns struct {
+		result1 []*types.DataMessageCache
+	}
+	getDataHistoryReturnsOnCall map[int]struct {
+		result1 []*types.DataMessageCache
+	}
 	GetParticipantInfoStub        func(livekit.ParticipantID) *livekit.ParticipantInfo
 	getParticipantInfoMutex       sync.RWMutex
 	getParticipantInfoArgsForCall []struct {
@@ -154,6 +165,67 @@ func (fake *FakeLocalParticipantHelper) GetCachedReliableDataMessageReturnsOnCal
 	}{result1}
 }
 
+func (fake *FakeLocalParticipantHelper) GetDataHistory(arg1 types.LocalParticipant) []*types.DataMessageCache {
+	fake.getDataHistoryMutex.Lock()
+	ret, specificReturn := fake.getDataHistoryReturnsOnCall[len(fake.getDataHistoryArgsForCall)]
+	fake.getDataHistoryArgsForCall = append(fake.getDataHistoryArgsForCall, struct {
+		arg1 types.LocalParticipant
+	}{arg1})
+	stub := fake.GetDataHistoryStub
+	fakeReturns := fake.getDataHistoryReturns
+	fake.recordInvocation("GetDataHistory", []interface{}{arg1})
+	fake.getDataHistoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipantHelper) GetDataHistoryCallCount() int {
+	fake.getDataHistoryMutex.RLock()
+	defer fake.getDataHistoryMutex.RUnlock()
+	return len(fake.getDataHistoryArgsForCall)
+}
+
+func (fake *FakeLocalParticipantHelper) GetDataHistoryCalls(stub func(types.LocalParticipant) []*types.DataMessageCache) {
+	fake.getDataHistoryMutex.Lock()
+	defer fake.getDataHistoryMutex.Unlock()
+	fake.GetDataHistoryStub = stub
+}
+
+func (fake *FakeLocalParticipantHelper) GetDataHistoryArgsForCall(i int) types.LocalParticipant {
+	fake.getDataHistoryMutex.RLock()
+	defer fake.getDataHistoryMutex.RUnlock()
+	argsForCall := fake.getDataHistoryArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipantHelper) GetDataHistoryReturns(result1 []*types.DataMessageCache) {
+	fake.getDataHistoryMutex.Lock()
+	defer fake.getDataHistoryMutex.Unlock()
+	fake.GetDataHistoryStub = nil
+	fake.getDataHistoryReturns = struct {
+		result1 []*types.DataMessageCache
+	}{result1}
+}
+
+func (fake *FakeLocalParticipantHelper) GetDataHistoryReturnsOnCall(i int, result1 []*types.DataMessageCache) {
+	fake.getDataHistoryMutex.Lock()
+	defer fake.getDataHistoryMutex.Unlock()
+	fake.GetDataHistoryStub = nil
+	if fake.getDataHistoryReturnsOnCall == nil {
+		fake.getDataHistoryReturnsOnCall = make(map[int]struct {
+			result1 []*types.DataMessageCache
+		})
+	}
+	fake.getDataHistoryReturnsOnCall[i] = struct {
+		result1 []*types.DataMessageCache
+	}{result1}
+}
+
 func (fake *FakeLocalParticipantHelper) GetParticipantInfo(arg1 livekit.ParticipantID) *livekit.ParticipantInfo {
 	fake.getParticipantInfoMutex.Lock()
 	ret, specificReturn := fake.getParticipantInfoReturnsOnCall[len(fake.getParticipantInfoArgsForCall)]
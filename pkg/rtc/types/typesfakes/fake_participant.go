@@ -147,11 +147,12 @@ type FakeParticipant struct {
 		arg2 *datatrack.Packet
 		arg3 int64
 	}
-	HasPermissionStub        func(livekit.TrackID, livekit.ParticipantIdentity) bool
+	HasPermissionStub        func(livekit.TrackID, livekit.ParticipantIdentity, map[string]string) bool
 	hasPermissionMutex       sync.RWMutex
 	hasPermissionArgsForCall []struct {
 		arg1 livekit.TrackID
 		arg2 livekit.ParticipantIdentity
+		arg3 map[string]string
 	}
 	hasPermissionReturns struct {
 		result1 bool
@@ -312,6 +313,17 @@ type FakeParticipant struct {
 		result1 *livekit.SubscriptionPermission
 		result2 utils.TimedVersion
 	}
+	SubscriptionRulesAllowStub        func(map[string]string) bool
+	subscriptionRulesAllowMutex       sync.RWMutex
+	subscriptionRulesAllowArgsForCall []struct {
+		arg1 map[string]string
+	}
+	subscriptionRulesAllowReturns struct {
+		result1 bool
+	}
+	subscriptionRulesAllowReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	ToProtoStub        func() *livekit.ParticipantInfo
 	toProtoMutex       sync.RWMutex
 	toProtoArgsForCall []struct {
@@ -1065,19 +1077,20 @@ func (fake *FakeParticipant) HandleReceivedDataTrackMessageArgsForCall(i int) ([
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeParticipant) HasPermission(arg1 livekit.TrackID, arg2 livekit.ParticipantIdentity) bool {
+func (fake *FakeParticipant) HasPermission(arg1 livekit.TrackID, arg2 livekit.ParticipantIdentity, arg3 map[string]string) bool {
 	fake.hasPermissionMutex.Lock()
 	ret, specificReturn := fake.hasPermissionReturnsOnCall[len(fake.hasPermissionArgsForCall)]
 	fake.hasPermissionArgsForCall = append(fake.hasPermissionArgsForCall, struct {
 		arg1 livekit.TrackID
 		arg2 livekit.ParticipantIdentity
-	}{arg1, arg2})
+		arg3 map[string]string
+	}{arg1, arg2, arg3})
 	stub := fake.HasPermissionStub
 	fakeReturns := fake.hasPermissionReturns
-	fake.recordInvocation("HasPermission", []interface{}{arg1, arg2})
+	fake.recordInvocation("HasPermission", []interface{}{arg1, arg2, arg3})
 	fake.hasPermissionMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1091,17 +1104,17 @@ func (fake *FakeParticipant) HasPermissionCallCount() int {
 	return len(fake.hasPermissionArgsForCall)
 }
 
-func (fake *FakeParticipant) HasPermissionCalls(stub func(livekit.TrackID, livekit.ParticipantIdentity) bool) {
+func (fake *FakeParticipant) HasPermissionCalls(stub func(livekit.TrackID, livekit.ParticipantIdentity, map[string]string) bool) {
 	fake.hasPermissionMutex.Lock()
 	defer fake.hasPermissionMutex.Unlock()
 	fake.HasPermissionStub = stub
 }
 
-func (fake *FakeParticipant) HasPermissionArgsForCall(i int) (livekit.TrackID, livekit.ParticipantIdentity) {
+func (fake *FakeParticipant) HasPermissionArgsForCall(i int) (livekit.TrackID, livekit.ParticipantIdentity, map[string]string) {
 	fake.hasPermissionMutex.RLock()
 	defer fake.hasPermissionMutex.RUnlock()
 	argsForCall := fake.hasPermissionArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeParticipant) HasPermissionReturns(result1 bool) {
@@ -1937,6 +1950,67 @@ func (fake *FakeParticipant) SubscriptionPermissionReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *FakeParticipant) SubscriptionRulesAllow(arg1 map[string]string) bool {
+	fake.subscriptionRulesAllowMutex.Lock()
+	ret, specificReturn := fake.subscriptionRulesAllowReturnsOnCall[len(fake.subscriptionRulesAllowArgsForCall)]
+	fake.subscriptionRulesAllowArgsForCall = append(fake.subscriptionRulesAllowArgsForCall, struct {
+		arg1 map[string]string
+	}{arg1})
+	stub := fake.SubscriptionRulesAllowStub
+	fakeReturns := fake.subscriptionRulesAllowReturns
+	fake.recordInvocation("SubscriptionRulesAllow", []interface{}{arg1})
+	fake.subscriptionRulesAllowMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeParticipant) SubscriptionRulesAllowCallCount() int {
+	fake.subscriptionRulesAllowMutex.RLock()
+	defer fake.subscriptionRulesAllowMutex.RUnlock()
+	return len(fake.subscriptionRulesAllowArgsForCall)
+}
+
+func (fake *FakeParticipant) SubscriptionRulesAllowCalls(stub func(map[string]string) bool) {
+	fake.subscriptionRulesAllowMutex.Lock()
+	defer fake.subscriptionRulesAllowMutex.Unlock()
+	fake.SubscriptionRulesAllowStub = stub
+}
+
+func (fake *FakeParticipant) SubscriptionRulesAllowArgsForCall(i int) map[string]string {
+	fake.subscriptionRulesAllowMutex.RLock()
+	defer fake.subscriptionRulesAllowMutex.RUnlock()
+	argsForCall := fake.subscriptionRulesAllowArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeParticipant) SubscriptionRulesAllowReturns(result1 bool) {
+	fake.subscriptionRulesAllowMutex.Lock()
+	defer fake.subscriptionRulesAllowMutex.Unlock()
+	fake.SubscriptionRulesAllowStub = nil
+	fake.subscriptionRulesAllowReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeParticipant) SubscriptionRulesAllowReturnsOnCall(i int, result1 bool) {
+	fake.subscriptionRulesAllowMutex.Lock()
+	defer fake.subscriptionRulesAllowMutex.Unlock()
+	fake.SubscriptionRulesAllowStub = nil
+	if fake.subscriptionRulesAllowReturnsOnCall == nil {
+		fake.subscriptionRulesAllowReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.subscriptionRulesAllowReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeParticipant) ToProto() *livekit.ParticipantInfo {
 	fake.toProtoMutex.Lock()
 	ret, specificReturn := fake.toProtoReturnsOnCall[len(fake.toProtoArgsForCall)]
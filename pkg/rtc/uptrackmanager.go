@@ -51,6 +51,9 @@ type UpTrackManager struct {
 	subscriptionPermission *livekit.SubscriptionPermission
 	// subscriber permission for published tracks
 	subscriberPermissions map[livekit.ParticipantIdentity]*livekit.TrackPermission // subscriberIdentity => *livekit.TrackPermission
+	// attribute based rules set by the participant, nil when not restricted by attributes
+	subscriptionRules   []SubscriptionRule
+	publisherAttributes map[string]string
 
 	lock sync.RWMutex
 
@@ -228,11 +231,26 @@ func (u *UpTrackManager) SubscriptionPermission() (*livekit.SubscriptionPermissi
 	return u.subscriptionPermission, u.subscriptionPermissionVersion.Load()
 }
 
-func (u *UpTrackManager) HasPermission(trackID livekit.TrackID, subIdentity livekit.ParticipantIdentity) bool {
+// UpdateSubscriptionRules sets the attribute based subscription rules from the attributes of the participant
+func (u *UpTrackManager) UpdateSubscriptionRules(attributes map[string]string) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.parseSubscriptionRulesLocked(attributes)
+}
+
+func (u *UpTrackManager) HasPermission(trackID livekit.TrackID, subIdentity livekit.ParticipantIdentity, subAttributes map[string]string) bool {
+	u.lock.RLock()
+	defer u.lock.RUnlock()
+
+	return u.hasPermissionLocked(trackID, subIdentity) && u.subscriptionRulesAllowLocked(subAttributes)
+}
+
+func (u *UpTrackManager) SubscriptionRulesAllow(subAttributes map[string]string) bool {
 	u.lock.RLock()
 	defer u.lock.RUnlock()
 
-	return u.hasPermissionLocked(trackID, subIdentity)
+	return u.subscriptionRulesAllowLocked(subAttributes)
 }
 
 func (u *UpTrackManager) UpdatePublishedAudioTrack(update *livekit.UpdateLocalAudioTrack) types.MediaTrack {
@@ -349,6 +367,15 @@ func (u *UpTrackManager) parseSubscriptionPermissionsLocked(
 	return nil
 }
 
+func (u *UpTrackManager) parseSubscriptionRulesLocked(attributes map[string]string) {
+	u.subscriptionRules = parseSubscriptionRules(attributes)
+	u.publisherAttributes = maps.Clone(attributes)
+}
+
+func (u *UpTrackManager) subscriptionRulesAllowLocked(subAttributes map[string]string) bool {
+	return subscriptionRulesMatch(u.subscriptionRules, u.publisherAttributes, subAttributes)
+}
+
 func (u *UpTrackManager) hasPermissionLocked(trackID livekit.TrackID, subscriberIdentity livekit.ParticipantIdentity) bool {
 	if u.subscriberPermissions == nil {
 		return true
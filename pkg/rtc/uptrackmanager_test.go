@@ -327,3 +327,37 @@ func TestSubscriptionPermission(t *testing.T) {
 		require.False(t, um.hasPermissionLocked("watch", "p3"))
 	})
 }
+
+func TestSubscriptionRulesPermission(t *testing.T) {
+	um := NewUpTrackManager(defaultUptrackManagerParams)
+	vg := utils.NewDefaultTimedVersionGenerator()
+
+	// not restricted without rules
+	require.True(t, um.HasPermission("audio", "p1", nil))
+
+	um.UpdateSubscriptionRules(map[string]string{
+		"team":                     "a",
+		SubscriptionRulesAttribute: `[{"attribute":"team","sameAsPublisher":true},{"attribute":"role","in":["host"]}]`,
+	})
+	require.True(t, um.HasPermission("audio", "p1", map[string]string{"team": "a"}))
+	require.True(t, um.HasPermission("audio", "p1", map[string]string{"role": "host"}))
+	require.False(t, um.HasPermission("audio", "p1", map[string]string{"team": "b"}))
+	require.False(t, um.HasPermission("audio", "p1", nil))
+	require.True(t, um.SubscriptionRulesAllow(map[string]string{"team": "a"}))
+	require.False(t, um.SubscriptionRulesAllow(map[string]string{"team": "b"}))
+
+	// both identity based permissions and rules have to allow the subscriber
+	um.UpdateSubscriptionPermission(&livekit.SubscriptionPermission{
+		TrackPermissions: []*livekit.TrackPermission{{ParticipantIdentity: "p1", AllTracks: true}},
+	}, vg.Next(), nil)
+	require.True(t, um.HasPermission("audio", "p1", map[string]string{"team": "a"}))
+	require.False(t, um.HasPermission("audio", "p2", map[string]string{"team": "a"}))
+	require.False(t, um.HasPermission("audio", "p1", map[string]string{"team": "b"}))
+
+	// invalid rules do not allow anyone
+	um.UpdateSubscriptionRules(map[string]string{SubscriptionRulesAttribute: "invalid"})
+	require.False(t, um.HasPermission("audio", "p1", map[string]string{"team": "a"}))
+
+	um.UpdateSubscriptionRules(nil)
+	require.True(t, um.HasPermission("audio", "p1", nil))
+}
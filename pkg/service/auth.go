@@ -31,6 +31,7 @@ const (
 	authorizationHeader = "Authorization"
 	bearerPrefix        = "Bearer "
 	accessTokenParam    = "access_token"
+	twirpPathPrefix     = "/twirp/"
 )
 
 type grantsKey struct{}
@@ -46,8 +47,19 @@ var (
 	ErrMissingAuthorization      = errors.New("invalid authorization header. Must start with " + bearerPrefix)
 	ErrInvalidAuthorizationToken = errors.New("invalid authorization token")
 	ErrInvalidAPIKey             = errors.New("invalid API key")
+	ErrClientCertRequired        = errors.New("client certificate required")
 )
 
+// RequireClientCertificate rejects requests to the server APIs without a verified client certificate,
+// other endpoints are used by clients without certificates
+func RequireClientCertificate(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if strings.HasPrefix(r.URL.Path, twirpPathPrefix) && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+		HandleError(w, r, http.StatusUnauthorized, ErrClientCertRequired)
+		return
+	}
+	next(w, r)
+}
+
 // authentication middleware
 type APIKeyAuthMiddleware struct {
 	provider auth.KeyProvider
@@ -86,13 +98,17 @@ func (m *APIKeyAuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request,
 			return
 		}
 
-		secret := m.provider.GetSecret(v.APIKey())
-		if secret == "" {
+		secrets := getVerificationSecrets(m.provider, v.APIKey())
+		if len(secrets) == 0 {
 			HandleError(w, r, http.StatusUnauthorized, errors.New("invalid API key: "+v.APIKey()))
 			return
 		}
 
-		claims, grants, err := v.Verify(secret)
+		// a key being rotated has several valid secrets, any of them is accepted
+		claims, grants, err := v.Verify(secrets[0])
+		for i := 1; err != nil && i < len(secrets); i++ {
+			claims, grants, err = v.Verify(secrets[i])
+		}
 		if err != nil {
 			HandleError(w, r, http.StatusUnauthorized, errors.New("invalid token: "+authToken+", error: "+err.Error()))
 			return
@@ -15,15 +15,19 @@
 package service_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/auth/authfakes"
 
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/service"
 )
 
@@ -72,3 +76,71 @@ func TestAuthMiddleware(t *testing.T) {
 	require.Nil(t, grants)
 	require.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func TestAuthMiddleware_RotatedSecrets(t *testing.T) {
+	api := "APIabcdefg"
+	oldSecret := "oldsecretencodedinbase62extendto32bytes"
+	newSecret := "newsecretencodedinbase62extendto32bytes"
+	expiredSecret := "expiredsecretencodedinbase62extendto32b"
+	now := time.Now()
+	provider := service.NewReloadableKeyProvider(&config.Config{
+		KeySecrets: map[string][]config.KeySecret{
+			api: {
+				{Secret: expiredSecret, NotAfter: now.Add(-time.Minute)},
+				{Secret: oldSecret, NotAfter: now.Add(time.Hour)},
+				{Secret: newSecret, NotBefore: now.Add(-time.Minute)},
+			},
+		},
+	})
+	// signing uses the newest secret
+	require.Equal(t, newSecret, provider.GetSecret(api))
+	require.Equal(t, []string{newSecret, oldSecret}, provider.GetSecrets(api))
+	// the old secret expires next
+	require.True(t, provider.NextSecretChange(api).Equal(now.Add(time.Hour)))
+
+	m := service.NewAPIKeyAuthMiddleware(provider)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for secret, code := range map[string]int{
+		oldSecret:     http.StatusOK,
+		newSecret:     http.StatusOK,
+		expiredSecret: http.StatusUnauthorized,
+	} {
+		token, err := auth.NewAccessToken(api, secret).
+			AddGrant(&auth.VideoGrant{Room: "abcdefg", RoomJoin: true}).
+			ToJWT()
+		require.NoError(t, err)
+
+		r := &http.Request{Header: http.Header{}}
+		w := httptest.NewRecorder()
+		service.SetAuthorizationToken(r, token)
+		m.ServeHTTP(w, r, handler)
+		require.Equal(t, code, w.Code)
+	}
+}
+
+func TestRequireClientCertificate(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+
+	for _, tc := range []struct {
+		path  string
+		state *tls.ConnectionState
+		code  int
+	}{
+		{path: "/twirp/livekit.RoomService/ListRooms", state: verified, code: http.StatusOK},
+		{path: "/twirp/livekit.RoomService/ListRooms", state: &tls.ConnectionState{}, code: http.StatusUnauthorized},
+		{path: "/twirp/livekit.RoomService/ListRooms", code: http.StatusUnauthorized},
+		{path: "/rtc", state: &tls.ConnectionState{}, code: http.StatusOK},
+	} {
+		r := httptest.NewRequest(http.MethodPost, tc.path, nil)
+		r.TLS = tc.state
+		w := httptest.NewRecorder()
+		service.RequireClientCertificate(w, r, handler)
+		require.Equal(t, tc.code, w.Code, tc.path)
+	}
+}
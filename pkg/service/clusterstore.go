@@ -0,0 +1,402 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/utils"
+	"github.com/livekit/protocol/utils/guid"
+	"github.com/livekit/psrpc"
+
+	"github.com/livekit/livekit-server/pkg/cluster"
+	"github.com/livekit/livekit-server/pkg/rtc"
+)
+
+var _ ObjectStore = (*ClusterStore)(nil)
+var _ AgentStore = (*ClusterStore)(nil)
+
+// ClusterStore keeps room state in the replicated store of an embedded cluster, using the same keys and
+// encodings as RedisStore
+type ClusterStore struct {
+	s *cluster.Store
+}
+
+func NewClusterStore(c *cluster.Cluster) *ClusterStore {
+	return &ClusterStore{
+		s: c.Store(),
+	}
+}
+
+func (s *ClusterStore) StoreRoom(ctx context.Context, room *livekit.Room, internal *livekit.RoomInternal) error {
+	if room.CreationTime == 0 {
+		now := time.Now()
+		room.CreationTime = now.Unix()
+		room.CreationTimeMs = now.UnixMilli()
+	}
+
+	roomData, err := proto.Marshal(room)
+	if err != nil {
+		return err
+	}
+
+	cmds := []cluster.Command{cluster.HSet(RoomsKey, room.Name, roomData)}
+	if internal != nil {
+		internalData, err := proto.Marshal(internal)
+		if err != nil {
+			return err
+		}
+		cmds = append(cmds, cluster.HSet(RoomInternalKey, room.Name, internalData))
+	} else {
+		cmds = append(cmds, cluster.HDel(RoomInternalKey, room.Name))
+	}
+
+	if _, err = s.s.Apply(ctx, cmds...); err != nil {
+		return errors.Wrap(err, "could not create room")
+	}
+	return nil
+}
+
+func (s *ClusterStore) LoadRoom(_ context.Context, roomName livekit.RoomName, includeInternal bool) (*livekit.Room, *livekit.RoomInternal, error) {
+	roomData, ok := s.s.HGet(RoomsKey, string(roomName))
+	if !ok {
+		return nil, nil, ErrRoomNotFound
+	}
+	room := &livekit.Room{}
+	if err := proto.Unmarshal(roomData, room); err != nil {
+		return nil, nil, err
+	}
+
+	var internal *livekit.RoomInternal
+	if includeInternal {
+		if internalData, ok := s.s.HGet(RoomInternalKey, string(roomName)); ok {
+			internal = &livekit.RoomInternal{}
+			if err := proto.Unmarshal(internalData, internal); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return room, internal, nil
+}
+
+func (s *ClusterStore) RoomExists(_ context.Context, roomName livekit.RoomName) (bool, error) {
+	_, ok := s.s.HGet(RoomsKey, string(roomName))
+	return ok, nil
+}
+
+func (s *ClusterStore) ListRooms(_ context.Context, roomNames []livekit.RoomName) ([]*livekit.Room, error) {
+	var items [][]byte
+	if roomNames == nil {
+		for _, item := range s.s.HGetAll(RoomsKey) {
+			items = append(items, item)
+		}
+	} else {
+		for _, item := range s.s.HMGet(RoomsKey, livekit.IDsAsStrings(roomNames)...) {
+			if item != nil {
+				items = append(items, item)
+			}
+		}
+	}
+
+	rooms := make([]*livekit.Room, 0, len(items))
+	for _, item := range items {
+		room := livekit.Room{}
+		if err := proto.Unmarshal(item, &room); err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, &room)
+	}
+	return rooms, nil
+}
+
+func (s *ClusterStore) DeleteRoom(ctx context.Context, roomName livekit.RoomName) error {
+	if _, ok := s.s.HGet(RoomsKey, string(roomName)); !ok {
+		return nil
+	}
+
+	_, err := s.s.Apply(ctx,
+		cluster.HDel(RoomsKey, string(roomName)),
+		cluster.HDel(RoomInternalKey, string(roomName)),
+		cluster.Del(RoomParticipantsPrefix+string(roomName)),
+		cluster.Del(AgentDispatchPrefix+string(roomName)),
+		cluster.Del(AgentJobPrefix+string(roomName)),
+	)
+	return err
+}
+
+func (s *ClusterStore) LockRoom(ctx context.Context, roomName livekit.RoomName, duration time.Duration) (string, error) {
+	token := guid.New("LOCK")
+	key := RoomLockPrefix + string(roomName)
+
+	startTime := time.Now()
+	for {
+		res, err := s.s.Apply(ctx, cluster.SetNX(key, []byte(token), duration))
+		if err != nil {
+			return "", err
+		}
+		if res[0] {
+			return token, nil
+		}
+
+		// stop waiting past lock duration
+		if time.Since(startTime) > duration {
+			break
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return "", ErrRoomLockFailed
+}
+
+func (s *ClusterStore) UnlockRoom(ctx context.Context, roomName livekit.RoomName, uid string) error {
+	key := RoomLockPrefix + string(roomName)
+	res, err := s.s.Apply(ctx, cluster.DelIfEqual(key, []byte(uid)))
+	if err != nil {
+		return err
+	}
+
+	// uid does not match
+	if !res[0] {
+		return ErrRoomUnlockFailed
+	}
+
+	return nil
+}
+
+func (s *ClusterStore) StoreParticipant(ctx context.Context, roomName livekit.RoomName, participant *livekit.ParticipantInfo) error {
+	key := RoomParticipantsPrefix + string(roomName)
+	return clusterStoreOne(ctx, s, key, participant.Identity, participant)
+}
+
+func (s *ClusterStore) LoadParticipant(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error) {
+	key := RoomParticipantsPrefix + string(roomName)
+	return clusterLoadOne[livekit.ParticipantInfo](s, key, string(identity), ErrParticipantNotFound)
+}
+
+func (s *ClusterStore) HasParticipant(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) (bool, error) {
+	p, err := s.LoadParticipant(ctx, roomName, identity)
+	return p != nil, utils.ScreenError(err, ErrParticipantNotFound)
+}
+
+func (s *ClusterStore) ListParticipants(_ context.Context, roomName livekit.RoomName) ([]*livekit.ParticipantInfo, error) {
+	key := RoomParticipantsPrefix + string(roomName)
+	return clusterLoadAll[livekit.ParticipantInfo](s, key)
+}
+
+func (s *ClusterStore) DeleteParticipant(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) error {
+	key := RoomParticipantsPrefix + string(roomName)
+	_, err := s.s.Apply(ctx, cluster.HDel(key, string(identity)))
+	return err
+}
+
+func (s *ClusterStore) StoreRoomSchedule(ctx context.Context, schedule *rtc.RoomSchedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.s.Apply(ctx, cluster.HSet(RoomSchedulesKey, schedule.Room, data))
+	return err
+}
+
+func (s *ClusterStore) LoadRoomSchedule(_ context.Context, roomName livekit.RoomName) (*rtc.RoomSchedule, error) {
+	data, ok := s.s.HGet(RoomSchedulesKey, string(roomName))
+	if !ok {
+		return nil, rtc.ErrRoomScheduleNotFound
+	}
+
+	schedule := &rtc.RoomSchedule{}
+	if err := json.Unmarshal(data, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *ClusterStore) ListRoomSchedules(_ context.Context) ([]*rtc.RoomSchedule, error) {
+	items := s.s.HGetAll(RoomSchedulesKey)
+
+	schedules := make([]*rtc.RoomSchedule, 0, len(items))
+	for _, item := range items {
+		schedule := &rtc.RoomSchedule{}
+		if err := json.Unmarshal(item, schedule); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+func (s *ClusterStore) DeleteRoomSchedule(ctx context.Context, roomName livekit.RoomName) error {
+	_, err := s.s.Apply(ctx, cluster.HDel(RoomSchedulesKey, string(roomName)))
+	return err
+}
+
+func (s *ClusterStore) StoreDataHistory(ctx context.Context, roomName livekit.RoomName, entries []*rtc.DataHistoryEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.s.Apply(ctx, cluster.HSet(RoomDataHistoryKey, string(roomName), data))
+	return err
+}
+
+func (s *ClusterStore) LoadDataHistory(_ context.Context, roomName livekit.RoomName) ([]*rtc.DataHistoryEntry, error) {
+	data, ok := s.s.HGet(RoomDataHistoryKey, string(roomName))
+	if !ok {
+		return nil, rtc.ErrDataHistoryNotFound
+	}
+
+	var entries []*rtc.DataHistoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *ClusterStore) DeleteDataHistory(ctx context.Context, roomName livekit.RoomName) error {
+	_, err := s.s.Apply(ctx, cluster.HDel(RoomDataHistoryKey, string(roomName)))
+	return err
+}
+
+func (s *ClusterStore) StoreAgentDispatch(ctx context.Context, dispatch *livekit.AgentDispatch) error {
+	di := utils.CloneProto(dispatch)
+
+	// Do not store jobs with the dispatch
+	if di.State != nil {
+		di.State.Jobs = nil
+	}
+
+	key := AgentDispatchPrefix + string(dispatch.Room)
+	return clusterStoreOne(ctx, s, key, di.Id, di)
+}
+
+// This will not delete the jobs created by the dispatch
+func (s *ClusterStore) DeleteAgentDispatch(ctx context.Context, dispatch *livekit.AgentDispatch) error {
+	key := AgentDispatchPrefix + string(dispatch.Room)
+	_, err := s.s.Apply(ctx, cluster.HDel(key, dispatch.Id))
+	return err
+}
+
+func (s *ClusterStore) ListAgentDispatches(_ context.Context, roomName livekit.RoomName) ([]*livekit.AgentDispatch, error) {
+	key := AgentDispatchPrefix + string(roomName)
+	dispatches, err := clusterLoadAll[livekit.AgentDispatch](s, key)
+	if err != nil {
+		return nil, err
+	}
+
+	dMap := make(map[string]*livekit.AgentDispatch)
+	for _, di := range dispatches {
+		dMap[di.Id] = di
+	}
+
+	key = AgentJobPrefix + string(roomName)
+	jobs, err := clusterLoadAll[livekit.Job](s, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Associate job to dispatch
+	for _, jb := range jobs {
+		di := dMap[jb.DispatchId]
+		if di == nil {
+			continue
+		}
+		if di.State == nil {
+			di.State = &livekit.AgentDispatchState{}
+		}
+		di.State.Jobs = append(di.State.Jobs, jb)
+	}
+
+	return dispatches, nil
+}
+
+func (s *ClusterStore) StoreAgentJob(ctx context.Context, job *livekit.Job) error {
+	if job.Room == nil {
+		return psrpc.NewErrorf(psrpc.InvalidArgument, "job doesn't have a valid Room field")
+	}
+
+	key := AgentJobPrefix + string(job.Room.Name)
+
+	jb := utils.CloneProto(job)
+
+	// Do not store room with the job
+	jb.Room = nil
+
+	// Only store the participant identity
+	if jb.Participant != nil {
+		jb.Participant = &livekit.ParticipantInfo{
+			Identity: jb.Participant.Identity,
+		}
+	}
+
+	return clusterStoreOne(ctx, s, key, job.Id, jb)
+}
+
+func (s *ClusterStore) DeleteAgentJob(ctx context.Context, job *livekit.Job) error {
+	if job.Room == nil {
+		return psrpc.NewErrorf(psrpc.InvalidArgument, "job doesn't have a valid Room field")
+	}
+
+	key := AgentJobPrefix + string(job.Room.Name)
+	_, err := s.s.Apply(ctx, cluster.HDel(key, job.Id))
+	return err
+}
+
+func clusterStoreOne(ctx context.Context, s *ClusterStore, key, id string, p proto.Message) error {
+	if id == "" {
+		return errors.New("id is not set")
+	}
+	data, err := proto.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = s.s.Apply(ctx, cluster.HSet(key, id, data))
+	return err
+}
+
+func clusterLoadOne[T any, P protoMsg[T]](s *ClusterStore, key, id string, notFoundErr error) (P, error) {
+	data, ok := s.s.HGet(key, id)
+	if !ok {
+		return nil, notFoundErr
+	}
+	var p P = new(T)
+	if err := proto.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func clusterLoadAll[T any, P protoMsg[T]](s *ClusterStore, key string) ([]P, error) {
+	data := s.s.HGetAll(key)
+
+	list := make([]P, 0, len(data))
+	for _, d := range data {
+		var p P = new(T)
+		if err := proto.Unmarshal(d, p); err != nil {
+			return list, err
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/cluster"
+	"github.com/livekit/livekit-server/pkg/service"
+)
+
+func clusterStore(t testing.TB) *service.ClusterStore {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	c, err := cluster.New(cluster.Params{
+		NodeID:          "node",
+		BindAddress:     addr,
+		BootstrapExpect: 1,
+		Secret:          "secret",
+	})
+	require.NoError(t, err)
+	t.Cleanup(c.Stop)
+
+	require.Eventually(t, func() bool {
+		return c.Leader() != ""
+	}, 10*time.Second, 50*time.Millisecond)
+	return service.NewClusterStore(c)
+}
+
+func TestClusterStore(t *testing.T) {
+	ctx := context.Background()
+	s := clusterStore(t)
+
+	t.Run("rooms", func(t *testing.T) {
+		room := &livekit.Room{
+			Sid:  "123",
+			Name: "test_room",
+		}
+		internal := &livekit.RoomInternal{
+			TrackEgress: &livekit.AutoTrackEgress{Filepath: "egress"},
+		}
+
+		require.NoError(t, s.StoreRoom(ctx, room, internal))
+		actualRoom, actualInternal, err := s.LoadRoom(ctx, livekit.RoomName(room.Name), true)
+		require.NoError(t, err)
+		require.Equal(t, room.Sid, actualRoom.Sid)
+		require.Equal(t, internal.TrackEgress.Filepath, actualInternal.TrackEgress.Filepath)
+
+		rooms, err := s.ListRooms(ctx, []livekit.RoomName{"test_room", "other_room"})
+		require.NoError(t, err)
+		require.Len(t, rooms, 1)
+
+		require.NoError(t, s.DeleteRoom(ctx, "test_room"))
+		_, _, err = s.LoadRoom(ctx, "test_room", false)
+		require.Equal(t, service.ErrRoomNotFound, err)
+	})
+
+	t.Run("participants", func(t *testing.T) {
+		roomName := livekit.RoomName("room1")
+		p := &livekit.ParticipantInfo{
+			Sid:      "PA_test",
+			Identity: "test",
+			State:    livekit.ParticipantInfo_ACTIVE,
+		}
+
+		require.NoError(t, s.StoreParticipant(ctx, roomName, p))
+		pGet, err := s.LoadParticipant(ctx, roomName, livekit.ParticipantIdentity(p.Identity))
+		require.NoError(t, err)
+		require.Equal(t, p.Sid, pGet.Sid)
+
+		participants, err := s.ListParticipants(ctx, roomName)
+		require.NoError(t, err)
+		require.Len(t, participants, 1)
+
+		require.NoError(t, s.DeleteParticipant(ctx, roomName, livekit.ParticipantIdentity(p.Identity)))
+		_, err = s.LoadParticipant(ctx, roomName, livekit.ParticipantIdentity(p.Identity))
+		require.Equal(t, service.ErrParticipantNotFound, err)
+	})
+
+	t.Run("locks", func(t *testing.T) {
+		roomName := livekit.RoomName("myroom")
+		lockInterval := 200 * time.Millisecond
+
+		token, err := s.LockRoom(ctx, roomName, lockInterval)
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+		require.Equal(t, service.ErrRoomUnlockFailed, s.UnlockRoom(ctx, roomName, "other"))
+		require.NoError(t, s.UnlockRoom(ctx, roomName, token))
+
+		// lock expires
+		_, err = s.LockRoom(ctx, roomName, lockInterval)
+		require.NoError(t, err)
+		time.Sleep(lockInterval + 10*time.Millisecond)
+		token2, err := s.LockRoom(ctx, roomName, lockInterval)
+		require.NoError(t, err)
+		require.NoError(t, s.UnlockRoom(ctx, roomName, token2))
+	})
+}
@@ -0,0 +1,403 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/webhook"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+)
+
+const configWatchInterval = 5 * time.Second
+
+// ConfigLoader reads and validates the configuration from its source
+type ConfigLoader func() (*config.Config, error)
+
+// ConfigReloader applies configuration changes to a running server. Sections that can be changed live
+// are pushed to the components using them, changes to any other section are logged and ignored until restart.
+type ConfigReloader struct {
+	keyProvider   *ReloadableKeyProvider
+	notifier      *ReloadableNotifier
+	roomAllocator RoomAllocator
+	roomService   *RoomService
+	rtcService    *RTCService
+	whipService   *WHIPService
+	roomManager   *RoomManager
+	currentNode   routing.LocalNode
+	peerFilter    *TURNPeerFilter
+
+	lock      sync.Mutex
+	current   *config.Config
+	loader    ConfigLoader
+	file      string
+	modTime   time.Time
+	onReload  []func(conf *config.Config) error
+	stopChan  chan struct{}
+	isStarted bool
+}
+
+func NewConfigReloader(
+	conf *config.Config,
+	keyProvider *ReloadableKeyProvider,
+	notifier *ReloadableNotifier,
+	roomAllocator RoomAllocator,
+	roomService *RoomService,
+	rtcService *RTCService,
+	whipService *WHIPService,
+	roomManager *RoomManager,
+	currentNode routing.LocalNode,
+	peerFilter *TURNPeerFilter,
+) *ConfigReloader {
+	return &ConfigReloader{
+		keyProvider:   keyProvider,
+		notifier:      notifier,
+		roomAllocator: roomAllocator,
+		roomService:   roomService,
+		rtcService:    rtcService,
+		whipService:   whipService,
+		roomManager:   roomManager,
+		currentNode:   currentNode,
+		peerFilter:    peerFilter,
+		current:       conf,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// SetLoader sets where the configuration is reloaded from, when file is not empty
+// it is watched and changes to it are applied automatically
+func (c *ConfigReloader) SetLoader(loader ConfigLoader, file string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.loader = loader
+	c.file = file
+	if file != "" {
+		if st, err := os.Stat(file); err == nil {
+			c.modTime = st.ModTime()
+		}
+	}
+}
+
+// OnReload registers a callback invoked with the updated config after reloadable sections changed
+func (c *ConfigReloader) OnReload(f func(conf *config.Config) error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.onReload = append(c.onReload, f)
+}
+
+func (c *ConfigReloader) Start() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.isStarted {
+		return
+	}
+	c.isStarted = true
+	go c.watchWorker()
+}
+
+func (c *ConfigReloader) Stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.isStarted {
+		return
+	}
+	c.isStarted = false
+	close(c.stopChan)
+}
+
+// Reload loads the configuration again and applies the changes
+func (c *ConfigReloader) Reload() (config.ConfigDiff, error) {
+	c.lock.Lock()
+	loader := c.loader
+	c.lock.Unlock()
+
+	if loader == nil {
+		return config.ConfigDiff{}, ErrConfigReloadNotSupported
+	}
+	next, err := loader()
+	if err != nil {
+		logger.Warnw("could not load config, keeping current config", err)
+		return config.ConfigDiff{}, err
+	}
+	return c.Apply(next)
+}
+
+// Apply diffs next against the running config and applies the reloadable sections.
+// The running config is left unchanged when any of the sections fails to apply.
+func (c *ConfigReloader) Apply(next *config.Config) (config.ConfigDiff, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	diff := c.current.Diff(next)
+	if len(diff.RequiresRestart) != 0 {
+		logger.Warnw("config changes require a restart to take effect", nil, "sections", diff.RequiresRestart)
+	}
+	if len(diff.Reloadable) == 0 {
+		logger.Infow("no reloadable config changes")
+		return diff, nil
+	}
+
+	merged := c.current.WithReloadable(next)
+	if err := c.applyLocked(merged); err != nil {
+		logger.Warnw("could not apply config changes, rolling back", err, "sections", diff.Reloadable)
+		if rollbackErr := c.applyLocked(c.current); rollbackErr != nil {
+			logger.Errorw("could not roll back config changes", rollbackErr)
+		}
+		return diff, err
+	}
+
+	if slices.Contains(diff.Reloadable, "logging") {
+		config.InitLoggerFromConfig(&merged.Logging)
+	}
+	c.current = merged
+	logger.Infow("config reloaded", "sections", diff.Reloadable)
+	return diff, nil
+}
+
+func (c *ConfigReloader) applyLocked(conf *config.Config) error {
+	// allocator validates the node selector, apply it first to fail before anything else changed
+	if err := c.roomAllocator.UpdateConfig(conf); err != nil {
+		return err
+	}
+	if err := c.keyProvider.Update(conf); err != nil {
+		return err
+	}
+	if err := c.notifier.Update(conf.WebHook, c.keyProvider); err != nil {
+		return err
+	}
+
+	c.roomService.UpdateLimitConfig(conf.Limit)
+	c.rtcService.UpdateLimitConfig(conf.Limit)
+	c.whipService.UpdateLimitConfig(conf.Limit)
+	c.roomManager.UpdateLimitConfig(conf.Limit)
+	c.currentNode.UpdateNodeStatsConfig(&conf.NodeStats)
+	c.peerFilter.UpdateDenyCIDRs(conf.TURN.DenyPeerCIDRs)
+
+	for _, f := range c.onReload {
+		if err := f(conf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ConfigReloader) watchWorker() {
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopChan:
+			return
+
+		case <-ticker.C:
+			if c.fileChanged() {
+				logger.Infow("config file changed, reloading", "file", c.file)
+				_, _ = c.Reload()
+			}
+		}
+	}
+}
+
+func (c *ConfigReloader) fileChanged() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.file == "" {
+		return false
+	}
+	st, err := os.Stat(c.file)
+	if err != nil {
+		return false
+	}
+	if st.ModTime().Equal(c.modTime) {
+		return false
+	}
+	c.modTime = st.ModTime()
+	return true
+}
+
+// ------------------------------------------------
+
+// ReloadableNotifier sends webhooks through a notifier which is replaced when the webhook config or the
+// secret of its API key changes, including when a rotated secret becomes valid or expires.
+type ReloadableNotifier struct {
+	current atomic.Pointer[webhook.QueuedNotifier]
+
+	lock         sync.Mutex
+	config       webhook.WebHookConfig
+	provider     auth.KeyProvider
+	secret       string
+	hooks        []func(ctx context.Context, whi *livekit.WebhookInfo)
+	filter       *webhook.FilterParams
+	refreshTimer *time.Timer
+	stopped      bool
+}
+
+func NewReloadableNotifier(conf webhook.WebHookConfig, provider auth.KeyProvider) (*ReloadableNotifier, error) {
+	notifier, err := newWebhookNotifier(conf, provider)
+	if err != nil {
+		return nil, err
+	}
+	n := &ReloadableNotifier{
+		config:   conf,
+		provider: provider,
+		secret:   provider.GetSecret(conf.APIKey),
+	}
+	n.current.Store(&notifier)
+	n.lock.Lock()
+	n.scheduleRefreshLocked()
+	n.lock.Unlock()
+	return n, nil
+}
+
+// Update replaces the notifier when the config or the secret changed, webhooks queued on the previous one are still delivered
+func (n *ReloadableNotifier) Update(conf webhook.WebHookConfig, provider auth.KeyProvider) error {
+	n.lock.Lock()
+	prev, err := n.updateLocked(conf, provider)
+	n.lock.Unlock()
+
+	if prev != nil {
+		go prev.Stop(false)
+	}
+	return err
+}
+
+func (n *ReloadableNotifier) updateLocked(conf webhook.WebHookConfig, provider auth.KeyProvider) (webhook.QueuedNotifier, error) {
+	secret := provider.GetSecret(conf.APIKey)
+	if reflect.DeepEqual(n.config, conf) && n.secret == secret {
+		n.provider = provider
+		n.scheduleRefreshLocked()
+		return nil, nil
+	}
+	notifier, err := newWebhookNotifier(conf, provider)
+	if err != nil {
+		// the current notifier is kept, check again at the next change of the secrets
+		n.scheduleRefreshLocked()
+		return nil, err
+	}
+	for _, hook := range n.hooks {
+		notifier.RegisterProcessedHook(hook)
+	}
+	if n.filter != nil {
+		notifier.SetFilter(*n.filter)
+	}
+	prev := *n.current.Swap(&notifier)
+	n.config = conf
+	n.provider = provider
+	n.secret = secret
+	n.scheduleRefreshLocked()
+	return prev, nil
+}
+
+// scheduleRefreshLocked rebuilds the notifier when the secrets of its API key change next,
+// the notifier signs with the secret valid when it was created
+func (n *ReloadableNotifier) scheduleRefreshLocked() {
+	if n.refreshTimer != nil {
+		n.refreshTimer.Stop()
+		n.refreshTimer = nil
+	}
+	p, ok := n.provider.(MultiSecretKeyProvider)
+	if !ok || n.stopped {
+		return
+	}
+	next := p.NextSecretChange(n.config.APIKey)
+	if next.IsZero() {
+		return
+	}
+	n.refreshTimer = time.AfterFunc(time.Until(next), n.refresh)
+}
+
+func (n *ReloadableNotifier) refresh() {
+	n.lock.Lock()
+	if n.stopped {
+		n.lock.Unlock()
+		return
+	}
+	apiKey := n.config.APIKey
+	prev, err := n.updateLocked(n.config, n.provider)
+	n.lock.Unlock()
+
+	if err != nil {
+		logger.Warnw("could not update webhook notifier after secret change", err, "apiKey", apiKey)
+		return
+	}
+	if prev != nil {
+		logger.Infow("webhook secret changed", "apiKey", apiKey)
+		go prev.Stop(false)
+	}
+}
+
+func (n *ReloadableNotifier) RegisterProcessedHook(f func(ctx context.Context, whi *livekit.WebhookInfo)) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.hooks = append(n.hooks, f)
+	(*n.current.Load()).RegisterProcessedHook(f)
+}
+
+// SetKeys replaces the keys of the current notifier, they are reset when the notifier is replaced next
+func (n *ReloadableNotifier) SetKeys(apiKey, apiSecret string) {
+	(*n.current.Load()).SetKeys(apiKey, apiSecret)
+}
+
+func (n *ReloadableNotifier) SetFilter(params webhook.FilterParams) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.filter = &params
+	(*n.current.Load()).SetFilter(params)
+}
+
+func (n *ReloadableNotifier) QueueNotify(ctx context.Context, event *livekit.WebhookEvent, opts ...webhook.NotifyOption) error {
+	return (*n.current.Load()).QueueNotify(ctx, event, opts...)
+}
+
+func (n *ReloadableNotifier) Stop(force bool) {
+	n.lock.Lock()
+	n.stopped = true
+	if n.refreshTimer != nil {
+		n.refreshTimer.Stop()
+		n.refreshTimer = nil
+	}
+	n.lock.Unlock()
+
+	(*n.current.Load()).Stop(force)
+}
+
+func newWebhookNotifier(conf webhook.WebHookConfig, provider auth.KeyProvider) (webhook.QueuedNotifier, error) {
+	secret := provider.GetSecret(conf.APIKey)
+	if secret == "" && len(conf.URLs) > 0 {
+		return nil, ErrWebHookMissingAPIKey
+	}
+
+	return webhook.NewDefaultNotifier(conf, provider)
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+)
+
+const roomDataHistoryPath = "/room_data_history/{room}"
+
+// DataHistoryService exposes the data history kept for late joiners over HTTP.
+// History is read from the object store, where it is persisted periodically by the node hosting the room.
+type DataHistoryService struct {
+	store       ObjectStore
+	roomService livekit.RoomService
+}
+
+type dataHistoryEntryResponse struct {
+	Topic     string            `json:"topic"`
+	StreamID  string            `json:"streamId,omitempty"`
+	CreatedAt int64             `json:"createdAt"`
+	Packets   []json.RawMessage `json:"packets"`
+}
+
+func NewDataHistoryService(store ObjectStore, roomService livekit.RoomService) *DataHistoryService {
+	return &DataHistoryService{
+		store:       store,
+		roomService: roomService,
+	}
+}
+
+func (s *DataHistoryService) SetupRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+roomDataHistoryPath, s.handleGet)
+	mux.HandleFunc("DELETE "+roomDataHistoryPath, s.handleDelete)
+}
+
+func (s *DataHistoryService) handleGet(w http.ResponseWriter, r *http.Request) {
+	roomName := livekit.RoomName(r.PathValue("room"))
+	if err := EnsureAdminPermission(r.Context(), roomName); err != nil {
+		HandleErrorJson(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	entries, err := s.store.LoadDataHistory(r.Context(), roomName)
+	if err != nil && !errors.Is(err, rtc.ErrDataHistoryNotFound) {
+		HandleErrorJson(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	topic := r.URL.Query().Get("topic")
+	res := make([]*dataHistoryEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		if topic != "" && entry.Topic != topic {
+			continue
+		}
+		er := &dataHistoryEntryResponse{
+			Topic:     entry.Topic,
+			StreamID:  entry.StreamID,
+			CreatedAt: entry.CreatedAt.UnixMilli(),
+			Packets:   make([]json.RawMessage, 0, len(entry.Packets)),
+		}
+		for _, p := range entry.Packets {
+			dp := &livekit.DataPacket{}
+			if err := proto.Unmarshal(p.Data, dp); err != nil {
+				HandleErrorJson(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			data, err := protojson.Marshal(dp)
+			if err != nil {
+				HandleErrorJson(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			er.Packets = append(er.Packets, data)
+		}
+		res = append(res, er)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// handleDelete clears the persisted history and the history of the room if it is active
+func (s *DataHistoryService) handleDelete(w http.ResponseWriter, r *http.Request) {
+	roomName := livekit.RoomName(r.PathValue("room"))
+	if err := EnsureAdminPermission(r.Context(), roomName); err != nil {
+		HandleErrorJson(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	topic := r.URL.Query().Get("topic")
+	payload, err := json.Marshal(&rtc.DataHistoryRequest{
+		Action: rtc.DataHistoryActionClear,
+		Topic:  topic,
+	})
+	if err != nil {
+		HandleErrorJson(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	exists, err := s.store.RoomExists(r.Context(), roomName)
+	if err != nil {
+		HandleErrorJson(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if exists {
+		dataTopic := rtc.DataHistoryTopic
+		if _, err := s.roomService.SendData(r.Context(), &livekit.SendDataRequest{
+			Room:  string(roomName),
+			Data:  payload,
+			Kind:  livekit.DataPacket_RELIABLE,
+			Topic: &dataTopic,
+		}); err != nil {
+			HandleErrorJson(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	if topic == "" {
+		err = s.store.DeleteDataHistory(r.Context(), roomName)
+	} else {
+		err = s.clearTopic(r, roomName, topic)
+	}
+	if err != nil {
+		HandleErrorJson(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *DataHistoryService) clearTopic(r *http.Request, roomName livekit.RoomName, topic string) error {
+	entries, err := s.store.LoadDataHistory(r.Context(), roomName)
+	if err != nil {
+		if errors.Is(err, rtc.ErrDataHistoryNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.Topic != topic {
+			kept = append(kept, entry)
+		}
+	}
+	return s.store.StoreDataHistory(r.Context(), roomName, kept)
+}
@@ -0,0 +1,222 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/routing/selector"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// handedOverCloseDelay is how long participants asked to migrate are kept on this node, giving them time to
+// resume on the room's new node before they are closed
+const handedOverCloseDelay = 30 * time.Second
+
+type DrainState string
+
+const (
+	DrainStateIdle      DrainState = "idle"
+	DrainStateMigrating DrainState = "migrating"
+	// rooms which could not be migrated wait for their participants to leave
+	DrainStateWaiting DrainState = "waiting"
+	DrainStateDone    DrainState = "done"
+)
+
+type DrainStatus struct {
+	State                DrainState `json:"state"`
+	StartedAt            time.Time  `json:"started_at,omitzero"`
+	RoomsMigrated        int        `json:"rooms_migrated"`
+	ParticipantsMigrated int        `json:"participants_migrated"`
+	// rooms and participants still on this node, including migrating ones
+	RoomsRemaining        int `json:"rooms_remaining"`
+	ParticipantsRemaining int `json:"participants_remaining"`
+	// rooms which could not be moved, with the reason
+	Failed map[livekit.RoomName]string `json:"failed,omitempty"`
+}
+
+// Drainer moves rooms hosted on a node shutting down to other nodes. Ownership of each room moves to a node
+// picked by the node selector, then participants are asked to migrate in batches and reconnect to the new node.
+type Drainer struct {
+	config      config.DrainConfig
+	router      routing.Router
+	roomManager *RoomManager
+	selector    selector.NodeSelector
+	nodeID      livekit.NodeID
+
+	lock    sync.Mutex
+	started bool
+	status  DrainStatus
+}
+
+func NewDrainer(conf *config.Config, router routing.Router, roomManager *RoomManager, currentNode routing.LocalNode) (*Drainer, error) {
+	sel, err := selector.CreateNodeSelector(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Drainer{
+		config:      conf.Drain,
+		router:      router,
+		roomManager: roomManager,
+		selector:    sel,
+		nodeID:      currentNode.NodeID(),
+		status: DrainStatus{
+			State: DrainStateIdle,
+		},
+	}, nil
+}
+
+// Start stops new rooms from being placed on this node and starts migrating its rooms, it returns immediately
+func (d *Drainer) Start() {
+	d.lock.Lock()
+	if d.started {
+		d.lock.Unlock()
+		return
+	}
+	d.started = true
+	d.status.State = DrainStateMigrating
+	d.status.StartedAt = time.Now()
+	d.lock.Unlock()
+
+	d.router.Drain()
+	go d.worker()
+}
+
+// UpdateNodeSelector recreates the selector picking target nodes from an updated config
+func (d *Drainer) UpdateNodeSelector(conf *config.Config) error {
+	sel, err := selector.CreateNodeSelector(conf)
+	if err != nil {
+		return err
+	}
+
+	d.lock.Lock()
+	d.selector = sel
+	d.lock.Unlock()
+	return nil
+}
+
+func (d *Drainer) Status() DrainStatus {
+	d.roomManager.lock.RLock()
+	rooms := slices.Collect(maps.Values(d.roomManager.rooms))
+	d.roomManager.lock.RUnlock()
+
+	participants := 0
+	for _, room := range rooms {
+		participants += room.GetParticipantCount()
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	status := d.status
+	status.RoomsRemaining = len(rooms)
+	status.ParticipantsRemaining = participants
+	status.Failed = maps.Clone(d.status.Failed)
+	if status.State == DrainStateWaiting && participants == 0 {
+		status.State = DrainStateDone
+	}
+	return status
+}
+
+func (d *Drainer) worker() {
+	logger.Infow("migrating rooms to drain node", "batchSize", d.config.BatchSize, "batchInterval", d.config.BatchInterval)
+
+	inBatch := 0
+	for _, room := range d.roomManager.drainableRooms() {
+		if inBatch > 0 && d.config.BatchSize > 0 && inBatch+room.GetParticipantCount() > d.config.BatchSize {
+			time.Sleep(d.config.BatchInterval)
+			inBatch = 0
+		}
+
+		migrated, err := d.migrateRoom(room)
+		if err != nil {
+			room.Logger().Warnw("could not migrate room", err)
+			d.lock.Lock()
+			if d.status.Failed == nil {
+				d.status.Failed = make(map[livekit.RoomName]string)
+			}
+			d.status.Failed[room.Name()] = err.Error()
+			d.lock.Unlock()
+			continue
+		}
+		inBatch += migrated
+	}
+
+	d.lock.Lock()
+	d.status.State = DrainStateWaiting
+	status := d.status
+	d.lock.Unlock()
+
+	logger.Infow(
+		"room migration done, waiting for remaining participants",
+		"roomsMigrated", status.RoomsMigrated,
+		"participantsMigrated", status.ParticipantsMigrated,
+		"roomsFailed", len(status.Failed),
+	)
+}
+
+func (d *Drainer) migrateRoom(room *rtc.Room) (int, error) {
+	nodes, err := d.router.ListNodes()
+	if err != nil {
+		return 0, err
+	}
+
+	candidates := make([]*livekit.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if livekit.NodeID(node.Id) != d.nodeID {
+			candidates = append(candidates, node)
+		}
+	}
+	d.lock.Lock()
+	sel := d.selector
+	d.lock.Unlock()
+	target, err := sel.SelectNode(candidates)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := d.roomManager.handOverRoom(context.Background(), room.Name(), livekit.NodeID(target.Id)); err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, p := range room.GetParticipants() {
+		// participants which cannot migrate, like WHIP publishers, stay till they leave
+		if d.roomManager.handOverParticipant(p) {
+			migrated++
+			time.AfterFunc(handedOverCloseDelay, func() {
+				_ = p.Close(true, types.ParticipantCloseReasonHandedOver, false)
+			})
+		}
+	}
+	room.Logger().Infow("migrating room", "targetNodeID", target.Id, "participants", migrated)
+
+	d.lock.Lock()
+	d.status.RoomsMigrated++
+	d.status.ParticipantsMigrated += migrated
+	d.lock.Unlock()
+	return migrated, nil
+}
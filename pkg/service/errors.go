@@ -41,6 +41,7 @@ var (
 	ErrRemoteUnmuteNoteEnabled          = psrpc.NewErrorf(psrpc.FailedPrecondition, "remote unmute not enabled")
 	ErrTrackNotFound                    = psrpc.NewErrorf(psrpc.NotFound, "track is not found")
 	ErrWebHookMissingAPIKey             = psrpc.NewErrorf(psrpc.InvalidArgument, "api_key is required to use webhooks")
+	ErrParticipantRPCMissingAPIKey      = psrpc.NewErrorf(psrpc.InvalidArgument, "api_key is required to use participant rpc")
 	ErrSIPNotConnected                  = psrpc.NewErrorf(psrpc.Internal, "sip not connected (redis required)")
 	ErrSIPTrunkNotFound                 = psrpc.NewErrorf(psrpc.NotFound, "requested sip trunk does not exist")
 	ErrSIPDispatchRuleNotFound          = psrpc.NewErrorf(psrpc.NotFound, "requested sip dispatch rule does not exist")
@@ -49,4 +50,7 @@ var (
 	ErrNoConnectRequest                 = psrpc.NewErrorf(psrpc.InvalidArgument, "no connect request")
 	ErrNoConnectResponse                = psrpc.NewErrorf(psrpc.InvalidArgument, "no connect response")
 	ErrDestinationIdentityRequired      = psrpc.NewErrorf(psrpc.InvalidArgument, "destination identity is required")
+	ErrDevelopmentOnly                  = psrpc.NewErrorf(psrpc.PermissionDenied, "only available in development mode")
+	ErrRelayOnlyWithoutTURN             = psrpc.NewErrorf(psrpc.FailedPrecondition, "relay only mode requires the embedded TURN server")
+	ErrConfigReloadNotSupported         = psrpc.NewErrorf(psrpc.FailedPrecondition, "config source does not support reloading")
 )
@@ -0,0 +1,175 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/routing/selector"
+)
+
+const (
+	// node stats older than this mean the stats worker is stuck
+	nodeStatsMaxAge    = 4 * time.Second
+	busHealthTimeout   = 2 * time.Second
+	busHealthCacheTime = time.Second
+)
+
+type NodeCapacity struct {
+	NodeID livekit.NodeID `json:"node_id"`
+	State  string         `json:"state"`
+	Ready  bool           `json:"ready"`
+	// reasons the node does not take new sessions
+	NotReadyReasons []string  `json:"not_ready_reasons,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	CPULoad      float32 `json:"cpu_load"`
+	Sysload      float32 `json:"sysload"`
+	Rooms        int32   `json:"rooms"`
+	Participants int32   `json:"participants"`
+	Tracks       int32   `json:"tracks"`
+	BytesPerSec  float32 `json:"bytes_per_sec"`
+
+	// rooms and participants the node can still take before reaching its limits, zero when not ready.
+	// Omitted when the node has no such limit
+	RoomHeadroom        *int32 `json:"room_headroom,omitempty"`
+	ParticipantHeadroom *int32 `json:"participant_headroom,omitempty"`
+}
+
+// NodeHealth reports whether this node can take new sessions and how much room it has left.
+// A node is not ready while draining, when its stats are stale, when it reached the limits used by the
+// node selector, or when the bus shared with other nodes cannot be reached
+type NodeHealth struct {
+	router      routing.Router
+	currentNode routing.LocalNode
+
+	lock           sync.Mutex
+	limitConfig    config.LimitConfig
+	selectorConfig config.NodeSelectorConfig
+	busCheckedAt   time.Time
+	busErr         error
+}
+
+func NewNodeHealth(conf *config.Config, router routing.Router, currentNode routing.LocalNode) *NodeHealth {
+	return &NodeHealth{
+		router:         router,
+		currentNode:    currentNode,
+		limitConfig:    conf.Limit,
+		selectorConfig: conf.NodeSelector,
+	}
+}
+
+func (h *NodeHealth) UpdateConfig(conf *config.Config) error {
+	h.lock.Lock()
+	h.limitConfig = conf.Limit
+	h.selectorConfig = conf.NodeSelector
+	h.lock.Unlock()
+	return nil
+}
+
+func (h *NodeHealth) Capacity() NodeCapacity {
+	node := h.currentNode.Clone()
+	stats := node.Stats
+	if stats == nil {
+		stats = &livekit.NodeStats{}
+	}
+
+	c := NodeCapacity{
+		NodeID:       livekit.NodeID(node.Id),
+		State:        strings.ToLower(node.State.String()),
+		Sysload:      selector.GetNodeSysload(node),
+		CPULoad:      stats.CpuLoad,
+		Rooms:        stats.NumRooms,
+		Participants: stats.NumClients,
+		Tracks:       stats.NumTracksIn + stats.NumTracksOut,
+	}
+	if stats.UpdatedAt != 0 {
+		c.UpdatedAt = time.Unix(stats.UpdatedAt, 0)
+	}
+	if len(stats.Rates) > 0 {
+		c.BytesPerSec = stats.Rates[0].BytesIn + stats.Rates[0].BytesOut
+	}
+
+	h.lock.Lock()
+	limitConfig := h.limitConfig
+	selectorConfig := h.selectorConfig
+	h.lock.Unlock()
+
+	if node.State != livekit.NodeState_SERVING {
+		c.NotReadyReasons = append(c.NotReadyReasons, "node is "+c.State)
+	}
+	if time.Since(c.UpdatedAt) > nodeStatsMaxAge {
+		c.NotReadyReasons = append(c.NotReadyReasons, fmt.Sprintf("node stats not updated since %s", c.UpdatedAt))
+	}
+	if selector.LimitsReached(limitConfig, stats) {
+		c.NotReadyReasons = append(c.NotReadyReasons, "node limits reached")
+	}
+	// participant limits are enforced when joining, a full node is not ready for new participants
+	if limitConfig.NumParticipants > 0 && limitConfig.NumParticipants <= c.Participants {
+		c.NotReadyReasons = append(c.NotReadyReasons, "participant limit reached")
+	}
+	if selector.LoadLimitReached(selectorConfig, node) {
+		c.NotReadyReasons = append(c.NotReadyReasons, "node load limit reached")
+	}
+	if err := h.checkBus(); err != nil {
+		c.NotReadyReasons = append(c.NotReadyReasons, err.Error())
+	}
+	c.Ready = len(c.NotReadyReasons) == 0
+
+	if limitConfig.NumRooms > 0 {
+		c.RoomHeadroom = headroom(c.Ready, limitConfig.NumRooms, c.Rooms)
+	}
+	if limitConfig.NumParticipants > 0 {
+		c.ParticipantHeadroom = headroom(c.Ready, limitConfig.NumParticipants, c.Participants)
+	}
+	return c
+}
+
+// checkBus reports whether the router can reach the bus, results are cached briefly as probes can be frequent
+func (h *NodeHealth) checkBus() error {
+	checker, ok := h.router.(routing.HealthChecker)
+	if !ok {
+		return nil
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if time.Since(h.busCheckedAt) < busHealthCacheTime {
+		return h.busErr
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), busHealthTimeout)
+	defer cancel()
+	h.busErr = checker.CheckHealth(ctx)
+	h.busCheckedAt = time.Now()
+	return h.busErr
+}
+
+func headroom(ready bool, limit, used int32) *int32 {
+	remaining := max(limit-used, 0)
+	if !ready {
+		remaining = 0
+	}
+	return &remaining
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/routing/routingfakes"
+	"github.com/livekit/livekit-server/pkg/service"
+)
+
+type healthCheckedRouter struct {
+	*routingfakes.FakeRouter
+	err error
+}
+
+func (r *healthCheckedRouter) CheckHealth(_ context.Context) error {
+	return r.err
+}
+
+func newHealthTestNode(t *testing.T, stats *livekit.NodeStats) *routing.LocalNodeImpl {
+	node, err := routing.NewLocalNodeFromNodeProto(&livekit.Node{
+		Id:    "ND_test",
+		State: livekit.NodeState_SERVING,
+		Stats: stats,
+	})
+	require.NoError(t, err)
+	return node
+}
+
+func TestNodeHealth(t *testing.T) {
+	t.Run("ready with headroom", func(t *testing.T) {
+		conf := &config.Config{
+			Limit: config.LimitConfig{NumRooms: 10, NumParticipants: 100},
+		}
+		node := newHealthTestNode(t, &livekit.NodeStats{
+			UpdatedAt:  time.Now().Unix(),
+			NumRooms:   4,
+			NumClients: 30,
+		})
+
+		c := service.NewNodeHealth(conf, &routingfakes.FakeRouter{}, node).Capacity()
+		require.True(t, c.Ready, c.NotReadyReasons)
+		require.Equal(t, "serving", c.State)
+		require.Equal(t, int32(6), *c.RoomHeadroom)
+		require.Equal(t, int32(70), *c.ParticipantHeadroom)
+	})
+
+	t.Run("unlimited headroom is omitted", func(t *testing.T) {
+		node := newHealthTestNode(t, &livekit.NodeStats{UpdatedAt: time.Now().Unix()})
+
+		c := service.NewNodeHealth(&config.Config{}, &routingfakes.FakeRouter{}, node).Capacity()
+		require.True(t, c.Ready)
+		require.Nil(t, c.RoomHeadroom)
+		require.Nil(t, c.ParticipantHeadroom)
+	})
+
+	t.Run("not ready when draining", func(t *testing.T) {
+		conf := &config.Config{
+			Limit: config.LimitConfig{NumParticipants: 100},
+		}
+		node := newHealthTestNode(t, &livekit.NodeStats{UpdatedAt: time.Now().Unix()})
+		node.SetState(livekit.NodeState_SHUTTING_DOWN)
+
+		c := service.NewNodeHealth(conf, &routingfakes.FakeRouter{}, node).Capacity()
+		require.False(t, c.Ready)
+		require.Equal(t, []string{"node is shutting_down"}, c.NotReadyReasons)
+		require.Equal(t, int32(0), *c.ParticipantHeadroom)
+	})
+
+	t.Run("not ready when limits are reached", func(t *testing.T) {
+		conf := &config.Config{
+			Limit: config.LimitConfig{NumParticipants: 100},
+			NodeSelector: config.NodeSelectorConfig{
+				Kind:         "cpuload",
+				CPULoadLimit: 0.8,
+			},
+		}
+		node := newHealthTestNode(t, &livekit.NodeStats{
+			UpdatedAt:  time.Now().Unix(),
+			NumClients: 100,
+		})
+		health := service.NewNodeHealth(conf, &routingfakes.FakeRouter{}, node)
+
+		c := health.Capacity()
+		require.False(t, c.Ready)
+		require.Equal(t, []string{"participant limit reached"}, c.NotReadyReasons)
+
+		node.SetStats(&livekit.NodeStats{
+			UpdatedAt:  time.Now().Unix(),
+			NumClients: 10,
+			CpuLoad:    0.9,
+		})
+		c = health.Capacity()
+		require.False(t, c.Ready)
+		require.Equal(t, []string{"node load limit reached"}, c.NotReadyReasons)
+
+		// limits are reloadable
+		conf.NodeSelector.CPULoadLimit = 0.95
+		require.NoError(t, health.UpdateConfig(conf))
+		require.True(t, health.Capacity().Ready)
+	})
+
+	t.Run("not ready when stats are stale", func(t *testing.T) {
+		node := newHealthTestNode(t, &livekit.NodeStats{UpdatedAt: time.Now().Unix() - 10})
+
+		c := service.NewNodeHealth(&config.Config{}, &routingfakes.FakeRouter{}, node).Capacity()
+		require.False(t, c.Ready)
+		require.Len(t, c.NotReadyReasons, 1)
+		require.Contains(t, c.NotReadyReasons[0], "node stats not updated")
+	})
+
+	t.Run("not ready when bus is unhealthy", func(t *testing.T) {
+		node := newHealthTestNode(t, &livekit.NodeStats{UpdatedAt: time.Now().Unix()})
+		router := &healthCheckedRouter{
+			FakeRouter: &routingfakes.FakeRouter{},
+			err:        errors.New("could not reach redis"),
+		}
+
+		c := service.NewNodeHealth(&config.Config{}, router, node).Capacity()
+		require.False(t, c.Ready)
+		require.Equal(t, []string{"could not reach redis"}, c.NotReadyReasons)
+	})
+}
@@ -0,0 +1,643 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/utils/protojson"
+	"github.com/livekit/psrpc"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/utils"
+)
+
+// Clients which cannot establish a WebSocket open a signal session over plain HTTP, either by requesting
+// the regular signal endpoint with `Accept: text/event-stream` (or `transport=sse`) to receive responses
+// as Server-Sent Events, or with `transport=poll` to receive them with long polling.
+// Requests are sent with POST to the session. Every response carries a sequence number, allowing the
+// stream to be resumed from the last one received, as long as the session has not timed out.
+const (
+	httpSignalSessionPath = "/rtc/v1/sessions/{session}"
+
+	httpSignalTransportSSE  = "sse"
+	httpSignalTransportPoll = "poll"
+
+	httpSignalEncodingJSON = "json"
+
+	sseContentType      = "text/event-stream"
+	protobufContentType = "application/x-protobuf"
+
+	maxHTTPSignalRequestSize = 1 << 20
+)
+
+var (
+	ErrSignalSessionNotFound = errors.New("signal session not found")
+	ErrSignalSessionGap      = errors.New("signal session cannot be resumed, responses have been dropped")
+	ErrStreamingNotSupported = errors.New("streaming not supported")
+)
+
+// returns the HTTP signal transport requested by a client, if any
+func httpSignalTransport(r *http.Request) string {
+	switch transport := r.FormValue("transport"); transport {
+	case httpSignalTransportSSE, httpSignalTransportPoll:
+		return transport
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && mediaType == sseContentType {
+			return httpSignalTransportSSE
+		}
+	}
+	return ""
+}
+
+func (s *RTCService) serveHTTPSignal(w http.ResponseWriter, r *http.Request, needsJoinRequest bool, transport string) {
+	pLogger := utils.GetLogger(r.Context())
+
+	roomName, pi, code, err := s.validateInternal(pLogger, r, needsJoinRequest, false)
+	if err != nil {
+		prometheus.IncrementParticipantJoinValidationFail(1)
+		HandleError(w, r, code, err, "room", roomName, "participant", pi.Identity, "transport", transport)
+		return
+	}
+
+	// the session outlives the request creating it
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+
+	var cr connectionResult
+	var initialResponse *livekit.SignalResponse
+	for attempt := 0; attempt < s.config.SignalRelay.ConnectAttempts; attempt++ {
+		connectionTimeout := 3 * time.Second * time.Duration(attempt+1)
+		cr, initialResponse, err = s.startConnection(utils.ContextWithAttempt(ctx, attempt), roomName, pi, connectionTimeout)
+		if err == nil || errors.Is(err, context.Canceled) {
+			break
+		}
+	}
+	if err != nil {
+		cancel()
+		prometheus.IncrementParticipantJoinFail(1)
+		status := http.StatusInternalServerError
+		var psrpcErr psrpc.Error
+		if errors.As(err, &psrpcErr) {
+			status = psrpcErr.ToHttp()
+		}
+		HandleError(w, r, status, err, "room", roomName, "participant", pi.Identity, "transport", transport)
+		return
+	}
+	prometheus.IncrementParticipantJoin(1)
+
+	signalStats := rtc.NewBytesSignalStats(ctx, s.telemetry)
+	if join := initialResponse.GetJoin(); join != nil {
+		signalStats.ResolveRoom(join.GetRoom())
+		signalStats.ResolveParticipant(join.GetParticipant())
+	}
+	if pi.Reconnect && pi.ID != "" {
+		signalStats.ResolveParticipant(&livekit.ParticipantInfo{
+			Sid:      string(pi.ID),
+			Identity: string(pi.Identity),
+		})
+	}
+
+	sess := newHTTPSignalSession(httpSignalSessionParams{
+		UseJSON: r.FormValue("encoding") == httpSignalEncodingJSON,
+		Conn:    cr,
+		Logger: pLogger.WithValues(
+			"room", roomName,
+			"participant", pi.Identity,
+			"connID", cr.ConnectionID,
+			"transport", transport,
+		),
+		SignalStats: signalStats,
+		Config:      s.config.HTTPSignal,
+		Cancel:      cancel,
+		OnClose:     s.removeSignalSession,
+	})
+	s.mu.Lock()
+	s.signalSessions[sess.id] = sess
+	s.mu.Unlock()
+
+	sess.logger.Debugw(
+		"new client HTTP signal session",
+		"reconnect", pi.Reconnect,
+		"reconnectReason", pi.ReconnectReason,
+		"selectedNodeID", cr.NodeID,
+		"nodeSelectionReason", cr.NodeSelectionReason,
+	)
+	sess.start(initialResponse)
+
+	if transport == httpSignalTransportSSE {
+		s.streamSignalSession(w, r, sess, 0)
+		return
+	}
+	writeHTTPSignalJSON(w, struct {
+		Session string `json:"session"`
+	}{
+		Session: sess.id,
+	})
+}
+
+func (s *RTCService) getSignalSession(w http.ResponseWriter, r *http.Request) *httpSignalSession {
+	s.mu.Lock()
+	sess := s.signalSessions[r.PathValue("session")]
+	s.mu.Unlock()
+
+	if sess == nil {
+		HandleError(w, r, http.StatusNotFound, ErrSignalSessionNotFound)
+	}
+	return sess
+}
+
+func (s *RTCService) removeSignalSession(sess *httpSignalSession) {
+	s.mu.Lock()
+	delete(s.signalSessions, sess.id)
+	s.mu.Unlock()
+}
+
+// handles resuming a Server-Sent Events stream, starting after the Last-Event-ID
+func (s *RTCService) handleSignalSessionEvents(w http.ResponseWriter, r *http.Request) {
+	sess := s.getSignalSession(w, r)
+	if sess == nil {
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.FormValue("last_event_id")
+	}
+	after, err := parseSignalSequence(lastEventID)
+	if err != nil {
+		HandleError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	s.streamSignalSession(w, r, sess, after)
+}
+
+func (s *RTCService) streamSignalSession(w http.ResponseWriter, r *http.Request, sess *httpSignalSession, after uint64) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		HandleError(w, r, http.StatusInternalServerError, ErrStreamingNotSupported)
+		return
+	}
+	if err := sess.acquire(after); err != nil {
+		HandleError(w, r, http.StatusGone, err)
+		return
+	}
+	defer sess.release()
+
+	w.Header().Set("Content-Type", sseContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	// disable response buffering of nginx based proxies
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeSSEEvent(w, "", "session", []byte(sess.id)); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	ping := time.NewTicker(pingFrequency)
+	defer ping.Stop()
+
+	for {
+		messages, notify, ended := sess.pending(after)
+		for _, m := range messages {
+			if err := writeSSEEvent(w, strconv.FormatUint(m.seq, 10), "", m.payload); err != nil {
+				return
+			}
+			after = m.seq
+		}
+		if ended && len(messages) == 0 {
+			_ = writeSSEEvent(w, "", "close", nil)
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-notify:
+		case <-ping.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+type httpSignalPollResponse struct {
+	Messages []httpSignalPollMessage `json:"messages"`
+	Closed   bool                    `json:"closed,omitempty"`
+}
+
+type httpSignalPollMessage struct {
+	Seq  uint64 `json:"seq"`
+	Data string `json:"data"`
+}
+
+// handles long polling for responses after the given sequence number, acknowledging earlier ones
+func (s *RTCService) handleSignalSessionPoll(w http.ResponseWriter, r *http.Request) {
+	sess := s.getSignalSession(w, r)
+	if sess == nil {
+		return
+	}
+	after, err := parseSignalSequence(r.FormValue("after"))
+	if err != nil {
+		HandleError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := sess.acquire(after); err != nil {
+		HandleError(w, r, http.StatusGone, err)
+		return
+	}
+	defer sess.release()
+
+	timeout := time.NewTimer(s.config.HTTPSignal.PollTimeout)
+	defer timeout.Stop()
+
+	var messages []httpSignalMessage
+	var ended bool
+wait:
+	for {
+		var notify <-chan struct{}
+		messages, notify, ended = sess.pending(after)
+		if len(messages) != 0 || ended {
+			break
+		}
+
+		select {
+		case <-notify:
+		case <-timeout.C:
+			break wait
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	res := httpSignalPollResponse{
+		Messages: make([]httpSignalPollMessage, 0, len(messages)),
+		Closed:   ended && len(messages) == 0,
+	}
+	for _, m := range messages {
+		res.Messages = append(res.Messages, httpSignalPollMessage{Seq: m.seq, Data: string(m.payload)})
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	writeHTTPSignalJSON(w, res)
+}
+
+// handles a signal request from the client, either protobuf or JSON encoded depending on the content type.
+// An optional ack parameter acknowledges responses received over the event stream.
+func (s *RTCService) handleSignalSessionRequest(w http.ResponseWriter, r *http.Request) {
+	sess := s.getSignalSession(w, r)
+	if sess == nil {
+		return
+	}
+	if ack := r.FormValue("ack"); ack != "" {
+		seq, err := parseSignalSequence(ack)
+		if err != nil {
+			HandleError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		sess.ack(seq)
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxHTTPSignalRequestSize))
+	if err != nil {
+		HandleError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if len(payload) == 0 {
+		sess.touch()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	req := &livekit.SignalRequest{}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		err = protojson.Unmarshal(payload, req)
+	} else {
+		err = proto.Unmarshal(payload, req)
+	}
+	if err != nil {
+		HandleError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := sess.handleRequest(req, len(payload)); err != nil {
+		HandleError(w, r, http.StatusGone, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *RTCService) handleSignalSessionDelete(w http.ResponseWriter, r *http.Request) {
+	sess := s.getSignalSession(w, r)
+	if sess == nil {
+		return
+	}
+	sess.logger.Debugw("HTTP signal session closed by client")
+	sess.Close()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseSignalSequence(value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func writeSSEEvent(w io.Writer, id string, event string, data []byte) error {
+	var b bytes.Buffer
+	if id != "" {
+		fmt.Fprintf(&b, "id: %s\n", id)
+	}
+	if event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event)
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		b.WriteString("data: ")
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func writeHTTPSignalJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// ------------------------------------------------
+
+type httpSignalMessage struct {
+	seq     uint64
+	payload []byte
+}
+
+type httpSignalSessionParams struct {
+	UseJSON     bool
+	Conn        connectionResult
+	Logger      logger.Logger
+	SignalStats *rtc.BytesSignalStats
+	Config      config.HTTPSignalConfig
+	Cancel      context.CancelFunc
+	OnClose     func(*httpSignalSession)
+}
+
+// httpSignalSession buffers signal responses until they are acknowledged by the client,
+// bridging the routing.MessageSource and routing.MessageSink of a participant to HTTP requests
+type httpSignalSession struct {
+	params httpSignalSessionParams
+	id     string
+	logger logger.Logger
+
+	lock      sync.Mutex
+	messages  []httpSignalMessage
+	lastSeq   uint64
+	notify    chan struct{}
+	readers   int
+	idleTimer *time.Timer
+	ended     bool
+	closed    bool
+}
+
+func newHTTPSignalSession(params httpSignalSessionParams) *httpSignalSession {
+	s := &httpSignalSession{
+		params: params,
+		id:     newSignalSessionID(),
+		logger: params.Logger,
+		notify: make(chan struct{}),
+	}
+	s.idleTimer = time.AfterFunc(params.Config.SessionTimeout, s.onIdle)
+	return s
+}
+
+func newSignalSessionID() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (s *httpSignalSession) start(initialResponse *livekit.SignalResponse) {
+	s.queue(initialResponse)
+	go s.readResponses()
+}
+
+func (s *httpSignalSession) readResponses() {
+	defer func() {
+		s.lock.Lock()
+		s.ended = true
+		s.notifyLocked()
+		s.lock.Unlock()
+	}()
+	for msg := range s.params.Conn.ResponseSource.ReadChan() {
+		res, ok := msg.(*livekit.SignalResponse)
+		if !ok {
+			s.logger.Errorw("unexpected message type", nil, "type", fmt.Sprintf("%T", msg))
+			continue
+		}
+		switch m := res.Message.(type) {
+		case *livekit.SignalResponse_Join:
+			s.params.SignalStats.ResolveRoom(m.Join.GetRoom())
+			s.params.SignalStats.ResolveParticipant(m.Join.GetParticipant())
+		case *livekit.SignalResponse_RoomUpdate:
+			s.params.SignalStats.ResolveRoom(m.RoomUpdate.GetRoom())
+		case *livekit.SignalResponse_RoomMoved:
+			s.params.SignalStats.Reset()
+			s.params.SignalStats.ResolveRoom(m.RoomMoved.GetRoom())
+			s.params.SignalStats.ResolveParticipant(m.RoomMoved.GetParticipant())
+		}
+		s.queue(res)
+	}
+	s.logger.Debugw("nothing to read from response source")
+}
+
+func (s *httpSignalSession) queue(res *livekit.SignalResponse) {
+	var payload []byte
+	var err error
+	if s.params.UseJSON {
+		payload, err = protojson.Marshal(res)
+	} else {
+		var encoded []byte
+		encoded, err = proto.Marshal(res)
+		payload = []byte(base64.StdEncoding.EncodeToString(encoded))
+	}
+	if err != nil {
+		s.logger.Warnw("could not encode signal response", err)
+		return
+	}
+	s.params.SignalStats.AddBytes(uint64(len(payload)), true)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.lastSeq++
+	s.messages = append(s.messages, httpSignalMessage{seq: s.lastSeq, payload: payload})
+	if over := len(s.messages) - s.params.Config.MaxBufferedMessages; over > 0 {
+		s.messages = s.messages[over:]
+	}
+	s.notifyLocked()
+}
+
+func (s *httpSignalSession) notifyLocked() {
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
+
+// acquire registers a reader resuming after the given sequence number,
+// failing if responses it has not received have already been dropped
+func (s *httpSignalSession) acquire(after uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return ErrSignalSessionNotFound
+	}
+	if err := s.ackLocked(after); err != nil {
+		return err
+	}
+	s.readers++
+	s.idleTimer.Stop()
+	return nil
+}
+
+func (s *httpSignalSession) release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.readers--
+	if s.readers == 0 && !s.closed {
+		s.idleTimer.Reset(s.params.Config.SessionTimeout)
+	}
+}
+
+func (s *httpSignalSession) touch() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.readers == 0 && !s.closed {
+		s.idleTimer.Reset(s.params.Config.SessionTimeout)
+	}
+}
+
+func (s *httpSignalSession) ack(seq uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_ = s.ackLocked(seq)
+}
+
+func (s *httpSignalSession) ackLocked(seq uint64) error {
+	if len(s.messages) != 0 && s.messages[0].seq > seq+1 {
+		return ErrSignalSessionGap
+	}
+	for len(s.messages) != 0 && s.messages[0].seq <= seq {
+		s.messages = s.messages[1:]
+	}
+	return nil
+}
+
+// pending returns the buffered responses after the given sequence number, a channel notified
+// when new responses are queued, and whether the response source has terminated
+func (s *httpSignalSession) pending(after uint64) ([]httpSignalMessage, <-chan struct{}, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var messages []httpSignalMessage
+	for _, m := range s.messages {
+		if m.seq > after {
+			messages = append(messages, m)
+		}
+	}
+	return messages, s.notify, s.ended
+}
+
+func (s *httpSignalSession) handleRequest(req *livekit.SignalRequest, size int) error {
+	s.touch()
+	s.params.SignalStats.AddBytes(uint64(size), false)
+
+	if pong := pongResponse(req); pong != nil {
+		s.queue(pong)
+	}
+
+	switch m := req.Message.(type) {
+	case *livekit.SignalRequest_Offer:
+		s.logger.Debugw("received offer", "offer", m)
+	case *livekit.SignalRequest_Answer:
+		s.logger.Debugw("received answer", "answer", m)
+	default:
+		s.logger.Debugw("received signal request", "request", m)
+	}
+
+	if err := s.params.Conn.RequestSink.WriteMessage(req); err != nil {
+		s.logger.Warnw("error writing to request sink", err)
+		return err
+	}
+	return nil
+}
+
+func (s *httpSignalSession) onIdle() {
+	s.lock.Lock()
+	idle := s.readers == 0
+	s.lock.Unlock()
+
+	if idle {
+		s.logger.Debugw("closing idle HTTP signal session")
+		s.Close()
+	}
+}
+
+func (s *httpSignalSession) Close() {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return
+	}
+	s.closed = true
+	s.idleTimer.Stop()
+	s.lock.Unlock()
+
+	s.params.Conn.ResponseSource.Close()
+	s.params.Conn.RequestSink.Close()
+	s.params.SignalStats.Stop()
+	s.params.Cancel()
+	if s.params.OnClose != nil {
+		s.params.OnClose(s)
+	}
+}
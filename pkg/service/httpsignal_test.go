@@ -0,0 +1,154 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/observability/roomobs"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/telemetry/telemetryfakes"
+)
+
+func newTestHTTPSignalSession(t *testing.T, maxBuffered int) (*httpSignalSession, *routing.MessageChannel, *routing.MessageChannel) {
+	telemetryService := &telemetryfakes.FakeTelemetryService{}
+	telemetryService.RoomProjectReporterReturns(roomobs.NewNoopProjectReporter())
+
+	requests := routing.NewDefaultMessageChannel("conn")
+	responses := routing.NewDefaultMessageChannel("conn")
+	_, cancel := context.WithCancel(context.Background())
+	sess := newHTTPSignalSession(httpSignalSessionParams{
+		Conn: connectionResult{
+			StartParticipantSignalResults: routing.StartParticipantSignalResults{
+				ConnectionID:   "conn",
+				RequestSink:    requests,
+				ResponseSource: responses,
+			},
+		},
+		Logger:      logger.GetLogger(),
+		SignalStats: rtc.NewBytesSignalStats(context.Background(), telemetryService),
+		Config: config.HTTPSignalConfig{
+			Enabled:             true,
+			SessionTimeout:      time.Minute,
+			PollTimeout:         time.Second,
+			MaxBufferedMessages: maxBuffered,
+		},
+		Cancel: cancel,
+	})
+	t.Cleanup(sess.Close)
+	return sess, requests, responses
+}
+
+func decodeTestSignalResponse(t *testing.T, m httpSignalMessage) *livekit.SignalResponse {
+	encoded, err := base64.StdEncoding.DecodeString(string(m.payload))
+	require.NoError(t, err)
+	res := &livekit.SignalResponse{}
+	require.NoError(t, proto.Unmarshal(encoded, res))
+	return res
+}
+
+func TestHTTPSignalSession(t *testing.T) {
+	t.Run("responses are sequenced and acknowledged", func(t *testing.T) {
+		sess, _, responses := newTestHTTPSignalSession(t, 10)
+		sess.start(&livekit.SignalResponse{Message: &livekit.SignalResponse_Leave{Leave: &livekit.LeaveRequest{}}})
+		require.NoError(t, responses.WriteMessage(&livekit.SignalResponse{Message: &livekit.SignalResponse_Pong{Pong: 1}}))
+
+		var messages []httpSignalMessage
+		require.Eventually(t, func() bool {
+			messages, _, _ = sess.pending(0)
+			return len(messages) == 2
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, uint64(1), messages[0].seq)
+		require.NotNil(t, decodeTestSignalResponse(t, messages[0]).GetLeave())
+		require.Equal(t, int64(1), decodeTestSignalResponse(t, messages[1]).GetPong())
+
+		// resuming after the first response only returns the second
+		require.NoError(t, sess.acquire(1))
+		sess.release()
+		messages, _, _ = sess.pending(0)
+		require.Len(t, messages, 1)
+		require.Equal(t, uint64(2), messages[0].seq)
+	})
+
+	t.Run("resuming fails once responses are dropped", func(t *testing.T) {
+		sess, _, _ := newTestHTTPSignalSession(t, 2)
+		for i := range 4 {
+			sess.queue(&livekit.SignalResponse{Message: &livekit.SignalResponse_Pong{Pong: int64(i)}})
+		}
+
+		require.ErrorIs(t, sess.acquire(1), ErrSignalSessionGap)
+		require.NoError(t, sess.acquire(2))
+		sess.release()
+	})
+
+	t.Run("ping requests are answered and forwarded", func(t *testing.T) {
+		sess, requests, _ := newTestHTTPSignalSession(t, 10)
+		require.NoError(t, sess.handleRequest(&livekit.SignalRequest{
+			Message: &livekit.SignalRequest_PingReq{PingReq: &livekit.Ping{Timestamp: 1234}},
+		}, 10))
+
+		messages, _, _ := sess.pending(0)
+		require.Len(t, messages, 1)
+		require.Equal(t, int64(1234), decodeTestSignalResponse(t, messages[0]).GetPongResp().GetLastPingTimestamp())
+
+		select {
+		case msg := <-requests.ReadChan():
+			require.Equal(t, int64(1234), msg.(*livekit.SignalRequest).GetPingReq().GetTimestamp())
+		default:
+			t.Fatal("request was not forwarded")
+		}
+	})
+
+	t.Run("readers are notified when the response source ends", func(t *testing.T) {
+		sess, _, responses := newTestHTTPSignalSession(t, 10)
+		sess.start(&livekit.SignalResponse{Message: &livekit.SignalResponse_Pong{Pong: 1}})
+
+		_, notify, ended := sess.pending(1)
+		require.False(t, ended)
+		responses.Close()
+
+		select {
+		case <-notify:
+		case <-time.After(time.Second):
+			t.Fatal("reader was not notified")
+		}
+		require.Eventually(t, func() bool {
+			_, _, ended = sess.pending(1)
+			return ended
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestWriteSSEEvent(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, writeSSEEvent(&b, "3", "", []byte("{\n\"a\":1}")))
+	require.Equal(t, "id: 3\ndata: {\ndata: \"a\":1}\n\n", b.String())
+
+	b.Reset()
+	require.NoError(t, writeSSEEvent(&b, "", "close", nil))
+	require.Equal(t, "event: close\ndata: \n\n", b.String())
+}
@@ -19,6 +19,9 @@ import (
 	"time"
 
 	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -29,6 +32,8 @@ import (
 type ObjectStore interface {
 	ServiceStore
 	OSSServiceStore
+	RoomScheduleStore
+	DataHistoryStore
 
 	// enable locking on a specific room to prevent race
 	// returns a (lock uuid, error)
@@ -51,6 +56,19 @@ type ServiceStore interface {
 	ListRooms(ctx context.Context, roomNames []livekit.RoomName) ([]*livekit.Room, error)
 }
 
+type RoomScheduleStore interface {
+	StoreRoomSchedule(ctx context.Context, schedule *rtc.RoomSchedule) error
+	LoadRoomSchedule(ctx context.Context, roomName livekit.RoomName) (*rtc.RoomSchedule, error)
+	ListRoomSchedules(ctx context.Context) ([]*rtc.RoomSchedule, error)
+	DeleteRoomSchedule(ctx context.Context, roomName livekit.RoomName) error
+}
+
+type DataHistoryStore interface {
+	StoreDataHistory(ctx context.Context, roomName livekit.RoomName, entries []*rtc.DataHistoryEntry) error
+	LoadDataHistory(ctx context.Context, roomName livekit.RoomName) ([]*rtc.DataHistoryEntry, error)
+	DeleteDataHistory(ctx context.Context, roomName livekit.RoomName) error
+}
+
 type OSSServiceStore interface {
 	DeleteRoom(ctx context.Context, roomName livekit.RoomName) error
 	HasParticipant(context.Context, livekit.RoomName, livekit.ParticipantIdentity) (bool, error)
@@ -81,8 +99,10 @@ type IngressStore interface {
 type RoomAllocator interface {
 	AutoCreateEnabled(ctx context.Context) bool
 	SelectRoomNode(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error
+	SelectCascadeNode(ctx context.Context, roomName livekit.RoomName) (livekit.NodeID, error)
 	CreateRoom(ctx context.Context, req *livekit.CreateRoomRequest, isExplicit bool) (*livekit.Room, *livekit.RoomInternal, bool, error)
-	ValidateCreateRoom(ctx context.Context, roomName livekit.RoomName) error
+	ValidateCreateRoom(ctx context.Context, roomName livekit.RoomName, reconnect bool) error
+	UpdateConfig(conf *config.Config) error
 }
 
 //counterfeiter:generate . SIPStore
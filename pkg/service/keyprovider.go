@@ -0,0 +1,140 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/auth"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// MultiSecretKeyProvider is a KeyProvider which can have several valid secrets for an API key while it is rotated
+type MultiSecretKeyProvider interface {
+	auth.KeyProvider
+
+	// GetSecrets returns all currently valid secrets of the key, newest first
+	GetSecrets(key string) []string
+	// NextSecretChange returns when a secret of the key becomes valid or expires next, zero when none does
+	NextSecretChange(key string) time.Time
+}
+
+// ReloadableKeyProvider holds the API keys of the config, keys can be replaced while the server is running.
+// GetSecret returns the newest valid secret of a key which is used for signing.
+type ReloadableKeyProvider struct {
+	lock    sync.RWMutex
+	secrets map[string][]config.KeySecret
+}
+
+func NewReloadableKeyProvider(conf *config.Config) *ReloadableKeyProvider {
+	return &ReloadableKeyProvider{
+		secrets: keySecretsFromConfig(conf),
+	}
+}
+
+func (p *ReloadableKeyProvider) Update(conf *config.Config) error {
+	if len(conf.Keys) == 0 && len(conf.KeySecrets) == 0 {
+		return config.ErrKeysNotSet
+	}
+
+	secrets := keySecretsFromConfig(conf)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.secrets = secrets
+	return nil
+}
+
+func (p *ReloadableKeyProvider) GetSecret(key string) string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	now := time.Now()
+	for _, s := range p.secrets[key] {
+		if s.IsValidAt(now) {
+			return s.Secret
+		}
+	}
+	return ""
+}
+
+func (p *ReloadableKeyProvider) GetSecrets(key string) []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	now := time.Now()
+	var secrets []string
+	for _, s := range p.secrets[key] {
+		if s.IsValidAt(now) {
+			secrets = append(secrets, s.Secret)
+		}
+	}
+	return secrets
+}
+
+func (p *ReloadableKeyProvider) NextSecretChange(key string) time.Time {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	now := time.Now()
+	var next time.Time
+	for _, s := range p.secrets[key] {
+		for _, t := range []time.Time{s.NotBefore, s.NotAfter} {
+			if t.After(now) && (next.IsZero() || t.Before(next)) {
+				next = t
+			}
+		}
+	}
+	return next
+}
+
+func (p *ReloadableKeyProvider) NumKeys() int {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return len(p.secrets)
+}
+
+// secrets of each key ordered newest first, by not_before and then by position in the list
+func keySecretsFromConfig(conf *config.Config) map[string][]config.KeySecret {
+	secrets := make(map[string][]config.KeySecret, len(conf.Keys)+len(conf.KeySecrets))
+	for key, secret := range conf.Keys {
+		secrets[key] = []config.KeySecret{{Secret: secret}}
+	}
+	for key, keySecrets := range conf.KeySecrets {
+		sorted := slices.Clone(keySecrets)
+		slices.Reverse(sorted)
+		slices.SortStableFunc(sorted, func(a, b config.KeySecret) int {
+			return b.NotBefore.Compare(a.NotBefore)
+		})
+		secrets[key] = sorted
+	}
+	return secrets
+}
+
+// getVerificationSecrets returns the secrets tokens signed with the key are checked against
+func getVerificationSecrets(provider auth.KeyProvider, key string) []string {
+	if p, ok := provider.(MultiSecretKeyProvider); ok {
+		return p.GetSecrets(key)
+	}
+	if secret := provider.GetSecret(key); secret != "" {
+		return []string{secret}
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ package service
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"time"
 
@@ -23,6 +24,8 @@ import (
 
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
 )
 
 var _ OSSServiceStore = (*LocalStore)(nil)
@@ -38,6 +41,10 @@ type LocalStore struct {
 	agentDispatches map[livekit.RoomName]map[string]*livekit.AgentDispatch
 	agentJobs       map[livekit.RoomName]map[string]*livekit.Job
 
+	schedules map[livekit.RoomName]*rtc.RoomSchedule
+	// map of roomName => data history
+	dataHistory map[livekit.RoomName][]*rtc.DataHistoryEntry
+
 	lock       sync.RWMutex
 	globalLock sync.Mutex
 }
@@ -49,6 +56,8 @@ func NewLocalStore() *LocalStore {
 		participants:    make(map[livekit.RoomName]map[livekit.ParticipantIdentity]*livekit.ParticipantInfo),
 		agentDispatches: make(map[livekit.RoomName]map[string]*livekit.AgentDispatch),
 		agentJobs:       make(map[livekit.RoomName]map[string]*livekit.Job),
+		schedules:       make(map[livekit.RoomName]*rtc.RoomSchedule),
+		dataHistory:     make(map[livekit.RoomName][]*rtc.DataHistoryEntry),
 		lock:            sync.RWMutex{},
 	}
 }
@@ -296,3 +305,71 @@ func (s *LocalStore) DeleteAgentJob(ctx context.Context, job *livekit.Job) error
 
 	return nil
 }
+
+func (s *LocalStore) StoreRoomSchedule(_ context.Context, schedule *rtc.RoomSchedule) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	clone := *schedule
+	s.schedules[livekit.RoomName(schedule.Room)] = &clone
+	return nil
+}
+
+func (s *LocalStore) LoadRoomSchedule(_ context.Context, roomName livekit.RoomName) (*rtc.RoomSchedule, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	schedule := s.schedules[roomName]
+	if schedule == nil {
+		return nil, rtc.ErrRoomScheduleNotFound
+	}
+	clone := *schedule
+	return &clone, nil
+}
+
+func (s *LocalStore) ListRoomSchedules(_ context.Context) ([]*rtc.RoomSchedule, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	schedules := make([]*rtc.RoomSchedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		clone := *schedule
+		schedules = append(schedules, &clone)
+	}
+	return schedules, nil
+}
+
+func (s *LocalStore) DeleteRoomSchedule(_ context.Context, roomName livekit.RoomName) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.schedules, roomName)
+	return nil
+}
+
+func (s *LocalStore) StoreDataHistory(_ context.Context, roomName livekit.RoomName, entries []*rtc.DataHistoryEntry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.dataHistory[roomName] = slices.Clone(entries)
+	return nil
+}
+
+func (s *LocalStore) LoadDataHistory(_ context.Context, roomName livekit.RoomName) ([]*rtc.DataHistoryEntry, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	entries, ok := s.dataHistory[roomName]
+	if !ok {
+		return nil, rtc.ErrDataHistoryNotFound
+	}
+	return slices.Clone(entries), nil
+}
+
+func (s *LocalStore) DeleteDataHistory(_ context.Context, roomName livekit.RoomName) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.dataHistory, roomName)
+	return nil
+}
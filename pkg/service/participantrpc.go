@@ -0,0 +1,191 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const participantRPCTokenValidity = 5 * time.Minute
+
+var _ rtc.ServerRPCHandler = (*ParticipantRPCForwarder)(nil)
+
+type participantRPCRequest struct {
+	Room                  string            `json:"room"`
+	RoomSid               string            `json:"roomSid"`
+	ParticipantIdentity   string            `json:"participantIdentity"`
+	ParticipantSid        string            `json:"participantSid"`
+	ParticipantAttributes map[string]string `json:"participantAttributes,omitempty"`
+	RequestID             string            `json:"requestId"`
+	Method                string            `json:"method"`
+	Payload               string            `json:"payload"`
+	ResponseTimeoutMs     uint32            `json:"responseTimeoutMs"`
+}
+
+// ParticipantRPCForwarder forwards RPC requests sent by participants to the server to an HTTP backend.
+// Requests are signed like webhooks: the Authorization header carries a token of the configured API key
+// with the sha256 of the body, so backends can verify them with webhook.Receive.
+// A 2xx response body is returned as the RPC payload, other statuses are returned as RPC errors.
+type ParticipantRPCForwarder struct {
+	config   config.ParticipantRPCConfig
+	apiKey   string
+	provider auth.KeyProvider
+	client   *http.Client
+}
+
+func NewParticipantRPCForwarder(conf *config.Config, provider auth.KeyProvider) (*ParticipantRPCForwarder, error) {
+	rc := conf.ParticipantRPC
+	if rc.URL == "" {
+		return nil, nil
+	}
+
+	apiKey := rc.APIKey
+	if apiKey == "" {
+		apiKey = conf.WebHook.APIKey
+	}
+	if provider.GetSecret(apiKey) == "" {
+		return nil, ErrParticipantRPCMissingAPIKey
+	}
+
+	return &ParticipantRPCForwarder{
+		config:   rc,
+		apiKey:   apiKey,
+		provider: provider,
+		client:   &http.Client{Timeout: rc.Timeout},
+	}, nil
+}
+
+func (f *ParticipantRPCForwarder) HandleRpc(
+	ctx context.Context,
+	room *livekit.Room,
+	caller types.LocalParticipant,
+	req *livekit.RpcRequest,
+) (string, *utils.DataChannelRpcError) {
+	if !f.isMethodAllowed(req.Method) {
+		return "", utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcUnsupportedMethod, "")
+	}
+
+	rpcReq := &participantRPCRequest{
+		Room:                room.Name,
+		RoomSid:             room.Sid,
+		ParticipantIdentity: string(caller.Identity()),
+		ParticipantSid:      string(caller.ID()),
+		RequestID:           req.Id,
+		Method:              req.Method,
+		Payload:             req.Payload,
+		ResponseTimeoutMs:   req.ResponseTimeoutMs,
+	}
+	if grants := caller.ClaimGrants(); grants != nil {
+		rpcReq.ParticipantAttributes = grants.Attributes
+	}
+	body, err := json.Marshal(rpcReq)
+	if err != nil {
+		return "", utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcApplicationError, "")
+	}
+
+	httpReq, err := f.newRequest(ctx, body)
+	if err != nil {
+		caller.GetLogger().Warnw("could not create participant rpc request", err, "method", req.Method)
+		return "", utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcApplicationError, "")
+	}
+
+	res, err := f.client.Do(httpReq)
+	if err != nil {
+		caller.GetLogger().Infow("participant rpc request failed", "error", err, "method", req.Method)
+		if ctx.Err() != nil {
+			return "", utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcResponseTimeout, "")
+		}
+		return "", utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcApplicationError, "")
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, utils.DataChannelRpcMaxPayloadBytes+1))
+	if err != nil {
+		return "", utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcApplicationError, "")
+	}
+	if len(data) > utils.DataChannelRpcMaxPayloadBytes {
+		return "", utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcResponsePayloadTooLarge, "")
+	}
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return string(data), nil
+	case res.StatusCode == http.StatusNotFound:
+		return "", utils.DataChannelRpcErrorFromBuiltInCodes(utils.DataChannelRpcUnsupportedMethod, string(data))
+	default:
+		return "", &utils.DataChannelRpcError{
+			Code:    utils.DataChannelRpcApplicationError,
+			Message: http.StatusText(res.StatusCode),
+			Data:    string(data),
+		}
+	}
+}
+
+func (f *ParticipantRPCForwarder) newRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	// the newest secret of the key is used while it is being rotated
+	secret := f.provider.GetSecret(f.apiKey)
+	if secret == "" {
+		return nil, ErrParticipantRPCMissingAPIKey
+	}
+
+	sum := sha256.Sum256(body)
+	token, err := auth.NewAccessToken(f.apiKey, secret).
+		SetValidFor(participantRPCTokenValidity).
+		SetSha256(base64.StdEncoding.EncodeToString(sum[:])).
+		ToJWT()
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, f.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", token)
+	httpReq.Header.Set("Content-Type", "application/json")
+	return httpReq, nil
+}
+
+func (f *ParticipantRPCForwarder) isMethodAllowed(method string) bool {
+	if len(f.config.Methods) == 0 {
+		return true
+	}
+	for _, pattern := range f.config.Methods {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(method, prefix) {
+				return true
+			}
+		} else if pattern == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/utils"
+	"github.com/livekit/protocol/webhook"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+)
+
+func TestParticipantRPCForwarder(t *testing.T) {
+	provider := auth.NewSimpleKeyProvider(turnTestAPIKey, turnTestAPISecret)
+
+	var received participantRPCRequest
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := webhook.Receive(r, provider)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, json.Unmarshal(body, &received))
+		switch received.Method {
+		case "echo":
+			_, _ = w.Write([]byte(received.Payload))
+		case "fail":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("bad payload"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer backend.Close()
+
+	conf := &config.Config{
+		ParticipantRPC: config.ParticipantRPCConfig{
+			URL:     backend.URL,
+			APIKey:  turnTestAPIKey,
+			Methods: []string{"echo", "fail", "missing.*"},
+		},
+	}
+	f, err := NewParticipantRPCForwarder(conf, provider)
+	require.NoError(t, err)
+
+	caller := &typesfakes.FakeLocalParticipant{}
+	caller.IdentityReturns("caller")
+	caller.IDReturns("PA_caller")
+	caller.GetLoggerReturns(logger.GetLogger())
+	caller.ClaimGrantsReturns(&auth.ClaimGrants{Attributes: map[string]string{"role": "host"}})
+	room := &livekit.Room{Name: "room", Sid: "RM_room"}
+
+	payload, rpcErr := f.HandleRpc(context.Background(), room, caller, &livekit.RpcRequest{Id: "r1", Method: "echo", Payload: "hello"})
+	require.Nil(t, rpcErr)
+	require.Equal(t, "hello", payload)
+	require.Equal(t, "caller", received.ParticipantIdentity)
+	require.Equal(t, "room", received.Room)
+	require.Equal(t, "r1", received.RequestID)
+	require.Equal(t, map[string]string{"role": "host"}, received.ParticipantAttributes)
+
+	_, rpcErr = f.HandleRpc(context.Background(), room, caller, &livekit.RpcRequest{Id: "r2", Method: "fail"})
+	require.NotNil(t, rpcErr)
+	require.Equal(t, utils.DataChannelRpcApplicationError, rpcErr.Code)
+	require.Equal(t, "bad payload", rpcErr.Data)
+
+	_, rpcErr = f.HandleRpc(context.Background(), room, caller, &livekit.RpcRequest{Id: "r3", Method: "missing.method"})
+	require.NotNil(t, rpcErr)
+	require.Equal(t, utils.DataChannelRpcUnsupportedMethod, rpcErr.Code)
+
+	// methods not configured are not forwarded
+	received = participantRPCRequest{}
+	_, rpcErr = f.HandleRpc(context.Background(), room, caller, &livekit.RpcRequest{Id: "r4", Method: "other"})
+	require.NotNil(t, rpcErr)
+	require.Equal(t, utils.DataChannelRpcUnsupportedMethod, rpcErr.Code)
+	require.Empty(t, received.RequestID)
+}
@@ -16,6 +16,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"slices"
 	"strconv"
@@ -34,6 +35,7 @@ import (
 	"github.com/livekit/protocol/utils/guid"
 	"github.com/livekit/psrpc"
 
+	"github.com/livekit/livekit-server/pkg/rtc"
 	"github.com/livekit/livekit-server/version"
 )
 
@@ -61,6 +63,12 @@ const (
 	// RoomLockPrefix is a simple key containing a provided lock uid
 	RoomLockPrefix = "room_lock:"
 
+	// RoomSchedulesKey is a hash of room_name => RoomSchedule json
+	RoomSchedulesKey = "room_schedules"
+
+	// RoomDataHistoryKey is a hash of room_name => data history json
+	RoomDataHistoryKey = "room_data_history"
+
 	// Agents
 	AgentDispatchPrefix = "agent_dispatch:"
 	AgentJobPrefix      = "agent_job:"
@@ -357,6 +365,81 @@ func (s *RedisStore) DeleteParticipant(_ context.Context, roomName livekit.RoomN
 	return s.rc.HDel(s.ctx, key, string(identity)).Err()
 }
 
+func (s *RedisStore) StoreRoomSchedule(_ context.Context, schedule *rtc.RoomSchedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return err
+	}
+
+	return s.rc.HSet(s.ctx, RoomSchedulesKey, schedule.Room, data).Err()
+}
+
+func (s *RedisStore) LoadRoomSchedule(_ context.Context, roomName livekit.RoomName) (*rtc.RoomSchedule, error) {
+	data, err := s.rc.HGet(s.ctx, RoomSchedulesKey, string(roomName)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			err = rtc.ErrRoomScheduleNotFound
+		}
+		return nil, err
+	}
+
+	schedule := &rtc.RoomSchedule{}
+	if err = json.Unmarshal([]byte(data), schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *RedisStore) ListRoomSchedules(_ context.Context) ([]*rtc.RoomSchedule, error) {
+	items, err := s.rc.HVals(s.ctx, RoomSchedulesKey).Result()
+	if err != nil && err != redis.Nil {
+		return nil, errors.Wrap(err, "could not get room schedules")
+	}
+
+	schedules := make([]*rtc.RoomSchedule, 0, len(items))
+	for _, item := range items {
+		schedule := &rtc.RoomSchedule{}
+		if err = json.Unmarshal([]byte(item), schedule); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+func (s *RedisStore) DeleteRoomSchedule(_ context.Context, roomName livekit.RoomName) error {
+	return s.rc.HDel(s.ctx, RoomSchedulesKey, string(roomName)).Err()
+}
+
+func (s *RedisStore) StoreDataHistory(_ context.Context, roomName livekit.RoomName, entries []*rtc.DataHistoryEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	return s.rc.HSet(s.ctx, RoomDataHistoryKey, string(roomName), data).Err()
+}
+
+func (s *RedisStore) LoadDataHistory(_ context.Context, roomName livekit.RoomName) ([]*rtc.DataHistoryEntry, error) {
+	data, err := s.rc.HGet(s.ctx, RoomDataHistoryKey, string(roomName)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			err = rtc.ErrDataHistoryNotFound
+		}
+		return nil, err
+	}
+
+	var entries []*rtc.DataHistoryEntry
+	if err = json.Unmarshal([]byte(data), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *RedisStore) DeleteDataHistory(_ context.Context, roomName livekit.RoomName) error {
+	return s.rc.HDel(s.ctx, RoomDataHistoryKey, string(roomName)).Err()
+}
+
 func (s *RedisStore) StoreEgress(_ context.Context, info *livekit.EgressInfo) error {
 	data, err := proto.Marshal(info)
 	if err != nil {
@@ -17,6 +17,8 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/livekit/protocol/livekit"
@@ -28,13 +30,16 @@ import (
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/routing/selector"
+	"github.com/livekit/livekit-server/pkg/rtc"
 )
 
 type StandardRoomAllocator struct {
-	config    *config.Config
 	router    routing.Router
-	selector  selector.NodeSelector
 	roomStore ObjectStore
+
+	lock     sync.RWMutex
+	config   *config.Config
+	selector selector.NodeSelector
 }
 
 func NewRoomAllocator(conf *config.Config, router routing.Router, rs ObjectStore) (RoomAllocator, error) {
@@ -51,8 +56,37 @@ func NewRoomAllocator(conf *config.Config, router routing.Router, rs ObjectStore
 	}, nil
 }
 
+// UpdateConfig switches to a new config, the node selector is recreated from it
+func (r *StandardRoomAllocator) UpdateConfig(conf *config.Config) error {
+	ns, err := selector.CreateNodeSelector(conf)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.config = conf
+	r.selector = ns
+	return nil
+}
+
+func (r *StandardRoomAllocator) getConfig() *config.Config {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.config
+}
+
+func (r *StandardRoomAllocator) getSelector() selector.NodeSelector {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.selector
+}
+
 func (r *StandardRoomAllocator) AutoCreateEnabled(context.Context) bool {
-	return r.config.Room.AutoCreate
+	return r.getConfig().Room.AutoCreate
 }
 
 // CreateRoom creates a new room from a request and allocates it to a node to handle
@@ -70,6 +104,9 @@ func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.Cre
 	var created bool
 	rm, internal, err := r.roomStore.LoadRoom(ctx, livekit.RoomName(req.Name), true)
 	if errors.Is(err, ErrRoomNotFound) {
+		if err = r.deleteExpiredSchedule(ctx, livekit.RoomName(req.Name)); err != nil {
+			return nil, nil, false, err
+		}
 		created = true
 		now := time.Now()
 		rm = &livekit.Room{
@@ -80,7 +117,7 @@ func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.Cre
 			TurnPassword:   utils.RandomSecret(),
 		}
 		internal = &livekit.RoomInternal{}
-		applyDefaultRoomConfig(rm, internal, &r.config.Room)
+		applyDefaultRoomConfig(rm, internal, &r.getConfig().Room)
 	} else if err != nil {
 		return nil, nil, false, err
 	}
@@ -141,7 +178,7 @@ func (r *StandardRoomAllocator) SelectRoomNode(ctx context.Context, roomName liv
 	// if already assigned and still available, keep it on that node
 	if err == nil && selector.IsAvailable(existing) {
 		// if node hosting the room is full, deny entry
-		if selector.LimitsReached(r.config.Limit, existing.Stats) {
+		if selector.LimitsReached(r.getConfig().Limit, existing.Stats) {
 			return routing.ErrNodeLimitReached
 		}
 
@@ -154,8 +191,13 @@ func (r *StandardRoomAllocator) SelectRoomNode(ctx context.Context, roomName liv
 		if err != nil {
 			return err
 		}
+		// nodes hosting as many rooms as allowed only take participants of their rooms
+		limitConfig := r.getConfig().Limit
+		nodes = slices.DeleteFunc(nodes, func(node *livekit.Node) bool {
+			return selector.RoomLimitReached(limitConfig, node.Stats)
+		})
 
-		node, err := r.selector.SelectNode(nodes)
+		node, err := r.getSelector().SelectNode(nodes)
 		if err != nil {
 			return err
 		}
@@ -172,15 +214,95 @@ func (r *StandardRoomAllocator) SelectRoomNode(ctx context.Context, roomName liv
 	return nil
 }
 
-func (r *StandardRoomAllocator) ValidateCreateRoom(ctx context.Context, roomName livekit.RoomName) error {
+// SelectCascadeNode selects a node to host participants of a room whose node reached its limits,
+// the room is cascaded to the selected node. Nodes the room is already cascaded to are preferred
+// while they have headroom, so that a room does not spread over more nodes than it needs.
+func (r *StandardRoomAllocator) SelectCascadeNode(ctx context.Context, roomName livekit.RoomName) (livekit.NodeID, error) {
+	existing, err := r.router.GetNodeForRoom(ctx, roomName)
+	if err != nil {
+		return "", err
+	}
+	cascadeNodeIDs, err := r.router.GetCascadeNodesForRoom(ctx, roomName)
+	if err != nil {
+		return "", err
+	}
+
+	nodes, err := r.router.ListNodes()
+	if err != nil {
+		return "", err
+	}
+	limitConfig := r.getConfig().Limit
+	nodes = slices.DeleteFunc(nodes, func(node *livekit.Node) bool {
+		return node.Id == existing.Id || selector.LimitsReached(limitConfig, node.Stats)
+	})
+
+	cascadeNodes := slices.DeleteFunc(slices.Clone(nodes), func(node *livekit.Node) bool {
+		return !slices.Contains(cascadeNodeIDs, livekit.NodeID(node.Id))
+	})
+	if len(cascadeNodes) != 0 {
+		node, err := r.getSelector().SelectNode(cascadeNodes)
+		if err != nil {
+			return "", err
+		}
+		return livekit.NodeID(node.Id), nil
+	}
+
+	node, err := r.getSelector().SelectNode(nodes)
+	if err != nil {
+		return "", err
+	}
+	if err = r.router.AddCascadeNodeForRoom(ctx, roomName, livekit.NodeID(node.Id)); err != nil {
+		return "", err
+	}
+
+	logger.Infow("selected cascade node for room", "room", roomName, "selectedNodeID", node.Id, "primaryNodeID", existing.Id)
+	return livekit.NodeID(node.Id), nil
+}
+
+func (r *StandardRoomAllocator) ValidateCreateRoom(ctx context.Context, roomName livekit.RoomName, reconnect bool) error {
 	// when auto create is disabled, we'll check to ensure it's already created
-	if !r.config.Room.AutoCreate && EnsureCreatePermission(ctx) != nil {
+	if !r.getConfig().Room.AutoCreate && EnsureCreatePermission(ctx) != nil {
 		_, _, err := r.roomStore.LoadRoom(ctx, roomName, false)
 		if err != nil {
 			return err
 		}
 	}
-	return nil
+
+	// scheduled rooms can only be joined within their window
+	schedule, err := r.roomStore.LoadRoomSchedule(ctx, roomName)
+	if err != nil {
+		if errors.Is(err, rtc.ErrRoomScheduleNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	if schedule.Expired(now) {
+		// the room has been closed, the name can be used again. The schedule is deleted when the room is created
+		return nil
+	}
+	if reconnect {
+		return schedule.CheckRejoin(now)
+	}
+	return schedule.CheckJoin(now)
+}
+
+// deletes the schedule of a room which has been closed, so that it does not apply to a new room of the same name
+func (r *StandardRoomAllocator) deleteExpiredSchedule(ctx context.Context, roomName livekit.RoomName) error {
+	schedule, err := r.roomStore.LoadRoomSchedule(ctx, roomName)
+	if err != nil {
+		if errors.Is(err, rtc.ErrRoomScheduleNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !schedule.Expired(time.Now()) {
+		return nil
+	}
+
+	logger.Infow("deleting expired room schedule", "room", roomName)
+	return r.roomStore.DeleteRoomSchedule(ctx, roomName)
 }
 
 func applyDefaultRoomConfig(room *livekit.Room, internal *livekit.RoomInternal, conf *config.RoomConfig) {
@@ -206,7 +328,7 @@ func (r *StandardRoomAllocator) applyNamedRoomConfiguration(req *livekit.CreateR
 		return req, nil
 	}
 
-	conf, ok := r.config.Room.RoomConfigurations[req.RoomPreset]
+	conf, ok := r.getConfig().Room.RoomConfigurations[req.RoomPreset]
 	if !ok {
 		return req, psrpc.NewErrorf(psrpc.InvalidArgument, "unknown room configuration in create room request")
 	}
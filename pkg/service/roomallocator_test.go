@@ -17,6 +17,7 @@ package service_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/routing/routingfakes"
+	"github.com/livekit/livekit-server/pkg/rtc"
 	"github.com/livekit/livekit-server/pkg/service"
 	"github.com/livekit/livekit-server/pkg/service/servicefakes"
 )
@@ -85,6 +87,107 @@ func SelectRoomNode(t *testing.T) {
 	})
 }
 
+func TestSelectCascadeNode(t *testing.T) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+	conf.Limit.NumTracks = 10
+
+	newNode := func(id string, numTracks uint32) *livekit.Node {
+		return &livekit.Node{
+			Id:    id,
+			State: livekit.NodeState_SERVING,
+			Stats: &livekit.NodeStats{UpdatedAt: time.Now().Unix(), NumTracksIn: numTracks},
+		}
+	}
+	primary := newNode("ND_primary", 100)
+
+	t.Run("prefers nodes the room is cascaded to", func(t *testing.T) {
+		router := &routingfakes.FakeRouter{}
+		router.GetNodeForRoomReturns(primary, nil)
+		router.GetCascadeNodesForRoomReturns([]livekit.NodeID{"ND_b"}, nil)
+		router.ListNodesReturns([]*livekit.Node{primary, newNode("ND_a", 0), newNode("ND_b", 5)}, nil)
+		ra, err := service.NewRoomAllocator(conf, router, &servicefakes.FakeObjectStore{})
+		require.NoError(t, err)
+
+		for range 5 {
+			nodeID, err := ra.SelectCascadeNode(context.Background(), "room")
+			require.NoError(t, err)
+			require.Equal(t, livekit.NodeID("ND_b"), nodeID)
+		}
+		require.Zero(t, router.AddCascadeNodeForRoomCallCount())
+	})
+
+	t.Run("cascades to a new node when cascade nodes are full", func(t *testing.T) {
+		router := &routingfakes.FakeRouter{}
+		router.GetNodeForRoomReturns(primary, nil)
+		router.GetCascadeNodesForRoomReturns([]livekit.NodeID{"ND_b"}, nil)
+		router.ListNodesReturns([]*livekit.Node{primary, newNode("ND_a", 0), newNode("ND_b", 10)}, nil)
+		ra, err := service.NewRoomAllocator(conf, router, &servicefakes.FakeObjectStore{})
+		require.NoError(t, err)
+
+		nodeID, err := ra.SelectCascadeNode(context.Background(), "room")
+		require.NoError(t, err)
+		require.Equal(t, livekit.NodeID("ND_a"), nodeID)
+		require.Equal(t, 1, router.AddCascadeNodeForRoomCallCount())
+		_, roomName, added := router.AddCascadeNodeForRoomArgsForCall(0)
+		require.Equal(t, livekit.RoomName("room"), roomName)
+		require.Equal(t, livekit.NodeID("ND_a"), added)
+	})
+}
+
+func TestValidateCreateRoom(t *testing.T) {
+	t.Run("scheduled rooms can only be joined within their window", func(t *testing.T) {
+		conf, err := config.NewConfig("", true, nil, nil)
+		require.NoError(t, err)
+
+		store := service.NewLocalStore()
+		ra, err := service.NewRoomAllocator(conf, &routingfakes.FakeRouter{}, store)
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		require.NoError(t, ra.ValidateCreateRoom(ctx, "unscheduled", false))
+
+		now := time.Now()
+		require.NoError(t, store.StoreRoomSchedule(ctx, &rtc.RoomSchedule{
+			Room:      "upcoming",
+			NotBefore: now.Add(time.Hour),
+			NotAfter:  now.Add(2 * time.Hour),
+		}))
+		require.ErrorIs(t, ra.ValidateCreateRoom(ctx, "upcoming", false), rtc.ErrRoomNotYetOpen)
+
+		require.NoError(t, store.StoreRoomSchedule(ctx, &rtc.RoomSchedule{
+			Room:      "open",
+			NotBefore: now.Add(-time.Hour),
+			NotAfter:  now.Add(time.Hour),
+		}))
+		require.NoError(t, ra.ValidateCreateRoom(ctx, "open", false))
+
+		require.NoError(t, store.StoreRoomSchedule(ctx, &rtc.RoomSchedule{
+			Room:        "ended",
+			NotBefore:   now.Add(-2 * time.Hour),
+			NotAfter:    now.Add(-time.Hour),
+			GracePeriod: 2 * time.Hour,
+		}))
+		require.ErrorIs(t, ra.ValidateCreateRoom(ctx, "ended", false), rtc.ErrRoomScheduleEnded)
+		// participants of the room can reconnect until it closes
+		require.NoError(t, ra.ValidateCreateRoom(ctx, "ended", true))
+
+		require.NoError(t, store.StoreRoomSchedule(ctx, &rtc.RoomSchedule{
+			Room:      "expired",
+			NotBefore: now.Add(-2 * time.Hour),
+			NotAfter:  now.Add(-time.Hour),
+		}))
+		require.NoError(t, ra.ValidateCreateRoom(ctx, "expired", false))
+		// validating does not change the schedule, it is deleted when the room is created again
+		_, err = store.LoadRoomSchedule(ctx, "expired")
+		require.NoError(t, err)
+		_, _, _, err = ra.CreateRoom(ctx, &livekit.CreateRoomRequest{Name: "expired"}, true)
+		require.NoError(t, err)
+		_, err = store.LoadRoomSchedule(ctx, "expired")
+		require.ErrorIs(t, err, rtc.ErrRoomScheduleNotFound)
+	})
+}
+
 func newTestRoomAllocator(t *testing.T, conf *config.Config, node *livekit.Node) (service.RoomAllocator, *config.Config) {
 	store := &servicefakes.FakeObjectStore{}
 	store.LoadRoomReturns(nil, nil, service.ErrRoomNotFound)
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/atomic"
 
 	"github.com/livekit/mediatransportutil/pkg/rtcconfig"
 	"github.com/livekit/protocol/auth"
@@ -46,12 +47,15 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/agent"
 	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/impairment"
 	sutils "github.com/livekit/livekit-server/pkg/utils"
 
 	"github.com/livekit/livekit-server/pkg/clientconfiguration"
 	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/portmux"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/cascade"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
@@ -74,6 +78,7 @@ type RoomManager struct {
 	lock sync.RWMutex
 
 	config            *config.Config
+	limits            atomic.Pointer[config.LimitConfig]
 	rtcConfig         *rtc.WebRTCConfig
 	serverInfo        *livekit.ServerInfo
 	currentNode       routing.LocalNode
@@ -92,9 +97,14 @@ type RoomManager struct {
 	bus               psrpc.MessageBus
 
 	rooms map[livekit.RoomName]*rtc.Room
+	// rooms moved to another node while draining, their state is kept when they close on this node
+	handedOverRooms map[livekit.RoomName]livekit.NodeID
+	// participants asked to migrate to the node their room was handed over to, they live on in that node
+	handedOverParticipants map[livekit.ParticipantID]struct{}
 
 	roomServers                  utils.MultitonService[rpc.RoomTopic]
 	agentDispatchServers         utils.MultitonService[rpc.RoomTopic]
+	roomScheduleServers          utils.MultitonService[rpc.RoomTopic]
 	participantServers           utils.MultitonService[rpc.ParticipantTopic]
 	httpSignalParticipantServers utils.MultitonService[rpc.ParticipantTopic]
 	whipParticipantServers       utils.MultitonService[rpc.ParticipantTopic]
@@ -103,6 +113,10 @@ type RoomManager struct {
 
 	forwardStats *sfu.ForwardStats
 
+	participantRPC *ParticipantRPCForwarder
+
+	cascadeRelay *cascade.Relay
+
 	rpc.UnimplementedParticipantServer
 	rpc.UnimplementedRoomServer
 	rpc.UnimplementedRoomManagerServer
@@ -122,8 +136,14 @@ func NewLocalRoomManager(
 	turnAuthHandler *TURNAuthHandler,
 	bus psrpc.MessageBus,
 	forwardStats *sfu.ForwardStats,
+	participantRPC *ParticipantRPCForwarder,
+	portMux *portmux.Mux,
 ) (*RoomManager, error) {
-	rtcConf, err := rtc.NewWebRTCConfig(conf)
+	var iceTCPListener net.Listener
+	if portMux != nil {
+		iceTCPListener = portMux.Listener(portmux.ProtocolICETCP)
+	}
+	rtcConf, err := rtc.NewWebRTCConfig(conf, iceTCPListener)
 	if err != nil {
 		return nil, err
 	}
@@ -144,8 +164,11 @@ func NewLocalRoomManager(
 		turnAuthHandler:   turnAuthHandler,
 		bus:               bus,
 		forwardStats:      forwardStats,
+		participantRPC:    participantRPC,
 
-		rooms: make(map[livekit.RoomName]*rtc.Room),
+		rooms:                  make(map[livekit.RoomName]*rtc.Room),
+		handedOverRooms:        make(map[livekit.RoomName]livekit.NodeID),
+		handedOverParticipants: make(map[livekit.ParticipantID]struct{}),
 
 		iceConfigCache: sutils.NewIceConfigCache[iceConfigCacheKey](0),
 
@@ -158,6 +181,7 @@ func NewLocalRoomManager(
 			NodeId:        string(currentNode.NodeID()),
 		},
 	}
+	r.UpdateLimitConfig(conf.Limit)
 
 	r.roomManagerServer, err = rpc.NewTypedRoomManagerServer(r, bus, rpc.WithServerLogger(logger.GetLogger()), middleware.WithServerMetrics(rpc.PSRPCMetricsObserver{}), psrpc.WithServerChannelSize(conf.PSRPC.BufferSize))
 	if err != nil {
@@ -179,22 +203,216 @@ func NewLocalRoomManager(
 		return nil, err
 	}
 
+	if conf.Cascade.Enabled {
+		conn, err := net.ListenPacket("udp", net.JoinHostPort("", strconv.Itoa(int(conf.Cascade.Port))))
+		if err != nil {
+			return nil, err
+		}
+		advertisedAddr, err := r.cascadeAddr(currentNode.Clone())
+		if err != nil {
+			return nil, err
+		}
+		r.cascadeRelay = cascade.NewRelay(cascade.RelayParams{
+			NodeID:              currentNode.NodeID(),
+			Secret:              conf.Cascade.Secret,
+			Conn:                conn,
+			AdvertisedAddr:      advertisedAddr,
+			SubscriptionTimeout: conf.Cascade.SubscriptionTimeout,
+			TrackResolver:       r.getLocalMediaTrack,
+			ParticipantResolver: r.getCascadeParticipants,
+			OnParticipantUpdate: r.onCascadeParticipantUpdate,
+			Logger:              logger.GetLogger().WithComponent("cascade"),
+		})
+		r.cascadeRelay.Start()
+	}
+
 	return r, nil
 }
 
+// UpdateLimitConfig applies new limits to participants joining from now on
+func (r *RoomManager) UpdateLimitConfig(limits config.LimitConfig) {
+	r.limits.Store(&limits)
+}
+
 func (r *RoomManager) GetRoom(_ context.Context, roomName livekit.RoomName) *rtc.Room {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
 	return r.rooms[roomName]
 }
 
+// CascadeEnabled returns true when rooms of this node can be cascaded to other nodes and rooms of other nodes to this node
+func (r *RoomManager) CascadeEnabled() bool {
+	return r.cascadeRelay != nil
+}
+
+// cascadeAddr returns the address of the relay of a node, tracks published on the node are relayed from it
+func (r *RoomManager) cascadeAddr(node *livekit.Node) (net.Addr, error) {
+	return net.ResolveUDPAddr("udp", net.JoinHostPort(node.Ip, strconv.Itoa(int(r.config.Cascade.Port))))
+}
+
+// getCascadePrimary returns the primary node of a room, the node the room is assigned to. When it is another node,
+// participants were placed on this node because the primary node was full and the room is hosted here as an edge,
+// the address of the primary's relay is returned then.
+func (r *RoomManager) getCascadePrimary(ctx context.Context, roomName livekit.RoomName) (livekit.NodeID, net.Addr) {
+	nodeID := r.currentNode.NodeID()
+	if r.cascadeRelay == nil {
+		return nodeID, nil
+	}
+
+	node, err := r.router.GetNodeForRoom(ctx, roomName)
+	if err != nil || livekit.NodeID(node.Id) == nodeID {
+		return nodeID, nil
+	}
+	addr, err := r.cascadeAddr(node)
+	if err != nil {
+		logger.Warnw("could not resolve cascade address of primary node", err, "room", roomName, "primaryNodeID", node.Id)
+		return nodeID, nil
+	}
+	return livekit.NodeID(node.Id), addr
+}
+
+func (r *RoomManager) getCascadeParticipants(roomName livekit.RoomName) []*cascade.ParticipantUpdate {
+	room := r.GetRoom(context.Background(), roomName)
+	if room == nil {
+		return nil
+	}
+	return room.CascadeParticipants()
+}
+
+func (r *RoomManager) onCascadeParticipantUpdate(roomName livekit.RoomName, nodeID livekit.NodeID, origin net.Addr, update *cascade.ParticipantUpdate) {
+	if room := r.GetRoom(context.Background(), roomName); room != nil {
+		room.UpdateRemoteParticipant(nodeID, origin, update)
+	}
+}
+
+func (r *RoomManager) getLocalMediaTrack(trackID livekit.TrackID) types.LocalMediaTrack {
+	r.lock.RLock()
+	rooms := slices.Collect(maps.Values(r.rooms))
+	r.lock.RUnlock()
+
+	for _, room := range rooms {
+		if track := room.GetLocalMediaTrack(trackID); track != nil {
+			return track
+		}
+	}
+	return nil
+}
+
+// SimulateImpairment impairs packets of a participant connected to this node, only available in development mode
+func (r *RoomManager) SimulateImpairment(roomName livekit.RoomName, identity livekit.ParticipantIdentity, upstream impairment.Config, downstream impairment.Config) error {
+	if !r.config.Development {
+		return ErrDevelopmentOnly
+	}
+
+	room := r.GetRoom(context.Background(), roomName)
+	if room == nil {
+		return ErrRoomNotFound
+	}
+	participant := room.GetParticipant(identity)
+	if participant == nil {
+		return ErrParticipantNotFound
+	}
+	room.SimulateImpairment(participant, upstream, downstream)
+	return nil
+}
+
+// GetImpairment returns impairments of a participant connected to this node
+func (r *RoomManager) GetImpairment(roomName livekit.RoomName, identity livekit.ParticipantIdentity) (upstream impairment.Config, downstream impairment.Config, err error) {
+	room := r.GetRoom(context.Background(), roomName)
+	if room == nil {
+		return upstream, downstream, ErrRoomNotFound
+	}
+	participant := room.GetParticipant(identity)
+	if participant == nil {
+		return upstream, downstream, ErrParticipantNotFound
+	}
+	up, down := participant.GetBufferFactory().GetImpairment()
+	if up != nil {
+		upstream = up.Config()
+	}
+	if down != nil {
+		downstream = down.Config()
+	}
+	return upstream, downstream, nil
+}
+
+// handOverRoom routes new sessions of a room hosted on this node to another node,
+// participants need to be migrated separately
+func (r *RoomManager) handOverRoom(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) error {
+	r.lock.Lock()
+	r.handedOverRooms[roomName] = nodeID
+	r.lock.Unlock()
+
+	if err := r.router.SetNodeForRoom(ctx, roomName, nodeID); err != nil {
+		r.lock.Lock()
+		delete(r.handedOverRooms, roomName)
+		r.lock.Unlock()
+		return err
+	}
+	return nil
+}
+
+// handOverParticipant asks a participant of a handed over room to migrate to the room's new node,
+// it returns false when the participant cannot migrate
+func (r *RoomManager) handOverParticipant(p types.LocalParticipant) bool {
+	r.lock.Lock()
+	r.handedOverParticipants[p.ID()] = struct{}{}
+	r.lock.Unlock()
+
+	if !p.MaybeStartMigration(true, nil) {
+		r.lock.Lock()
+		delete(r.handedOverParticipants, p.ID())
+		r.lock.Unlock()
+		return false
+	}
+	return true
+}
+
+// clearHandedOverParticipant returns true when the participant was handed over to another node
+func (r *RoomManager) clearHandedOverParticipant(participantID livekit.ParticipantID) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, ok := r.handedOverParticipants[participantID]
+	delete(r.handedOverParticipants, participantID)
+	return ok
+}
+
+// drainableRooms returns rooms hosted on this node which have not been handed over to another node
+func (r *RoomManager) drainableRooms() []*rtc.Room {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	rooms := make([]*rtc.Room, 0, len(r.rooms))
+	for name, room := range r.rooms {
+		if _, ok := r.handedOverRooms[name]; !ok {
+			rooms = append(rooms, room)
+		}
+	}
+	return rooms
+}
+
+func (r *RoomManager) isHandedOver(roomName livekit.RoomName) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	_, ok := r.handedOverRooms[roomName]
+	return ok
+}
+
 // deleteRoom completely deletes all room information, including active sessions, room store, and routing info
 func (r *RoomManager) deleteRoom(ctx context.Context, roomName livekit.RoomName) error {
 	logger.Infow("deleting room state", "room", roomName)
 	r.lock.Lock()
 	delete(r.rooms, roomName)
+	_, handedOver := r.handedOverRooms[roomName]
+	delete(r.handedOverRooms, roomName)
 	r.lock.Unlock()
 
+	if handedOver {
+		// the room lives on in another node
+		return nil
+	}
+
 	var err, err2 error
 	wg := sync.WaitGroup{}
 	wg.Add(2)
@@ -207,6 +425,11 @@ func (r *RoomManager) deleteRoom(ctx context.Context, roomName livekit.RoomName)
 	go func() {
 		defer wg.Done()
 		err2 = r.roomStore.DeleteRoom(ctx, roomName)
+		if r.config.Room.DataHistory.IsEnabled() {
+			if err := r.roomStore.DeleteDataHistory(ctx, roomName); err != nil {
+				logger.Warnw("could not delete data history", err, "room", roomName)
+			}
+		}
 	}()
 
 	wg.Wait()
@@ -253,6 +476,7 @@ func (r *RoomManager) Stop() {
 	r.whipServer.Kill()
 	r.roomServers.Kill()
 	r.agentDispatchServers.Kill()
+	r.roomScheduleServers.Kill()
 	r.participantServers.Kill()
 	r.httpSignalParticipantServers.Kill()
 	r.whipParticipantServers.Kill()
@@ -268,6 +492,10 @@ func (r *RoomManager) Stop() {
 
 	r.iceConfigCache.Stop()
 
+	if r.cascadeRelay != nil {
+		r.cascadeRelay.Stop()
+	}
+
 	if r.forwardStats != nil {
 		r.forwardStats.Stop()
 	}
@@ -410,6 +638,7 @@ func (r *RoomManager) StartSession(
 	}
 
 	sid := livekit.ParticipantID(guid.New(utils.ParticipantPrefix))
+	limits := r.limits.Load()
 	pLogger := rtc.LoggerWithParticipant(
 		rtc.LoggerWithRoom(logger.GetLogger(), room.Name(), room.ID()),
 		pi.Identity,
@@ -426,6 +655,20 @@ func (r *RoomManager) StartSession(
 
 	clientConf := r.clientConfManager.GetConfiguration(pi.Client)
 
+	relayOnly := r.config.Room.RelayOnly.Enabled || room.IsRelayOnly() || rtc.RequiresRelayOnly(pi.Grants)
+	if relayOnly {
+		if !r.config.TURN.Enabled {
+			pLogger.Warnw("could not start relay only session", ErrRelayOnlyWithoutTURN)
+			return ErrRelayOnlyWithoutTURN
+		}
+		if clientConf != nil {
+			clientConf = utils.CloneProto(clientConf)
+		} else {
+			clientConf = &livekit.ClientConfiguration{}
+		}
+		clientConf.ForceRelay = livekit.ClientConfigSetting_ENABLED
+	}
+
 	pv := types.ProtocolVersion(pi.Client.Protocol)
 	rtcConf := *r.rtcConfig
 	rtcConf.SetBufferFactory(room.GetBufferFactory())
@@ -478,7 +721,7 @@ func (r *RoomManager) StartSession(
 		Sink:                     responseSink,
 		AudioConfig:              r.config.Audio,
 		VideoConfig:              r.config.Video,
-		LimitConfig:              r.config.Limit,
+		LimitConfig:              *limits,
 		ProtocolVersion:          pv,
 		SessionStartTime:         sessionStartTime,
 		SessionTimer:             observability.NewSessionTimer(sessionStartTime),
@@ -511,8 +754,8 @@ func (r *RoomManager) StartSession(
 		ReconnectOnDataChannelError:     reconnectOnDataChannelError,
 		VersionGenerator:                r.versionGenerator,
 		SubscriberAllowPause:            subscriberAllowPause,
-		SubscriptionLimitAudio:          r.config.Limit.SubscriptionLimitAudio,
-		SubscriptionLimitVideo:          r.config.Limit.SubscriptionLimitVideo,
+		SubscriptionLimitAudio:          limits.SubscriptionLimitAudio,
+		SubscriptionLimitVideo:          limits.SubscriptionLimitVideo,
 		PlayoutDelay:                    roomInternal.GetPlayoutDelay(),
 		SyncStreams:                     roomInternal.GetSyncStreams(),
 		ForwardStats:                    r.forwardStats,
@@ -524,7 +767,12 @@ func (r *RoomManager) StartSession(
 		FireOnTrackBySdp:                true,
 		UseSinglePeerConnection:         pi.UseSinglePeerConnection,
 		EnableDataTracks:                r.config.EnableDataTracks,
+		DataTrackSubscriber:             r.config.DataTrackSubscriber,
+		DataTrackReliable:               r.config.DataTrackReliable,
 		EnableRTPStreamRestartDetection: r.config.RTC.EnableRTPStreamRestartDetection,
+		Timeline:                        room.Timeline().WithParticipant(string(pi.Identity)),
+		RelayOnly:                       relayOnly,
+		RelayAddresses:                  r.config.RTC.NodeIP.ToStringSlice(),
 	})
 	if err != nil {
 		return err
@@ -539,10 +787,14 @@ func (r *RoomManager) StartSession(
 		opts.AutoSubscribeDataTrack = *pi.AutoSubscribeDataTrack
 	}
 	iceServers := r.iceServersForParticipant(apiKey, participant, iceConfig.PreferenceSubscriber == livekit.ICECandidateType_ICT_TLS)
-	if err = room.Join(participant, requestSource, &opts, iceServers); err != nil {
-		pLogger.Errorw("could not join room", err)
-		_ = participant.Close(true, types.ParticipantCloseReasonJoinFailed, false)
-		return err
+	// participants waiting in the lobby join the room once admitted by a host
+	requiresAdmission := room.RequiresAdmission(participant)
+	if !requiresAdmission {
+		if err = room.Join(participant, requestSource, &opts, iceServers); err != nil {
+			pLogger.Errorw("could not join room", err)
+			_ = participant.Close(true, types.ParticipantCloseReasonJoinFailed, false)
+			return err
+		}
 	}
 
 	var participantServerClosers utils.Closers
@@ -567,12 +819,18 @@ func (r *RoomManager) StartSession(
 		}
 	}
 
-	if err = r.roomStore.StoreParticipant(ctx, room.Name(), participant.ToProto()); err != nil {
-		pLogger.Errorw("could not store participant", err)
+	storeParticipant := func() {
+		if err := r.roomStore.StoreParticipant(ctx, room.Name(), participant.ToProto()); err != nil {
+			pLogger.Errorw("could not store participant", err)
+		}
+	}
+	if !requiresAdmission {
+		storeParticipant()
 	}
 
 	persistRoomForParticipantCount := func(proto *livekit.Room) {
-		if !participant.Hidden() && !room.IsClosed() {
+		// the room is stored by its primary node, counting participants of all nodes
+		if !participant.Hidden() && !room.IsClosed() && !room.IsCascadeEdge() {
 			err = r.roomStore.StoreRoom(ctx, proto, room.Internal())
 			if err != nil {
 				logger.Errorw("could not store room", err)
@@ -584,10 +842,28 @@ func (r *RoomManager) StartSession(
 	persistRoomForParticipantCount(room.ToProto())
 
 	clientMeta := &livekit.AnalyticsClientMeta{Region: r.currentNode.Region(), Node: string(r.currentNode.NodeID())}
-	r.telemetry.ParticipantJoined(ctx, protoRoom, participant.ToProto(), pi.Client, clientMeta, true, participant.TelemetryGuard())
+	clientInfo := pi.Client
+	if relayOnly {
+		clientInfo = sutils.ClientInfoWithoutAddress(clientInfo)
+	}
+	// participants waiting in the lobby are reported once admitted
+	joined := atomic.NewBool(false)
+	participantJoined := func() {
+		joined.Store(true)
+		r.telemetry.ParticipantJoined(ctx, protoRoom, participant.ToProto(), clientInfo, clientMeta, true, participant.TelemetryGuard())
+	}
+	if !requiresAdmission {
+		participantJoined()
+	}
 	participant.AddOnClose(types.ParticipantCloseKeyNormal, func(p types.LocalParticipant) {
 		participantServerClosers.Close()
 
+		if r.clearHandedOverParticipant(p.ID()) {
+			// the participant is kept in the store and counted by the node its room was handed over to
+			pLogger.Infow("participant handed over", "closeReason", p.CloseReason())
+			return
+		}
+
 		if err := r.roomStore.DeleteParticipant(ctx, room.Name(), p.Identity()); err != nil {
 			pLogger.Errorw("could not delete participant", err)
 		}
@@ -595,7 +871,9 @@ func (r *RoomManager) StartSession(
 		// update room store with new numParticipants
 		proto := room.ToProto()
 		persistRoomForParticipantCount(proto)
-		r.telemetry.ParticipantLeft(ctx, proto, p.ToProto(), true, participant.TelemetryGuard())
+		if joined.Load() {
+			r.telemetry.ParticipantLeft(ctx, proto, p.ToProto(), true, participant.TelemetryGuard())
+		}
 	})
 	participant.OnClaimsChanged(func(participant types.LocalParticipant) {
 		pLogger.Debugw("refreshing client token after claims change")
@@ -607,11 +885,26 @@ func (r *RoomManager) StartSession(
 		r.iceConfigCache.Put(iceConfigCacheKey{room.Name(), participant.Identity()}, iceConfig)
 	})
 
-	for _, addTrackRequest := range pi.AddTrackRequests {
-		participant.AddTrack(addTrackRequest)
+	onJoined := func() {
+		for _, addTrackRequest := range pi.AddTrackRequests {
+			participant.AddTrack(addTrackRequest)
+		}
+		if pi.PublisherOffer != nil {
+			participant.HandleOffer(pi.PublisherOffer)
+		}
 	}
-	if pi.PublisherOffer != nil {
-		participant.HandleOffer(pi.PublisherOffer)
+	if requiresAdmission {
+		if err = room.JoinLobby(participant, requestSource, &opts, iceServers, func() {
+			storeParticipant()
+			participantJoined()
+			onJoined()
+		}); err != nil {
+			pLogger.Errorw("could not join lobby", err)
+			_ = participant.Close(true, types.ParticipantCloseReasonJoinFailed, false)
+			return err
+		}
+	} else {
+		onJoined()
 	}
 
 	go r.rtcSessionWorker(room, participant, requestSource)
@@ -635,6 +928,8 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, createRoom *livekit.C
 	if err != nil {
 		return nil, err
 	}
+	primaryNodeID, primary := r.getCascadePrimary(ctx, roomName)
+	isCascadeEdge := primary != nil
 
 	r.lock.Lock()
 
@@ -650,52 +945,129 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, createRoom *livekit.C
 		currentRoom = r.rooms[roomName]
 	}
 
+	if isCascadeEdge {
+		// agents are dispatched by the primary node
+		internal = utils.CloneProto(internal)
+		if internal != nil {
+			internal.AgentDispatches = nil
+		}
+	}
+
 	// construct ice servers
 	newRoom := rtc.NewRoom(ri, internal, *r.rtcConfig, r.config.Room, &r.config.Audio, r.serverInfo, r.telemetry, r.agentClient, r.agentStore, r.egressLauncher)
-
-	roomTopic := rpc.FormatRoomTopic(roomName)
-	roomServer := must.Get(rpc.NewTypedRoomServer(r, r.bus))
-	killRoomServer := r.roomServers.Replace(roomTopic, roomServer)
-	if err := roomServer.RegisterAllRoomTopics(roomTopic); err != nil {
-		killRoomServer()
-		r.lock.Unlock()
-		return nil, err
+	if r.cascadeRelay != nil {
+		newRoom.SetCascade(rtc.RoomCascadeParams{
+			Relay:       r.cascadeRelay,
+			IsPrimary:   !isCascadeEdge,
+			VideoConfig: r.config.Video,
+		})
 	}
-	agentDispatchServer := must.Get(rpc.NewTypedAgentDispatchInternalServer(r, r.bus))
-	killDispServer := r.agentDispatchServers.Replace(roomTopic, agentDispatchServer)
-	if err := agentDispatchServer.RegisterAllRoomTopics(roomTopic); err != nil {
-		killRoomServer()
-		killDispServer()
-		r.lock.Unlock()
-		return nil, err
+
+	// rooms are served by their primary node
+	killRoomServer, killDispServer, killScheduleServer := func() {}, func() {}, func() {}
+	if !isCascadeEdge {
+		roomTopic := rpc.FormatRoomTopic(roomName)
+		roomServer := must.Get(rpc.NewTypedRoomServer(r, r.bus))
+		killRoomServer = r.roomServers.Replace(roomTopic, roomServer)
+		if err := roomServer.RegisterAllRoomTopics(roomTopic); err != nil {
+			killRoomServer()
+			r.lock.Unlock()
+			return nil, err
+		}
+		agentDispatchServer := must.Get(rpc.NewTypedAgentDispatchInternalServer(r, r.bus))
+		killDispServer = r.agentDispatchServers.Replace(roomTopic, agentDispatchServer)
+		if err := agentDispatchServer.RegisterAllRoomTopics(roomTopic); err != nil {
+			killRoomServer()
+			killDispServer()
+			r.lock.Unlock()
+			return nil, err
+		}
+		scheduleServer, err := newRoomScheduleServer(r.bus, roomTopic, func(ctx context.Context) error {
+			return r.loadRoomSchedule(ctx, newRoom)
+		})
+		if err != nil {
+			killRoomServer()
+			killDispServer()
+			r.lock.Unlock()
+			return nil, err
+		}
+		killScheduleServer = r.roomScheduleServers.Replace(roomTopic, scheduleServer)
 	}
 
 	newRoom.OnClose(func() {
 		killRoomServer()
 		killDispServer()
+		killScheduleServer()
+		if r.cascadeRelay != nil {
+			r.cascadeRelay.LeaveRoom(roomName)
+		}
 
 		roomInfo := newRoom.ToProto()
-		r.telemetry.RoomEnded(ctx, roomInfo)
+		if !r.isHandedOver(roomName) && !isCascadeEdge {
+			r.telemetry.RoomEnded(ctx, roomInfo)
+		}
 		prometheus.RoomEnded(time.Unix(roomInfo.CreationTime, 0))
-		if err := r.deleteRoom(ctx, roomName); err != nil {
+		if isCascadeEdge {
+			// the room and its state are kept by the primary node
+			r.lock.Lock()
+			delete(r.rooms, roomName)
+			r.lock.Unlock()
+		} else if err := r.deleteRoom(ctx, roomName); err != nil {
 			newRoom.Logger().Errorw("could not delete room", err)
 		}
+		if schedule := newRoom.GetSchedule(); schedule != nil && schedule.Expired(time.Now()) {
+			if err := r.roomStore.DeleteRoomSchedule(ctx, roomName); err != nil {
+				newRoom.Logger().Errorw("could not delete room schedule", err)
+			}
+		}
 
 		newRoom.Logger().Infow("room closed")
 	})
 
 	newRoom.OnRoomUpdated(func() {
+		if isCascadeEdge {
+			return
+		}
 		if err := r.roomStore.StoreRoom(ctx, newRoom.ToProto(), newRoom.Internal()); err != nil {
 			newRoom.Logger().Errorw("could not handle metadata update", err)
 		}
 	})
 
+	if r.participantRPC != nil {
+		newRoom.SetServerRPCHandler(r.participantRPC, r.config.ParticipantRPC)
+	}
+
+	if policy, ok := r.config.Room.AutoSubscribePolicies[createRoom.RoomPreset]; ok && createRoom.RoomPreset != "" {
+		if err := newRoom.SetAutoSubscribePolicy(policy); err != nil {
+			newRoom.Logger().Warnw("could not set auto subscribe policy", err, "preset", createRoom.RoomPreset)
+		}
+	}
+	if createRoom.RoomPreset != "" && slices.Contains(r.config.Room.RelayOnly.RoomPresets, createRoom.RoomPreset) {
+		newRoom.SetRelayOnly(true)
+	}
+
+	newRoom.OnDataHistoryUpdated(func(entries []*rtc.DataHistoryEntry) {
+		if err := r.roomStore.StoreDataHistory(ctx, roomName, entries); err != nil {
+			newRoom.Logger().Errorw("could not store data history", err)
+		}
+	})
+
 	newRoom.OnParticipantChanged(func(p types.Participant) {
+		lp, ok := p.(types.LocalParticipant)
+		if !ok {
+			// participants of other nodes are stored by their node
+			return
+		}
 		if !p.IsDisconnected() {
 			if err := r.roomStore.StoreParticipant(ctx, roomName, p.ToProto()); err != nil {
 				newRoom.Logger().Errorw("could not handle participant change", err)
 			}
 		}
+		if r.cascadeRelay != nil {
+			if err := r.cascadeRelay.UpdateParticipant(roomName, newRoom.CascadeParticipantUpdate(lp)); err != nil {
+				lp.GetLogger().Warnw("could not relay participant to cascade nodes", err)
+			}
+		}
 	})
 
 	r.rooms[roomName] = newRoom
@@ -703,10 +1075,29 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, createRoom *livekit.C
 	r.lock.Unlock()
 
 	newRoom.Hold()
+	if r.cascadeRelay != nil {
+		if err := r.cascadeRelay.JoinRoom(roomName, primaryNodeID, primary); err != nil {
+			newRoom.Logger().Errorw("could not join cascaded room", err)
+		}
+	}
 
-	r.telemetry.RoomStarted(ctx, newRoom.ToProto())
+	if !isCascadeEdge {
+		r.telemetry.RoomStarted(ctx, newRoom.ToProto())
+	}
 	prometheus.RoomStarted()
 
+	if err := r.loadRoomSchedule(ctx, newRoom); err != nil {
+		newRoom.Logger().Warnw("could not load room schedule", err)
+	}
+
+	if r.config.Room.DataHistory.IsEnabled() {
+		if entries, err := r.roomStore.LoadDataHistory(ctx, roomName); err == nil {
+			newRoom.RestoreDataHistory(entries)
+		} else if !errors.Is(err, rtc.ErrDataHistoryNotFound) {
+			newRoom.Logger().Warnw("could not load data history", err)
+		}
+	}
+
 	if created && createRoom.GetEgress().GetRoom() != nil {
 		// ensure room name matches
 		createRoom.Egress.Room.RoomName = createRoom.Name
@@ -725,6 +1116,20 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, createRoom *livekit.C
 	return newRoom, nil
 }
 
+// loadRoomSchedule applies the stored schedule of the room, or clears it once deleted
+func (r *RoomManager) loadRoomSchedule(ctx context.Context, room *rtc.Room) error {
+	schedule, err := r.roomStore.LoadRoomSchedule(ctx, room.Name())
+	switch {
+	case err == nil:
+		room.SetSchedule(schedule)
+	case errors.Is(err, rtc.ErrRoomScheduleNotFound):
+		room.ClearSchedule()
+	default:
+		return err
+	}
+	return nil
+}
+
 // manages an RTC session for a participant, runs on the RTC node
 func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalParticipant, requestSource routing.MessageSource) {
 	pLogger := participant.GetLogger()
@@ -758,6 +1163,10 @@ func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalPa
 			if obj == nil {
 				if room.GetParticipantRequestSource(participant.Identity()) == requestSource {
 					participant.HandleSignalSourceClose()
+				} else if room.IsParticipantPending(participant.Identity()) {
+					// there is no session to resume for a participant which has not been admitted
+					room.RemoveParticipant(participant.Identity(), participant.ID(), types.ParticipantCloseReasonSignalSourceClose)
+					_ = participant.Close(false, types.ParticipantCloseReasonSignalSourceClose, false)
 				}
 				return
 			}
@@ -948,6 +1357,21 @@ func (r *RoomManager) SendData(ctx context.Context, req *livekit.SendDataRequest
 		return nil, ErrRoomNotFound
 	}
 
+	if req.GetTopic() == rtc.LobbyTopic {
+		room.Logger().Debugw("api lobby request")
+		if err := room.HandleLobbyRequest(nil, req.Data); err != nil {
+			return nil, err
+		}
+		return &livekit.SendDataResponse{}, nil
+	}
+	if req.GetTopic() == rtc.DataHistoryTopic {
+		room.Logger().Debugw("api data history request")
+		if err := room.HandleDataHistoryRequest(req.Data); err != nil {
+			return nil, err
+		}
+		return &livekit.SendDataResponse{}, nil
+	}
+
 	room.Logger().Debugw("api send data", "size", len(req.Data))
 	room.SendDataPacket(&livekit.DataPacket{
 		Kind:                  req.Kind,
@@ -1047,8 +1471,7 @@ func (r *RoomManager) iceServersForParticipant(apiKey string, participant types.
 			urls = append(urls, fmt.Sprintf("turns:%s:443?transport=tcp", r.config.TURN.Domain))
 		}
 		if len(urls) > 0 {
-			username, expiry := r.turnAuthHandler.CreateUsername(apiKey, participant.ID(), r.config.TURN.TTLSeconds)
-			password, err := r.turnAuthHandler.CreatePassword(apiKey, participant.ID(), expiry)
+			username, password, err := r.turnAuthHandler.CreateCredentials(apiKey, participant.ID(), r.config.TURN.TTLSeconds)
 			if err != nil {
 				participant.GetLogger().Warnw("could not create turn password", err)
 				hasSTUN = false
@@ -1062,6 +1485,11 @@ func (r *RoomManager) iceServersForParticipant(apiKey string, participant types.
 		}
 	}
 
+	if participant.IsRelayOnly() {
+		// only the embedded TURN server can relay to the SFU addresses allowed in relay only mode
+		return iceServers
+	}
+
 	if len(rtcConf.TURNServers) > 0 {
 		hasSTUN = true
 		for _, s := range r.config.RTC.TURNServers {
@@ -1193,6 +1621,9 @@ func (h *roomManagerParticipantHelper) GetParticipantInfo(pID livekit.Participan
 	if p := h.room.GetParticipantByID(pID); p != nil {
 		return p.ToProto()
 	}
+	if rp := h.room.GetRemoteParticipantByID(pID); rp != nil {
+		return rp.ToProto()
+	}
 	return nil
 }
 
@@ -1219,3 +1650,7 @@ func (h *roomManagerParticipantHelper) ShouldRegressCodec() bool {
 func (h *roomManagerParticipantHelper) GetCachedReliableDataMessage(seqs map[livekit.ParticipantID]uint32) []*types.DataMessageCache {
 	return h.room.GetCachedReliableDataMessage(seqs)
 }
+
+func (h *roomManagerParticipantHelper) GetDataHistory(lp types.LocalParticipant) []*types.DataMessageCache {
+	return h.room.GetDataHistoryForParticipant(lp)
+}
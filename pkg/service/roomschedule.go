@@ -0,0 +1,208 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/rpc"
+	"github.com/livekit/psrpc"
+	"github.com/livekit/psrpc/pkg/client"
+	"github.com/livekit/psrpc/pkg/info"
+	"github.com/livekit/psrpc/pkg/rand"
+	"github.com/livekit/psrpc/pkg/server"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+)
+
+const (
+	roomSchedulesPath = "/room_schedules"
+	roomSchedulePath  = "/room_schedules/{room}"
+
+	maxRoomScheduleSize = 64 * 1024
+)
+
+const reloadRoomScheduleRPC = "ReloadRoomSchedule"
+
+// RoomScheduleService manages scheduled room definitions over HTTP.
+// Schedules are applied when the room is created on a media node, rooms already running are asked to
+// reload their schedule through an internal RPC served by the node hosting the room.
+type RoomScheduleService struct {
+	store          ObjectStore
+	topicFormatter rpc.TopicFormatter
+	client         *client.RPCClient
+}
+
+func NewRoomScheduleService(store ObjectStore, topicFormatter rpc.TopicFormatter, bus psrpc.MessageBus) (*RoomScheduleService, error) {
+	c, err := client.NewRPCClient(newRoomScheduleServiceDefinition(rand.NewClientID()), bus)
+	if err != nil {
+		return nil, err
+	}
+	return &RoomScheduleService{
+		store:          store,
+		topicFormatter: topicFormatter,
+		client:         c,
+	}, nil
+}
+
+func (s *RoomScheduleService) SetupRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+roomSchedulesPath, s.handleList)
+	mux.HandleFunc("GET "+roomSchedulePath, s.handleGet)
+	mux.HandleFunc("PUT "+roomSchedulePath, s.handlePut)
+	mux.HandleFunc("DELETE "+roomSchedulePath, s.handleDelete)
+}
+
+func (s *RoomScheduleService) handleList(w http.ResponseWriter, r *http.Request) {
+	if err := EnsureListPermission(r.Context()); err != nil {
+		HandleErrorJson(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	schedules, err := s.store.ListRoomSchedules(r.Context())
+	if err != nil {
+		HandleErrorJson(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeRoomScheduleResponse(w, schedules)
+}
+
+func (s *RoomScheduleService) handleGet(w http.ResponseWriter, r *http.Request) {
+	if err := EnsureListPermission(r.Context()); err != nil {
+		HandleErrorJson(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	schedule, err := s.store.LoadRoomSchedule(r.Context(), livekit.RoomName(r.PathValue("room")))
+	if err != nil {
+		HandleErrorJson(w, r, roomScheduleErrorStatus(err), err)
+		return
+	}
+	writeRoomScheduleResponse(w, schedule)
+}
+
+func (s *RoomScheduleService) handlePut(w http.ResponseWriter, r *http.Request) {
+	if err := EnsureCreatePermission(r.Context()); err != nil {
+		HandleErrorJson(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRoomScheduleSize))
+	if err != nil {
+		HandleErrorJson(w, r, http.StatusBadRequest, err)
+		return
+	}
+	schedule := &rtc.RoomSchedule{}
+	if err := json.Unmarshal(body, schedule); err != nil {
+		HandleErrorJson(w, r, http.StatusBadRequest, rtc.ErrInvalidRoomSchedule)
+		return
+	}
+	schedule.Room = r.PathValue("room")
+	if err := schedule.Validate(); err != nil {
+		HandleErrorJson(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.store.StoreRoomSchedule(r.Context(), schedule); err != nil {
+		HandleErrorJson(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	s.notifyRoom(r.Context(), livekit.RoomName(schedule.Room))
+	writeRoomScheduleResponse(w, schedule)
+}
+
+func (s *RoomScheduleService) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if err := EnsureCreatePermission(r.Context()); err != nil {
+		HandleErrorJson(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	roomName := livekit.RoomName(r.PathValue("room"))
+	if err := s.store.DeleteRoomSchedule(r.Context(), roomName); err != nil {
+		HandleErrorJson(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	s.notifyRoom(r.Context(), roomName)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// notifyRoom asks the node hosting a running room to reload its schedule from the store
+func (s *RoomScheduleService) notifyRoom(ctx context.Context, roomName livekit.RoomName) {
+	if exists, err := s.store.RoomExists(ctx, roomName); err != nil || !exists {
+		return
+	}
+
+	topic := s.topicFormatter.RoomTopic(ctx, roomName)
+	_, err := client.RequestSingle[*emptypb.Empty](ctx, s.client, reloadRoomScheduleRPC, []string{string(topic)}, &emptypb.Empty{})
+	if err != nil {
+		logger.Warnw("could not update schedule of running room", err, "room", roomName)
+	}
+}
+
+// roomScheduleServer serves schedule reloads of a room on the node hosting it
+type roomScheduleServer struct {
+	rpc *server.RPCServer
+}
+
+func newRoomScheduleServer(
+	bus psrpc.MessageBus,
+	topic rpc.RoomTopic,
+	reload func(ctx context.Context) error,
+) (*roomScheduleServer, error) {
+	s := server.NewRPCServer(newRoomScheduleServiceDefinition(rand.NewServerID()), bus)
+	err := server.RegisterHandler(s, reloadRoomScheduleRPC, []string{string(topic)}, func(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+		if err := reload(ctx); err != nil {
+			return nil, err
+		}
+		return &emptypb.Empty{}, nil
+	}, nil)
+	if err != nil {
+		s.Close(true)
+		return nil, err
+	}
+	return &roomScheduleServer{rpc: s}, nil
+}
+
+func (s *roomScheduleServer) Kill() {
+	s.rpc.Close(true)
+}
+
+func newRoomScheduleServiceDefinition(id string) *info.ServiceDefinition {
+	sd := &info.ServiceDefinition{
+		Name: "RoomSchedule",
+		ID:   id,
+	}
+	sd.RegisterMethod(reloadRoomScheduleRPC, false, false, true, true)
+	return sd
+}
+
+func roomScheduleErrorStatus(err error) int {
+	if errors.Is(err, rtc.ErrRoomScheduleNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func writeRoomScheduleResponse(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -20,6 +20,7 @@ import (
 	"strconv"
 
 	"github.com/twitchtv/twirp"
+	"go.uber.org/atomic"
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
@@ -31,7 +32,7 @@ import (
 )
 
 type RoomService struct {
-	limitConf         config.LimitConfig
+	limitConf         atomic.Pointer[config.LimitConfig]
 	apiConf           config.APIConfig
 	router            routing.MessageRouter
 	roomAllocator     RoomAllocator
@@ -57,7 +58,6 @@ func NewRoomService(
 	participantClient rpc.TypedParticipantClient,
 ) (svc *RoomService, err error) {
 	svc = &RoomService{
-		limitConf:         limitConf,
 		apiConf:           apiConf,
 		router:            router,
 		roomAllocator:     roomAllocator,
@@ -67,9 +67,15 @@ func NewRoomService(
 		roomClient:        roomClient,
 		participantClient: participantClient,
 	}
+	svc.limitConf.Store(&limitConf)
 	return
 }
 
+// UpdateLimitConfig applies new limits to requests received from now on
+func (s *RoomService) UpdateLimitConfig(limitConf config.LimitConfig) {
+	s.limitConf.Store(&limitConf)
+}
+
 func (s *RoomService) CreateRoom(ctx context.Context, req *livekit.CreateRoomRequest) (*livekit.Room, error) {
 	RecordRequest(ctx, req)
 
@@ -80,8 +86,8 @@ func (s *RoomService) CreateRoom(ctx context.Context, req *livekit.CreateRoomReq
 		return nil, ErrEgressNotConnected
 	}
 
-	if !s.limitConf.CheckRoomNameLength(req.Name) {
-		return nil, fmt.Errorf("%w: max length %d", ErrRoomNameExceedsLimits, s.limitConf.MaxRoomNameLength)
+	if limitConf := s.limitConf.Load(); !limitConf.CheckRoomNameLength(req.Name) {
+		return nil, fmt.Errorf("%w: max length %d", ErrRoomNameExceedsLimits, limitConf.MaxRoomNameLength)
 	}
 
 	err := s.roomAllocator.SelectRoomNode(ctx, livekit.RoomName(req.Name), livekit.NodeID(req.NodeId))
@@ -249,16 +255,17 @@ func (s *RoomService) UpdateParticipant(ctx context.Context, req *livekit.Update
 
 	AppendLogFields(ctx, "room", req.Room, "participant", req.Identity)
 
-	if !s.limitConf.CheckParticipantNameLength(req.Name) {
-		return nil, twirp.InvalidArgumentError(ErrNameExceedsLimits.Error(), strconv.Itoa(s.limitConf.MaxParticipantNameLength))
+	limitConf := s.limitConf.Load()
+	if !limitConf.CheckParticipantNameLength(req.Name) {
+		return nil, twirp.InvalidArgumentError(ErrNameExceedsLimits.Error(), strconv.Itoa(limitConf.MaxParticipantNameLength))
 	}
 
-	if !s.limitConf.CheckMetadataSize(req.Metadata) {
-		return nil, twirp.InvalidArgumentError(ErrMetadataExceedsLimits.Error(), strconv.Itoa(int(s.limitConf.MaxMetadataSize)))
+	if !limitConf.CheckMetadataSize(req.Metadata) {
+		return nil, twirp.InvalidArgumentError(ErrMetadataExceedsLimits.Error(), strconv.Itoa(int(limitConf.MaxMetadataSize)))
 	}
 
-	if !s.limitConf.CheckAttributesSize(req.Attributes) {
-		return nil, twirp.InvalidArgumentError(ErrAttributeExceedsLimits.Error(), strconv.Itoa(int(s.limitConf.MaxAttributesSize)))
+	if !limitConf.CheckAttributesSize(req.Attributes) {
+		return nil, twirp.InvalidArgumentError(ErrAttributeExceedsLimits.Error(), strconv.Itoa(int(limitConf.MaxAttributesSize)))
 	}
 
 	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
@@ -320,7 +327,7 @@ func (s *RoomService) UpdateRoomMetadata(ctx context.Context, req *livekit.Updat
 	RecordRequest(ctx, req)
 
 	AppendLogFields(ctx, "room", req.Room, "size", len(req.Metadata))
-	maxMetadataSize := int(s.limitConf.MaxMetadataSize)
+	maxMetadataSize := int(s.limitConf.Load().MaxMetadataSize)
 	if maxMetadataSize > 0 && len(req.Metadata) > maxMetadataSize {
 		return nil, twirp.InvalidArgumentError(ErrMetadataExceedsLimits.Error(), strconv.Itoa(maxMetadataSize))
 	}
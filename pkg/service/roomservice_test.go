@@ -123,7 +123,7 @@ func newTestRoomService(limitConf config.LimitConfig) *TestRoomService {
 		panic(err)
 	}
 	return &TestRoomService{
-		RoomService: *svc,
+		RoomService: svc,
 		router:      router,
 		allocator:   allocator,
 		store:       store,
@@ -131,7 +131,7 @@ func newTestRoomService(limitConf config.LimitConfig) *TestRoomService {
 }
 
 type TestRoomService struct {
-	service.RoomService
+	*service.RoomService
 	router    *routingfakes.FakeRouter
 	allocator *servicefakes.FakeRoomAllocator
 	store     *servicefakes.FakeServiceStore
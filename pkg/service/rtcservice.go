@@ -50,11 +50,12 @@ type RTCService struct {
 	upgrader      websocket.Upgrader
 	config        *config.Config
 	isDev         bool
-	limits        config.LimitConfig
+	limits        atomic.Pointer[config.LimitConfig]
 	telemetry     telemetry.TelemetryService
 
-	mu          sync.Mutex
-	connections map[*websocket.Conn]struct{}
+	mu             sync.Mutex
+	connections    map[*websocket.Conn]struct{}
+	signalSessions map[string]*httpSignalSession
 }
 
 func NewRTCService(
@@ -64,15 +65,17 @@ func NewRTCService(
 	telemetry telemetry.TelemetryService,
 ) *RTCService {
 	s := &RTCService{
-		router:        router,
-		roomAllocator: ra,
-		config:        conf,
-		isDev:         conf.Development,
-		limits:        conf.Limit,
-		telemetry:     telemetry,
-		connections:   map[*websocket.Conn]struct{}{},
+		router:         router,
+		roomAllocator:  ra,
+		config:         conf,
+		isDev:          conf.Development,
+		telemetry:      telemetry,
+		connections:    map[*websocket.Conn]struct{}{},
+		signalSessions: map[string]*httpSignalSession{},
 	}
 
+	s.UpdateLimitConfig(conf.Limit)
+
 	s.upgrader = websocket.Upgrader{
 		EnableCompression: true,
 
@@ -86,11 +89,23 @@ func NewRTCService(
 	return s
 }
 
+// UpdateLimitConfig applies new limits to connections made from now on
+func (s *RTCService) UpdateLimitConfig(limits config.LimitConfig) {
+	s.limits.Store(&limits)
+}
+
 func (s *RTCService) SetupRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/rtc", s.v0)
 	mux.HandleFunc("/rtc/validate", s.v0Validate)
 	mux.HandleFunc("/rtc/v1", s.v1)
 	mux.HandleFunc("/rtc/v1/validate", s.v1Validate)
+
+	if s.config.HTTPSignal.Enabled {
+		mux.HandleFunc("GET "+httpSignalSessionPath+"/events", s.handleSignalSessionEvents)
+		mux.HandleFunc("GET "+httpSignalSessionPath+"/poll", s.handleSignalSessionPoll)
+		mux.HandleFunc("POST "+httpSignalSessionPath+"/signal", s.handleSignalSessionRequest)
+		mux.HandleFunc("DELETE "+httpSignalSessionPath, s.handleSignalSessionDelete)
+	}
 }
 
 func (s *RTCService) v0Validate(w http.ResponseWriter, r *http.Request) {
@@ -148,6 +163,7 @@ func (s *RTCService) validateInternal(
 		}
 
 		params.publish = r.FormValue("publish")
+		params.reconnect = boolValue(r.FormValue("reconnect"))
 
 		attributesStrParam := r.FormValue("attributes")
 		if attributesStrParam != "" {
@@ -199,13 +215,14 @@ func (s *RTCService) validateInternal(
 
 			params.metadata = joinRequest.Metadata
 			params.attributes = joinRequest.ParticipantAttributes
+			params.reconnect = joinRequest.Reconnect
 		}
 	}
 
 	res, code, err := ValidateConnectRequest(
 		lgr,
 		r,
-		s.limits,
+		*s.limits.Load(),
 		params,
 		s.router,
 		s.roomAllocator,
@@ -292,8 +309,12 @@ func (s *RTCService) v1(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *RTCService) serve(w http.ResponseWriter, r *http.Request, needsJoinRequest bool) {
-	// reject non websocket requests
+	// reject non websocket requests, unless the client negotiates an HTTP signal transport
 	if !websocket.IsWebSocketUpgrade(r) {
+		if transport := httpSignalTransport(r); transport != "" && s.config.HTTPSignal.Enabled {
+			s.serveHTTPSignal(w, r, needsJoinRequest, transport)
+			return
+		}
 		w.WriteHeader(404)
 		return
 	}
@@ -581,29 +602,8 @@ func (s *RTCService) serve(w http.ResponseWriter, r *http.Request, needsJoinRequ
 		}
 		signalStats.AddBytes(uint64(count), false)
 
-		switch m := req.Message.(type) {
-		case *livekit.SignalRequest_Ping:
-			count, perr := sigConn.WriteResponse(&livekit.SignalResponse{
-				Message: &livekit.SignalResponse_Pong{
-					//
-					// Although this field is int64, some clients (like JS) cause overflow if nanosecond granularity is used.
-					// So. use UnixMillis().
-					//
-					Pong: time.Now().UnixMilli(),
-				},
-			})
-			if perr == nil {
-				signalStats.AddBytes(uint64(count), true)
-			}
-		case *livekit.SignalRequest_PingReq:
-			count, perr := sigConn.WriteResponse(&livekit.SignalResponse{
-				Message: &livekit.SignalResponse_PongResp{
-					PongResp: &livekit.Pong{
-						LastPingTimestamp: m.PingReq.Timestamp,
-						Timestamp:         time.Now().UnixMilli(),
-					},
-				},
-			})
+		if pong := pongResponse(req); pong != nil {
+			count, perr := sigConn.WriteResponse(pong)
 			if perr == nil {
 				signalStats.AddBytes(uint64(count), true)
 			}
@@ -628,6 +628,7 @@ func (s *RTCService) serve(w http.ResponseWriter, r *http.Request, needsJoinRequ
 func (s *RTCService) DrainConnections(interval time.Duration) {
 	s.mu.Lock()
 	conns := maps.Clone(s.connections)
+	sessions := maps.Clone(s.signalSessions)
 	s.mu.Unlock()
 
 	// jitter drain start
@@ -640,6 +641,10 @@ func (s *RTCService) DrainConnections(interval time.Duration) {
 		_ = c.Close()
 		<-t.C
 	}
+	for _, sess := range sessions {
+		sess.Close()
+		<-t.C
+	}
 }
 
 type connectionResult struct {
@@ -657,7 +662,13 @@ func (s *RTCService) startConnection(
 	var err error
 
 	if err := s.roomAllocator.SelectRoomNode(ctx, roomName, ""); err != nil {
-		return cr, nil, err
+		if !errors.Is(err, routing.ErrNodeLimitReached) || !s.config.Cascade.Enabled {
+			return cr, nil, err
+		}
+		// the room's node is full, the participant joins the room on another node the room is cascaded to
+		if pi.NodeID, err = s.roomAllocator.SelectCascadeNode(ctx, roomName); err != nil {
+			return cr, nil, err
+		}
 	}
 
 	// this needs to be started first *before* using router functions on this node
@@ -699,3 +710,29 @@ func readInitialResponse(source routing.MessageSource, timeout time.Duration) (*
 		}
 	}
 }
+
+// returns the response to a ping request, nil for other requests
+func pongResponse(req *livekit.SignalRequest) *livekit.SignalResponse {
+	switch m := req.Message.(type) {
+	case *livekit.SignalRequest_Ping:
+		return &livekit.SignalResponse{
+			Message: &livekit.SignalResponse_Pong{
+				//
+				// Although this field is int64, some clients (like JS) cause overflow if nanosecond granularity is used.
+				// So. use UnixMillis().
+				//
+				Pong: time.Now().UnixMilli(),
+			},
+		}
+	case *livekit.SignalRequest_PingReq:
+		return &livekit.SignalResponse{
+			Message: &livekit.SignalResponse_PongResp{
+				PongResp: &livekit.Pong{
+					LastPingTimestamp: m.PingReq.Timestamp,
+					Timestamp:         time.Now().UnixMilli(),
+				},
+			},
+		}
+	}
+	return nil
+}
@@ -16,15 +16,18 @@ package service
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	httppprof "net/http/pprof"
 	"runtime"
 	"runtime/pprof"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pion/turn/v5"
@@ -40,8 +43,13 @@ import (
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils/xtwirp"
 
+	"github.com/livekit/livekit-server/pkg/certmanager"
+	"github.com/livekit/livekit-server/pkg/cluster"
 	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/portmux"
 	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/sfu/impairment"
+	"github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/livekit-server/version"
 )
 
@@ -59,6 +67,13 @@ type LivekitServer struct {
 	signalServer *SignalServer
 	turnServer   *turn.Server
 	currentNode  routing.LocalNode
+	drainer      *Drainer
+	health       *NodeHealth
+	reloader     *ConfigReloader
+	portMux      *portmux.Mux
+	certManager  *certmanager.Manager
+	cluster      *cluster.Cluster
+	acmeServer   *http.Server
 	running      atomic.Bool
 	doneChan     chan struct{}
 	closedChan   chan struct{}
@@ -71,6 +86,8 @@ func NewLivekitServer(conf *config.Config,
 	ingressService *IngressService,
 	sipService *SIPService,
 	ioService *IOInfoService,
+	roomScheduleService *RoomScheduleService,
+	dataHistoryService *DataHistoryService,
 	rtcService *RTCService,
 	whipService *WHIPService,
 	agentService *AgentService,
@@ -80,6 +97,10 @@ func NewLivekitServer(conf *config.Config,
 	signalServer *SignalServer,
 	turnServer *turn.Server,
 	currentNode routing.LocalNode,
+	reloader *ConfigReloader,
+	portMux *portmux.Mux,
+	certManager *certmanager.Manager,
+	clusterNode *cluster.Cluster,
 ) (s *LivekitServer, err error) {
 	s = &LivekitServer{
 		config:       conf,
@@ -93,9 +114,20 @@ func NewLivekitServer(conf *config.Config,
 		// turn server starts automatically
 		turnServer:  turnServer,
 		currentNode: currentNode,
+		reloader:    reloader,
+		portMux:     portMux,
+		certManager: certManager,
+		cluster:     clusterNode,
 		closedChan:  make(chan struct{}),
 	}
 
+	if s.drainer, err = NewDrainer(conf, router, roomManager, currentNode); err != nil {
+		return
+	}
+	reloader.OnReload(s.drainer.UpdateNodeSelector)
+	s.health = NewNodeHealth(conf, router, currentNode)
+	reloader.OnReload(s.health.UpdateConfig)
+
 	middlewares := []negroni.Handler{
 		// always first
 		negroni.NewRecovery(),
@@ -112,6 +144,9 @@ func NewLivekitServer(conf *config.Config,
 		}),
 		negroni.HandlerFunc(RemoveDoubleSlashes),
 	}
+	if certManager.VerifiesClientCertificates() {
+		middlewares = append(middlewares, negroni.HandlerFunc(RequireClientCertificate))
+	}
 	if keyProvider != nil {
 		middlewares = append(middlewares, NewAPIKeyAuthMiddleware(keyProvider))
 	}
@@ -138,6 +173,11 @@ func NewLivekitServer(conf *config.Config,
 		mux = http.DefaultServeMux
 		mux.HandleFunc("/debug/goroutine", s.debugGoroutines)
 		mux.HandleFunc("/debug/rooms", s.debugInfo)
+		mux.HandleFunc("/debug/rooms/{room}/timeline", s.debugTimeline)
+		mux.HandleFunc("/debug/impairment/{room}/{identity}", s.debugImpairment)
+		mux.HandleFunc("/debug/drain", s.debugDrain)
+		mux.HandleFunc("/capacity", s.capacity)
+		mux.HandleFunc("/prestop", s.preStop)
 	}
 
 	xtwirp.RegisterServer(mux, roomServer)
@@ -145,15 +185,26 @@ func NewLivekitServer(conf *config.Config,
 	xtwirp.RegisterServer(mux, egressServer)
 	xtwirp.RegisterServer(mux, ingressServer)
 	xtwirp.RegisterServer(mux, sipServer)
+	roomScheduleService.SetupRoutes(mux)
+	dataHistoryService.SetupRoutes(mux)
 	rtcService.SetupRoutes(mux)
 	whipService.SetupRoutes(mux)
 	mux.Handle("/agent", agentService)
+	mux.HandleFunc("/livez", s.livenessCheck)
+	mux.HandleFunc("/readyz", s.readinessCheck)
 	mux.HandleFunc("/", s.defaultHandler)
 
 	s.httpServer = &http.Server{
 		Handler: configureMiddlewares(mux, middlewares...),
 	}
 
+	if conf.TLS.ACME.Enabled && conf.TLS.ACME.HTTPPort > 0 {
+		// answers HTTP-01 challenges, redirects other requests to HTTPS
+		s.acmeServer = &http.Server{
+			Handler: certManager.HTTPHandler(nil),
+		}
+	}
+
 	if conf.PrometheusPort > 0 {
 		logger.Warnw("prometheus_port is deprecated, please switch prometheus.port instead", nil)
 		conf.Prometheus.Port = conf.PrometheusPort
@@ -181,6 +232,15 @@ func NewLivekitServer(conf *config.Config,
 		debugMux.HandleFunc("/debug/pprof/trace", httppprof.Trace)
 		debugMux.HandleFunc("/debug/goroutine", s.debugGoroutines)
 		debugMux.HandleFunc("/debug/rooms", s.debugInfo)
+		debugMux.HandleFunc("/debug/rooms/{room}/timeline", s.debugTimeline)
+		debugMux.HandleFunc("/debug/drain", s.debugDrain)
+		debugMux.HandleFunc("/livez", s.livenessCheck)
+		debugMux.HandleFunc("/readyz", s.readinessCheck)
+		debugMux.HandleFunc("/capacity", s.capacity)
+		debugMux.HandleFunc("/prestop", s.preStop)
+		if conf.Development {
+			debugMux.HandleFunc("/debug/impairment/{room}/{identity}", s.debugImpairment)
+		}
 		s.debugServer = &http.Server{
 			Handler: http.Handler(debugMux),
 		}
@@ -237,13 +297,25 @@ func (s *LivekitServer) Start() error {
 	listeners := make([]net.Listener, 0)
 	promListeners := make([]net.Listener, 0)
 	debugListeners := make([]net.Listener, 0)
+	acmeListeners := make([]net.Listener, 0)
 	for _, addr := range addresses {
 		ln, err := net.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(int(s.config.Port))))
 		if err != nil {
 			return err
 		}
+		if s.certManager.Enabled() {
+			ln = tls.NewListener(ln, s.certManager.TLSConfig("http/1.1"))
+		}
 		listeners = append(listeners, ln)
 
+		if s.acmeServer != nil {
+			ln, err = net.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(int(s.config.TLS.ACME.HTTPPort))))
+			if err != nil {
+				return err
+			}
+			acmeListeners = append(acmeListeners, ln)
+		}
+
 		if s.promServer != nil {
 			ln, err = net.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(int(s.config.Prometheus.Port))))
 			if err != nil {
@@ -261,8 +333,17 @@ func (s *LivekitServer) Start() error {
 		}
 	}
 
+	if s.portMux != nil {
+		muxListeners, err := s.portMuxHTTPListeners()
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, muxListeners...)
+	}
+
 	values := []any{
 		"portHttp", s.config.Port,
+		"tls", s.certManager.Enabled(),
 		"nodeID", s.currentNode.NodeID(),
 		"nodeIP", s.currentNode.NodeIP(),
 		"version", version.Version,
@@ -280,6 +361,9 @@ func (s *LivekitServer) Start() error {
 			"rtc.portICERange", []uint32{s.config.RTC.ICEPortRangeStart, s.config.RTC.ICEPortRangeEnd},
 		)
 	}
+	if s.portMux != nil {
+		values = append(values, "portMux", s.config.PortMux.Port, "portMuxTLS", s.config.PortMux.CertFile != "" || s.certManager.Enabled())
+	}
 	if s.config.Prometheus.Port != 0 {
 		values = append(values, "portPrometheus", s.config.Prometheus.Port)
 	}
@@ -289,6 +373,9 @@ func (s *LivekitServer) Start() error {
 	if s.config.Region != "" {
 		values = append(values, "region", s.config.Region)
 	}
+	if s.acmeServer != nil {
+		values = append(values, "portACME", s.config.TLS.ACME.HTTPPort)
+	}
 	logger.Infow("starting LiveKit server", values...)
 	if runtime.GOOS == "windows" {
 		logger.Infow("Windows detected, capacity management is unavailable")
@@ -302,6 +389,11 @@ func (s *LivekitServer) Start() error {
 		go s.debugServer.Serve(debugLn)
 	}
 
+	for _, acmeLn := range acmeListeners {
+		go s.acmeServer.Serve(acmeLn)
+	}
+	s.certManager.Start()
+
 	if err := s.signalServer.Start(); err != nil {
 		return err
 	}
@@ -320,7 +412,12 @@ func (s *LivekitServer) Start() error {
 		}
 	}()
 
+	if s.portMux != nil {
+		s.portMux.Start()
+	}
+
 	go s.backgroundWorker()
+	s.reloader.Start()
 
 	// give time for Serve goroutine to start
 	time.Sleep(100 * time.Millisecond)
@@ -336,22 +433,42 @@ func (s *LivekitServer) Start() error {
 	if s.debugServer != nil {
 		_ = s.debugServer.Shutdown(ctx)
 	}
+	if s.acmeServer != nil {
+		_ = s.acmeServer.Shutdown(ctx)
+	}
+	s.certManager.Stop()
 
 	if s.turnServer != nil {
 		_ = s.turnServer.Close()
 	}
 
+	if s.portMux != nil {
+		_ = s.portMux.Close()
+	}
+
+	s.reloader.Stop()
 	s.roomManager.Stop()
 	s.signalServer.Stop()
 	s.ioService.Stop()
 
+	// rooms have been closed and their state cleared, other nodes can take over
+	if s.cluster != nil {
+		s.cluster.Leave()
+		s.cluster.Stop()
+	}
+
 	close(s.closedChan)
 	return nil
 }
 
 func (s *LivekitServer) Stop(force bool) {
+	if s.config.Drain.Migrate && !force {
+		s.drainer.Start()
+	} else {
+		s.router.Drain()
+	}
+
 	// wait for all participants to exit
-	s.router.Drain()
 	partTicker := time.NewTicker(5 * time.Second)
 	waitingForParticipants := !force && s.roomManager.HasParticipants()
 	for waitingForParticipants {
@@ -372,6 +489,33 @@ func (s *LivekitServer) Stop(force bool) {
 	<-s.closedChan
 }
 
+// portMuxHTTPListeners returns the listeners of signalling connections accepted on the multiplexed port,
+// HTTPS is terminated with the certificate of the port, or the one of the node
+func (s *LivekitServer) portMuxHTTPListeners() ([]net.Listener, error) {
+	listeners := []net.Listener{s.portMux.Listener(portmux.ProtocolHTTP)}
+
+	var tlsConf *tls.Config
+	switch {
+	case s.config.PortMux.CertFile != "":
+		cert, err := s.certManager.LoadFiles(s.config.PortMux.CertFile, s.config.PortMux.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load port_mux certificate: %w", err)
+		}
+		tlsConf = s.certManager.TLSConfigWithCertificate(cert, "http/1.1")
+	case s.certManager.Enabled():
+		tlsConf = s.certManager.TLSConfig("http/1.1")
+	default:
+		return listeners, nil
+	}
+
+	listeners = append(listeners, tls.NewListener(s.portMux.Listener(portmux.ProtocolHTTPS), tlsConf))
+	return listeners, nil
+}
+
+func (s *LivekitServer) ConfigReloader() *ConfigReloader {
+	return s.reloader
+}
+
 func (s *LivekitServer) RoomManager() *RoomManager {
 	return s.roomManager
 }
@@ -397,6 +541,87 @@ func (s *LivekitServer) debugInfo(w http.ResponseWriter, _ *http.Request) {
 	}
 }
 
+// debugTimeline returns recorded events of a room hosted on this node, oldest first
+func (s *LivekitServer) debugTimeline(w http.ResponseWriter, r *http.Request) {
+	room := s.roomManager.GetRoom(r.Context(), livekit.RoomName(r.PathValue("room")))
+	if room == nil {
+		HandleErrorJson(w, r, http.StatusNotFound, ErrRoomNotFound)
+		return
+	}
+
+	events := room.Timeline().Events()
+	if events == nil {
+		events = []utils.TimelineEvent{}
+	}
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(events)
+}
+
+// debugDrain reports progress of draining (GET) or starts migrating rooms to other nodes (POST)
+func (s *LivekitServer) debugDrain(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		s.drainer.Start()
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.drainer.Status())
+}
+
+type participantImpairment struct {
+	Upstream   impairment.Config `json:"upstream"`
+	Downstream impairment.Config `json:"downstream"`
+}
+
+// debugImpairment gets (GET), sets (PUT) or clears (DELETE) network impairments of a participant connected to this node
+func (s *LivekitServer) debugImpairment(w http.ResponseWriter, r *http.Request) {
+	roomName := livekit.RoomName(r.PathValue("room"))
+	identity := livekit.ParticipantIdentity(r.PathValue("identity"))
+
+	var imp participantImpairment
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if err := json.NewDecoder(io.LimitReader(r.Body, 64*1024)).Decode(&imp); err != nil {
+			HandleErrorJson(w, r, http.StatusBadRequest, err)
+			return
+		}
+		fallthrough
+	case http.MethodDelete:
+		if err := s.roomManager.SimulateImpairment(roomName, identity, imp.Upstream, imp.Downstream); err != nil {
+			HandleErrorJson(w, r, debugImpairmentErrorStatus(err), err)
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var err error
+	imp.Upstream, imp.Downstream, err = s.roomManager.GetImpairment(roomName, identity)
+	if err != nil {
+		HandleErrorJson(w, r, debugImpairmentErrorStatus(err), err)
+		return
+	}
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(imp)
+}
+
+func debugImpairmentErrorStatus(err error) int {
+	switch err {
+	case ErrRoomNotFound, ErrParticipantNotFound:
+		return http.StatusNotFound
+	case ErrDevelopmentOnly:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func (s *LivekitServer) defaultHandler(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path == "/" {
 		s.healthCheck(w, r)
@@ -410,7 +635,7 @@ func (s *LivekitServer) healthCheck(w http.ResponseWriter, _ *http.Request) {
 	if s.Node().Stats != nil {
 		updatedAt = time.Unix(s.Node().Stats.UpdatedAt, 0)
 	}
-	if time.Since(updatedAt) > 4*time.Second {
+	if time.Since(updatedAt) > nodeStatsMaxAge {
 		w.WriteHeader(http.StatusNotAcceptable)
 		_, _ = fmt.Fprintf(w, "Not Ready\nNode Updated At %s", updatedAt)
 		return
@@ -420,6 +645,72 @@ func (s *LivekitServer) healthCheck(w http.ResponseWriter, _ *http.Request) {
 	_, _ = w.Write([]byte("OK"))
 }
 
+// livenessCheck succeeds as long as the server is able to answer requests
+func (s *LivekitServer) livenessCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("OK"))
+}
+
+// readinessCheck fails while the node should not take new sessions
+func (s *LivekitServer) readinessCheck(w http.ResponseWriter, _ *http.Request) {
+	c := s.health.Capacity()
+	if !c.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintf(w, "Not Ready\n%s", strings.Join(c.NotReadyReasons, "\n"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("OK"))
+}
+
+// capacity reports the load of the node and how many rooms and participants it can still take
+func (s *LivekitServer) capacity(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Add("Content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.health.Capacity())
+}
+
+// preStop starts draining the node and blocks until participants are gone or drain.prestop_timeout elapsed,
+// to be used as a preStop hook so that the node is drained before it is sent a termination signal
+func (s *LivekitServer) preStop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	logger.Infow("draining node before stop", "timeout", s.config.Drain.PreStopTimeout)
+	if s.config.Drain.Migrate {
+		s.drainer.Start()
+	} else {
+		s.router.Drain()
+	}
+
+	ctx := r.Context()
+	if s.config.Drain.PreStopTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.Drain.PreStopTimeout)
+		defer cancel()
+	}
+
+	status := http.StatusOK
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+wait:
+	for s.roomManager.HasParticipants() {
+		select {
+		case <-ctx.Done():
+			logger.Infow("node not drained before preStop timeout")
+			status = http.StatusGatewayTimeout
+			break wait
+		case <-ticker.C:
+		}
+	}
+
+	w.Header().Add("Content-type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(s.drainer.Status())
+}
+
 // worker to perform periodic tasks per node
 func (s *LivekitServer) backgroundWorker() {
 	roomTicker := time.NewTicker(1 * time.Second)
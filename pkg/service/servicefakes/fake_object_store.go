@@ -6,11 +6,24 @@ import (
 	"sync"
 	"time"
 
+	"github.com/livekit/livekit-server/pkg/rtc"
 	"github.com/livekit/livekit-server/pkg/service"
 	"github.com/livekit/protocol/livekit"
 )
 
 type FakeObjectStore struct {
+	DeleteDataHistoryStub        func(context.Context, livekit.RoomName) error
+	deleteDataHistoryMutex       sync.RWMutex
+	deleteDataHistoryArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	deleteDataHistoryReturns struct {
+		result1 error
+	}
+	deleteDataHistoryReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DeleteParticipantStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) error
 	deleteParticipantMutex       sync.RWMutex
 	deleteParticipantArgsForCall []struct {
@@ -36,6 +49,18 @@ type FakeObjectStore struct {
 	deleteRoomReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteRoomScheduleStub        func(context.Context, livekit.RoomName) error
+	deleteRoomScheduleMutex       sync.RWMutex
+	deleteRoomScheduleArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	deleteRoomScheduleReturns struct {
+		result1 error
+	}
+	deleteRoomScheduleReturnsOnCall map[int]struct {
+		result1 error
+	}
 	HasParticipantStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) (bool, error)
 	hasParticipantMutex       sync.RWMutex
 	hasParticipantArgsForCall []struct {
@@ -65,6 +90,19 @@ type FakeObjectStore struct {
 		result1 []*livekit.ParticipantInfo
 		result2 error
 	}
+	ListRoomSchedulesStub        func(context.Context) ([]*rtc.RoomSchedule, error)
+	listRoomSchedulesMutex       sync.RWMutex
+	listRoomSchedulesArgsForCall []struct {
+		arg1 context.Context
+	}
+	listRoomSchedulesReturns struct {
+		result1 []*rtc.RoomSchedule
+		result2 error
+	}
+	listRoomSchedulesReturnsOnCall map[int]struct {
+		result1 []*rtc.RoomSchedule
+		result2 error
+	}
 	ListRoomsStub        func(context.Context, []livekit.RoomName) ([]*livekit.Room, error)
 	listRoomsMutex       sync.RWMutex
 	listRoomsArgsForCall []struct {
@@ -79,6 +117,20 @@ type FakeObjectStore struct {
 		result1 []*livekit.Room
 		result2 error
 	}
+	LoadDataHistoryStub        func(context.Context, livekit.RoomName) ([]*rtc.DataHistoryEntry, error)
+	loadDataHistoryMutex       sync.RWMutex
+	loadDataHistoryArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	loadDataHistoryReturns struct {
+		result1 []*rtc.DataHistoryEntry
+		result2 error
+	}
+	loadDataHistoryReturnsOnCall map[int]struct {
+		result1 []*rtc.DataHistoryEntry
+		result2 error
+	}
 	LoadParticipantStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error)
 	loadParticipantMutex       sync.RWMutex
 	loadParticipantArgsForCall []struct {
@@ -111,6 +163,20 @@ type FakeObjectStore struct {
 		result2 *livekit.RoomInternal
 		result3 error
 	}
+	LoadRoomScheduleStub        func(context.Context, livekit.RoomName) (*rtc.RoomSchedule, error)
+	loadRoomScheduleMutex       sync.RWMutex
+	loadRoomScheduleArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	loadRoomScheduleReturns struct {
+		result1 *rtc.RoomSchedule
+		result2 error
+	}
+	loadRoomScheduleReturnsOnCall map[int]struct {
+		result1 *rtc.RoomSchedule
+		result2 error
+	}
 	LockRoomStub        func(context.Context, livekit.RoomName, time.Duration) (string, error)
 	lockRoomMutex       sync.RWMutex
 	lockRoomArgsForCall []struct {
@@ -140,6 +206,19 @@ type FakeObjectStore struct {
 		result1 bool
 		result2 error
 	}
+	StoreDataHistoryStub        func(context.Context, livekit.RoomName, []*rtc.DataHistoryEntry) error
+	storeDataHistoryMutex       sync.RWMutex
+	storeDataHistoryArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 []*rtc.DataHistoryEntry
+	}
+	storeDataHistoryReturns struct {
+		result1 error
+	}
+	storeDataHistoryReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StoreParticipantStub        func(context.Context, livekit.RoomName, *livekit.ParticipantInfo) error
 	storeParticipantMutex       sync.RWMutex
 	storeParticipantArgsForCall []struct {
@@ -166,6 +245,18 @@ type FakeObjectStore struct {
 	storeRoomReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StoreRoomScheduleStub        func(context.Context, *rtc.RoomSchedule) error
+	storeRoomScheduleMutex       sync.RWMutex
+	storeRoomScheduleArgsForCall []struct {
+		arg1 context.Context
+		arg2 *rtc.RoomSchedule
+	}
+	storeRoomScheduleReturns struct {
+		result1 error
+	}
+	storeRoomScheduleReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UnlockRoomStub        func(context.Context, livekit.RoomName, string) error
 	unlockRoomMutex       sync.RWMutex
 	unlockRoomArgsForCall []struct {
@@ -183,6 +274,68 @@ type FakeObjectStore struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeObjectStore) DeleteDataHistory(arg1 context.Context, arg2 livekit.RoomName) error {
+	fake.deleteDataHistoryMutex.Lock()
+	ret, specificReturn := fake.deleteDataHistoryReturnsOnCall[len(fake.deleteDataHistoryArgsForCall)]
+	fake.deleteDataHistoryArgsForCall = append(fake.deleteDataHistoryArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.DeleteDataHistoryStub
+	fakeReturns := fake.deleteDataHistoryReturns
+	fake.recordInvocation("DeleteDataHistory", []interface{}{arg1, arg2})
+	fake.deleteDataHistoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeObjectStore) DeleteDataHistoryCallCount() int {
+	fake.deleteDataHistoryMutex.RLock()
+	defer fake.deleteDataHistoryMutex.RUnlock()
+	return len(fake.deleteDataHistoryArgsForCall)
+}
+
+func (fake *FakeObjectStore) DeleteDataHistoryCalls(stub func(context.Context, livekit.RoomName) error) {
+	fake.deleteDataHistoryMutex.Lock()
+	defer fake.deleteDataHistoryMutex.Unlock()
+	fake.DeleteDataHistoryStub = stub
+}
+
+func (fake *FakeObjectStore) DeleteDataHistoryArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.deleteDataHistoryMutex.RLock()
+	defer fake.deleteDataHistoryMutex.RUnlock()
+	argsForCall := fake.deleteDataHistoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) DeleteDataHistoryReturns(result1 error) {
+	fake.deleteDataHistoryMutex.Lock()
+	defer fake.deleteDataHistoryMutex.Unlock()
+	fake.DeleteDataHistoryStub = nil
+	fake.deleteDataHistoryReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) DeleteDataHistoryReturnsOnCall(i int, result1 error) {
+	fake.deleteDataHistoryMutex.Lock()
+	defer fake.deleteDataHistoryMutex.Unlock()
+	fake.DeleteDataHistoryStub = nil
+	if fake.deleteDataHistoryReturnsOnCall == nil {
+		fake.deleteDataHistoryReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteDataHistoryReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeObjectStore) DeleteParticipant(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity) error {
 	fake.deleteParticipantMutex.Lock()
 	ret, specificReturn := fake.deleteParticipantReturnsOnCall[len(fake.deleteParticipantArgsForCall)]
@@ -308,6 +461,68 @@ func (fake *FakeObjectStore) DeleteRoomReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeObjectStore) DeleteRoomSchedule(arg1 context.Context, arg2 livekit.RoomName) error {
+	fake.deleteRoomScheduleMutex.Lock()
+	ret, specificReturn := fake.deleteRoomScheduleReturnsOnCall[len(fake.deleteRoomScheduleArgsForCall)]
+	fake.deleteRoomScheduleArgsForCall = append(fake.deleteRoomScheduleArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.DeleteRoomScheduleStub
+	fakeReturns := fake.deleteRoomScheduleReturns
+	fake.recordInvocation("DeleteRoomSchedule", []interface{}{arg1, arg2})
+	fake.deleteRoomScheduleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeObjectStore) DeleteRoomScheduleCallCount() int {
+	fake.deleteRoomScheduleMutex.RLock()
+	defer fake.deleteRoomScheduleMutex.RUnlock()
+	return len(fake.deleteRoomScheduleArgsForCall)
+}
+
+func (fake *FakeObjectStore) DeleteRoomScheduleCalls(stub func(context.Context, livekit.RoomName) error) {
+	fake.deleteRoomScheduleMutex.Lock()
+	defer fake.deleteRoomScheduleMutex.Unlock()
+	fake.DeleteRoomScheduleStub = stub
+}
+
+func (fake *FakeObjectStore) DeleteRoomScheduleArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.deleteRoomScheduleMutex.RLock()
+	defer fake.deleteRoomScheduleMutex.RUnlock()
+	argsForCall := fake.deleteRoomScheduleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) DeleteRoomScheduleReturns(result1 error) {
+	fake.deleteRoomScheduleMutex.Lock()
+	defer fake.deleteRoomScheduleMutex.Unlock()
+	fake.DeleteRoomScheduleStub = nil
+	fake.deleteRoomScheduleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) DeleteRoomScheduleReturnsOnCall(i int, result1 error) {
+	fake.deleteRoomScheduleMutex.Lock()
+	defer fake.deleteRoomScheduleMutex.Unlock()
+	fake.DeleteRoomScheduleStub = nil
+	if fake.deleteRoomScheduleReturnsOnCall == nil {
+		fake.deleteRoomScheduleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteRoomScheduleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeObjectStore) HasParticipant(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity) (bool, error) {
 	fake.hasParticipantMutex.Lock()
 	ret, specificReturn := fake.hasParticipantReturnsOnCall[len(fake.hasParticipantArgsForCall)]
@@ -439,6 +654,70 @@ func (fake *FakeObjectStore) ListParticipantsReturnsOnCall(i int, result1 []*liv
 	}{result1, result2}
 }
 
+func (fake *FakeObjectStore) ListRoomSchedules(arg1 context.Context) ([]*rtc.RoomSchedule, error) {
+	fake.listRoomSchedulesMutex.Lock()
+	ret, specificReturn := fake.listRoomSchedulesReturnsOnCall[len(fake.listRoomSchedulesArgsForCall)]
+	fake.listRoomSchedulesArgsForCall = append(fake.listRoomSchedulesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListRoomSchedulesStub
+	fakeReturns := fake.listRoomSchedulesReturns
+	fake.recordInvocation("ListRoomSchedules", []interface{}{arg1})
+	fake.listRoomSchedulesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) ListRoomSchedulesCallCount() int {
+	fake.listRoomSchedulesMutex.RLock()
+	defer fake.listRoomSchedulesMutex.RUnlock()
+	return len(fake.listRoomSchedulesArgsForCall)
+}
+
+func (fake *FakeObjectStore) ListRoomSchedulesCalls(stub func(context.Context) ([]*rtc.RoomSchedule, error)) {
+	fake.listRoomSchedulesMutex.Lock()
+	defer fake.listRoomSchedulesMutex.Unlock()
+	fake.ListRoomSchedulesStub = stub
+}
+
+func (fake *FakeObjectStore) ListRoomSchedulesArgsForCall(i int) context.Context {
+	fake.listRoomSchedulesMutex.RLock()
+	defer fake.listRoomSchedulesMutex.RUnlock()
+	argsForCall := fake.listRoomSchedulesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeObjectStore) ListRoomSchedulesReturns(result1 []*rtc.RoomSchedule, result2 error) {
+	fake.listRoomSchedulesMutex.Lock()
+	defer fake.listRoomSchedulesMutex.Unlock()
+	fake.ListRoomSchedulesStub = nil
+	fake.listRoomSchedulesReturns = struct {
+		result1 []*rtc.RoomSchedule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) ListRoomSchedulesReturnsOnCall(i int, result1 []*rtc.RoomSchedule, result2 error) {
+	fake.listRoomSchedulesMutex.Lock()
+	defer fake.listRoomSchedulesMutex.Unlock()
+	fake.ListRoomSchedulesStub = nil
+	if fake.listRoomSchedulesReturnsOnCall == nil {
+		fake.listRoomSchedulesReturnsOnCall = make(map[int]struct {
+			result1 []*rtc.RoomSchedule
+			result2 error
+		})
+	}
+	fake.listRoomSchedulesReturnsOnCall[i] = struct {
+		result1 []*rtc.RoomSchedule
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeObjectStore) ListRooms(arg1 context.Context, arg2 []livekit.RoomName) ([]*livekit.Room, error) {
 	var arg2Copy []livekit.RoomName
 	if arg2 != nil {
@@ -509,6 +788,71 @@ func (fake *FakeObjectStore) ListRoomsReturnsOnCall(i int, result1 []*livekit.Ro
 	}{result1, result2}
 }
 
+func (fake *FakeObjectStore) LoadDataHistory(arg1 context.Context, arg2 livekit.RoomName) ([]*rtc.DataHistoryEntry, error) {
+	fake.loadDataHistoryMutex.Lock()
+	ret, specificReturn := fake.loadDataHistoryReturnsOnCall[len(fake.loadDataHistoryArgsForCall)]
+	fake.loadDataHistoryArgsForCall = append(fake.loadDataHistoryArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.LoadDataHistoryStub
+	fakeReturns := fake.loadDataHistoryReturns
+	fake.recordInvocation("LoadDataHistory", []interface{}{arg1, arg2})
+	fake.loadDataHistoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) LoadDataHistoryCallCount() int {
+	fake.loadDataHistoryMutex.RLock()
+	defer fake.loadDataHistoryMutex.RUnlock()
+	return len(fake.loadDataHistoryArgsForCall)
+}
+
+func (fake *FakeObjectStore) LoadDataHistoryCalls(stub func(context.Context, livekit.RoomName) ([]*rtc.DataHistoryEntry, error)) {
+	fake.loadDataHistoryMutex.Lock()
+	defer fake.loadDataHistoryMutex.Unlock()
+	fake.LoadDataHistoryStub = stub
+}
+
+func (fake *FakeObjectStore) LoadDataHistoryArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.loadDataHistoryMutex.RLock()
+	defer fake.loadDataHistoryMutex.RUnlock()
+	argsForCall := fake.loadDataHistoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) LoadDataHistoryReturns(result1 []*rtc.DataHistoryEntry, result2 error) {
+	fake.loadDataHistoryMutex.Lock()
+	defer fake.loadDataHistoryMutex.Unlock()
+	fake.LoadDataHistoryStub = nil
+	fake.loadDataHistoryReturns = struct {
+		result1 []*rtc.DataHistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) LoadDataHistoryReturnsOnCall(i int, result1 []*rtc.DataHistoryEntry, result2 error) {
+	fake.loadDataHistoryMutex.Lock()
+	defer fake.loadDataHistoryMutex.Unlock()
+	fake.LoadDataHistoryStub = nil
+	if fake.loadDataHistoryReturnsOnCall == nil {
+		fake.loadDataHistoryReturnsOnCall = make(map[int]struct {
+			result1 []*rtc.DataHistoryEntry
+			result2 error
+		})
+	}
+	fake.loadDataHistoryReturnsOnCall[i] = struct {
+		result1 []*rtc.DataHistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeObjectStore) LoadParticipant(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error) {
 	fake.loadParticipantMutex.Lock()
 	ret, specificReturn := fake.loadParticipantReturnsOnCall[len(fake.loadParticipantArgsForCall)]
@@ -644,6 +988,71 @@ func (fake *FakeObjectStore) LoadRoomReturnsOnCall(i int, result1 *livekit.Room,
 	}{result1, result2, result3}
 }
 
+func (fake *FakeObjectStore) LoadRoomSchedule(arg1 context.Context, arg2 livekit.RoomName) (*rtc.RoomSchedule, error) {
+	fake.loadRoomScheduleMutex.Lock()
+	ret, specificReturn := fake.loadRoomScheduleReturnsOnCall[len(fake.loadRoomScheduleArgsForCall)]
+	fake.loadRoomScheduleArgsForCall = append(fake.loadRoomScheduleArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.LoadRoomScheduleStub
+	fakeReturns := fake.loadRoomScheduleReturns
+	fake.recordInvocation("LoadRoomSchedule", []interface{}{arg1, arg2})
+	fake.loadRoomScheduleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) LoadRoomScheduleCallCount() int {
+	fake.loadRoomScheduleMutex.RLock()
+	defer fake.loadRoomScheduleMutex.RUnlock()
+	return len(fake.loadRoomScheduleArgsForCall)
+}
+
+func (fake *FakeObjectStore) LoadRoomScheduleCalls(stub func(context.Context, livekit.RoomName) (*rtc.RoomSchedule, error)) {
+	fake.loadRoomScheduleMutex.Lock()
+	defer fake.loadRoomScheduleMutex.Unlock()
+	fake.LoadRoomScheduleStub = stub
+}
+
+func (fake *FakeObjectStore) LoadRoomScheduleArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.loadRoomScheduleMutex.RLock()
+	defer fake.loadRoomScheduleMutex.RUnlock()
+	argsForCall := fake.loadRoomScheduleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) LoadRoomScheduleReturns(result1 *rtc.RoomSchedule, result2 error) {
+	fake.loadRoomScheduleMutex.Lock()
+	defer fake.loadRoomScheduleMutex.Unlock()
+	fake.LoadRoomScheduleStub = nil
+	fake.loadRoomScheduleReturns = struct {
+		result1 *rtc.RoomSchedule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) LoadRoomScheduleReturnsOnCall(i int, result1 *rtc.RoomSchedule, result2 error) {
+	fake.loadRoomScheduleMutex.Lock()
+	defer fake.loadRoomScheduleMutex.Unlock()
+	fake.LoadRoomScheduleStub = nil
+	if fake.loadRoomScheduleReturnsOnCall == nil {
+		fake.loadRoomScheduleReturnsOnCall = make(map[int]struct {
+			result1 *rtc.RoomSchedule
+			result2 error
+		})
+	}
+	fake.loadRoomScheduleReturnsOnCall[i] = struct {
+		result1 *rtc.RoomSchedule
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeObjectStore) LockRoom(arg1 context.Context, arg2 livekit.RoomName, arg3 time.Duration) (string, error) {
 	fake.lockRoomMutex.Lock()
 	ret, specificReturn := fake.lockRoomReturnsOnCall[len(fake.lockRoomArgsForCall)]
@@ -775,6 +1184,74 @@ func (fake *FakeObjectStore) RoomExistsReturnsOnCall(i int, result1 bool, result
 	}{result1, result2}
 }
 
+func (fake *FakeObjectStore) StoreDataHistory(arg1 context.Context, arg2 livekit.RoomName, arg3 []*rtc.DataHistoryEntry) error {
+	var arg3Copy []*rtc.DataHistoryEntry
+	if arg3 != nil {
+		arg3Copy = make([]*rtc.DataHistoryEntry, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.storeDataHistoryMutex.Lock()
+	ret, specificReturn := fake.storeDataHistoryReturnsOnCall[len(fake.storeDataHistoryArgsForCall)]
+	fake.storeDataHistoryArgsForCall = append(fake.storeDataHistoryArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 []*rtc.DataHistoryEntry
+	}{arg1, arg2, arg3Copy})
+	stub := fake.StoreDataHistoryStub
+	fakeReturns := fake.storeDataHistoryReturns
+	fake.recordInvocation("StoreDataHistory", []interface{}{arg1, arg2, arg3Copy})
+	fake.storeDataHistoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeObjectStore) StoreDataHistoryCallCount() int {
+	fake.storeDataHistoryMutex.RLock()
+	defer fake.storeDataHistoryMutex.RUnlock()
+	return len(fake.storeDataHistoryArgsForCall)
+}
+
+func (fake *FakeObjectStore) StoreDataHistoryCalls(stub func(context.Context, livekit.RoomName, []*rtc.DataHistoryEntry) error) {
+	fake.storeDataHistoryMutex.Lock()
+	defer fake.storeDataHistoryMutex.Unlock()
+	fake.StoreDataHistoryStub = stub
+}
+
+func (fake *FakeObjectStore) StoreDataHistoryArgsForCall(i int) (context.Context, livekit.RoomName, []*rtc.DataHistoryEntry) {
+	fake.storeDataHistoryMutex.RLock()
+	defer fake.storeDataHistoryMutex.RUnlock()
+	argsForCall := fake.storeDataHistoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeObjectStore) StoreDataHistoryReturns(result1 error) {
+	fake.storeDataHistoryMutex.Lock()
+	defer fake.storeDataHistoryMutex.Unlock()
+	fake.StoreDataHistoryStub = nil
+	fake.storeDataHistoryReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) StoreDataHistoryReturnsOnCall(i int, result1 error) {
+	fake.storeDataHistoryMutex.Lock()
+	defer fake.storeDataHistoryMutex.Unlock()
+	fake.StoreDataHistoryStub = nil
+	if fake.storeDataHistoryReturnsOnCall == nil {
+		fake.storeDataHistoryReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeDataHistoryReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeObjectStore) StoreParticipant(arg1 context.Context, arg2 livekit.RoomName, arg3 *livekit.ParticipantInfo) error {
 	fake.storeParticipantMutex.Lock()
 	ret, specificReturn := fake.storeParticipantReturnsOnCall[len(fake.storeParticipantArgsForCall)]
@@ -901,6 +1378,68 @@ func (fake *FakeObjectStore) StoreRoomReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeObjectStore) StoreRoomSchedule(arg1 context.Context, arg2 *rtc.RoomSchedule) error {
+	fake.storeRoomScheduleMutex.Lock()
+	ret, specificReturn := fake.storeRoomScheduleReturnsOnCall[len(fake.storeRoomScheduleArgsForCall)]
+	fake.storeRoomScheduleArgsForCall = append(fake.storeRoomScheduleArgsForCall, struct {
+		arg1 context.Context
+		arg2 *rtc.RoomSchedule
+	}{arg1, arg2})
+	stub := fake.StoreRoomScheduleStub
+	fakeReturns := fake.storeRoomScheduleReturns
+	fake.recordInvocation("StoreRoomSchedule", []interface{}{arg1, arg2})
+	fake.storeRoomScheduleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeObjectStore) StoreRoomScheduleCallCount() int {
+	fake.storeRoomScheduleMutex.RLock()
+	defer fake.storeRoomScheduleMutex.RUnlock()
+	return len(fake.storeRoomScheduleArgsForCall)
+}
+
+func (fake *FakeObjectStore) StoreRoomScheduleCalls(stub func(context.Context, *rtc.RoomSchedule) error) {
+	fake.storeRoomScheduleMutex.Lock()
+	defer fake.storeRoomScheduleMutex.Unlock()
+	fake.StoreRoomScheduleStub = stub
+}
+
+func (fake *FakeObjectStore) StoreRoomScheduleArgsForCall(i int) (context.Context, *rtc.RoomSchedule) {
+	fake.storeRoomScheduleMutex.RLock()
+	defer fake.storeRoomScheduleMutex.RUnlock()
+	argsForCall := fake.storeRoomScheduleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) StoreRoomScheduleReturns(result1 error) {
+	fake.storeRoomScheduleMutex.Lock()
+	defer fake.storeRoomScheduleMutex.Unlock()
+	fake.StoreRoomScheduleStub = nil
+	fake.storeRoomScheduleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) StoreRoomScheduleReturnsOnCall(i int, result1 error) {
+	fake.storeRoomScheduleMutex.Lock()
+	defer fake.storeRoomScheduleMutex.Unlock()
+	fake.StoreRoomScheduleStub = nil
+	if fake.storeRoomScheduleReturnsOnCall == nil {
+		fake.storeRoomScheduleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeRoomScheduleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeObjectStore) UnlockRoom(arg1 context.Context, arg2 livekit.RoomName, arg3 string) error {
 	fake.unlockRoomMutex.Lock()
 	ret, specificReturn := fake.unlockRoomReturnsOnCall[len(fake.unlockRoomArgsForCall)]
@@ -5,6 +5,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/service"
 	"github.com/livekit/protocol/livekit"
 )
@@ -40,6 +41,20 @@ type FakeRoomAllocator struct {
 		result3 bool
 		result4 error
 	}
+	SelectCascadeNodeStub        func(context.Context, livekit.RoomName) (livekit.NodeID, error)
+	selectCascadeNodeMutex       sync.RWMutex
+	selectCascadeNodeArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	selectCascadeNodeReturns struct {
+		result1 livekit.NodeID
+		result2 error
+	}
+	selectCascadeNodeReturnsOnCall map[int]struct {
+		result1 livekit.NodeID
+		result2 error
+	}
 	SelectRoomNodeStub        func(context.Context, livekit.RoomName, livekit.NodeID) error
 	selectRoomNodeMutex       sync.RWMutex
 	selectRoomNodeArgsForCall []struct {
@@ -53,11 +68,23 @@ type FakeRoomAllocator struct {
 	selectRoomNodeReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ValidateCreateRoomStub        func(context.Context, livekit.RoomName) error
+	UpdateConfigStub        func(*config.Config) error
+	updateConfigMutex       sync.RWMutex
+	updateConfigArgsForCall []struct {
+		arg1 *config.Config
+	}
+	updateConfigReturns struct {
+		result1 error
+	}
+	updateConfigReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ValidateCreateRoomStub        func(context.Context, livekit.RoomName, bool) error
 	validateCreateRoomMutex       sync.RWMutex
 	validateCreateRoomArgsForCall []struct {
 		arg1 context.Context
 		arg2 livekit.RoomName
+		arg3 bool
 	}
 	validateCreateRoomReturns struct {
 		result1 error
@@ -202,6 +229,71 @@ func (fake *FakeRoomAllocator) CreateRoomReturnsOnCall(i int, result1 *livekit.R
 	}{result1, result2, result3, result4}
 }
 
+func (fake *FakeRoomAllocator) SelectCascadeNode(arg1 context.Context, arg2 livekit.RoomName) (livekit.NodeID, error) {
+	fake.selectCascadeNodeMutex.Lock()
+	ret, specificReturn := fake.selectCascadeNodeReturnsOnCall[len(fake.selectCascadeNodeArgsForCall)]
+	fake.selectCascadeNodeArgsForCall = append(fake.selectCascadeNodeArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.SelectCascadeNodeStub
+	fakeReturns := fake.selectCascadeNodeReturns
+	fake.recordInvocation("SelectCascadeNode", []interface{}{arg1, arg2})
+	fake.selectCascadeNodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNodeCallCount() int {
+	fake.selectCascadeNodeMutex.RLock()
+	defer fake.selectCascadeNodeMutex.RUnlock()
+	return len(fake.selectCascadeNodeArgsForCall)
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNodeCalls(stub func(context.Context, livekit.RoomName) (livekit.NodeID, error)) {
+	fake.selectCascadeNodeMutex.Lock()
+	defer fake.selectCascadeNodeMutex.Unlock()
+	fake.SelectCascadeNodeStub = stub
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNodeArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.selectCascadeNodeMutex.RLock()
+	defer fake.selectCascadeNodeMutex.RUnlock()
+	argsForCall := fake.selectCascadeNodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNodeReturns(result1 livekit.NodeID, result2 error) {
+	fake.selectCascadeNodeMutex.Lock()
+	defer fake.selectCascadeNodeMutex.Unlock()
+	fake.SelectCascadeNodeStub = nil
+	fake.selectCascadeNodeReturns = struct {
+		result1 livekit.NodeID
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomAllocator) SelectCascadeNodeReturnsOnCall(i int, result1 livekit.NodeID, result2 error) {
+	fake.selectCascadeNodeMutex.Lock()
+	defer fake.selectCascadeNodeMutex.Unlock()
+	fake.SelectCascadeNodeStub = nil
+	if fake.selectCascadeNodeReturnsOnCall == nil {
+		fake.selectCascadeNodeReturnsOnCall = make(map[int]struct {
+			result1 livekit.NodeID
+			result2 error
+		})
+	}
+	fake.selectCascadeNodeReturnsOnCall[i] = struct {
+		result1 livekit.NodeID
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRoomAllocator) SelectRoomNode(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.NodeID) error {
 	fake.selectRoomNodeMutex.Lock()
 	ret, specificReturn := fake.selectRoomNodeReturnsOnCall[len(fake.selectRoomNodeArgsForCall)]
@@ -265,19 +357,81 @@ func (fake *FakeRoomAllocator) SelectRoomNodeReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
-func (fake *FakeRoomAllocator) ValidateCreateRoom(arg1 context.Context, arg2 livekit.RoomName) error {
+func (fake *FakeRoomAllocator) UpdateConfig(arg1 *config.Config) error {
+	fake.updateConfigMutex.Lock()
+	ret, specificReturn := fake.updateConfigReturnsOnCall[len(fake.updateConfigArgsForCall)]
+	fake.updateConfigArgsForCall = append(fake.updateConfigArgsForCall, struct {
+		arg1 *config.Config
+	}{arg1})
+	stub := fake.UpdateConfigStub
+	fakeReturns := fake.updateConfigReturns
+	fake.recordInvocation("UpdateConfig", []interface{}{arg1})
+	fake.updateConfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRoomAllocator) UpdateConfigCallCount() int {
+	fake.updateConfigMutex.RLock()
+	defer fake.updateConfigMutex.RUnlock()
+	return len(fake.updateConfigArgsForCall)
+}
+
+func (fake *FakeRoomAllocator) UpdateConfigCalls(stub func(*config.Config) error) {
+	fake.updateConfigMutex.Lock()
+	defer fake.updateConfigMutex.Unlock()
+	fake.UpdateConfigStub = stub
+}
+
+func (fake *FakeRoomAllocator) UpdateConfigArgsForCall(i int) *config.Config {
+	fake.updateConfigMutex.RLock()
+	defer fake.updateConfigMutex.RUnlock()
+	argsForCall := fake.updateConfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRoomAllocator) UpdateConfigReturns(result1 error) {
+	fake.updateConfigMutex.Lock()
+	defer fake.updateConfigMutex.Unlock()
+	fake.UpdateConfigStub = nil
+	fake.updateConfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomAllocator) UpdateConfigReturnsOnCall(i int, result1 error) {
+	fake.updateConfigMutex.Lock()
+	defer fake.updateConfigMutex.Unlock()
+	fake.UpdateConfigStub = nil
+	if fake.updateConfigReturnsOnCall == nil {
+		fake.updateConfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateConfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomAllocator) ValidateCreateRoom(arg1 context.Context, arg2 livekit.RoomName, arg3 bool) error {
 	fake.validateCreateRoomMutex.Lock()
 	ret, specificReturn := fake.validateCreateRoomReturnsOnCall[len(fake.validateCreateRoomArgsForCall)]
 	fake.validateCreateRoomArgsForCall = append(fake.validateCreateRoomArgsForCall, struct {
 		arg1 context.Context
 		arg2 livekit.RoomName
-	}{arg1, arg2})
+		arg3 bool
+	}{arg1, arg2, arg3})
 	stub := fake.ValidateCreateRoomStub
 	fakeReturns := fake.validateCreateRoomReturns
-	fake.recordInvocation("ValidateCreateRoom", []interface{}{arg1, arg2})
+	fake.recordInvocation("ValidateCreateRoom", []interface{}{arg1, arg2, arg3})
 	fake.validateCreateRoomMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -291,17 +445,17 @@ func (fake *FakeRoomAllocator) ValidateCreateRoomCallCount() int {
 	return len(fake.validateCreateRoomArgsForCall)
 }
 
-func (fake *FakeRoomAllocator) ValidateCreateRoomCalls(stub func(context.Context, livekit.RoomName) error) {
+func (fake *FakeRoomAllocator) ValidateCreateRoomCalls(stub func(context.Context, livekit.RoomName, bool) error) {
 	fake.validateCreateRoomMutex.Lock()
 	defer fake.validateCreateRoomMutex.Unlock()
 	fake.ValidateCreateRoomStub = stub
 }
 
-func (fake *FakeRoomAllocator) ValidateCreateRoomArgsForCall(i int) (context.Context, livekit.RoomName) {
+func (fake *FakeRoomAllocator) ValidateCreateRoomArgsForCall(i int) (context.Context, livekit.RoomName, bool) {
 	fake.validateCreateRoomMutex.RLock()
 	defer fake.validateCreateRoomMutex.RUnlock()
 	argsForCall := fake.validateCreateRoomArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeRoomAllocator) ValidateCreateRoomReturns(result1 error) {
@@ -16,6 +16,7 @@ package service
 
 import (
 	"context"
+	"slices"
 
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
@@ -105,16 +106,35 @@ func (s *defaultSessionHandler) HandleSession(
 	}
 
 	if livekit.NodeID(rtcNode.Id) != s.currentNode.NodeID() {
-		err = routing.ErrIncorrectRTCNode
-		logger.Errorw("called participant on incorrect node", err,
-			"rtcNode", rtcNode,
-		)
-		return err
+		// participants are placed on other nodes the room is cascaded to when the room's node is full
+		isCascadeNode, err := s.isCascadeNode(ctx, livekit.RoomName(pi.CreateRoom.Name))
+		if err != nil {
+			return err
+		}
+		if !isCascadeNode {
+			err = routing.ErrIncorrectRTCNode
+			logger.Errorw("called participant on incorrect node", err,
+				"rtcNode", rtcNode,
+			)
+			return err
+		}
 	}
 
 	return s.roomManager.StartSession(ctx, pi, requestSource, responseSink, false)
 }
 
+// isCascadeNode returns true when the allocator cascaded the room to this node
+func (s *defaultSessionHandler) isCascadeNode(ctx context.Context, roomName livekit.RoomName) (bool, error) {
+	if !s.roomManager.CascadeEnabled() {
+		return false, nil
+	}
+	nodeIDs, err := s.router.GetCascadeNodesForRoom(ctx, roomName)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(nodeIDs, s.currentNode.NodeID()), nil
+}
+
 func (s *SignalServer) Start() error {
 	logger.Debugw("starting relay signal server", "topic", s.nodeID)
 	return s.server.RegisterAllNodeTopics(s.nodeID)
@@ -17,6 +17,7 @@ package service
 import (
 	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"strconv"
@@ -27,13 +28,16 @@ import (
 	"github.com/pion/stun/v3"
 	"github.com/pion/turn/v5"
 	"github.com/pkg/errors"
+	"go.uber.org/atomic"
 
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/logger/pionlogger"
 
+	"github.com/livekit/livekit-server/pkg/certmanager"
 	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/portmux"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
 )
@@ -42,17 +46,22 @@ const (
 	LivekitRealm = "livekit"
 
 	allocateRetries = 50
+
+	turnAuthFailureMalformed     = "malformed_username"
+	turnAuthFailureExpired       = "expired"
+	turnAuthFailureInvalidAPIKey = "invalid_api_key"
+	turnAuthFailureIntegrity     = "integrity"
 )
 
 var ErrExpired = errors.New("expired")
 
-func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, standalone bool) (*turn.Server, error) {
+func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, peerFilter *TURNPeerFilter, portMux *portmux.Mux, certManager *certmanager.Manager, standalone bool) (*turn.Server, error) {
 	turnConf := conf.TURN
 	if !turnConf.Enabled {
 		return nil, nil
 	}
 
-	if turnConf.TLSPort <= 0 && turnConf.UDPPort <= 0 {
+	if turnConf.TLSPort <= 0 && turnConf.UDPPort <= 0 && portMux == nil {
 		return nil, errors.New("invalid TURN ports")
 	} else if turnConf.TLSPort > 0 {
 		if turnConf.Domain == "" {
@@ -64,17 +73,23 @@ func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, standalone
 		}
 	}
 
+	limiter := NewTURNLimiter(turnConf)
 	serverConfig := turn.ServerConfig{
 		Realm:         LivekitRealm,
 		AuthHandler:   authHandler,
+		QuotaHandler:  limiter.HandleQuota,
+		EventHandler:  limiter.EventHandler(),
 		LoggerFactory: pionlogger.NewLoggerFactory(logger.GetLogger()),
 	}
 
 	var logValues []any
 	logValues = append(logValues, "turn.relay_range_start", turnConf.RelayPortRangeStart)
 	logValues = append(logValues, "turn.relay_range_end", turnConf.RelayPortRangeEnd)
+	logValues = append(logValues, "turn.max_allocations_per_user", turnConf.MaxAllocationsPerUser)
+	logValues = append(logValues, "turn.max_allocations_per_api_key", turnConf.MaxAllocationsPerAPIKey)
+	logValues = append(logValues, "turn.relay_bytes_per_sec_per_user", turnConf.RelayBytesPerSecPerUser)
 
-	for _, addr := range turnConf.BindAddresses {
+	for i, addr := range turnConf.BindAddresses {
 		var nodeIP string
 		if net.ParseIP(addr).To4() != nil {
 			nodeIP = conf.RTC.NodeIP.V4
@@ -92,43 +107,16 @@ func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, standalone
 			MaxPort:      turnConf.RelayPortRangeEnd,
 			MaxRetries:   allocateRetries,
 		}
+		relayAddrGen = limiter.WrapRelayAddressGenerator(relayAddrGen)
 		if standalone {
 			relayAddrGen = telemetry.NewRelayAddressGenerator(relayAddrGen)
 		}
 
+		if peerFilter == nil {
+			peerFilter = NewTURNPeerFilter(conf)
+		}
 		permissionHandler := func(_clientAddr net.Addr, peerIP net.IP) bool {
-			// restricted peer IP is denied by default, unless allowed by the allow list,
-			if peerIP.IsLoopback() ||
-				peerIP.IsLinkLocalUnicast() ||
-				peerIP.IsLinkLocalMulticast() ||
-				peerIP.IsMulticast() ||
-				peerIP.IsPrivate() ||
-				peerIP.IsUnspecified() {
-				allowed := false
-				for _, cidr := range turnConf.AllowRestrictedPeerCIDRs {
-					if _, ipnet, err := net.ParseCIDR(cidr); err == nil {
-						if ipnet.Contains(peerIP) {
-							allowed = true
-							break
-						}
-					}
-				}
-				if !allowed {
-					return false
-				}
-
-				// if allowed, check deny list for overrides
-			}
-
-			for _, cidr := range turnConf.DenyPeerCIDRs {
-				if _, ipnet, err := net.ParseCIDR(cidr); err == nil {
-					if ipnet.Contains(peerIP) {
-						return false
-					}
-				}
-			}
-
-			return true
+			return peerFilter.IsPermitted(peerIP)
 		}
 
 		if turnConf.TLSPort > 0 {
@@ -138,15 +126,15 @@ func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, standalone
 			if turnConf.ExternalTLS {
 				listener, listenerErr = net.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(turnConf.TLSPort)))
 			} else {
-				cert, err := tls.LoadX509KeyPair(turnConf.CertFile, turnConf.KeyFile)
+				cert, err := turnCertificate(conf, certManager)
 				if err != nil {
-					return nil, errors.Wrap(err, "TURN tls cert required")
+					return nil, err
 				}
 
 				listener, listenerErr = tls.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(turnConf.TLSPort)),
 					&tls.Config{
-						MinVersion:   tls.VersionTLS12,
-						Certificates: []tls.Certificate{cert},
+						MinVersion:     tls.VersionTLS12,
+						GetCertificate: cert.GetCertificate,
 					})
 			}
 
@@ -185,12 +173,134 @@ func NewTurnServer(conf *config.Config, authHandler turn.AuthHandler, standalone
 			serverConfig.PacketConnConfigs = append(serverConfig.PacketConnConfigs, packetConfig)
 			logValues = append(logValues, "turn.portUDP", turnConf.UDPPort)
 		}
+
+		// connections multiplexed on the shared port are relayed from the first bind address
+		if portMux != nil && i == 0 {
+			listeners, err := newTURNMuxListeners(conf, portMux, certManager)
+			if err != nil {
+				return nil, err
+			}
+			for _, listener := range listeners {
+				if standalone {
+					listener = telemetry.NewListener(listener)
+				}
+				serverConfig.ListenerConfigs = append(serverConfig.ListenerConfigs, turn.ListenerConfig{
+					Listener:              listener,
+					RelayAddressGenerator: relayAddrGen,
+					PermissionHandler:     permissionHandler,
+				})
+			}
+			logValues = append(logValues, "turn.portMux", conf.PortMux.Port, "turn.portMuxTLS", len(listeners) > 1)
+		}
 	}
 
 	logger.Infow("Starting TURN server", logValues...)
 	return turn.NewServer(serverConfig)
 }
 
+// turnCertificate returns the TURN certificate, the certificate of the node when TURN does not have its own
+func turnCertificate(conf *config.Config, certManager *certmanager.Manager) (certmanager.Certificate, error) {
+	if conf.TURN.CertFile != "" {
+		cert, err := certManager.LoadFiles(conf.TURN.CertFile, conf.TURN.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "TURN tls cert required")
+		}
+		return cert, nil
+	}
+	if certManager.Enabled() {
+		return certManager, nil
+	}
+	return nil, errors.New("TURN tls cert required")
+}
+
+// newTURNMuxListeners returns the listeners of TURN over TCP and, when a certificate is available, TURN/TLS
+// connections accepted on the multiplexed port. The TURN certificate is preferred over the one of the port,
+// then the certificate of the node.
+func newTURNMuxListeners(conf *config.Config, portMux *portmux.Mux, certManager *certmanager.Manager) ([]net.Listener, error) {
+	listeners := []net.Listener{portMux.Listener(portmux.ProtocolTURN)}
+
+	var cert certmanager.Certificate
+	var err error
+	switch {
+	case conf.TURN.CertFile != "":
+		cert, err = turnCertificate(conf, certManager)
+	case conf.PortMux.CertFile != "":
+		cert, err = certManager.LoadFiles(conf.PortMux.CertFile, conf.PortMux.KeyFile)
+	case certManager.Enabled():
+		cert = certManager
+	default:
+		return listeners, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load TURN tls cert for multiplexed port")
+	}
+
+	listeners = append(listeners, tls.NewListener(portMux.Listener(portmux.ProtocolTURNTLS), &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: cert.GetCertificate,
+	}))
+	return listeners, nil
+}
+
+// TURNPeerFilter decides which peers TURN allocations may relay to,
+// the deny list can be updated while the server is running
+type TURNPeerFilter struct {
+	allowRestricted []*net.IPNet
+	deny            atomic.Pointer[[]*net.IPNet]
+}
+
+func NewTURNPeerFilter(conf *config.Config) *TURNPeerFilter {
+	f := &TURNPeerFilter{
+		allowRestricted: parseCIDRs(conf.TURN.AllowRestrictedPeerCIDRs),
+	}
+	f.UpdateDenyCIDRs(conf.TURN.DenyPeerCIDRs)
+	return f
+}
+
+func (f *TURNPeerFilter) UpdateDenyCIDRs(cidrs []string) {
+	deny := parseCIDRs(cidrs)
+	f.deny.Store(&deny)
+}
+
+func (f *TURNPeerFilter) IsPermitted(peerIP net.IP) bool {
+	// restricted peer IP is denied by default, unless allowed by the allow list,
+	if peerIP.IsLoopback() ||
+		peerIP.IsLinkLocalUnicast() ||
+		peerIP.IsLinkLocalMulticast() ||
+		peerIP.IsMulticast() ||
+		peerIP.IsPrivate() ||
+		peerIP.IsUnspecified() {
+		if !containsIP(f.allowRestricted, peerIP) {
+			return false
+		}
+
+		// if allowed, check deny list for overrides
+	}
+
+	return !containsIP(*f.deny.Load(), peerIP)
+}
+
+func parseCIDRs(cidrs []string) []*net.IPNet {
+	ipnets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if _, ipnet, err := net.ParseCIDR(cidr); err == nil {
+			ipnets = append(ipnets, ipnet)
+		} else {
+			logger.Warnw("invalid TURN peer CIDR", err, "cidr", cidr)
+		}
+	}
+	return ipnets
+}
+
+func containsIP(ipnets []*net.IPNet, ip net.IP) bool {
+	for _, ipnet := range ipnets {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 func getTURNAuthHandlerFunc(handler *TURNAuthHandler) turn.AuthHandler {
 	return handler.HandleAuth
 }
@@ -205,65 +315,118 @@ func NewTURNAuthHandler(keyProvider auth.KeyProvider) *TURNAuthHandler {
 	}
 }
 
+// CreateCredentials creates a username and its password from the newest secret of the key
+func (h *TURNAuthHandler) CreateCredentials(apiKey string, pID livekit.ParticipantID, ttlSeconds int) (string, string, error) {
+	secret := h.keyProvider.GetSecret(apiKey)
+	username, expiry := createTURNUsername(apiKey, pID, ttlSeconds, secret)
+	password, err := createTURNPassword(pID, expiry, secret)
+	if err != nil {
+		return "", "", err
+	}
+	return username, password, nil
+}
+
+// CreateUsername creates a username for the newest secret of the key, it identifies the secret
+// so that credentials handed out before a secret rotation stay valid while the secret is still valid
 func (h *TURNAuthHandler) CreateUsername(apiKey string, pID livekit.ParticipantID, ttlSeconds int) (string, int64) {
+	return createTURNUsername(apiKey, pID, ttlSeconds, h.keyProvider.GetSecret(apiKey))
+}
+
+func createTURNUsername(apiKey string, pID livekit.ParticipantID, ttlSeconds int, secret string) (string, int64) {
 	expiry := time.Now().Add(time.Duration(ttlSeconds) * time.Second).Unix()
-	return base62.EncodeToString(fmt.Appendf(nil, "%s|%s|%d", apiKey, pID, expiry)), expiry
+	username := fmt.Appendf(nil, "%s|%s|%d", apiKey, pID, expiry)
+	if secret != "" {
+		username = fmt.Appendf(username, "|%s", turnSecretID(secret))
+	}
+	return base62.EncodeToString(username), expiry
 }
 
 func (h *TURNAuthHandler) ParseUsername(username string) (string, livekit.ParticipantID, int64, error) {
+	apiKey, pID, expiry, _, err := parseTURNUsername(username)
+	return apiKey, pID, expiry, err
+}
+
+// parseTURNUsername parses usernames created by CreateUsername, usernames created before
+// they identified the secret have no secret ID
+func parseTURNUsername(username string) (string, livekit.ParticipantID, int64, string, error) {
 	decoded, err := base62.DecodeString(username)
 	if err != nil {
-		return "", "", 0, err
+		return "", "", 0, "", err
 	}
 	parts := strings.Split(string(decoded), "|")
-	if len(parts) != 3 {
-		return "", "", 0, errors.New("invalid username")
+	if len(parts) != 3 && len(parts) != 4 {
+		return "", "", 0, "", errors.New("invalid username")
 	}
 	expiry, err := strconv.ParseInt(parts[2], 10, 64)
 	if err != nil {
-		return "", "", 0, err
+		return "", "", 0, "", err
 	}
 	if expiry == 0 {
-		return "", "", 0, ErrExpired
+		return "", "", 0, "", ErrExpired
 	}
 
-	return parts[0], livekit.ParticipantID(parts[1]), expiry, nil
+	var secretID string
+	if len(parts) == 4 {
+		secretID = parts[3]
+	}
+	return parts[0], livekit.ParticipantID(parts[1]), expiry, secretID, nil
+}
+
+// turnSecretID identifies a secret of a key without revealing it
+func turnSecretID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:4])
 }
 
+// CreatePassword creates the password for a username created by CreateUsername, from the newest secret of the key
 func (h *TURNAuthHandler) CreatePassword(apiKey string, pID livekit.ParticipantID, expiry int64) (string, error) {
+	return createTURNPassword(pID, expiry, h.keyProvider.GetSecret(apiKey))
+}
+
+func createTURNPassword(pID livekit.ParticipantID, expiry int64, secret string) (string, error) {
 	if expiry == 0 || time.Now().After(time.Unix(expiry, 0)) {
 		return "", ErrExpired
 	}
-	return h.computePassword(apiKey, pID, expiry)
+	if secret == "" {
+		return "", ErrInvalidAPIKey
+	}
+	return computeTURNPassword(secret, pID, expiry), nil
 }
 
-func (h *TURNAuthHandler) computePassword(apiKey string, pID livekit.ParticipantID, expiry int64) (string, error) {
-	secret := h.keyProvider.GetSecret(apiKey)
-	if secret == "" {
+// computePassword derives the password of a username from the secret it identifies, among all currently
+// valid secrets of the key. Usernames not identifying a secret use the newest one.
+func (h *TURNAuthHandler) computePassword(apiKey string, pID livekit.ParticipantID, expiry int64, secretID string) (string, error) {
+	secrets := getVerificationSecrets(h.keyProvider, apiKey)
+	if len(secrets) == 0 {
 		return "", ErrInvalidAPIKey
 	}
+	if secretID == "" {
+		return computeTURNPassword(secrets[0], pID, expiry), nil
+	}
+	for _, secret := range secrets {
+		if turnSecretID(secret) == secretID {
+			return computeTURNPassword(secret, pID, expiry), nil
+		}
+	}
+	return "", ErrInvalidAPIKey
+}
 
+func computeTURNPassword(secret string, pID livekit.ParticipantID, expiry int64) string {
 	keyInput := fmt.Sprintf("%s|%s|%d", secret, pID, expiry)
 
 	sum := sha256.Sum256([]byte(keyInput))
-	return base62.EncodeToString(sum[:]), nil
+	return base62.EncodeToString(sum[:])
 }
 
 func (h *TURNAuthHandler) HandleAuth(ra *turn.RequestAttributes) (userID string, key []byte, ok bool) {
 	username := ra.Username
-	decoded, err := base62.DecodeString(username)
-	if err != nil {
-		return "", nil, false
-	}
-	parts := strings.Split(string(decoded), "|")
-	if len(parts) != 3 {
-		return "", nil, false
-	}
-	expiry, err := strconv.ParseInt(parts[2], 10, 64)
+	apiKey, pID, expiry, secretID, err := parseTURNUsername(username)
 	if err != nil {
-		return "", nil, false
-	}
-	if expiry == 0 {
+		if errors.Is(err, ErrExpired) {
+			prometheus.RecordTURNAuthFailure(turnAuthFailureExpired)
+		} else {
+			prometheus.RecordTURNAuthFailure(turnAuthFailureMalformed)
+		}
 		return "", nil, false
 	}
 	expiryTime := time.Unix(expiry, 0)
@@ -273,14 +436,16 @@ func (h *TURNAuthHandler) HandleAuth(ra *turn.RequestAttributes) (userID string,
 		// username/password but skip the TTL check so long-running sessions can
 		// keep refreshing past the credential expiry.
 		if ra.Method == stun.MethodAllocate {
-			logger.Infow("TURN credential expired", "username", decoded, "participantID", parts[1], "expiry", expiryTime, "method", ra.Method)
+			logger.Infow("TURN credential expired", "apiKey", apiKey, "participantID", pID, "expiry", expiryTime, "method", ra.Method)
+			prometheus.RecordTURNAuthFailure(turnAuthFailureExpired)
 			return "", nil, false
 		}
 	}
-	password, err := h.computePassword(parts[0], livekit.ParticipantID(parts[1]), expiry)
+	password, err := h.computePassword(apiKey, pID, expiry, secretID)
 	if err != nil {
-		logger.Warnw("could not create TURN password", err, "username", decoded)
+		logger.Warnw("could not create TURN password", err, "apiKey", apiKey, "participantID", pID)
+		prometheus.RecordTURNAuthFailure(turnAuthFailureInvalidAPIKey)
 		return "", nil, false
 	}
-	return parts[1], turn.GenerateAuthKey(username, LivekitRealm, password), true
+	return string(pID), turn.GenerateAuthKey(username, LivekitRealm, password), true
 }
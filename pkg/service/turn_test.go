@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/jxskiss/base62"
 	"github.com/pion/stun/v3"
@@ -26,6 +27,8 @@ import (
 
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
 )
 
 const (
@@ -94,7 +97,9 @@ func TestTURNAuthHandler_HandleAuth_ExpiredNonAllocateAllowed(t *testing.T) {
 	// CreatePassword still enforces ErrExpired on its own, but the server hands
 	// the same key it generated at allocation time — reproduce that by directly
 	// hashing without going through CreatePassword's expiry guard.
-	password, err := h.computePassword(turnTestAPIKey, pID, expiry)
+	_, _, _, secretID, err := parseTURNUsername(username)
+	require.NoError(t, err)
+	password, err := h.computePassword(turnTestAPIKey, pID, expiry, secretID)
 	require.NoError(t, err)
 	expectedKey := turn.GenerateAuthKey(username, LivekitRealm, password)
 
@@ -118,6 +123,57 @@ func TestTURNAuthHandler_HandleAuth_ExpiredNonAllocateAllowed(t *testing.T) {
 	}
 }
 
+func TestTURNAuthHandler_HandleAuth_RotatedSecrets(t *testing.T) {
+	oldSecret := "oldsecretencodedinbase62extendto32bytes"
+	newSecret := "newsecretencodedinbase62extendto32bytes"
+	now := time.Now()
+	conf := &config.Config{
+		KeySecrets: map[string][]config.KeySecret{
+			turnTestAPIKey: {{Secret: oldSecret}},
+		},
+	}
+	provider := NewReloadableKeyProvider(conf)
+	h := NewTURNAuthHandler(provider)
+	pID := livekit.ParticipantID("PA_rotated")
+	username, expectedKey := mustAuthCreds(t, h, pID, 300)
+
+	handleAuth := func(username string) ([]byte, bool) {
+		_, key, ok := h.HandleAuth(&turn.RequestAttributes{
+			Username: username,
+			Realm:    LivekitRealm,
+			SrcAddr:  &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234},
+			Method:   stun.MethodAllocate,
+		})
+		return key, ok
+	}
+
+	// credentials of the old secret stay valid while it is
+	conf.KeySecrets[turnTestAPIKey] = []config.KeySecret{
+		{Secret: oldSecret, NotAfter: now.Add(time.Hour)},
+		{Secret: newSecret, NotBefore: now.Add(-time.Minute)},
+	}
+	require.NoError(t, provider.Update(conf))
+	key, ok := handleAuth(username)
+	require.True(t, ok)
+	require.Equal(t, expectedKey, key)
+
+	// new credentials use the new secret
+	newUsername, newExpectedKey := mustAuthCreds(t, h, pID, 300)
+	key, ok = handleAuth(newUsername)
+	require.True(t, ok)
+	require.Equal(t, newExpectedKey, key)
+	require.NotEqual(t, expectedKey, key)
+
+	// and the old ones are rejected once it expired
+	conf.KeySecrets[turnTestAPIKey] = []config.KeySecret{
+		{Secret: oldSecret, NotAfter: now.Add(-time.Minute)},
+		{Secret: newSecret, NotBefore: now.Add(-time.Minute)},
+	}
+	require.NoError(t, provider.Update(conf))
+	_, ok = handleAuth(username)
+	require.False(t, ok)
+}
+
 func TestTURNAuthHandler_HandleAuth_WrongUsernameRejected(t *testing.T) {
 	h := newTestTurnAuthHandler()
 	_, _, ok := h.HandleAuth(&turn.RequestAttributes{
@@ -206,3 +262,90 @@ func TestTURNAuthHandler_CreatePassword_ZeroExpiryRejected(t *testing.T) {
 	_, err := h.CreatePassword(turnTestAPIKey, pID, 0)
 	require.ErrorIs(t, err, ErrExpired)
 }
+
+func TestTURNLimiter_AllocationQuotas(t *testing.T) {
+	h := newTestTurnAuthHandler()
+	limiter := NewTURNLimiter(config.TURNConfig{
+		MaxAllocationsPerUser:   1,
+		MaxAllocationsPerAPIKey: 2,
+	})
+	events := limiter.EventHandler()
+	srcAddr := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}
+
+	allocate := func(pID livekit.ParticipantID) bool {
+		username, _ := mustAuthCreds(t, h, pID, 300)
+		events.OnAuth(srcAddr, srcAddr, "UDP", username, LivekitRealm, stun.MethodAllocate.String(), true)
+		if !limiter.HandleQuota(string(pID), LivekitRealm, srcAddr) {
+			return false
+		}
+		events.OnAllocationCreated(srcAddr, srcAddr, "UDP", string(pID), LivekitRealm, srcAddr, 0)
+		return true
+	}
+
+	require.True(t, allocate("PA_1"))
+	// per user limit
+	require.False(t, allocate("PA_1"))
+	require.True(t, allocate("PA_2"))
+	// per API key limit
+	require.False(t, allocate("PA_3"))
+
+	events.OnAllocationDeleted(srcAddr, srcAddr, "UDP", "PA_1", LivekitRealm)
+	require.True(t, allocate("PA_3"))
+	require.False(t, allocate("PA_1"))
+
+	events.OnAllocationDeleted(srcAddr, srcAddr, "UDP", "PA_2", LivekitRealm)
+	events.OnAllocationDeleted(srcAddr, srcAddr, "UDP", "PA_3", LivekitRealm)
+	require.Empty(t, limiter.users)
+	require.Empty(t, limiter.apiKeyAllocations)
+}
+
+func TestTURNLimiter_FailedAllocationReleased(t *testing.T) {
+	h := newTestTurnAuthHandler()
+	limiter := NewTURNLimiter(config.TURNConfig{
+		MaxAllocationsPerUser:   1,
+		RelayBytesPerSecPerUser: 1000,
+	})
+	events := limiter.EventHandler()
+	srcAddr := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}
+
+	pID := livekit.ParticipantID("PA_failed")
+	username, _ := mustAuthCreds(t, h, pID, 300)
+	events.OnAuth(srcAddr, srcAddr, "UDP", username, LivekitRealm, stun.MethodAllocate.String(), true)
+	require.True(t, limiter.HandleQuota(string(pID), LivekitRealm, srcAddr))
+	require.Empty(t, limiter.users)
+
+	// relay connection allocated, but the allocation fails before it is created
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	relayConn := newTURNLimitedPacketConn(conn, string(pID), limiter)
+	require.Len(t, limiter.users, 1)
+	require.NoError(t, relayConn.Close())
+	require.Empty(t, limiter.users)
+
+	// quota is not held by the failed allocation
+	require.True(t, limiter.HandleQuota(string(pID), LivekitRealm, srcAddr))
+	events.OnAllocationCreated(srcAddr, srcAddr, "UDP", string(pID), LivekitRealm, srcAddr, 0)
+	require.False(t, limiter.HandleQuota(string(pID), LivekitRealm, srcAddr))
+}
+
+func TestTURNPeerFilter(t *testing.T) {
+	conf := &config.Config{
+		TURN: config.TURNConfig{
+			AllowRestrictedPeerCIDRs: []string{"10.0.0.0/8"},
+			DenyPeerCIDRs:            []string{"203.0.113.0/24"},
+		},
+	}
+	f := NewTURNPeerFilter(conf)
+
+	require.True(t, f.IsPermitted(net.ParseIP("198.51.100.1")))
+	require.False(t, f.IsPermitted(net.ParseIP("203.0.113.1")))
+	require.True(t, f.IsPermitted(net.ParseIP("10.1.2.3")))
+	require.False(t, f.IsPermitted(net.ParseIP("192.168.1.1")))
+	require.False(t, f.IsPermitted(net.ParseIP("127.0.0.1")))
+
+	// deny list can be replaced, and overrides the restricted allow list
+	f.UpdateDenyCIDRs([]string{"10.1.0.0/16"})
+	require.True(t, f.IsPermitted(net.ParseIP("203.0.113.1")))
+	require.False(t, f.IsPermitted(net.ParseIP("10.1.2.3")))
+	require.True(t, f.IsPermitted(net.ParseIP("10.2.2.3")))
+}
@@ -0,0 +1,348 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pion/stun/v3"
+	"github.com/pion/turn/v5"
+	"go.uber.org/atomic"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/utils"
+)
+
+const (
+	turnDeniedUserLimit   = "user_limit"
+	turnDeniedAPIKeyLimit = "api_key_limit"
+
+	turnClosedMaxLifetime = "max_lifetime"
+	turnClosedIdle        = "idle"
+
+	// API keys learned during authentication are discarded if no allocation follows
+	turnPendingAuthTTL = time.Minute
+
+	// allow at least a few full sized packets through the relay rate limiter
+	minTURNRelayBurst = 16 * 1500
+)
+
+type turnPendingAuth struct {
+	apiKey string
+	at     time.Time
+}
+
+type turnUserState struct {
+	apiKey       string
+	allocations  int
+	relayLimiter *utils.ByteRateLimiter
+}
+
+// TURNLimiter enforces per participant and per API key limits on TURN allocations.
+// Participants are identified by the user ID returned from TURNAuthHandler.HandleAuth.
+type TURNLimiter struct {
+	conf config.TURNConfig
+
+	lock              sync.Mutex
+	pendingAuths      map[string]turnPendingAuth
+	users             map[string]*turnUserState
+	apiKeyAllocations map[string]int
+}
+
+func NewTURNLimiter(conf config.TURNConfig) *TURNLimiter {
+	return &TURNLimiter{
+		conf:              conf,
+		pendingAuths:      make(map[string]turnPendingAuth),
+		users:             make(map[string]*turnUserState),
+		apiKeyAllocations: make(map[string]int),
+	}
+}
+
+func (l *TURNLimiter) EventHandler() turn.EventHandler {
+	return turn.EventHandler{
+		OnAuth:              l.onAuth,
+		OnAllocationCreated: l.onAllocationCreated,
+		OnAllocationDeleted: l.onAllocationDeleted,
+	}
+}
+
+// WrapRelayAddressGenerator wraps relay connections to count relayed bytes and apply the relay limits
+func (l *TURNLimiter) WrapRelayAddressGenerator(g turn.RelayAddressGenerator) turn.RelayAddressGenerator {
+	return &turnLimitedRelayAddressGenerator{RelayAddressGenerator: g, limiter: l}
+}
+
+// HandleQuota is called before an allocation is created, returning false rejects it.
+// User state is only created once the allocation succeeds, so failed allocations leave nothing behind.
+func (l *TURNLimiter) HandleQuota(userID, _realm string, _srcAddr net.Addr) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	allocations := 0
+	apiKey := ""
+	if state := l.users[userID]; state != nil {
+		allocations = state.allocations
+		apiKey = state.apiKey
+	}
+	if pending, ok := l.pendingAuths[userID]; ok {
+		apiKey = pending.apiKey
+	}
+
+	reason := ""
+	if l.conf.MaxAllocationsPerUser > 0 && allocations >= l.conf.MaxAllocationsPerUser {
+		reason = turnDeniedUserLimit
+	} else if l.conf.MaxAllocationsPerAPIKey > 0 && l.apiKeyAllocations[apiKey] >= l.conf.MaxAllocationsPerAPIKey {
+		reason = turnDeniedAPIKeyLimit
+	}
+	if reason == "" {
+		return true
+	}
+
+	prometheus.RecordTURNAllocationDenied(reason)
+	logger.Infow("TURN allocation denied", "participantID", userID, "reason", reason)
+	return false
+}
+
+func (l *TURNLimiter) onAuth(_srcAddr, _dstAddr net.Addr, _protocol, username, _realm, method string, verdict bool) {
+	if !verdict {
+		// the credentials parsed and resolved to a key, but the message integrity check failed
+		prometheus.RecordTURNAuthFailure(turnAuthFailureIntegrity)
+		return
+	}
+	if method != stun.MethodAllocate.String() {
+		return
+	}
+	apiKey, pID, _, _, err := parseTURNUsername(username)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for userID, pending := range l.pendingAuths {
+		if now.Sub(pending.at) > turnPendingAuthTTL {
+			delete(l.pendingAuths, userID)
+		}
+	}
+	l.pendingAuths[string(pID)] = turnPendingAuth{apiKey: apiKey, at: now}
+}
+
+func (l *TURNLimiter) onAllocationCreated(_srcAddr, _dstAddr net.Addr, _protocol, userID, _realm string, _relayAddr net.Addr, _requestedPort int) {
+	l.lock.Lock()
+	state := l.getOrCreateUserLocked(userID)
+	if pending, ok := l.pendingAuths[userID]; ok {
+		state.apiKey = pending.apiKey
+		delete(l.pendingAuths, userID)
+	}
+	state.allocations++
+	l.apiKeyAllocations[state.apiKey]++
+	l.lock.Unlock()
+
+	prometheus.AddTURNAllocation()
+}
+
+func (l *TURNLimiter) onAllocationDeleted(_srcAddr, _dstAddr net.Addr, _protocol, userID, _realm string) {
+	l.lock.Lock()
+	state := l.users[userID]
+	if state == nil || state.allocations == 0 {
+		l.lock.Unlock()
+		return
+	}
+	state.allocations--
+	if state.allocations == 0 {
+		delete(l.users, userID)
+	}
+	if l.apiKeyAllocations[state.apiKey] <= 1 {
+		delete(l.apiKeyAllocations, state.apiKey)
+	} else {
+		l.apiKeyAllocations[state.apiKey]--
+	}
+	l.lock.Unlock()
+
+	prometheus.SubTURNAllocation()
+}
+
+func (l *TURNLimiter) getOrCreateUserLocked(userID string) *turnUserState {
+	state := l.users[userID]
+	if state == nil {
+		state = &turnUserState{}
+		l.users[userID] = state
+	}
+	return state
+}
+
+func (l *TURNLimiter) getRelayLimiter(userID string) *utils.ByteRateLimiter {
+	if l.conf.RelayBytesPerSecPerUser == 0 {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	state := l.getOrCreateUserLocked(userID)
+	if state.relayLimiter == nil {
+		state.relayLimiter = utils.NewByteRateLimiter(l.conf.RelayBytesPerSecPerUser, minTURNRelayBurst)
+	}
+	return state.relayLimiter
+}
+
+// releaseUser drops the state created for a relay connection whose allocation never completed
+func (l *TURNLimiter) releaseUser(userID string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if state := l.users[userID]; state != nil && state.allocations == 0 {
+		delete(l.users, userID)
+	}
+}
+
+// ------------------------------------------------
+
+type turnLimitedRelayAddressGenerator struct {
+	turn.RelayAddressGenerator
+	limiter *TURNLimiter
+}
+
+func (g *turnLimitedRelayAddressGenerator) AllocatePacketConn(c turn.AllocateListenerConfig) (net.PacketConn, net.Addr, error) {
+	conn, addr, err := g.RelayAddressGenerator.AllocatePacketConn(c)
+	if err != nil {
+		return nil, addr, err
+	}
+
+	return newTURNLimitedPacketConn(conn, c.UserID, g.limiter), addr, nil
+}
+
+// turnLimitedPacketConn wraps a relay connection, applying the relay bandwidth cap and closing
+// the connection once it exceeds its lifetime or goes idle, which deletes the allocation
+type turnLimitedPacketConn struct {
+	net.PacketConn
+
+	userID       string
+	limiter      *TURNLimiter
+	relayLimiter *utils.ByteRateLimiter
+	lastActivity atomic.Int64
+
+	closed        atomic.Bool
+	lifetimeTimer *time.Timer
+	idleTimer     *time.Timer
+	idleTimeout   time.Duration
+}
+
+func newTURNLimitedPacketConn(conn net.PacketConn, userID string, limiter *TURNLimiter) *turnLimitedPacketConn {
+	c := &turnLimitedPacketConn{
+		PacketConn:   conn,
+		userID:       userID,
+		limiter:      limiter,
+		relayLimiter: limiter.getRelayLimiter(userID),
+		idleTimeout:  limiter.conf.AllocationIdleTimeout,
+	}
+	c.lastActivity.Store(time.Now().UnixNano())
+
+	if limiter.conf.MaxAllocationLifetime > 0 {
+		c.lifetimeTimer = time.AfterFunc(limiter.conf.MaxAllocationLifetime, func() {
+			c.closeWithReason(turnClosedMaxLifetime)
+		})
+	}
+	if c.idleTimeout > 0 {
+		c.idleTimer = time.AfterFunc(c.idleTimeout, c.checkIdle)
+	}
+	return c
+}
+
+func (c *turnLimitedPacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	for {
+		n, addr, err := c.PacketConn.ReadFrom(p)
+		if err != nil || n == 0 {
+			return n, addr, err
+		}
+
+		c.lastActivity.Store(time.Now().UnixNano())
+		if !c.allow(n) {
+			continue
+		}
+		prometheus.IncrementTURNRelayedBytes(prometheus.Incoming, uint64(n))
+		return n, addr, err
+	}
+}
+
+func (c *turnLimitedPacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	c.lastActivity.Store(time.Now().UnixNano())
+	if !c.allow(len(p)) {
+		// dropped as if lost on the network
+		return len(p), nil
+	}
+
+	n, err := c.PacketConn.WriteTo(p, addr)
+	if n > 0 {
+		prometheus.IncrementTURNRelayedBytes(prometheus.Outgoing, uint64(n))
+	}
+	return n, err
+}
+
+func (c *turnLimitedPacketConn) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	c.stopTimers()
+	if c.relayLimiter != nil {
+		c.limiter.releaseUser(c.userID)
+	}
+	return c.PacketConn.Close()
+}
+
+func (c *turnLimitedPacketConn) allow(n int) bool {
+	if c.relayLimiter == nil || c.relayLimiter.Allow(n) {
+		return true
+	}
+	prometheus.IncrementTURNDroppedBytes(uint64(n))
+	return false
+}
+
+func (c *turnLimitedPacketConn) checkIdle() {
+	if c.closed.Load() {
+		return
+	}
+	idle := time.Since(time.Unix(0, c.lastActivity.Load()))
+	if idle >= c.idleTimeout {
+		c.closeWithReason(turnClosedIdle)
+		return
+	}
+	c.idleTimer.Reset(c.idleTimeout - idle)
+}
+
+func (c *turnLimitedPacketConn) closeWithReason(reason string) {
+	if c.closed.Load() {
+		return
+	}
+
+	logger.Infow("closing TURN allocation", "participantID", c.userID, "reason", reason)
+	prometheus.RecordTURNAllocationClosed(reason)
+	_ = c.Close()
+}
+
+func (c *turnLimitedPacketConn) stopTimers() {
+	if c.lifetimeTimer != nil {
+		c.lifetimeTimer.Stop()
+	}
+	if c.idleTimer != nil {
+		c.idleTimer.Stop()
+	}
+}
@@ -285,6 +285,7 @@ type ValidateConnectRequestParams struct {
 	publish    string
 	metadata   string
 	attributes map[string]string
+	reconnect  bool
 }
 
 type ValidateConnectRequestResult struct {
@@ -353,10 +354,12 @@ func ValidateConnectRequest(
 	}
 
 	// room allocator validations
-	err = roomAllocator.ValidateCreateRoom(r.Context(), res.roomName)
+	err = roomAllocator.ValidateCreateRoom(r.Context(), res.roomName, params.reconnect)
 	if err != nil {
 		if errors.Is(err, ErrRoomNotFound) {
 			return res, http.StatusNotFound, err
+		} else if errors.Is(err, rtc.ErrRoomNotYetOpen) || errors.Is(err, rtc.ErrRoomScheduleEnded) {
+			return res, http.StatusForbidden, err
 		} else {
 			return res, http.StatusInternalServerError, err
 		}
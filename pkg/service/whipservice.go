@@ -25,6 +25,7 @@ import (
 
 	"github.com/pion/webrtc/v4"
 	"github.com/tomnomnom/linkheader"
+	"go.uber.org/atomic"
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
@@ -52,6 +53,7 @@ type WHIPService struct {
 	client            rpc.WHIPClient[livekit.NodeID]
 	topicFormatter    rpc.TopicFormatter
 	participantClient rpc.TypedWHIPParticipantClient
+	limits            atomic.Pointer[config.LimitConfig]
 }
 
 func NewWHIPService(
@@ -67,14 +69,21 @@ func NewWHIPService(
 		return nil, err
 	}
 
-	return &WHIPService{
+	s := &WHIPService{
 		config:            config,
 		router:            router,
 		roomAllocator:     roomAllocator,
 		client:            client,
 		topicFormatter:    topicFormatter,
 		participantClient: participantClient,
-	}, nil
+	}
+	s.UpdateLimitConfig(config.Limit)
+	return s, nil
+}
+
+// UpdateLimitConfig applies new limits to sessions created from now on
+func (s *WHIPService) UpdateLimitConfig(limits config.LimitConfig) {
+	s.limits.Store(&limits)
 }
 
 func (s *WHIPService) SetupRoutes(mux *http.ServeMux) {
@@ -132,15 +141,16 @@ func (s *WHIPService) validateCreate(w http.ResponseWriter, r *http.Request) (*c
 	if roomName == "" {
 		return nil, http.StatusUnauthorized, errors.New("room name cannot be empty")
 	}
-	if !s.config.Limit.CheckRoomNameLength(string(roomName)) {
-		return nil, http.StatusBadRequest, fmt.Errorf("%w: max length %d", ErrRoomNameExceedsLimits, s.config.Limit.MaxRoomNameLength)
+	limits := s.limits.Load()
+	if !limits.CheckRoomNameLength(string(roomName)) {
+		return nil, http.StatusBadRequest, fmt.Errorf("%w: max length %d", ErrRoomNameExceedsLimits, limits.MaxRoomNameLength)
 	}
 
 	if claims.Identity == "" {
 		return nil, http.StatusBadRequest, ErrIdentityEmpty
 	}
-	if !s.config.Limit.CheckParticipantIdentityLength(claims.Identity) {
-		return nil, http.StatusBadRequest, fmt.Errorf("%w: max length %d", ErrParticipantIdentityExceedsLimits, s.config.Limit.MaxParticipantIdentityLength)
+	if !limits.CheckParticipantIdentityLength(claims.Identity) {
+		return nil, http.StatusBadRequest, fmt.Errorf("%w: max length %d", ErrParticipantIdentityExceedsLimits, limits.MaxParticipantIdentityLength)
 	}
 
 	var clientInfo struct {
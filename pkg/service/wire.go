@@ -19,7 +19,10 @@ package service
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"slices"
+	"strconv"
 
 	"github.com/google/wire"
 	"github.com/pion/turn/v5"
@@ -38,7 +41,10 @@ import (
 	"github.com/livekit/psrpc/pkg/middleware/otelpsrpc"
 
 	"github.com/livekit/livekit-server/pkg/agent"
+	"github.com/livekit/livekit-server/pkg/certmanager"
+	"github.com/livekit/livekit-server/pkg/cluster"
 	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/portmux"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/telemetry"
@@ -48,10 +54,14 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	wire.Build(
 		getNodeID,
 		createRedisClient,
+		createCluster,
 		createStore,
 		wire.Bind(new(ServiceStore), new(ObjectStore)),
 		createKeyProvider,
+		wire.Bind(new(auth.KeyProvider), new(*ReloadableKeyProvider)),
 		createWebhookNotifier,
+		wire.Bind(new(webhook.QueuedNotifier), new(*ReloadableNotifier)),
+		NewParticipantRPCForwarder,
 		createForwardStats,
 		getNodeStatsConfig,
 		routing.CreateRouter,
@@ -78,6 +88,8 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		NewSIPService,
 		NewRoomAllocator,
 		NewRoomService,
+		NewRoomScheduleService,
+		NewDataHistoryService,
 		NewRTCService,
 		NewWHIPService,
 		NewAgentService,
@@ -98,10 +110,14 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		rpc.NewTypedParticipantClient,
 		rpc.NewTypedWHIPParticipantClient,
 		rpc.NewTypedAgentDispatchInternalClient,
+		createPortMux,
+		createCertManager,
 		NewLocalRoomManager,
 		NewTURNAuthHandler,
 		getTURNAuthHandlerFunc,
+		NewTURNPeerFilter,
 		newInProcessTurnServer,
+		NewConfigReloader,
 		utils.NewDefaultTimedVersionGenerator,
 		NewLivekitServer,
 	)
@@ -111,6 +127,7 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 func InitializeRouter(conf *config.Config, currentNode routing.LocalNode) (routing.Router, error) {
 	wire.Build(
 		createRedisClient,
+		createCluster,
 		getNodeID,
 		getMessageBus,
 		getSignalRelayConfig,
@@ -131,7 +148,7 @@ func getNodeID(currentNode routing.LocalNode) livekit.NodeID {
 	return currentNode.NodeID()
 }
 
-func createKeyProvider(conf *config.Config) (auth.KeyProvider, error) {
+func createKeyProvider(conf *config.Config) (*ReloadableKeyProvider, error) {
 	// prefer keyfile if set
 	if conf.KeyFile != "" {
 		var otherFilter os.FileMode = 0007
@@ -153,22 +170,15 @@ func createKeyProvider(conf *config.Config) (auth.KeyProvider, error) {
 		}
 	}
 
-	if len(conf.Keys) == 0 {
-		return nil, errors.New("one of key-file or keys must be provided in order to support a secure installation")
+	if len(conf.Keys) == 0 && len(conf.KeySecrets) == 0 {
+		return nil, errors.New("one of key-file, keys or key_secrets must be provided in order to support a secure installation")
 	}
 
-	return auth.NewFileBasedKeyProviderFromMap(conf.Keys), nil
+	return NewReloadableKeyProvider(conf), nil
 }
 
-func createWebhookNotifier(conf *config.Config, provider auth.KeyProvider) (webhook.QueuedNotifier, error) {
-	wc := conf.WebHook
-
-	secret := provider.GetSecret(wc.APIKey)
-	if secret == "" && len(wc.URLs) > 0 {
-		return nil, ErrWebHookMissingAPIKey
-	}
-
-	return webhook.NewDefaultNotifier(wc, provider)
+func createWebhookNotifier(conf *config.Config, provider *ReloadableKeyProvider) (*ReloadableNotifier, error) {
+	return NewReloadableNotifier(conf.WebHook, provider)
 }
 
 func createTelemetryService(notifier webhook.QueuedNotifier, analytics telemetry.AnalyticsService) telemetry.TelemetryService {
@@ -186,18 +196,51 @@ func createRedisClient(conf *config.Config) (redis.UniversalClient, error) {
 	return redisLiveKit.GetRedisClient(&conf.Redis)
 }
 
-func createStore(rc redis.UniversalClient) ObjectStore {
+func createCluster(conf *config.Config, currentNode routing.LocalNode) (*cluster.Cluster, error) {
+	if !conf.Cluster.Enabled {
+		return nil, nil
+	}
+	clusterConf := conf.Cluster
+	port := strconv.Itoa(int(clusterConf.Port))
+	advertiseHost := clusterConf.AdvertiseAddress
+	if advertiseHost == "" {
+		advertiseHost = currentNode.NodeIP()
+	}
+	c, err := cluster.New(cluster.Params{
+		NodeID:           string(currentNode.NodeID()),
+		BindAddress:      net.JoinHostPort(clusterConf.BindAddress, port),
+		AdvertiseAddress: net.JoinHostPort(advertiseHost, port),
+		Seeds:            clusterConf.Seeds,
+		BootstrapExpect:  clusterConf.BootstrapExpect,
+		Secret:           clusterConf.Secret,
+		GossipInterval:   clusterConf.GossipInterval,
+		DeadNodeTimeout:  clusterConf.DeadNodeTimeout,
+		Logger:           logger.GetLogger().WithComponent("cluster"),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not start cluster")
+	}
+	return c, nil
+}
+
+func createStore(rc redis.UniversalClient, c *cluster.Cluster) ObjectStore {
 	if rc != nil {
 		return NewRedisStore(rc)
 	}
+	if c != nil {
+		return NewClusterStore(c)
+	}
 	return NewLocalStore()
 }
 
-func getMessageBus(rc redis.UniversalClient) psrpc.MessageBus {
-	if rc == nil {
-		return psrpc.NewLocalMessageBus()
+func getMessageBus(rc redis.UniversalClient, c *cluster.Cluster) psrpc.MessageBus {
+	if rc != nil {
+		return psrpc.NewRedisMessageBus(rc)
+	}
+	if c != nil {
+		return c.MessageBus()
 	}
-	return psrpc.NewRedisMessageBus(rc)
+	return psrpc.NewLocalMessageBus()
 }
 
 func getEgressStore(s ObjectStore) EgressStore {
@@ -224,6 +267,8 @@ func getAgentStore(s ObjectStore) AgentStore {
 		return store
 	case *LocalStore:
 		return store
+	case *ClusterStore:
+		return store
 	default:
 		return nil
 	}
@@ -288,8 +333,50 @@ func createForwardStats(conf *config.Config) *sfu.ForwardStats {
 	return sfu.NewForwardStats(conf.RTC.ForwardStats.SummaryInterval, conf.RTC.ForwardStats.ReportInterval, conf.RTC.ForwardStats.ReportWindow)
 }
 
-func newInProcessTurnServer(conf *config.Config, authHandler turn.AuthHandler) (*turn.Server, error) {
-	return NewTurnServer(conf, authHandler, false)
+func newInProcessTurnServer(conf *config.Config, authHandler turn.AuthHandler, peerFilter *TURNPeerFilter, portMux *portmux.Mux, certManager *certmanager.Manager) (*turn.Server, error) {
+	return NewTurnServer(conf, authHandler, peerFilter, portMux, certManager, false)
+}
+
+func createCertManager(conf *config.Config) (*certmanager.Manager, error) {
+	params := certmanager.Params{
+		CertFile:      conf.TLS.CertFile,
+		KeyFile:       conf.TLS.KeyFile,
+		ClientCAFile:  conf.TLS.ClientCAFile,
+		WatchInterval: conf.TLS.WatchInterval,
+		Logger:        logger.GetLogger().WithComponent("certmanager"),
+	}
+	if acmeConf := conf.TLS.ACME; acmeConf.Enabled {
+		domains := acmeConf.Domains
+		// TURN/TLS uses the certificate of the node when it does not have its own
+		turnConf := conf.TURN
+		if turnConf.Enabled && turnConf.TLSPort > 0 && !turnConf.ExternalTLS && turnConf.CertFile == "" && !slices.Contains(domains, turnConf.Domain) {
+			domains = append(slices.Clone(domains), turnConf.Domain)
+		}
+		params.ACME = &certmanager.ACMEParams{
+			Domains:      domains,
+			Email:        acmeConf.Email,
+			DirectoryURL: acmeConf.DirectoryURL,
+			CAFile:       acmeConf.CAFile,
+			CacheDir:     acmeConf.CacheDir,
+		}
+	}
+	return certmanager.New(params)
+}
+
+func createPortMux(conf *config.Config) (*portmux.Mux, error) {
+	if conf.PortMux.Port == 0 {
+		return nil, nil
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(int(conf.PortMux.Port))))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not listen on multiplexed port")
+	}
+	return portmux.New(portmux.Params{
+		Listener:        ln,
+		TURNServerNames: conf.PortMux.TURNServerNames,
+		SniffTimeout:    conf.PortMux.SniffTimeout,
+		Logger:          logger.GetLogger().WithComponent("portmux"),
+	}), nil
 }
 
 func getNodeStatsConfig(config *config.Config) config.NodeStatsConfig {
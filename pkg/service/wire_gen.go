@@ -9,11 +9,13 @@ package service
 import (
 	"fmt"
 	"github.com/livekit/livekit-server/pkg/agent"
+	"github.com/livekit/livekit-server/pkg/certmanager"
+	"github.com/livekit/livekit-server/pkg/cluster"
 	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/portmux"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/telemetry"
-	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	redis2 "github.com/livekit/protocol/redis"
@@ -26,7 +28,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
 	"gopkg.in/yaml.v3"
+	"net"
 	"os"
+	"slices"
+	"strconv"
 )
 
 import (
@@ -42,8 +47,12 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	if err != nil {
 		return nil, err
 	}
+	clusterCluster, err := createCluster(conf, currentNode)
+	if err != nil {
+		return nil, err
+	}
 	nodeID := getNodeID(currentNode)
-	messageBus := getMessageBus(universalClient)
+	messageBus := getMessageBus(universalClient, clusterCluster)
 	signalRelayConfig := getSignalRelayConfig(conf)
 	signalClient, err := routing.NewSignalClient(nodeID, messageBus, signalRelayConfig)
 	if err != nil {
@@ -61,8 +70,8 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		return nil, err
 	}
 	nodeStatsConfig := getNodeStatsConfig(conf)
-	router := routing.CreateRouter(universalClient, currentNode, signalClient, roomManagerClient, keepalivePubSub, nodeStatsConfig)
-	objectStore := createStore(universalClient)
+	router := routing.CreateRouter(universalClient, clusterCluster, currentNode, signalClient, roomManagerClient, keepalivePubSub, nodeStatsConfig)
+	objectStore := createStore(universalClient, clusterCluster)
 	roomAllocator, err := NewRoomAllocator(conf, router, objectStore)
 	if err != nil {
 		return nil, err
@@ -74,16 +83,16 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	egressStore := getEgressStore(objectStore)
 	ingressStore := getIngressStore(objectStore)
 	sipStore := getSIPStore(objectStore)
-	keyProvider, err := createKeyProvider(conf)
+	reloadableKeyProvider, err := createKeyProvider(conf)
 	if err != nil {
 		return nil, err
 	}
-	queuedNotifier, err := createWebhookNotifier(conf, keyProvider)
+	reloadableNotifier, err := createWebhookNotifier(conf, reloadableKeyProvider)
 	if err != nil {
 		return nil, err
 	}
 	analyticsService := telemetry.NewAnalyticsService(conf, currentNode)
-	telemetryService := createTelemetryService(queuedNotifier, analyticsService)
+	telemetryService := createTelemetryService(reloadableNotifier, analyticsService)
 	ioInfoService, err := NewIOInfoService(messageBus, egressStore, ingressStore, sipStore, telemetryService)
 	if err != nil {
 		return nil, err
@@ -120,6 +129,11 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		return nil, err
 	}
 	sipService := NewSIPService(sipConfig, nodeID, messageBus, sipClient, sipStore, roomService, telemetryService)
+	roomScheduleService, err := NewRoomScheduleService(objectStore, topicFormatter, messageBus)
+	if err != nil {
+		return nil, err
+	}
+	dataHistoryService := NewDataHistoryService(objectStore, roomService)
 	rtcService := NewRTCService(conf, roomAllocator, router, telemetryService)
 	whipParticipantClient, err := rpc.NewTypedWHIPParticipantClient(clientParams)
 	if err != nil {
@@ -129,7 +143,7 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	if err != nil {
 		return nil, err
 	}
-	agentService, err := NewAgentService(conf, currentNode, messageBus, keyProvider)
+	agentService, err := NewAgentService(conf, currentNode, messageBus, reloadableKeyProvider)
 	if err != nil {
 		return nil, err
 	}
@@ -140,9 +154,17 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	}
 	agentStore := getAgentStore(objectStore)
 	timedVersionGenerator := utils.NewDefaultTimedVersionGenerator()
-	turnAuthHandler := NewTURNAuthHandler(keyProvider)
+	turnAuthHandler := NewTURNAuthHandler(reloadableKeyProvider)
 	forwardStats := createForwardStats(conf)
-	roomManager, err := NewLocalRoomManager(conf, objectStore, currentNode, router, roomAllocator, telemetryService, client, agentStore, rtcEgressLauncher, timedVersionGenerator, turnAuthHandler, messageBus, forwardStats)
+	participantRPCForwarder, err := NewParticipantRPCForwarder(conf, reloadableKeyProvider)
+	if err != nil {
+		return nil, err
+	}
+	mux, err := createPortMux(conf)
+	if err != nil {
+		return nil, err
+	}
+	roomManager, err := NewLocalRoomManager(conf, objectStore, currentNode, router, roomAllocator, telemetryService, client, agentStore, rtcEgressLauncher, timedVersionGenerator, turnAuthHandler, messageBus, forwardStats, participantRPCForwarder, mux)
 	if err != nil {
 		return nil, err
 	}
@@ -151,11 +173,17 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		return nil, err
 	}
 	authHandler := getTURNAuthHandlerFunc(turnAuthHandler)
-	server, err := newInProcessTurnServer(conf, authHandler)
+	turnPeerFilter := NewTURNPeerFilter(conf)
+	manager, err := createCertManager(conf)
 	if err != nil {
 		return nil, err
 	}
-	livekitServer, err := NewLivekitServer(conf, roomService, agentDispatchService, egressService, ingressService, sipService, ioInfoService, rtcService, serviceWHIPService, agentService, keyProvider, router, roomManager, signalServer, server, currentNode)
+	server, err := newInProcessTurnServer(conf, authHandler, turnPeerFilter, mux, manager)
+	if err != nil {
+		return nil, err
+	}
+	configReloader := NewConfigReloader(conf, reloadableKeyProvider, reloadableNotifier, roomAllocator, roomService, rtcService, serviceWHIPService, roomManager, currentNode, turnPeerFilter)
+	livekitServer, err := NewLivekitServer(conf, roomService, agentDispatchService, egressService, ingressService, sipService, ioInfoService, roomScheduleService, dataHistoryService, rtcService, serviceWHIPService, agentService, reloadableKeyProvider, router, roomManager, signalServer, server, currentNode, configReloader, mux, manager, clusterCluster)
 	if err != nil {
 		return nil, err
 	}
@@ -167,8 +195,12 @@ func InitializeRouter(conf *config.Config, currentNode routing.LocalNode) (routi
 	if err != nil {
 		return nil, err
 	}
+	clusterCluster, err := createCluster(conf, currentNode)
+	if err != nil {
+		return nil, err
+	}
 	nodeID := getNodeID(currentNode)
-	messageBus := getMessageBus(universalClient)
+	messageBus := getMessageBus(universalClient, clusterCluster)
 	signalRelayConfig := getSignalRelayConfig(conf)
 	signalClient, err := routing.NewSignalClient(nodeID, messageBus, signalRelayConfig)
 	if err != nil {
@@ -186,7 +218,7 @@ func InitializeRouter(conf *config.Config, currentNode routing.LocalNode) (routi
 		return nil, err
 	}
 	nodeStatsConfig := getNodeStatsConfig(conf)
-	router := routing.CreateRouter(universalClient, currentNode, signalClient, roomManagerClient, keepalivePubSub, nodeStatsConfig)
+	router := routing.CreateRouter(universalClient, clusterCluster, currentNode, signalClient, roomManagerClient, keepalivePubSub, nodeStatsConfig)
 	return router, nil
 }
 
@@ -196,7 +228,7 @@ func getNodeID(currentNode routing.LocalNode) livekit.NodeID {
 	return currentNode.NodeID()
 }
 
-func createKeyProvider(conf *config.Config) (auth.KeyProvider, error) {
+func createKeyProvider(conf *config.Config) (*ReloadableKeyProvider, error) {
 
 	if conf.KeyFile != "" {
 		var otherFilter os.FileMode = 0007
@@ -218,22 +250,15 @@ func createKeyProvider(conf *config.Config) (auth.KeyProvider, error) {
 		}
 	}
 
-	if len(conf.Keys) == 0 {
-		return nil, errors.New("one of key-file or keys must be provided in order to support a secure installation")
+	if len(conf.Keys) == 0 && len(conf.KeySecrets) == 0 {
+		return nil, errors.New("one of key-file, keys or key_secrets must be provided in order to support a secure installation")
 	}
 
-	return auth.NewFileBasedKeyProviderFromMap(conf.Keys), nil
+	return NewReloadableKeyProvider(conf), nil
 }
 
-func createWebhookNotifier(conf *config.Config, provider auth.KeyProvider) (webhook.QueuedNotifier, error) {
-	wc := conf.WebHook
-
-	secret := provider.GetSecret(wc.APIKey)
-	if secret == "" && len(wc.URLs) > 0 {
-		return nil, ErrWebHookMissingAPIKey
-	}
-
-	return webhook.NewDefaultNotifier(wc, provider)
+func createWebhookNotifier(conf *config.Config, provider *ReloadableKeyProvider) (*ReloadableNotifier, error) {
+	return NewReloadableNotifier(conf.WebHook, provider)
 }
 
 func createTelemetryService(notifier webhook.QueuedNotifier, analytics telemetry.AnalyticsService) telemetry.TelemetryService {
@@ -251,18 +276,51 @@ func createRedisClient(conf *config.Config) (redis.UniversalClient, error) {
 	return redis2.GetRedisClient(&conf.Redis)
 }
 
-func createStore(rc redis.UniversalClient) ObjectStore {
+func createCluster(conf *config.Config, currentNode routing.LocalNode) (*cluster.Cluster, error) {
+	if !conf.Cluster.Enabled {
+		return nil, nil
+	}
+	clusterConf := conf.Cluster
+	port := strconv.Itoa(int(clusterConf.Port))
+	advertiseHost := clusterConf.AdvertiseAddress
+	if advertiseHost == "" {
+		advertiseHost = currentNode.NodeIP()
+	}
+	c, err := cluster.New(cluster.Params{
+		NodeID:           string(currentNode.NodeID()),
+		BindAddress:      net.JoinHostPort(clusterConf.BindAddress, port),
+		AdvertiseAddress: net.JoinHostPort(advertiseHost, port),
+		Seeds:            clusterConf.Seeds,
+		BootstrapExpect:  clusterConf.BootstrapExpect,
+		Secret:           clusterConf.Secret,
+		GossipInterval:   clusterConf.GossipInterval,
+		DeadNodeTimeout:  clusterConf.DeadNodeTimeout,
+		Logger:           logger.GetLogger().WithComponent("cluster"),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not start cluster")
+	}
+	return c, nil
+}
+
+func createStore(rc redis.UniversalClient, c *cluster.Cluster) ObjectStore {
 	if rc != nil {
 		return NewRedisStore(rc)
 	}
+	if c != nil {
+		return NewClusterStore(c)
+	}
 	return NewLocalStore()
 }
 
-func getMessageBus(rc redis.UniversalClient) psrpc.MessageBus {
-	if rc == nil {
-		return psrpc.NewLocalMessageBus()
+func getMessageBus(rc redis.UniversalClient, c *cluster.Cluster) psrpc.MessageBus {
+	if rc != nil {
+		return psrpc.NewRedisMessageBus(rc)
 	}
-	return psrpc.NewRedisMessageBus(rc)
+	if c != nil {
+		return c.MessageBus()
+	}
+	return psrpc.NewLocalMessageBus()
 }
 
 func getEgressStore(s ObjectStore) EgressStore {
@@ -289,6 +347,8 @@ func getAgentStore(s ObjectStore) AgentStore {
 		return store
 	case *LocalStore:
 		return store
+	case *ClusterStore:
+		return store
 	default:
 		return nil
 	}
@@ -346,8 +406,50 @@ func createForwardStats(conf *config.Config) *sfu.ForwardStats {
 	return sfu.NewForwardStats(conf.RTC.ForwardStats.SummaryInterval, conf.RTC.ForwardStats.ReportInterval, conf.RTC.ForwardStats.ReportWindow)
 }
 
-func newInProcessTurnServer(conf *config.Config, authHandler turn.AuthHandler) (*turn.Server, error) {
-	return NewTurnServer(conf, authHandler, false)
+func newInProcessTurnServer(conf *config.Config, authHandler turn.AuthHandler, peerFilter *TURNPeerFilter, portMux *portmux.Mux, certManager *certmanager.Manager) (*turn.Server, error) {
+	return NewTurnServer(conf, authHandler, peerFilter, portMux, certManager, false)
+}
+
+func createCertManager(conf *config.Config) (*certmanager.Manager, error) {
+	params := certmanager.Params{
+		CertFile:      conf.TLS.CertFile,
+		KeyFile:       conf.TLS.KeyFile,
+		ClientCAFile:  conf.TLS.ClientCAFile,
+		WatchInterval: conf.TLS.WatchInterval,
+		Logger:        logger.GetLogger().WithComponent("certmanager"),
+	}
+	if acmeConf := conf.TLS.ACME; acmeConf.Enabled {
+		domains := acmeConf.Domains
+		// TURN/TLS uses the certificate of the node when it does not have its own
+		turnConf := conf.TURN
+		if turnConf.Enabled && turnConf.TLSPort > 0 && !turnConf.ExternalTLS && turnConf.CertFile == "" && !slices.Contains(domains, turnConf.Domain) {
+			domains = append(slices.Clone(domains), turnConf.Domain)
+		}
+		params.ACME = &certmanager.ACMEParams{
+			Domains:      domains,
+			Email:        acmeConf.Email,
+			DirectoryURL: acmeConf.DirectoryURL,
+			CAFile:       acmeConf.CAFile,
+			CacheDir:     acmeConf.CacheDir,
+		}
+	}
+	return certmanager.New(params)
+}
+
+func createPortMux(conf *config.Config) (*portmux.Mux, error) {
+	if conf.PortMux.Port == 0 {
+		return nil, nil
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(int(conf.PortMux.Port))))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not listen on multiplexed port")
+	}
+	return portmux.New(portmux.Params{
+		Listener:        ln,
+		TURNServerNames: conf.PortMux.TURNServerNames,
+		SniffTimeout:    conf.PortMux.SniffTimeout,
+		Logger:          logger.GetLogger().WithComponent("portmux"),
+	}), nil
 }
 
 func getNodeStatsConfig(config2 *config.Config) config.NodeStatsConfig {
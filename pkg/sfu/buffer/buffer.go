@@ -18,11 +18,14 @@ import (
 	"encoding/binary"
 	"errors"
 	"io"
+	"time"
 
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 	"github.com/pion/webrtc/v4"
+	"go.uber.org/atomic"
 
+	"github.com/livekit/livekit-server/pkg/sfu/impairment"
 	sutils "github.com/livekit/livekit-server/pkg/utils"
 	"github.com/livekit/mediatransportutil/pkg/bucket"
 	"github.com/livekit/mediatransportutil/pkg/twcc"
@@ -72,6 +75,8 @@ type Buffer struct {
 
 	primaryBufferForRTX *Buffer
 	rtxPktBuf           []byte
+
+	impairment *atomic.Pointer[impairment.Impairment]
 }
 
 func NewBuffer(ssrc uint32, maxVideoPkts, maxAudioPkts int) *Buffer {
@@ -139,6 +144,34 @@ func (b *Buffer) Bind(params webrtc.RTPParameters, codec webrtc.RTPCodecCapabili
 
 // Write adds an RTP Packet, ordering is not guaranteed, newer packets may arrive later
 func (b *Buffer) Write(pkt []byte) (n int, err error) {
+	if b.impairment != nil {
+		if imp := b.impairment.Load(); imp != nil {
+			return b.writeImpaired(imp, pkt)
+		}
+	}
+	return b.write(pkt)
+}
+
+func (b *Buffer) writeImpaired(imp *impairment.Impairment, pkt []byte) (int, error) {
+	for _, delay := range imp.Process() {
+		if delay == 0 {
+			if _, err := b.write(pkt); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		// caller re-uses the packet
+		packet := make([]byte, len(pkt))
+		copy(packet, pkt)
+		time.AfterFunc(delay, func() {
+			_, _ = b.write(packet)
+		})
+	}
+	return len(pkt), nil
+}
+
+func (b *Buffer) write(pkt []byte) (n int, err error) {
 	var rtpPacket rtp.Packet
 	err = rtpPacket.Unmarshal(pkt)
 	if err != nil {
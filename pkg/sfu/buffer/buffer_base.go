@@ -98,6 +98,8 @@ type BufferProvider interface {
 	GetStats() *livekit.RTPStats
 	GetDeltaStats() *StreamStatsWithLayers
 	GetDeltaStatsLite() *rtpstats.RTPDeltaInfoLite
+	GetUplinkStats() *rtpstats.RTPDeltaInfo
+	GetPublishStats() *rtpstats.RTPDeltaInfo
 	GetLastSenderReportTime() time.Time
 	GetNACKPairs() []rtcp.NackPair
 
@@ -179,10 +181,12 @@ type BufferBase struct {
 
 	pliThrottle int64
 
-	rtpStats             *rtpstats.RTPStatsReceiver
-	ppsSnapshotId        uint32
-	rrSnapshotId         uint32
-	deltaStatsSnapshotId uint32
+	rtpStats               *rtpstats.RTPStatsReceiver
+	ppsSnapshotId          uint32
+	rrSnapshotId           uint32
+	deltaStatsSnapshotId   uint32
+	uplinkStatsSnapshotId  uint32
+	publishStatsSnapshotId uint32
 
 	// callbacks
 	onRtcpSenderReport func()
@@ -455,6 +459,8 @@ func (b *BufferBase) setupRTPStats(clockRate uint32) {
 	if b.params.IsReportingEnabled {
 		b.rrSnapshotId = b.rtpStats.NewSnapshotId()
 		b.deltaStatsSnapshotId = b.rtpStats.NewSnapshotId()
+		b.uplinkStatsSnapshotId = b.rtpStats.NewSnapshotId()
+		b.publishStatsSnapshotId = b.rtpStats.NewSnapshotId()
 	}
 
 	b.setupRTPStatsLite(clockRate)
@@ -1386,6 +1392,31 @@ func (b *BufferBase) GetDeltaStatsLite() *rtpstats.RTPDeltaInfoLite {
 	return b.rtpStatsLite.DeltaInfoLite(b.liteStatsSnapshotId)
 }
 
+// GetUplinkStats returns stats since the last call, used to detect uplink congestion.
+// It uses a snapshot separate from GetDeltaStats so that the two can run at different cadences.
+func (b *BufferBase) GetUplinkStats() *rtpstats.RTPDeltaInfo {
+	b.RLock()
+	defer b.RUnlock()
+
+	if b.rtpStats == nil {
+		return nil
+	}
+
+	return b.rtpStats.DeltaInfo(b.uplinkStatsSnapshotId)
+}
+
+// GetPublishStats returns stats since the last call, used to measure the bitrate against the publisher quota.
+func (b *BufferBase) GetPublishStats() *rtpstats.RTPDeltaInfo {
+	b.RLock()
+	defer b.RUnlock()
+
+	if b.rtpStats == nil {
+		return nil
+	}
+
+	return b.rtpStats.DeltaInfo(b.publishStatsSnapshotId)
+}
+
 func (b *BufferBase) GetLastSenderReportTime() time.Time {
 	b.RLock()
 	defer b.RUnlock()
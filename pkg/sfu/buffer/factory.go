@@ -19,6 +19,9 @@ import (
 	"sync"
 
 	"github.com/pion/transport/v4/packetio"
+	"go.uber.org/atomic"
+
+	"github.com/livekit/livekit-server/pkg/sfu/impairment"
 )
 
 type FactoryOfBufferFactory struct {
@@ -50,6 +53,10 @@ type Factory struct {
 	rtpBuffers           map[uint32]*Buffer
 	rtcpReaders          map[uint32]*RTCPReader
 	rtxPair              map[uint32]uint32 // repair -> base
+
+	// impairments of packets received from and sent to the participant owning the factory, development only
+	upstreamImpairment   atomic.Pointer[impairment.Impairment]
+	downstreamImpairment atomic.Pointer[impairment.Impairment]
 }
 
 func (f *Factory) GetOrNew(packetType packetio.BufferPacketType, ssrc uint32) io.ReadWriteCloser {
@@ -73,6 +80,7 @@ func (f *Factory) GetOrNew(packetType packetio.BufferPacketType, ssrc uint32) io
 			return reader
 		}
 		buffer := NewBuffer(ssrc, f.trackingPacketsVideo, f.trackingPacketsAudio)
+		buffer.impairment = &f.upstreamImpairment
 		f.rtpBuffers[ssrc] = buffer
 		for repair, base := range f.rtxPair {
 			if repair == ssrc {
@@ -100,6 +108,23 @@ func (f *Factory) GetOrNew(packetType packetio.BufferPacketType, ssrc uint32) io
 	return nil
 }
 
+// SetImpairment impairs packets received by buffers of the factory and sent by down tracks using it, nil clears impairments
+func (f *Factory) SetImpairment(upstream *impairment.Impairment, downstream *impairment.Impairment) {
+	f.upstreamImpairment.Store(upstream)
+	f.downstreamImpairment.Store(downstream)
+}
+
+func (f *Factory) GetImpairment() (upstream *impairment.Impairment, downstream *impairment.Impairment) {
+	return f.upstreamImpairment.Load(), f.downstreamImpairment.Load()
+}
+
+func (f *Factory) DownstreamImpairment() *impairment.Impairment {
+	if f == nil {
+		return nil
+	}
+	return f.downstreamImpairment.Load()
+}
+
 func (f *Factory) GetBufferPair(ssrc uint32) (*Buffer, *RTCPReader) {
 	f.RLock()
 	defer f.RUnlock()
@@ -21,6 +21,7 @@ import (
 	"io"
 	"math"
 	"math/rand"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -42,6 +43,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/bwe"
 	"github.com/livekit/livekit-server/pkg/sfu/ccutils"
 	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
+	"github.com/livekit/livekit-server/pkg/sfu/impairment"
 	"github.com/livekit/livekit-server/pkg/sfu/pacer"
 	"github.com/livekit/livekit-server/pkg/sfu/packettrailer"
 	act "github.com/livekit/livekit-server/pkg/sfu/rtpextension/abscapturetime"
@@ -49,6 +51,7 @@ import (
 	pd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/playoutdelay"
 	"github.com/livekit/livekit-server/pkg/sfu/rtpstats"
 	"github.com/livekit/livekit-server/pkg/sfu/utils"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
 )
 
 // TrackSender defines an interface send media to remote peer
@@ -313,6 +316,7 @@ type DownTrackParams struct {
 	SupportsCodecChange            bool
 	StripPacketTrailer             bool
 	Listener                       DownTrackListener
+	Timeline                       sutils.TimelineRecorder
 }
 
 // DownTrack implements webrtc.TrackLocal, is the track used to write packets
@@ -1195,7 +1199,11 @@ func (d *DownTrack) WriteRTP(extPkt *buffer.ExtPacket, layer int32) int32 {
 		Pool:               PacketFactory,
 		PoolEntity:         poolEntity,
 	}
-	d.pacer.Enqueue(pacerPacket)
+	if imp := d.params.BufferFactory.DownstreamImpairment(); imp != nil {
+		d.enqueueImpaired(imp, pacerPacket)
+	} else {
+		d.pacer.Enqueue(pacerPacket)
+	}
 
 	if extPkt.IsKeyFrame {
 		d.isNACKThrottled.Store(false)
@@ -1210,6 +1218,7 @@ func (d *DownTrack) WriteRTP(extPkt *buffer.ExtPacket, layer int32) int32 {
 
 	if tp.isSwitching {
 		d.postMaxLayerNotifierEvent("switching")
+		d.params.Timeline.Record("layer_switch", "layer", layer, "isKeyFrame", extPkt.IsKeyFrame)
 	}
 
 	if tp.isResuming {
@@ -1224,6 +1233,58 @@ func (d *DownTrack) WriteRTP(extPkt *buffer.ExtPacket, layer int32) int32 {
 	return 1
 }
 
+// enqueueImpaired sends the packet subject to the impairment, copies are not pooled
+func (d *DownTrack) enqueueImpaired(imp *impairment.Impairment, p *pacer.Packet) {
+	delays := imp.Process()
+	if len(delays) == 0 {
+		releasePacket(p)
+		return
+	}
+
+	// copy before enqueueing as the pacer releases the original once it is sent
+	packets := make([]*pacer.Packet, len(delays))
+	packets[0] = p
+	for idx := 1; idx < len(delays); idx++ {
+		packet := pacer.PacketFactory.Get().(*pacer.Packet)
+		*packet = *p
+		header := p.Header.Clone()
+		packet.Header = &header
+		packet.HeaderPool = nil
+		packet.Payload = slices.Clone(p.Payload)
+		packet.Pool = nil
+		packet.PoolEntity = nil
+		packets[idx] = packet
+	}
+
+	for idx, delay := range delays {
+		packet := packets[idx]
+		if delay == 0 {
+			d.pacer.Enqueue(packet)
+			continue
+		}
+		time.AfterFunc(delay, func() {
+			if d.IsClosed() {
+				releasePacket(packet)
+				return
+			}
+			d.pacer.Enqueue(packet)
+		})
+	}
+}
+
+// releasePacket returns a packet which is not sent to its pools
+func releasePacket(p *pacer.Packet) {
+	if p.HeaderPool != nil && p.Header != nil {
+		*p.Header = rtp.Header{}
+		p.HeaderPool.Put(p.Header)
+	}
+	if p.Pool != nil && p.PoolEntity != nil {
+		p.Pool.Put(p.PoolEntity)
+	}
+	*p = pacer.Packet{}
+	pacer.PacketFactory.Put(p)
+}
+
 // WritePaddingRTP tries to write as many padding only RTP packets as necessary
 // to satisfy given size to the DownTrack
 func (d *DownTrack) WritePaddingRTP(bytesToSend int, paddingOnMute bool, forceMarker bool) int {
@@ -1980,6 +2041,7 @@ func (d *DownTrack) handleRTCP(bytes []byte) {
 		if pliOnce {
 			if layer != buffer.InvalidLayerSpatial {
 				d.params.Logger.Debugw("sending PLI RTCP", "layer", layer)
+				d.params.Timeline.Record("pli", "layer", layer)
 				d.Receiver().SendPLI(layer, false)
 				d.isNACKThrottled.Store(true)
 				d.rtpStats.UpdatePliTime()
@@ -0,0 +1,144 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package impairment injects packet level network impairments for testing, it is only meant for development.
+package impairment
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const defaultReorderDelay = 20 * time.Millisecond
+
+// Config describes impairments applied to packets, probabilities are in percent
+type Config struct {
+	// random loss
+	LossPercent float64 `json:"lossPercent,omitempty"`
+	// bursty loss, applied in addition to random loss
+	BurstLoss *GilbertElliottConfig `json:"burstLoss,omitempty"`
+	// delay added to every packet
+	LatencyMs int `json:"latencyMs,omitempty"`
+	// random delay of up to JitterMs added to every packet
+	JitterMs int `json:"jitterMs,omitempty"`
+	// packets held back by ReorderDelayMs (default 20) so that packets sent after them arrive first
+	ReorderPercent   float64 `json:"reorderPercent,omitempty"`
+	ReorderDelayMs   int     `json:"reorderDelayMs,omitempty"`
+	DuplicatePercent float64 `json:"duplicatePercent,omitempty"`
+	// seed of the random generator to make impairments repeatable, a random seed is used when 0
+	Seed int64 `json:"seed,omitempty"`
+}
+
+func (c Config) IsEnabled() bool {
+	return c.LossPercent > 0 ||
+		c.BurstLoss != nil ||
+		c.LatencyMs > 0 ||
+		c.JitterMs > 0 ||
+		c.ReorderPercent > 0 ||
+		c.DuplicatePercent > 0
+}
+
+// GilbertElliottConfig is a two state model of bursty loss, packets are lost with a
+// different probability in each state and the state changes with every packet
+type GilbertElliottConfig struct {
+	GoodToBadPercent float64 `json:"goodToBadPercent"`
+	BadToGoodPercent float64 `json:"badToGoodPercent"`
+	GoodLossPercent  float64 `json:"goodLossPercent,omitempty"`
+	BadLossPercent   float64 `json:"badLossPercent"`
+}
+
+type Impairment struct {
+	conf Config
+
+	lock  sync.Mutex
+	rng   *rand.Rand
+	isBad bool
+}
+
+// New returns nil when the config does not impair packets
+func New(conf Config) *Impairment {
+	if !conf.IsEnabled() {
+		return nil
+	}
+
+	seed := conf.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Impairment{
+		conf: conf,
+		rng:  rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (i *Impairment) Config() Config {
+	return i.conf
+}
+
+// Process decides the fate of a packet, returning the delay of each copy to deliver, none when the packet is lost
+func (i *Impairment) Process() []time.Duration {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.isLostLocked() {
+		return nil
+	}
+
+	delays := []time.Duration{i.delayLocked()}
+	if i.chanceLocked(i.conf.DuplicatePercent) {
+		delays = append(delays, i.delayLocked())
+	}
+	return delays
+}
+
+func (i *Impairment) isLostLocked() bool {
+	// advance the burst state for every packet, lost or not
+	lost := false
+	if ge := i.conf.BurstLoss; ge != nil {
+		if i.isBad {
+			i.isBad = !i.chanceLocked(ge.BadToGoodPercent)
+		} else {
+			i.isBad = i.chanceLocked(ge.GoodToBadPercent)
+		}
+		if i.isBad {
+			lost = i.chanceLocked(ge.BadLossPercent)
+		} else {
+			lost = i.chanceLocked(ge.GoodLossPercent)
+		}
+	}
+	return i.chanceLocked(i.conf.LossPercent) || lost
+}
+
+func (i *Impairment) delayLocked() time.Duration {
+	delay := time.Duration(i.conf.LatencyMs) * time.Millisecond
+	if i.conf.JitterMs > 0 {
+		delay += time.Duration(i.rng.Int63n(int64(i.conf.JitterMs) * int64(time.Millisecond)))
+	}
+	if i.chanceLocked(i.conf.ReorderPercent) {
+		if i.conf.ReorderDelayMs > 0 {
+			delay += time.Duration(i.conf.ReorderDelayMs) * time.Millisecond
+		} else {
+			delay += defaultReorderDelay
+		}
+	}
+	return delay
+}
+
+func (i *Impairment) chanceLocked(percent float64) bool {
+	if percent <= 0 {
+		return false
+	}
+	return i.rng.Float64()*100 < percent
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impairment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpairment(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		require.Nil(t, New(Config{Seed: 1}))
+	})
+
+	t.Run("loss", func(t *testing.T) {
+		i := New(Config{LossPercent: 100})
+		require.Empty(t, i.Process())
+
+		i = New(Config{LossPercent: 30, Seed: 1})
+		lost := 0
+		for range 10000 {
+			if len(i.Process()) == 0 {
+				lost++
+			}
+		}
+		require.InDelta(t, 3000, lost, 300)
+	})
+
+	t.Run("bursty loss", func(t *testing.T) {
+		i := New(Config{
+			BurstLoss: &GilbertElliottConfig{GoodToBadPercent: 5, BadToGoodPercent: 20, BadLossPercent: 100},
+			Seed:      1,
+		})
+		lost, bursts, wasLost := 0, 0, false
+		for range 10000 {
+			isLost := len(i.Process()) == 0
+			if isLost {
+				lost++
+				if !wasLost {
+					bursts++
+				}
+			}
+			wasLost = isLost
+		}
+		// steady state loss of 5 / (5 + 20), in bursts of 5 packets on average
+		require.InDelta(t, 2000, lost, 300)
+		require.InDelta(t, 5, float64(lost)/float64(bursts), 1)
+	})
+
+	t.Run("delay and duplication", func(t *testing.T) {
+		i := New(Config{LatencyMs: 50, JitterMs: 10, DuplicatePercent: 100, Seed: 1})
+		for range 100 {
+			delays := i.Process()
+			require.Len(t, delays, 2)
+			for _, delay := range delays {
+				require.GreaterOrEqual(t, delay, 50*time.Millisecond)
+				require.Less(t, delay, 60*time.Millisecond)
+			}
+		}
+	})
+
+	t.Run("reordering", func(t *testing.T) {
+		i := New(Config{ReorderPercent: 100, ReorderDelayMs: 30})
+		require.Equal(t, []time.Duration{30 * time.Millisecond}, i.Process())
+	})
+
+	t.Run("repeatable", func(t *testing.T) {
+		conf := Config{LossPercent: 50, JitterMs: 20, Seed: 42}
+		i1, i2 := New(conf), New(conf)
+		for range 100 {
+			require.Equal(t, i1.Process(), i2.Process())
+		}
+	})
+}
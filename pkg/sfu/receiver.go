@@ -321,6 +321,44 @@ func (w *WebRTCReceiver) GetDeltaStats() map[uint32]*buffer.StreamStatsWithLayer
 	return deltaStats
 }
 
+// GetUplinkStats returns stats of each spatial layer since the last call.
+func (w *WebRTCReceiver) GetUplinkStats() map[int32]*rtpstats.RTPDeltaInfo {
+	buffers := w.ReceiverBase.GetAllBuffers()
+	uplinkStats := make(map[int32]*rtpstats.RTPDeltaInfo, len(buffers))
+	for layer, buff := range buffers {
+		if buff == nil {
+			continue
+		}
+
+		if deltaInfo := buff.GetUplinkStats(); deltaInfo != nil {
+			uplinkStats[int32(layer)] = deltaInfo
+		}
+	}
+
+	return uplinkStats
+}
+
+// GetPublishBitrate returns the bitrate received across all layers since the last call, in bits per second.
+// Padding and duplicate packets are not counted.
+func (w *WebRTCReceiver) GetPublishBitrate() float64 {
+	var bitrate float64
+	for _, buff := range w.ReceiverBase.GetAllBuffers() {
+		if buff == nil {
+			continue
+		}
+
+		deltaInfo := buff.GetPublishStats()
+		if deltaInfo == nil {
+			continue
+		}
+		if duration := deltaInfo.EndTime.Sub(deltaInfo.StartTime).Seconds(); duration > 0 {
+			bitrate += float64(deltaInfo.Bytes+deltaInfo.HeaderBytes) * 8 / duration
+		}
+	}
+
+	return bitrate
+}
+
 func (w *WebRTCReceiver) GetLastSenderReportTime() time.Time {
 	buffers := w.ReceiverBase.GetAllBuffers()
 	latestSRTime := time.Time{}
@@ -53,10 +53,13 @@ type RTPDeltaInfo struct {
 	Frames               uint32
 	RttMax               uint32
 	JitterMax            float64
-	Nacks                uint32
-	NackRepeated         uint32
-	Plis                 uint32
-	Firs                 uint32
+	// mean one-way arrival delay (in micro seconds) of frames in the interval, relative to the first frame of the stream,
+	// a value growing across intervals indicates queues building up in the path
+	ArrivalDelayMean float64
+	Nacks            uint32
+	NackRepeated     uint32
+	Plis             uint32
+	Firs             uint32
 }
 
 func (r *RTPDeltaInfo) MarshalLogObject(e zapcore.ObjectEncoder) error {
@@ -81,6 +84,7 @@ func (r *RTPDeltaInfo) MarshalLogObject(e zapcore.ObjectEncoder) error {
 	e.AddUint32("Frames", r.Frames)
 	e.AddUint32("RttMax", r.RttMax)
 	e.AddFloat64("JitterMax", r.JitterMax)
+	e.AddFloat64("ArrivalDelayMean", r.ArrivalDelayMean)
 	e.AddUint32("Nacks", r.Nacks)
 	e.AddUint32("NackRepeated", r.NackRepeated)
 	e.AddUint32("Plis", r.Plis)
@@ -110,6 +114,9 @@ type snapshot struct {
 
 	maxRtt    uint32
 	maxJitter float64
+
+	arrivalDelaySum   float64
+	arrivalDelayCount uint32
 }
 
 func (s *snapshot) MarshalLogObject(e zapcore.ObjectEncoder) error {
@@ -130,6 +137,8 @@ func (s *snapshot) MarshalLogObject(e zapcore.ObjectEncoder) error {
 	e.AddUint32("firs", s.firs)
 	e.AddUint32("maxRtt", s.maxRtt)
 	e.AddFloat64("maxJitter", s.maxJitter)
+	e.AddFloat64("arrivalDelaySum", s.arrivalDelaySum)
+	e.AddUint32("arrivalDelayCount", s.arrivalDelayCount)
 	return nil
 }
 
@@ -145,6 +154,19 @@ func (s *snapshot) maybeUpdateMaxJitter(jitter float64) {
 	}
 }
 
+func (s *snapshot) addArrivalDelay(delay float64) {
+	s.arrivalDelaySum += delay
+	s.arrivalDelayCount++
+}
+
+func (s *snapshot) arrivalDelayMean() float64 {
+	if s.arrivalDelayCount == 0 {
+		return 0
+	}
+
+	return s.arrivalDelaySum / float64(s.arrivalDelayCount)
+}
+
 // ------------------------------------------------------------------
 
 type wrappedRTPDriftLogger struct {
@@ -212,6 +234,7 @@ type rtpStatsBase struct {
 	firstTimeAdjustment time.Duration
 	highestTime         int64
 
+	firstTransit           uint64
 	lastTransit            uint64
 	lastJitterExtTimestamp uint64
 
@@ -275,6 +298,7 @@ func (r *rtpStatsBase) seed(from *rtpStatsBase) bool {
 	r.firstTimeAdjustment = from.firstTimeAdjustment
 	r.highestTime = from.highestTime
 
+	r.firstTransit = from.firstTransit
 	r.lastTransit = from.lastTransit
 	r.lastJitterExtTimestamp = from.lastJitterExtTimestamp
 
@@ -552,6 +576,7 @@ func (r *rtpStatsBase) deltaInfo(
 		Frames:               now.frames - then.frames,
 		RttMax:               then.maxRtt,
 		JitterMax:            then.maxJitter / float64(r.clockRate) * 1e6,
+		ArrivalDelayMean:     then.arrivalDelayMean() / float64(r.clockRate) * 1e6,
 		Nacks:                now.nacks - then.nacks,
 		Plis:                 now.plis - then.plis,
 		Firs:                 now.firs - then.firs,
@@ -686,6 +711,15 @@ func (r *rtpStatsBase) updateJitter(ets uint64, packetTime int64) float64 {
 			for i := uint32(0); i < r.nextSnapshotID-cFirstSnapshotID; i++ {
 				r.snapshots[i].maybeUpdateMaxJitter(r.jitter)
 			}
+		} else {
+			r.firstTransit = transit
+		}
+
+		// arrival delay relative to the first frame, the absolute value is meaningless
+		// as sender and receiver clocks are not synchronised, but the trend indicates queuing
+		arrivalDelay := float64(int64(transit - r.firstTransit))
+		for i := uint32(0); i < r.nextSnapshotID-cFirstSnapshotID; i++ {
+			r.snapshots[i].addArrivalDelay(arrivalDelay)
 		}
 
 		r.lastTransit = transit
@@ -864,6 +898,7 @@ func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
 
 	maxRtt := uint32(0)
 	maxJitter := float64(0)
+	maxArrivalDelayMean := float64(0)
 
 	nacks := uint32(0)
 	plis := uint32(0)
@@ -908,6 +943,10 @@ func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
 			maxJitter = deltaInfo.JitterMax
 		}
 
+		if deltaInfo.ArrivalDelayMean > maxArrivalDelayMean {
+			maxArrivalDelayMean = deltaInfo.ArrivalDelayMean
+		}
+
 		nacks += deltaInfo.Nacks
 		plis += deltaInfo.Plis
 		firs += deltaInfo.Firs
@@ -934,6 +973,7 @@ func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
 		Frames:               frames,
 		RttMax:               maxRtt,
 		JitterMax:            maxJitter,
+		ArrivalDelayMean:     maxArrivalDelayMean,
 		Nacks:                nacks,
 		Plis:                 plis,
 		Firs:                 firs,
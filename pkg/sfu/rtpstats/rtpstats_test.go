@@ -251,6 +251,44 @@ func Test_RTPStatsReceiver_Restart(t *testing.T) {
 	r.Stop()
 }
 
+func Test_RTPStatsReceiver_ArrivalDelay(t *testing.T) {
+	clockRate := uint32(90000)
+	r := NewRTPStatsReceiver(RTPStatsParams{})
+	r.SetClockRate(clockRate)
+
+	// 10 ms frame interval
+	packetTime := time.Now().UnixNano()
+	sequenceNumber := uint16(rand.Float64() * float64(1<<16))
+	timestamp := uint32(rand.Float64() * float64(1<<32))
+	r.Update(packetTime, sequenceNumber, timestamp, true, 12, 1000, 0)
+
+	snapshotID := r.NewSnapshotId()
+
+	// packets arriving at the rate they are sent, no queuing
+	for i := 0; i < 10; i++ {
+		packetTime += int64(10 * time.Millisecond)
+		sequenceNumber++
+		timestamp += 900
+		r.Update(packetTime, sequenceNumber, timestamp, true, 12, 1000, 0)
+	}
+	deltaInfo := r.DeltaInfo(snapshotID)
+	require.NotNil(t, deltaInfo)
+	require.InDelta(t, 0.0, deltaInfo.ArrivalDelayMean, 1.0)
+
+	// each packet delayed 1 ms more than the previous one, queues building up
+	for i := 1; i <= 10; i++ {
+		packetTime += int64(11 * time.Millisecond)
+		sequenceNumber++
+		timestamp += 900
+		r.Update(packetTime, sequenceNumber, timestamp, true, 12, 1000, 0)
+	}
+	deltaInfo = r.DeltaInfo(snapshotID)
+	require.NotNil(t, deltaInfo)
+	require.InDelta(t, 5500.0, deltaInfo.ArrivalDelayMean, 1.0)
+
+	r.Stop()
+}
+
 func Test_RTPStatsSender_getIntervalStats(t *testing.T) {
 	t.Run("packetsNotFoundMetadata should match lost packets", func(t *testing.T) {
 		r := NewRTPStatsSender(RTPStatsParams{}, 1024)
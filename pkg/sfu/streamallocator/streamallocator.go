@@ -219,6 +219,7 @@ type StreamAllocatorParams struct {
 	Pacer     pacer.Pacer
 	RTTGetter func() (float64, bool)
 	Logger    logger.Logger
+	Timeline  utils.TimelineRecorder
 }
 
 type StreamAllocator struct {
@@ -855,6 +856,12 @@ func (s *StreamAllocator) handleSignalSetChannelCapacity(event Event) {
 
 func (s *StreamAllocator) handleSignalCongestionStateChange(event Event) {
 	cscd := event.congestionStateChangeData
+	s.params.Timeline.Record(
+		"congestion_state",
+		"from", cscd.fromState,
+		"to", cscd.toState,
+		"estimatedAvailableChannelCapacity", cscd.estimatedAvailableChannelCapacity,
+	)
 	if cscd.toState != bwe.CongestionStateNone {
 		// end/abort any running probe if channel is not clear
 		s.maybeStopProbe()
@@ -902,6 +909,7 @@ func (s *StreamAllocator) setState(state streamAllocatorState) {
 	}
 
 	s.params.Logger.Infow("stream allocator: state change", "from", s.state, "to", state)
+	s.params.Timeline.Record("stream_allocator_state", "from", s.state, "to", state)
 	s.state = state
 
 	// restart everything when state is STABLE
@@ -0,0 +1,186 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uplinkcongestion
+
+import (
+	"time"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/rtpstats"
+)
+
+type UplinkCongestionConfig struct {
+	Enabled        bool          `yaml:"enabled,omitempty"`
+	SampleInterval time.Duration `yaml:"sample_interval,omitempty"`
+	// fraction of packets lost in a sample above which the sample is considered congested
+	LossThreshold float64 `yaml:"loss_threshold,omitempty"`
+	// max jitter in a sample above which the sample is considered congested
+	JitterThreshold time.Duration `yaml:"jitter_threshold,omitempty"`
+	// growth of mean arrival delay between consecutive samples above which the sample is considered congested
+	DelayGrowthThreshold time.Duration `yaml:"delay_growth_threshold,omitempty"`
+	// number of consecutive congested samples needed to pause one more layer
+	CongestedSamples int `yaml:"congested_samples,omitempty"`
+	// number of consecutive clear samples needed to resume one layer
+	ClearSamples int `yaml:"clear_samples,omitempty"`
+}
+
+var (
+	DefaultUplinkCongestionConfig = UplinkCongestionConfig{
+		Enabled:              false,
+		SampleInterval:       time.Second,
+		LossThreshold:        0.1,
+		JitterThreshold:      50 * time.Millisecond,
+		DelayGrowthThreshold: 20 * time.Millisecond,
+		CongestedSamples:     2,
+		ClearSamples:         10,
+	}
+)
+
+// ------------------------------------------------
+
+type UplinkCongestionDetectorParams struct {
+	Config UplinkCongestionConfig
+	Logger logger.Logger
+}
+
+// UplinkCongestionDetector looks at receive side stats of the simulcast layers
+// of a published track and decides the highest spatial layer the publisher
+// should send. Layers are paused one at a time from the top when the uplink
+// is congested and resumed one at a time after the uplink has been clear for a while.
+//
+// Not thread safe, expected to be driven by a single sampling goroutine.
+type UplinkCongestionDetector struct {
+	params UplinkCongestionDetectorParams
+
+	maxLayer     int32
+	maxLayerSeen int32
+
+	arrivalDelays map[int32]float64
+
+	numCongested int
+	numClear     int
+}
+
+func NewUplinkCongestionDetector(params UplinkCongestionDetectorParams) *UplinkCongestionDetector {
+	if params.Logger == nil {
+		params.Logger = logger.GetLogger()
+	}
+	return &UplinkCongestionDetector{
+		params:        params,
+		maxLayer:      buffer.DefaultMaxLayerSpatial,
+		maxLayerSeen:  buffer.InvalidLayerSpatial,
+		arrivalDelays: make(map[int32]float64),
+	}
+}
+
+// MaxLayer returns the highest spatial layer the publisher should send,
+// buffer.DefaultMaxLayerSpatial when the uplink is not congested.
+func (u *UplinkCongestionDetector) MaxLayer() int32 {
+	return u.maxLayer
+}
+
+// Update processes stats of an interval, keyed by spatial layer, and returns the
+// highest spatial layer the publisher should send along with whether it changed.
+func (u *UplinkCongestionDetector) Update(layerStats map[int32]*rtpstats.RTPDeltaInfo) (int32, bool) {
+	highestActive := buffer.InvalidLayerSpatial
+	packets := uint32(0)
+	packetsLost := uint32(0)
+	maxJitter := float64(0)
+	maxDelayGrowth := float64(0)
+	for layer, stats := range layerStats {
+		if stats == nil || stats.Packets == 0 {
+			// a layer restarting after a pause starts a new delay baseline
+			delete(u.arrivalDelays, layer)
+			continue
+		}
+
+		if layer > highestActive {
+			highestActive = layer
+		}
+
+		packets += stats.Packets
+		packetsLost += stats.PacketsLost
+		if stats.JitterMax > maxJitter {
+			maxJitter = stats.JitterMax
+		}
+
+		if prev, ok := u.arrivalDelays[layer]; ok {
+			if growth := stats.ArrivalDelayMean - prev; growth > maxDelayGrowth {
+				maxDelayGrowth = growth
+			}
+		}
+		u.arrivalDelays[layer] = stats.ArrivalDelayMean
+	}
+	if highestActive == buffer.InvalidLayerSpatial {
+		// nothing flowing (muted or all layers paused), nothing to infer
+		u.numCongested = 0
+		u.numClear = 0
+		return u.maxLayer, false
+	}
+
+	if highestActive > u.maxLayerSeen {
+		u.maxLayerSeen = highestActive
+	}
+
+	lossRatio := float64(packetsLost) / float64(packets)
+	isCongested := (u.params.Config.LossThreshold > 0 && lossRatio > u.params.Config.LossThreshold) ||
+		(u.params.Config.JitterThreshold > 0 && maxJitter > float64(u.params.Config.JitterThreshold.Microseconds())) ||
+		(u.params.Config.DelayGrowthThreshold > 0 && maxDelayGrowth > float64(u.params.Config.DelayGrowthThreshold.Microseconds()))
+
+	if isCongested {
+		u.numClear = 0
+		u.numCongested++
+		if u.numCongested < u.params.Config.CongestedSamples {
+			return u.maxLayer, false
+		}
+		u.numCongested = 0
+
+		top := min(u.maxLayer, highestActive)
+		if top <= 0 {
+			// always leave the lowest layer on
+			return u.maxLayer, false
+		}
+
+		u.maxLayer = top - 1
+		u.params.Logger.Infow(
+			"uplink congested, pausing layer",
+			"maxLayer", u.maxLayer,
+			"lossRatio", lossRatio,
+			"maxJitter", time.Duration(maxJitter)*time.Microsecond,
+			"maxDelayGrowth", time.Duration(maxDelayGrowth)*time.Microsecond,
+		)
+		return u.maxLayer, true
+	}
+
+	u.numCongested = 0
+	u.numClear++
+	if u.numClear < u.params.Config.ClearSamples {
+		return u.maxLayer, false
+	}
+	u.numClear = 0
+
+	if u.maxLayer >= u.maxLayerSeen {
+		return u.maxLayer, false
+	}
+
+	u.maxLayer++
+	if u.maxLayer >= u.maxLayerSeen {
+		u.maxLayer = buffer.DefaultMaxLayerSpatial
+	}
+	u.params.Logger.Infow("uplink clear, resuming layer", "maxLayer", u.maxLayer)
+	return u.maxLayer, true
+}
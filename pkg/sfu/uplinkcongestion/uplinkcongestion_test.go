@@ -0,0 +1,130 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uplinkcongestion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/rtpstats"
+)
+
+func newTestDetector() *UplinkCongestionDetector {
+	return NewUplinkCongestionDetector(UplinkCongestionDetectorParams{
+		Config: DefaultUplinkCongestionConfig,
+	})
+}
+
+func layerStats(numLayers int32, packetsLost uint32, jitter float64, arrivalDelay float64) map[int32]*rtpstats.RTPDeltaInfo {
+	stats := make(map[int32]*rtpstats.RTPDeltaInfo, numLayers)
+	for layer := int32(0); layer < numLayers; layer++ {
+		stats[layer] = &rtpstats.RTPDeltaInfo{
+			Packets:          100,
+			PacketsLost:      packetsLost,
+			JitterMax:        jitter,
+			ArrivalDelayMean: arrivalDelay,
+		}
+	}
+	return stats
+}
+
+func TestUplinkCongestionDetector(t *testing.T) {
+	t.Run("loss pauses layers from the top and keeps the lowest", func(t *testing.T) {
+		u := newTestDetector()
+
+		maxLayer, changed := u.Update(layerStats(3, 0, 1000, 0))
+		require.False(t, changed)
+		require.Equal(t, int32(buffer.DefaultMaxLayerSpatial), maxLayer)
+
+		// needs consecutive congested samples
+		_, changed = u.Update(layerStats(3, 20, 1000, 0))
+		require.False(t, changed)
+		maxLayer, changed = u.Update(layerStats(3, 20, 1000, 0))
+		require.True(t, changed)
+		require.Equal(t, int32(1), maxLayer)
+
+		// top layer paused by publisher
+		_, changed = u.Update(layerStats(2, 20, 1000, 0))
+		require.False(t, changed)
+		maxLayer, changed = u.Update(layerStats(2, 20, 1000, 0))
+		require.True(t, changed)
+		require.Equal(t, int32(0), maxLayer)
+
+		// lowest layer is never paused
+		for i := 0; i < 4; i++ {
+			maxLayer, changed = u.Update(layerStats(1, 20, 1000, 0))
+			require.False(t, changed)
+			require.Equal(t, int32(0), maxLayer)
+		}
+	})
+
+	t.Run("clear uplink resumes layers one at a time", func(t *testing.T) {
+		u := newTestDetector()
+
+		u.Update(layerStats(3, 0, 1000, 0))
+		u.Update(layerStats(3, 0, 100000, 0))
+		maxLayer, changed := u.Update(layerStats(3, 0, 100000, 0))
+		require.True(t, changed)
+		require.Equal(t, int32(1), maxLayer)
+
+		for i := 0; i < DefaultUplinkCongestionConfig.ClearSamples-1; i++ {
+			_, changed = u.Update(layerStats(2, 0, 1000, 0))
+			require.False(t, changed)
+		}
+		maxLayer, changed = u.Update(layerStats(2, 0, 1000, 0))
+		require.True(t, changed)
+		require.Equal(t, int32(buffer.DefaultMaxLayerSpatial), maxLayer)
+
+		// nothing more to resume
+		for i := 0; i < DefaultUplinkCongestionConfig.ClearSamples; i++ {
+			_, changed = u.Update(layerStats(3, 0, 1000, 0))
+			require.False(t, changed)
+		}
+	})
+
+	t.Run("growing arrival delay is congestion", func(t *testing.T) {
+		u := newTestDetector()
+
+		// steady delay is not congestion, irrespective of absolute value
+		for i := 0; i < 5; i++ {
+			_, changed := u.Update(layerStats(3, 0, 1000, 100000))
+			require.False(t, changed)
+		}
+
+		u.Update(layerStats(3, 0, 1000, 130000))
+		maxLayer, changed := u.Update(layerStats(3, 0, 1000, 160000))
+		require.True(t, changed)
+		require.Equal(t, int32(1), maxLayer)
+	})
+
+	t.Run("no packets does not change state", func(t *testing.T) {
+		u := newTestDetector()
+
+		u.Update(layerStats(3, 20, 1000, 0))
+		maxLayer, changed := u.Update(map[int32]*rtpstats.RTPDeltaInfo{0: {}, 1: {}, 2: {}})
+		require.False(t, changed)
+		require.Equal(t, int32(buffer.DefaultMaxLayerSpatial), maxLayer)
+
+		// congested count is reset by a sample without packets
+		u.Update(layerStats(3, 20, 1000, 0))
+		_, changed = u.Update(map[int32]*rtpstats.RTPDeltaInfo{})
+		require.False(t, changed)
+		_, changed = u.Update(layerStats(3, 20, 1000, 0))
+		require.False(t, changed)
+		require.Equal(t, int32(buffer.DefaultMaxLayerSpatial), u.MaxLayer())
+	})
+}
@@ -29,6 +29,16 @@ import (
 	"github.com/livekit/protocol/webhook"
 )
 
+// webhook events for room features that do not have an equivalent in the protocol package
+const (
+	EventParticipantAdmitted = "participant_admitted"
+	EventParticipantRejected = "participant_rejected"
+
+	EventRoomScheduleStarted = "room_schedule_started"
+	EventRoomScheduleEnding  = "room_schedule_ending"
+	EventRoomScheduleEnded   = "room_schedule_ended"
+)
+
 func (t *telemetryService) NotifyEvent(ctx context.Context, event *livekit.WebhookEvent, opts ...webhook.NotifyOption) {
 	if t.notifier == nil {
 		return
@@ -493,6 +503,16 @@ func (t *telemetryService) NotifyEgressEvent(ctx context.Context, event string,
 	}, opts...)
 }
 
+func (t *telemetryService) NotifyRoomEvent(ctx context.Context, event string, room *livekit.Room, participant *livekit.ParticipantInfo) {
+	t.enqueue(func() {
+		t.NotifyEvent(ctx, &livekit.WebhookEvent{
+			Event:       event,
+			Room:        room,
+			Participant: participant,
+		})
+	})
+}
+
 func (t *telemetryService) EgressStarted(ctx context.Context, info *livekit.EgressInfo) {
 
 	t.enqueue(func() {
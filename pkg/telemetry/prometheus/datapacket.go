@@ -29,6 +29,7 @@ var (
 
 	promDataPacketStreamDestCount *prometheus.HistogramVec
 	promDataPacketStreamSize      *prometheus.HistogramVec
+	promDataPacketRejected        *prometheus.CounterVec
 )
 
 func initDataPacketStats(nodeID string, nodeType livekit.NodeType) {
@@ -47,8 +48,16 @@ func initDataPacketStats(nodeID string, nodeType livekit.NodeType) {
 		Buckets:     []float64{128, 512, 2048, 8192, 32768, 131072, 524288, 2097152, 8388608, 33554432},
 	}, promDataPacketStreamLabels)
 
+	promDataPacketRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "datapacket",
+		Name:        "rejected_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	}, []string{"type", "permission"})
+
 	prometheus.MustRegister(promDataPacketStreamDestCount)
 	prometheus.MustRegister(promDataPacketStreamSize)
+	prometheus.MustRegister(promDataPacketRejected)
 }
 
 func RecordDataPacketStream(h *livekit.DataStream_Header, destCount int) {
@@ -73,3 +82,12 @@ func RecordDataPacketStream(h *livekit.DataStream_Header, destCount int) {
 		promDataPacketStreamSize.WithLabelValues(streamType, mimeType).Observe(float64(*h.TotalLength))
 	}
 }
+
+// RecordDataPacketRejected counts data packets rejected by topic permissions,
+// permission is "publish" for rejected sends and "subscribe" for each receiver filtered out
+func RecordDataPacketRejected(packetType string, permission string) {
+	if promDataPacketRejected == nil {
+		return
+	}
+	promDataPacketRejected.WithLabelValues(packetType, permission).Inc()
+}
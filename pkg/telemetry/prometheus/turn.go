@@ -0,0 +1,126 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+var (
+	promTURNAllocations       prometheus.Gauge
+	promTURNRelayedBytes      *prometheus.CounterVec
+	promTURNDroppedBytes      prometheus.Counter
+	promTURNAuthFailures      *prometheus.CounterVec
+	promTURNAllocationsDenied *prometheus.CounterVec
+	promTURNAllocationsClosed *prometheus.CounterVec
+)
+
+func initTURNStats(nodeID string, nodeType livekit.NodeType) {
+	promTURNAllocations = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "allocations",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "Number of active TURN relay allocations.",
+	})
+	promTURNRelayedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "relayed_bytes",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "Bytes relayed through TURN allocations.",
+	}, []string{"direction"})
+	promTURNDroppedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "relay_dropped_bytes",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "Bytes dropped because of the per participant relay bandwidth cap.",
+	})
+	promTURNAuthFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "auth_failures",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "TURN requests rejected because of invalid credentials.",
+	}, []string{"reason"})
+	promTURNAllocationsDenied = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "allocations_denied",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "TURN allocations rejected by the per participant or per API key limits.",
+	}, []string{"reason"})
+	promTURNAllocationsClosed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "turn",
+		Name:        "allocations_closed",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+		Help:        "TURN allocations closed by the server before the client released them.",
+	}, []string{"reason"})
+
+	prometheus.MustRegister(promTURNAllocations)
+	prometheus.MustRegister(promTURNRelayedBytes)
+	prometheus.MustRegister(promTURNDroppedBytes)
+	prometheus.MustRegister(promTURNAuthFailures)
+	prometheus.MustRegister(promTURNAllocationsDenied)
+	prometheus.MustRegister(promTURNAllocationsClosed)
+}
+
+// TURN stats are recorded by handlers which may be used without Init, e.g. in tests
+
+func AddTURNAllocation() {
+	if promTURNAllocations != nil {
+		promTURNAllocations.Inc()
+	}
+}
+
+func SubTURNAllocation() {
+	if promTURNAllocations != nil {
+		promTURNAllocations.Dec()
+	}
+}
+
+func IncrementTURNRelayedBytes(direction Direction, count uint64) {
+	if promTURNRelayedBytes != nil {
+		promTURNRelayedBytes.WithLabelValues(string(direction)).Add(float64(count))
+	}
+}
+
+func IncrementTURNDroppedBytes(count uint64) {
+	if promTURNDroppedBytes != nil {
+		promTURNDroppedBytes.Add(float64(count))
+	}
+}
+
+func RecordTURNAuthFailure(reason string) {
+	if promTURNAuthFailures != nil {
+		promTURNAuthFailures.WithLabelValues(reason).Inc()
+	}
+}
+
+func RecordTURNAllocationDenied(reason string) {
+	if promTURNAllocationsDenied != nil {
+		promTURNAllocationsDenied.WithLabelValues(reason).Inc()
+	}
+}
+
+func RecordTURNAllocationClosed(reason string) {
+	if promTURNAllocationsClosed != nil {
+		promTURNAllocationsClosed.WithLabelValues(reason).Inc()
+	}
+}
@@ -83,6 +83,14 @@ type FakeTelemetryService struct {
 		arg2 string
 		arg3 *livekit.EgressInfo
 	}
+	NotifyRoomEventStub        func(context.Context, string, *livekit.Room, *livekit.ParticipantInfo)
+	notifyRoomEventMutex       sync.RWMutex
+	notifyRoomEventArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *livekit.Room
+		arg4 *livekit.ParticipantInfo
+	}
 	ParticipantActiveStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.AnalyticsClientMeta, bool, *telemetry.ReferenceGuard)
 	participantActiveMutex       sync.RWMutex
 	participantActiveArgsForCall []struct {
@@ -709,6 +717,41 @@ func (fake *FakeTelemetryService) NotifyEgressEventArgsForCall(i int) (context.C
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
+func (fake *FakeTelemetryService) NotifyRoomEvent(arg1 context.Context, arg2 string, arg3 *livekit.Room, arg4 *livekit.ParticipantInfo) {
+	fake.notifyRoomEventMutex.Lock()
+	fake.notifyRoomEventArgsForCall = append(fake.notifyRoomEventArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *livekit.Room
+		arg4 *livekit.ParticipantInfo
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.NotifyRoomEventStub
+	fake.recordInvocation("NotifyRoomEvent", []interface{}{arg1, arg2, arg3, arg4})
+	fake.notifyRoomEventMutex.Unlock()
+	if stub != nil {
+		fake.NotifyRoomEventStub(arg1, arg2, arg3, arg4)
+	}
+}
+
+func (fake *FakeTelemetryService) NotifyRoomEventCallCount() int {
+	fake.notifyRoomEventMutex.RLock()
+	defer fake.notifyRoomEventMutex.RUnlock()
+	return len(fake.notifyRoomEventArgsForCall)
+}
+
+func (fake *FakeTelemetryService) NotifyRoomEventCalls(stub func(context.Context, string, *livekit.Room, *livekit.ParticipantInfo)) {
+	fake.notifyRoomEventMutex.Lock()
+	defer fake.notifyRoomEventMutex.Unlock()
+	fake.NotifyRoomEventStub = stub
+}
+
+func (fake *FakeTelemetryService) NotifyRoomEventArgsForCall(i int) (context.Context, string, *livekit.Room, *livekit.ParticipantInfo) {
+	fake.notifyRoomEventMutex.RLock()
+	defer fake.notifyRoomEventMutex.RUnlock()
+	argsForCall := fake.notifyRoomEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
 func (fake *FakeTelemetryService) ParticipantActive(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo, arg4 *livekit.AnalyticsClientMeta, arg5 bool, arg6 *telemetry.ReferenceGuard) {
 	fake.participantActiveMutex.Lock()
 	fake.participantActiveArgsForCall = append(fake.participantActiveArgsForCall, struct {
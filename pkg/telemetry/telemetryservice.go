@@ -91,6 +91,7 @@ type TelemetryService interface {
 	// helpers
 	AnalyticsService
 	NotifyEgressEvent(ctx context.Context, event string, info *livekit.EgressInfo)
+	NotifyRoomEvent(ctx context.Context, event string, room *livekit.Room, participant *livekit.ParticipantInfo)
 	FlushStats()
 }
 
@@ -154,6 +155,8 @@ func (n NullTelemetryService) APICall(ctx context.Context, apiCallInfo *livekit.
 func (n NullTelemetryService) Webhook(ctx context.Context, webhookInfo *livekit.WebhookInfo)        {}
 func (n NullTelemetryService) NotifyEgressEvent(ctx context.Context, event string, info *livekit.EgressInfo) {
 }
+func (n NullTelemetryService) NotifyRoomEvent(ctx context.Context, event string, room *livekit.Room, participant *livekit.ParticipantInfo) {
+}
 func (n NullTelemetryService) FlushStats() {}
 
 // -----------------------------
@@ -0,0 +1,77 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// ByteRateLimiter is a token bucket limiting the number of bytes per second,
+// allowing bursts of up to one second worth of data, or minBurst bytes if larger
+type ByteRateLimiter struct {
+	lock       sync.Mutex
+	rate       float64
+	burst      float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+func NewByteRateLimiter(bytesPerSec uint64, minBurst uint64) *ByteRateLimiter {
+	burst := float64(max(bytesPerSec, minBurst))
+	return &ByteRateLimiter{
+		rate:       float64(bytesPerSec),
+		burst:      burst,
+		tokens:     burst,
+		lastRefill: time.Now(),
+	}
+}
+
+func (b *ByteRateLimiter) Allow(numBytes int) bool {
+	return b.AllowAt(numBytes, time.Now())
+}
+
+func (b *ByteRateLimiter) AllowAt(numBytes int, at time.Time) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.refillLocked(at)
+	if b.tokens < float64(numBytes) {
+		return false
+	}
+	b.tokens -= float64(numBytes)
+	return true
+}
+
+// Take consumes numBytes even if they exceed the available tokens,
+// the resulting debt is paid off before further bytes are allowed
+func (b *ByteRateLimiter) Take(numBytes int) {
+	b.TakeAt(numBytes, time.Now())
+}
+
+func (b *ByteRateLimiter) TakeAt(numBytes int, at time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.refillLocked(at)
+	b.tokens -= float64(numBytes)
+}
+
+func (b *ByteRateLimiter) refillLocked(at time.Time) {
+	if elapsed := at.Sub(b.lastRefill); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.lastRefill = at
+	}
+}
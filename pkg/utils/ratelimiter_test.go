@@ -0,0 +1,50 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestByteRateLimiter(t *testing.T) {
+	limiter := NewByteRateLimiter(100_000, 1500)
+	now := limiter.lastRefill
+
+	// burst of one second worth of data
+	require.True(t, limiter.AllowAt(60_000, now))
+	require.True(t, limiter.AllowAt(40_000, now))
+	require.False(t, limiter.AllowAt(1, now))
+
+	// refills at the configured rate
+	require.False(t, limiter.AllowAt(20_000, now.Add(100*time.Millisecond)))
+	require.True(t, limiter.AllowAt(10_000, now.Add(100*time.Millisecond)))
+
+	// does not accumulate beyond the burst
+	require.True(t, limiter.AllowAt(100_000, now.Add(time.Hour)))
+	require.False(t, limiter.AllowAt(1, now.Add(time.Hour)))
+
+	// taking beyond the available tokens delays further bytes
+	limiter.TakeAt(150_000, now.Add(time.Hour))
+	require.False(t, limiter.AllowAt(1, now.Add(time.Hour+time.Second)))
+	require.True(t, limiter.AllowAt(1, now.Add(time.Hour+1600*time.Millisecond)))
+
+	// minimum burst allows larger messages through low rates
+	limiter = NewByteRateLimiter(1000, 64*1024)
+	require.True(t, limiter.Allow(64*1024))
+	require.False(t, limiter.Allow(1024))
+}
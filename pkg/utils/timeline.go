@@ -0,0 +1,125 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gammazero/deque"
+)
+
+// TimelineEvent is a structured event recorded in a Timeline
+type TimelineEvent struct {
+	At          time.Time      `json:"at"`
+	Type        string         `json:"type"`
+	Participant string         `json:"participant,omitempty"`
+	TrackID     string         `json:"trackId,omitempty"`
+	Details     map[string]any `json:"details,omitempty"`
+}
+
+// Timeline keeps the most recent events of a room, to help understand what happened after the fact
+type Timeline struct {
+	maxEvents int
+
+	lock   sync.Mutex
+	events deque.Deque[TimelineEvent]
+}
+
+// NewTimeline returns nil when maxEvents is not positive, events recorded into a nil Timeline are dropped
+func NewTimeline(maxEvents int) *Timeline {
+	if maxEvents <= 0 {
+		return nil
+	}
+	return &Timeline{maxEvents: maxEvents}
+}
+
+func (t *Timeline) Record(event TimelineEvent) {
+	if t == nil {
+		return
+	}
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for t.events.Len() >= t.maxEvents {
+		t.events.PopFront()
+	}
+	t.events.PushBack(event)
+}
+
+// Events returns recorded events, oldest first
+func (t *Timeline) Events() []TimelineEvent {
+	if t == nil {
+		return nil
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	events := make([]TimelineEvent, 0, t.events.Len())
+	for i := 0; i < t.events.Len(); i++ {
+		events = append(events, t.events.At(i))
+	}
+	return events
+}
+
+func (t *Timeline) WithParticipant(identity string) TimelineRecorder {
+	return TimelineRecorder{timeline: t, participant: identity}
+}
+
+// TimelineRecorder records events of a participant or track into a Timeline, the zero value drops events
+type TimelineRecorder struct {
+	timeline    *Timeline
+	participant string
+	trackID     string
+}
+
+func (r TimelineRecorder) WithTrack(trackID string) TimelineRecorder {
+	r.trackID = trackID
+	return r
+}
+
+// Record records an event with details given as key value pairs, like loggers
+func (r TimelineRecorder) Record(eventType string, keysAndValues ...any) {
+	if r.timeline == nil {
+		return
+	}
+
+	event := TimelineEvent{
+		Type:        eventType,
+		Participant: r.participant,
+		TrackID:     r.trackID,
+	}
+	if len(keysAndValues) > 1 {
+		event.Details = make(map[string]any, len(keysAndValues)/2)
+		for i := 0; i+1 < len(keysAndValues); i += 2 {
+			key := fmt.Sprint(keysAndValues[i])
+			switch v := keysAndValues[i+1].(type) {
+			case fmt.Stringer:
+				event.Details[key] = v.String()
+			case error:
+				event.Details[key] = v.Error()
+			default:
+				event.Details[key] = v
+			}
+		}
+	}
+	r.timeline.Record(event)
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeline(t *testing.T) {
+	t.Run("keeps most recent events", func(t *testing.T) {
+		timeline := NewTimeline(3)
+		for _, eventType := range []string{"a", "b", "c", "d", "e"} {
+			timeline.Record(TimelineEvent{Type: eventType})
+		}
+
+		events := timeline.Events()
+		require.Len(t, events, 3)
+		for i, eventType := range []string{"c", "d", "e"} {
+			require.Equal(t, eventType, events[i].Type)
+			require.False(t, events[i].At.IsZero())
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		timeline := NewTimeline(0)
+		require.Nil(t, timeline)
+
+		timeline.Record(TimelineEvent{Type: "a"})
+		timeline.WithParticipant("p").Record("b")
+		TimelineRecorder{}.Record("c")
+		require.Empty(t, timeline.Events())
+	})
+
+	t.Run("recorder", func(t *testing.T) {
+		timeline := NewTimeline(10)
+		recorder := timeline.WithParticipant("p").WithTrack("TR_1")
+		recorder.Record("pli", "layer", int32(2), "duration", time.Second, "err", errors.New("failed"), "dangling")
+
+		events := timeline.Events()
+		require.Len(t, events, 1)
+		require.Equal(t, "pli", events[0].Type)
+		require.Equal(t, "p", events[0].Participant)
+		require.Equal(t, "TR_1", events[0].TrackID)
+		require.Equal(t, map[string]any{
+			"layer":    int32(2),
+			"duration": "1s",
+			"err":      "failed",
+		}, events[0].Details)
+	})
+}